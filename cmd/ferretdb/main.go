@@ -37,6 +37,7 @@ import (
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/registry"
 	"github.com/FerretDB/FerretDB/internal/util/debug"
 	"github.com/FerretDB/FerretDB/internal/util/debugbuild"
@@ -89,6 +90,11 @@ var cli struct {
 
 	Telemetry telemetry.Flag `default:"undecided" help:"Enable or disable basic telemetry. See https://beacon.ferretdb.io."`
 
+	GroupMemoryLimit int64 `default:"104857600" help:"Approximate memory limit of group aggregation stage in bytes, 0 for no limit."`
+	SortMemoryLimit  int64 `default:"104857600" help:"Approximate memory limit of sorting in bytes, 0 for no limit."`
+
+	TTLMonitorInterval time.Duration `default:"60s" help:"Interval between deletions of expired documents of TTL indexes, 0 to disable."`
+
 	Test struct {
 		RecordsDir string `default:"" help:"Testing: directory for record files."`
 
@@ -222,6 +228,36 @@ func setupState() *state.Provider {
 	return sp
 }
 
+// setupSpillDir returns the directory for temporary files of stages that exceed memory limits
+// and removes files left there by previous runs.
+//
+// If the state directory is not set, it returns an empty string,
+// so the default directory for temporary files is used.
+func setupSpillDir(logger *zap.Logger) string {
+	// https://github.com/alecthomas/kong/issues/389
+	if cli.StateDir == "" || cli.StateDir == "-" {
+		return ""
+	}
+
+	dir, err := filepath.Abs(filepath.Join(cli.StateDir, "tmp"))
+	if err != nil {
+		logger.Sugar().Fatalf("Failed to get path for temporary files: %s.", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, common.SpillFilePattern))
+	if err != nil {
+		logger.Sugar().Fatalf("Failed to list temporary files: %s.", err)
+	}
+
+	for _, f := range files {
+		if err = os.Remove(f); err != nil {
+			logger.Sugar().Warnf("Failed to remove temporary file: %s.", err)
+		}
+	}
+
+	return dir
+}
+
 // setupMetrics setups Prometheus metrics registerer with some metrics.
 func setupMetrics(stateProvider *state.Provider) prometheus.Registerer {
 	r := prometheus.DefaultRegisterer
@@ -387,6 +423,12 @@ func run() {
 		ConnMetrics:   metrics.ConnMetrics,
 		StateProvider: stateProvider,
 
+		GroupMemoryLimit: cli.GroupMemoryLimit,
+		SortMemoryLimit:  cli.SortMemoryLimit,
+		SpillDir:         setupSpillDir(logger),
+
+		TTLMonitorInterval: cli.TTLMonitorInterval,
+
 		PostgreSQLURL: postgreSQLFlags.PostgreSQLURL,
 
 		SQLiteURL: sqliteFlags.SQLiteURL,
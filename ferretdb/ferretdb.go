@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -121,6 +122,9 @@ func New(config *Config) (*FerretDB, error) {
 		PostgreSQLURL: config.PostgreSQLURL,
 
 		SQLiteURL: config.SQLiteURL,
+
+		// the same as the default value of the `--ttl-monitor-interval` flag
+		TTLMonitorInterval: time.Minute,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct handler: %s", err)
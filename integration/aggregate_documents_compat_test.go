@@ -38,6 +38,7 @@ type aggregateStagesCompatTestCase struct {
 
 	resultType     compatTestCaseResultType // defaults to nonEmptyResult
 	resultPushdown resultPushdown           // defaults to noPushdown
+	groupPushdown  resultPushdown           // defaults to noPushdown
 	skip           string                   // always skip this test case, must have issue number mentioned
 }
 
@@ -153,6 +154,9 @@ func testAggregateStagesCompatWithProviders(t *testing.T, providers shareddata.P
 					doc := ConvertDocument(t, explainRes)
 					pushdown, _ := doc.Get("filterPushdown")
 					assert.Equal(t, resPushdown.FilterPushdownExpected(t), pushdown, msg)
+
+					groupPushdown, _ := doc.Get("groupPushdown")
+					assert.Equal(t, tc.groupPushdown.GroupPushdownExpected(t), groupPushdown)
 				})
 			}
 
@@ -291,6 +295,23 @@ func TestAggregateCommandCompat(t *testing.T) {
 				{"maxTimeMS", float64(1000)},
 			},
 		},
+		"AllowDiskUse": {
+			command: bson.D{
+				{"aggregate", "collection-name"},
+				{"pipeline", bson.A{bson.D{{"$group", bson.D{{"_id", "$v"}}}}}},
+				{"cursor", bson.D{}},
+				{"allowDiskUse", true},
+			},
+		},
+		"AllowDiskUseTypeMismatch": {
+			command: bson.D{
+				{"aggregate", "collection-name"},
+				{"pipeline", bson.A{}},
+				{"cursor", bson.D{}},
+				{"allowDiskUse", "true"},
+			},
+			resultType: emptyResult,
+		},
 	}
 
 	testAggregateCommandCompat(t, testCases)
@@ -329,7 +350,8 @@ func TestAggregateCompatStages(t *testing.T) {
 				bson.D{{"$count", "v"}},
 				bson.D{{"$sort", bson.D{{"_id", 1}}}},
 			},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
+			groupPushdown:  allPushdown,
 		},
 		"CountAndMatch": {
 			pipeline: bson.A{
@@ -338,6 +360,7 @@ func TestAggregateCompatStages(t *testing.T) {
 				bson.D{{"$match", bson.D{{"v", 1}}}},
 				bson.D{{"$sort", bson.D{{"_id", 1}}}},
 			},
+			groupPushdown: allPushdown,
 		},
 	}
 
@@ -367,10 +390,20 @@ func TestAggregateCompatCount(t *testing.T) {
 
 	testCases := map[string]aggregateStagesCompatTestCase{
 		"Value": {
-			pipeline: bson.A{bson.D{{"$count", "v"}}},
+			pipeline:      bson.A{bson.D{{"$count", "v"}}},
+			groupPushdown: allPushdown,
 		},
 		"NonExistent": {
-			pipeline: bson.A{bson.D{{"$count", "nonexistent"}}},
+			pipeline:      bson.A{bson.D{{"$count", "nonexistent"}}},
+			groupPushdown: allPushdown,
+		},
+		"MatchCount": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", "foo"}}}},
+				bson.D{{"$count", "v"}},
+			},
+			resultPushdown: allPushdown,
+			groupPushdown:  allPushdown,
 		},
 		"CountGroupID": {
 			pipeline:   bson.A{bson.D{{"$count", "_id"}}},
@@ -508,11 +541,37 @@ func TestAggregateCompatGroup(t *testing.T) {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", nil},
 			}}}},
+			groupPushdown: allPushdown,
 		},
 		"DistinctID": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", "$_id"},
 			}}}},
+			groupPushdown: allPushdown,
+		},
+		"MatchGroupPushdown": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", 42}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", "$_id"},
+					{"count", bson.D{{"$count", bson.D{}}}},
+					{"sum", bson.D{{"$sum", "$v"}}},
+					{"avg", bson.D{{"$avg", "$v"}}},
+					{"min", bson.D{{"$min", "$_id"}}},
+					{"max", bson.D{{"$max", "$_id"}}},
+				}}},
+			},
+			resultPushdown: allPushdown,
+			groupPushdown:  allPushdown,
+		},
+		"MatchGroupNoPushdown": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", "$_id"},
+					{"count", bson.D{{"$count", bson.D{}}}},
+				}}},
+			},
 		},
 		"IDExpression": {
 			pipeline: bson.A{
@@ -634,11 +693,20 @@ func TestAggregateCompatGroup(t *testing.T) {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", "$invalid"},
 			}}}},
+			groupPushdown: allPushdown,
 		},
 		"NonExpressionID": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", "v"},
 			}}}},
+			groupPushdown: allPushdown,
+		},
+		"ConstantIDCount": {
+			pipeline: bson.A{bson.D{{"$group", bson.D{
+				{"_id", int32(1)},
+				{"n", bson.D{{"$sum", int32(1)}}},
+			}}}},
+			groupPushdown: allPushdown,
 		},
 		"NonStringID": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
@@ -649,6 +717,7 @@ func TestAggregateCompatGroup(t *testing.T) {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", "$add"},
 			}}}},
+			groupPushdown: allPushdown,
 		},
 		"EmptyPath": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
@@ -860,12 +929,14 @@ func TestAggregateCompatGroupCount(t *testing.T) {
 				{"_id", nil},
 				{"count", bson.D{{"$count", bson.D{}}}},
 			}}}},
+			groupPushdown: allPushdown,
 		},
 		"CountID": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", "$_id"},
 				{"count", bson.D{{"$count", bson.D{}}}},
 			}}}},
+			groupPushdown: allPushdown,
 		},
 		"TypeMismatch": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
@@ -886,6 +957,7 @@ func TestAggregateCompatGroupCount(t *testing.T) {
 				{"_id", "$nonexistent"},
 				{"count", bson.D{{"$count", bson.D{}}}},
 			}}}},
+			groupPushdown: allPushdown,
 		},
 		"Duplicate": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
@@ -990,7 +1062,7 @@ func TestAggregateCompatLimit(t *testing.T) {
 				bson.D{{"$match", bson.D{{"v", "foo"}}}},
 				bson.D{{"$limit", 1}},
 			},
-			resultPushdown: pgPushdown, // $sort and $match are first two stages
+			resultPushdown: allPushdown, // $sort and $match are first two stages
 		},
 		"BeforeMatch": {
 			pipeline: bson.A{
@@ -1005,7 +1077,7 @@ func TestAggregateCompatLimit(t *testing.T) {
 				bson.D{{"$match", bson.D{{"v", "foo"}}}},
 				bson.D{{"$limit", 100}},
 			},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"NoSortBeforeMatch": {
 			pipeline: bson.A{
@@ -1241,6 +1313,171 @@ func TestAggregateCompatGroupSum(t *testing.T) {
 	testAggregateStagesCompatWithProviders(t, providers, testCases)
 }
 
+func TestAggregateCompatGroupAccumulators(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.Int32s,
+		shareddata.Int64s,
+		shareddata.SmallDoubles,
+		shareddata.Strings,
+		shareddata.DateTimes,
+		shareddata.Nulls,
+		shareddata.Unsets,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"Avg": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"avg", bson.D{{"$avg", "$v"}}},
+				}}},
+			},
+			groupPushdown: allPushdown,
+		},
+		"MinMax": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"min", bson.D{{"$min", "$v"}}},
+					{"max", bson.D{{"$max", "$v"}}},
+				}}},
+			},
+			groupPushdown: allPushdown,
+		},
+		"FirstLast": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"first", bson.D{{"$first", "$v"}}},
+					{"last", bson.D{{"$last", "$v"}}},
+				}}},
+			},
+		},
+		"Push": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"push", bson.D{{"$push", bson.D{{"id", "$_id"}, {"v", "$v"}}}}},
+				}}},
+			},
+		},
+		"AddToSet": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", "$_id"},
+					{"set", bson.D{{"$addToSet", "$v"}}},
+				}}},
+			},
+		},
+		"MergeObjects": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"merged", bson.D{{"$mergeObjects", bson.D{{"v", "$v"}}}}},
+				}}},
+			},
+		},
+		"MergeObjectsInvalid": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"merged", bson.D{{"$mergeObjects", "$_id"}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"StdDev": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", "$_id"},
+					{"pop", bson.D{{"$stdDevPop", "$v"}}},
+					{"samp", bson.D{{"$stdDevSamp", "$v"}}},
+				}}},
+			},
+		},
+		"TopBottom": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"top", bson.D{{"$top", bson.D{{"sortBy", bson.D{{"_id", 1}}}, {"output", "$v"}}}}},
+					{"bottom", bson.D{{"$bottom", bson.D{{"sortBy", bson.D{{"_id", 1}}}, {"output", "$v"}}}}},
+				}}},
+			},
+		},
+		"TopNBottomN": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"top", bson.D{{"$topN", bson.D{{"n", 2}, {"sortBy", bson.D{{"_id", 1}}}, {"output", "$_id"}}}}},
+					{"bottom", bson.D{{"$bottomN", bson.D{{"n", 2}, {"sortBy", bson.D{{"_id", 1}}}, {"output", "$_id"}}}}},
+				}}},
+			},
+		},
+		"TopNMissingN": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"top", bson.D{{"$topN", bson.D{{"sortBy", bson.D{{"_id", 1}}}, {"output", "$v"}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"FirstNLastN": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"first", bson.D{{"$firstN", bson.D{{"input", "$_id"}, {"n", 2}}}}},
+					{"last", bson.D{{"$lastN", bson.D{{"input", "$_id"}, {"n", 2}}}}},
+				}}},
+			},
+		},
+		"MaxNMinN": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"max", bson.D{{"$maxN", bson.D{{"input", "$_id"}, {"n", 3}}}}},
+					{"min", bson.D{{"$minN", bson.D{{"input", "$_id"}, {"n", 3}}}}},
+				}}},
+			},
+		},
+		"NZero": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"first", bson.D{{"$firstN", bson.D{{"input", "$v"}, {"n", 0}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"NUnknownArgument": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"first", bson.D{{"$firstN", bson.D{{"input", "$v"}, {"n", 1}, {"foo", 1}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"Unary": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"push", bson.D{{"$push", bson.A{"$v", "$_id"}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
+
 func TestAggregateCompatMatch(t *testing.T) {
 	t.Parallel()
 
@@ -1256,13 +1493,13 @@ func TestAggregateCompatMatch(t *testing.T) {
 			pipeline: bson.A{
 				bson.D{{"$match", bson.D{{"v", 42}}}},
 			},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"String": {
 			pipeline: bson.A{
 				bson.D{{"$match", bson.D{{"v", "foo"}}}},
 			},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Document": {
 			pipeline: bson.A{bson.D{{"$match", bson.D{{"v", bson.D{{"foo", int32(42)}}}}}}},
@@ -1571,7 +1808,7 @@ func TestAggregateCompatSkip(t *testing.T) {
 				bson.D{{"$match", bson.D{{"v", "foo"}}}},
 				bson.D{{"$skip", int32(1)}},
 			},
-			resultPushdown: pgPushdown, // $match after $sort can be pushed down
+			resultPushdown: allPushdown, // $match after $sort can be pushed down
 		},
 		"BeforeMatch": {
 			pipeline: bson.A{
@@ -0,0 +1,384 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/util/testutil/teststress"
+)
+
+// listIndexSpec returns the specification of the index with the given name.
+func listIndexSpec(t *testing.T, ctx context.Context, collection *mongo.Collection, name string) bson.D {
+	t.Helper()
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var specs []bson.D
+	require.NoError(t, cursor.All(ctx, &specs))
+
+	for _, spec := range specs {
+		for _, e := range spec {
+			if e.Key == "name" && e.Value == name {
+				return spec
+			}
+		}
+	}
+
+	t.Fatalf("index %q not found", name)
+
+	return nil
+}
+
+// specValue returns the value of the given field of the specification, or nil if it is not set.
+func specValue(spec bson.D, key string) any {
+	for _, e := range spec {
+		if e.Key == key {
+			return e.Value
+		}
+	}
+
+	return nil
+}
+
+func TestCollModIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetName("v_1"),
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"keyPattern", bson.D{{"v", 1}}}, {"expireAfterSeconds", int32(60)}, {"hidden", true}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"expireAfterSeconds_new", int64(60)},
+		{"hidden_old", false},
+		{"hidden_new", true},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	spec := listIndexSpec(t, ctx, collection, "v_1")
+	assert.EqualValues(t, 60, specValue(spec, "expireAfterSeconds"))
+	assert.Equal(t, true, specValue(spec, "hidden"))
+
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", "v_1"}, {"expireAfterSeconds", int32(120)}, {"hidden", false}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	expected = bson.D{
+		{"expireAfterSeconds_old", int64(60)},
+		{"expireAfterSeconds_new", int64(120)},
+		{"hidden_old", true},
+		{"hidden_new", false},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	spec = listIndexSpec(t, ctx, collection, "v_1")
+	assert.EqualValues(t, 120, specValue(spec, "expireAfterSeconds"))
+	assert.Nil(t, specValue(spec, "hidden"))
+}
+
+func TestCollModIndexUnique(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetName("v_1"),
+	})
+	require.NoError(t, err)
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "a"}, {"v", int32(1)}},
+		bson.D{{"_id", "b"}, {"v", int32(1)}},
+		bson.D{{"_id", "c"}, {"v", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", "v_1"}, {"unique", true}}},
+	}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    72,
+		Name:    "InvalidOptions",
+		Message: "Cannot make index unique with 'prepareUnique=false'. Run collMod to set it first.",
+	}, err)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", "v_1"}, {"prepareUnique", true}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"prepareUnique_old", false},
+		{"prepareUnique_new", true},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	assert.Equal(t, true, specValue(listIndexSpec(t, ctx, collection, "v_1"), "prepareUnique"))
+
+	t.Run("NewDuplicates", func(t *testing.T) {
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "d"}, {"v", int32(2)}})
+		var we mongo.WriteException
+		require.ErrorAs(t, err, &we)
+		require.Len(t, we.WriteErrors, 1)
+		assert.Equal(t, 11000, we.WriteErrors[0].Code)
+
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "c"}}, bson.D{{"$set", bson.D{{"v", int32(1)}}}})
+		require.ErrorAs(t, err, &we)
+		require.Len(t, we.WriteErrors, 1)
+		assert.Equal(t, 11000, we.WriteErrors[0].Code)
+
+		// existing duplicates can still be updated
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "b"}}, bson.D{{"$set", bson.D{{"w", int32(1)}}}})
+		require.NoError(t, err)
+	})
+
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", "v_1"}, {"unique", true}}},
+	}).Err()
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(359), ce.Code)
+	assert.Equal(t, "CannotConvertIndexToUnique", ce.Name)
+
+	_, err = collection.DeleteOne(ctx, bson.D{{"_id", "b"}})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", "v_1"}, {"unique", true}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	expected = bson.D{
+		{"unique_new", true},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	assert.Equal(t, true, specValue(listIndexSpec(t, ctx, collection, "v_1"), "unique"))
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "e"}, {"v", int32(1)}})
+	var we mongo.WriteException
+	require.ErrorAs(t, err, &we)
+	require.Len(t, we.WriteErrors, 1)
+	assert.Equal(t, 11000, we.WriteErrors[0].Code)
+}
+
+func TestCollModIndexPrepareUniqueStress(t *testing.T) {
+	// no t.Parallel() for stress tests
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetName("v_1"),
+	})
+	require.NoError(t, err)
+
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", "v_1"}, {"prepareUnique", true}}},
+	}).Err()
+	require.NoError(t, err)
+
+	var id atomic.Int32
+
+	// documents are checked and inserted atomically, so only one of them should be inserted
+	teststress.Stress(t, func(ready chan<- struct{}, start <-chan struct{}) {
+		id := id.Add(1)
+
+		ready <- struct{}{}
+		<-start
+
+		_, err := collection.UpdateOne(
+			ctx,
+			bson.D{{"_id", id}},
+			bson.D{{"$set", bson.D{{"v", "duplicate"}}}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			var we mongo.WriteException
+			require.ErrorAs(t, err, &we)
+			require.Len(t, we.WriteErrors, 1)
+			assert.Equal(t, 11000, we.WriteErrors[0].Code)
+		}
+	})
+
+	n, err := collection.CountDocuments(ctx, bson.D{{"v", "duplicate"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestCollModCapped(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "a"}})
+	require.NoError(t, err)
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1024).SetMaxDocuments(10)
+	require.NoError(t, db.CreateCollection(ctx, "capped", opts))
+
+	err = db.RunCommand(ctx, bson.D{
+		{"collMod", "capped"},
+		{"cappedSize", int64(2000)},
+		{"cappedMax", int64(5)},
+	}).Err()
+	require.NoError(t, err)
+
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{"name", "capped"}})
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+
+	var actual bson.D
+	require.NoError(t, bson.Unmarshal(specs[0].Options, &actual))
+	assert.Equal(t, true, specValue(actual, "capped"))
+	assert.EqualValues(t, 2048, specValue(actual, "size"))
+	assert.EqualValues(t, 5, specValue(actual, "max"))
+
+	err = db.RunCommand(ctx, bson.D{{"collMod", collection.Name()}, {"cappedSize", int64(2048)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    72,
+		Name:    "InvalidOptions",
+		Message: "Cannot change the size or maximum number of documents of a non-capped collection",
+	}, err)
+}
+
+func TestCollModErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"a", 1}, {"b", 1}},
+		Options: options.Index().SetName("a_1_b_1"),
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		index    bson.D
+		expected mongo.CommandError
+	}{
+		"NotFound": {
+			index: bson.D{{"name", "notFound"}, {"hidden", true}},
+			expected: mongo.CommandError{
+				Code:    27,
+				Name:    "IndexNotFound",
+				Message: "cannot find index notFound for ns " + db.Name() + "." + collection.Name(),
+			},
+		},
+		"NameAndKeyPattern": {
+			index: bson.D{{"name", "a_1_b_1"}, {"keyPattern", bson.D{{"a", 1}, {"b", 1}}}, {"hidden", true}},
+			expected: mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "Cannot specify both key pattern and name.",
+			},
+		},
+		"NoNameOrKeyPattern": {
+			index: bson.D{{"hidden", true}},
+			expected: mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "Must specify either index name or key pattern.",
+			},
+		},
+		"NoModifications": {
+			index: bson.D{{"name", "a_1_b_1"}},
+			expected: mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "no expireAfterSeconds, hidden, prepareUnique or unique field",
+			},
+		},
+		"HideID": {
+			index: bson.D{{"name", "_id_"}, {"hidden", true}},
+			expected: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "can't hide _id index",
+			},
+		},
+		"CompoundTTL": {
+			index: bson.D{{"name", "a_1_b_1"}, {"expireAfterSeconds", int32(10)}},
+			expected: mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "TTL indexes are single-field indexes, compound indexes do not support TTL.",
+			},
+		},
+		"UniqueFalse": {
+			index: bson.D{{"name", "a_1_b_1"}, {"unique", false}},
+			expected: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "collMod does not support converting an index to 'unique: false'",
+			},
+		},
+		"HiddenType": {
+			index: bson.D{{"name", "a_1_b_1"}, {"hidden", "true"}},
+			expected: mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'collMod.index.hidden' is the wrong type 'string', expected type 'bool'",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.RunCommand(ctx, bson.D{{"collMod", collection.Name()}, {"index", tc.index}}).Err()
+			AssertEqualCommandError(t, tc.expected, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestCountWithoutQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 200)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	n, err := collection.EstimatedDocumentCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), n)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"count", collection.Name()}, {"skip", int32(150)}, {"limit", int32(20)},
+	}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"n", int32(20)}, {"ok", float64(1)}}, res)
+
+	// small collections are counted exactly, so the result does not depend on statistics that are not updated yet
+	_, err = collection.DeleteMany(ctx, bson.D{})
+	require.NoError(t, err)
+
+	n, err = collection.EstimatedDocumentCount(ctx, options.EstimatedDocumentCount())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
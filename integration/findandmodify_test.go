@@ -29,6 +29,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/util/testutil/teststress"
 )
 
 func TestFindAndModifyEmptyCollectionName(t *testing.T) {
@@ -636,3 +637,57 @@ func TestFindAndModifyCommentQuery(t *testing.T) {
 	))
 	testutil.AssertEqual(t, expectedLastErrObj, lastErrObj.(*types.Document))
 }
+
+func TestFindAndModifyIncStress(t *testing.T) {
+	// no t.Parallel() for stress tests
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "inc"}, {"v", int32(0)}})
+	require.NoError(t, err)
+
+	n := teststress.Stress(t, func(ready chan<- struct{}, start <-chan struct{}) {
+		ready <- struct{}{}
+		<-start
+
+		err := collection.FindOneAndUpdate(
+			ctx,
+			bson.D{{"_id", "inc"}},
+			bson.D{{"$inc", bson.D{{"v", int32(1)}}}},
+		).Err()
+		require.NoError(t, err)
+	})
+
+	var res bson.D
+	err = collection.FindOne(ctx, bson.D{{"_id", "inc"}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"_id", "inc"}, {"v", int32(n)}}, res)
+}
+
+func TestFindAndModifyUpsertStress(t *testing.T) {
+	// no t.Parallel() for stress tests
+
+	ctx, collection := setup.Setup(t)
+
+	n := teststress.Stress(t, func(ready chan<- struct{}, start <-chan struct{}) {
+		ready <- struct{}{}
+		<-start
+
+		err := collection.FindOneAndUpdate(
+			ctx,
+			bson.D{{"_id", "upsert"}},
+			bson.D{{"$inc", bson.D{{"v", int32(1)}}}},
+			options.FindOneAndUpdate().SetUpsert(true),
+		).Err()
+		if err != nil {
+			require.ErrorIs(t, err, mongo.ErrNoDocuments)
+		}
+	})
+
+	var res []bson.D
+	cursor, err := collection.Find(ctx, bson.D{})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &res))
+
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "upsert"}, {"v", int32(n)}}}, res)
+}
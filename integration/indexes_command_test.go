@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -597,3 +598,113 @@ func TestDropIndexesCommandInvalidCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateIndexesCommandText(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{"title", "text"}, {"body", "text"}},
+		Options: options.Index().
+			SetWeights(bson.D{{"title", int32(10)}}).
+			SetDefaultLanguage("spanish").
+			SetLanguageOverride("idioma"),
+	})
+	require.NoError(t, err)
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	expected := []bson.D{
+		{{"v", int32(2)}, {"key", bson.D{{"_id", int32(1)}}}, {"name", "_id_"}},
+		{
+			{"v", int32(2)},
+			{"key", bson.D{{"_fts", "text"}, {"_ftsx", int32(1)}}},
+			{"name", "title_text_body_text"},
+			{"weights", bson.D{{"body", int32(1)}, {"title", int32(10)}}},
+			{"default_language", "spanish"},
+			{"language_override", "idioma"},
+			{"textIndexVersion", int32(3)},
+		},
+	}
+	AssertEqualDocumentsSlice(t, expected, FetchAll(t, ctx, cursor))
+
+	_, err = collection.Indexes().DropOne(ctx, "title_text_body_text")
+	require.NoError(t, err)
+}
+
+func TestCreateIndexesCommandGeo(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"loc", "2dsphere"}}},
+		{
+			Keys:    bson.D{{"pos", "2d"}, {"v", int32(1)}},
+			Options: options.Index().SetBits(20).SetMin(-10).SetMax(10),
+		},
+	})
+	require.NoError(t, err)
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	expected := []bson.D{
+		{{"v", int32(2)}, {"key", bson.D{{"_id", int32(1)}}}, {"name", "_id_"}},
+		{
+			{"v", int32(2)},
+			{"key", bson.D{{"loc", "2dsphere"}}},
+			{"name", "loc_2dsphere"},
+			{"2dsphereIndexVersion", int32(3)},
+		},
+		{
+			{"v", int32(2)},
+			{"key", bson.D{{"pos", "2d"}, {"v", int32(1)}}},
+			{"name", "pos_2d_v_1"},
+			{"bits", int32(20)},
+			{"min", float64(-10)},
+			{"max", float64(10)},
+		},
+	}
+	AssertEqualDocumentsSlice(t, expected, FetchAll(t, ctx, cursor))
+
+	for name, tc := range map[string]struct {
+		index mongo.IndexModel    // required
+		err   *mongo.CommandError // required
+	}{
+		"BitsOn2DSphere": {
+			index: mongo.IndexModel{Keys: bson.D{{"a", "2dsphere"}}, Options: options.Index().SetBits(20)},
+			err: &mongo.CommandError{
+				Code:    197,
+				Name:    "InvalidIndexSpecificationOption",
+				Message: "The field 'bits' is valid only for 2d indexes",
+			},
+		},
+		"2DNotFirst": {
+			index: mongo.IndexModel{Keys: bson.D{{"a", int32(1)}, {"b", "2d"}}},
+			err: &mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "2d has to be first in index",
+			},
+		},
+		"MinMax": {
+			index: mongo.IndexModel{Keys: bson.D{{"a", "2d"}}, Options: options.Index().SetMin(10).SetMax(-10)},
+			err: &mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "max must be greater than min",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Indexes().CreateOne(ctx, tc.index)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// indexStats returns the number of accesses of each collection index reported by $indexStats.
+func indexStats(t *testing.T, ctx context.Context, collection *mongo.Collection) map[string]int64 {
+	t.Helper()
+
+	cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$indexStats", bson.D{}}}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	stats := make(map[string]int64, len(res))
+
+	for _, doc := range res {
+		m := doc.Map()
+		require.Contains(t, m, "key")
+		require.Contains(t, m, "host")
+		require.Contains(t, m, "spec")
+
+		accesses := m["accesses"].(bson.D).Map()
+		require.Contains(t, accesses, "since")
+
+		stats[m["name"].(string)] = accesses["ops"].(int64)
+	}
+
+	return stats
+}
+
+func TestIndexesHidden(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "foo"}},
+		bson.D{{"_id", int32(2)}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	indexName, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v.$**", 1}},
+		Options: options.Index().SetName("wildcard").SetHidden(true),
+	})
+	require.NoError(t, err)
+
+	spec := listIndexSpec(t, ctx, collection, indexName)
+	assert.Equal(t, true, specValue(spec, "hidden"))
+
+	stats := indexStats(t, ctx, collection)
+	assert.Equal(t, map[string]int64{"_id_": 0, "wildcard": 0}, stats)
+
+	// hidden index is not used, but still maintained
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", int32(3)}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	cursor, err := collection.Find(ctx, bson.D{{"v", "foo"}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []any{int32(1), int32(3)}, CollectIDs(t, FetchAll(t, ctx, cursor)))
+
+	assert.Equal(t, int64(0), indexStats(t, ctx, collection)["wildcard"])
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"index", bson.D{{"name", indexName}, {"hidden", false}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	cursor, err = collection.Find(ctx, bson.D{{"v", "foo"}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []any{int32(1), int32(3)}, CollectIDs(t, FetchAll(t, ctx, cursor)))
+
+	if !setup.IsMongoDB(t) && !setup.FilterPushdownDisabled() {
+		assert.Equal(t, int64(1), indexStats(t, ctx, collection)["wildcard"])
+
+		err = collection.Database().RunCommand(ctx, bson.D{
+			{"explain", bson.D{{"find", collection.Name()}, {"filter", bson.D{{"v", "foo"}}}}},
+		}).Decode(&res)
+		require.NoError(t, err)
+		assert.Equal(t, indexName, res.Map()["indexName"])
+	}
+
+	// counters are forgotten with the index
+	_, err = collection.Indexes().DropOne(ctx, indexName)
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v.$**", 1}},
+		Options: options.Index().SetName("wildcard"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(0), indexStats(t, ctx, collection)["wildcard"])
+}
+
+func TestIndexesHiddenErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		command  bson.D
+		expected mongo.CommandError
+		altMsg   string
+	}{
+		"HiddenID": {
+			command: bson.D{
+				{"createIndexes", collection.Name()},
+				{"indexes", bson.A{bson.D{{"key", bson.D{{"_id", 1}}}, {"name", "_id_"}, {"hidden", true}}}},
+			},
+			expected: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "can't hide _id index",
+			},
+		},
+		"HiddenType": {
+			command: bson.D{
+				{"createIndexes", collection.Name()},
+				{"indexes", bson.A{bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}, {"hidden", "true"}}}},
+			},
+			expected: mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "The field 'hidden' must be a boolean, but got string",
+			},
+		},
+		"IndexStatsSpec": {
+			command: bson.D{
+				{"aggregate", collection.Name()},
+				{"pipeline", bson.A{bson.D{{"$indexStats", bson.D{{"v", 1}}}}}},
+				{"cursor", bson.D{}},
+			},
+			expected: mongo.CommandError{
+				Code:    28803,
+				Name:    "Location28803",
+				Message: "The $indexStats stage specification must be an empty object",
+			},
+		},
+		"IndexStatsNotFirst": {
+			command: bson.D{
+				{"aggregate", collection.Name()},
+				{"pipeline", bson.A{bson.D{{"$match", bson.D{}}}, bson.D{{"$indexStats", bson.D{}}}}},
+				{"cursor", bson.D{}},
+			},
+			expected: mongo.CommandError{
+				Code:    40602,
+				Name:    "Location40602",
+				Message: "$indexStats is only valid as the first stage in a pipeline",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var res bson.D
+			err := collection.Database().RunCommand(ctx, tc.command).Decode(&res)
+
+			assert.Nil(t, res)
+			AssertEqualAltCommandError(t, tc.expected, tc.altMsg, err)
+		})
+	}
+}
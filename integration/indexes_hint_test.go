@@ -0,0 +1,240 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestIndexesHint(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "foo"}, {"w", int32(1)}},
+		bson.D{{"_id", int32(2)}, {"v", "bar"}},
+		bson.D{{"_id", int32(3)}, {"v", bson.A{"foo", "baz"}}, {"w", int32(3)}},
+		bson.D{{"_id", int32(4)}, {"v", int32(42)}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"v", 1}}},
+		{Keys: bson.D{{"w", -1}}, Options: options.Index().SetSparse(true)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		filter      bson.D
+		hint        any
+		expectedIDs []any
+		index       string // expected winning plan index, or an empty string for a collection scan
+	}{
+		"Name": {
+			filter:      bson.D{{"v", "foo"}},
+			hint:        "v_1",
+			expectedIDs: []any{int32(1), int32(3)},
+			index:       "v_1",
+		},
+		"KeyPattern": {
+			filter:      bson.D{{"v", int32(42)}},
+			hint:        bson.D{{"v", 1}},
+			expectedIDs: []any{int32(4)},
+			index:       "v_1",
+		},
+		"ID": {
+			filter:      bson.D{{"v", "bar"}},
+			hint:        "_id_",
+			expectedIDs: []any{int32(2)},
+			index:       "_id_",
+		},
+		"Sparse": {
+			hint:        bson.D{{"w", -1}},
+			expectedIDs: []any{int32(1), int32(3)},
+			index:       "w_-1",
+		},
+		"Natural": {
+			filter:      bson.D{{"w", int32(1)}},
+			hint:        bson.D{{"$natural", 1}},
+			expectedIDs: []any{int32(1)},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filter := tc.filter
+			if filter == nil {
+				filter = bson.D{}
+			}
+
+			cursor, err := collection.Find(ctx, filter, options.Find().SetHint(tc.hint))
+			require.NoError(t, err)
+
+			var docs []bson.D
+			require.NoError(t, cursor.All(ctx, &docs))
+
+			ids := make([]any, len(docs))
+			for i, doc := range docs {
+				ids[i] = doc.Map()["_id"]
+			}
+
+			assert.ElementsMatch(t, tc.expectedIDs, ids)
+
+			count, err := collection.CountDocuments(ctx, filter, options.Count().SetHint(tc.hint))
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.expectedIDs)), count)
+
+			var res bson.D
+			err = collection.Database().RunCommand(ctx, bson.D{{"explain", bson.D{
+				{"find", collection.Name()},
+				{"filter", filter},
+				{"hint", tc.hint},
+			}}}).Decode(&res)
+			require.NoError(t, err)
+
+			winningPlan, ok := res.Map()["queryPlanner"].(bson.D).Map()["winningPlan"].(bson.D)
+			require.True(t, ok)
+
+			if tc.index == "" {
+				assert.Equal(t, "COLLSCAN", winningPlan.Map()["stage"])
+				return
+			}
+
+			inputStage, ok := winningPlan.Map()["inputStage"].(bson.D)
+			require.True(t, ok)
+			assert.Equal(t, "IXSCAN", inputStage.Map()["stage"])
+			assert.Equal(t, tc.index, inputStage.Map()["indexName"])
+		})
+	}
+
+	t.Run("UpdateDelete", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", int32(1)}, {"v", "foo"}},
+			bson.D{{"_id", int32(2)}, {"v", "bar"}},
+		})
+		require.NoError(t, err)
+
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+		require.NoError(t, err)
+
+		updateRes, err := collection.UpdateOne(
+			ctx, bson.D{{"v", "foo"}}, bson.D{{"$set", bson.D{{"w", int32(1)}}}}, options.Update().SetHint("v_1"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), updateRes.ModifiedCount)
+
+		deleteRes, err := collection.DeleteOne(ctx, bson.D{{"v", "bar"}}, options.Delete().SetHint(bson.D{{"v", 1}}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleteRes.DeletedCount)
+	})
+}
+
+func TestIndexesHintPartial(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", int32(10)}},
+		bson.D{{"_id", int32(2)}, {"v", bson.A{int32(3), int32(10)}}},
+		bson.D{{"_id", int32(3)}, {"v", int32(3)}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.D{{"v", bson.D{{"$gt", int32(5)}}}}),
+	})
+	require.NoError(t, err)
+
+	// the document with array field matches both the query and the partial filter
+	filter := bson.D{{"v", bson.D{{"$gt", int32(7)}}}}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetHint("v_1").SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	expected := []bson.D{
+		{{"_id", int32(1)}, {"v", int32(10)}},
+		{{"_id", int32(2)}, {"v", bson.A{int32(3), int32(10)}}},
+	}
+	AssertEqualDocumentsSlice(t, expected, res)
+
+	count, err := collection.CountDocuments(ctx, filter, options.Count().SetHint("v_1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestIndexesHintErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetHidden(true),
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		hint     any
+		expected mongo.CommandError
+	}{
+		"NonExistentName": {
+			hint:     "foo",
+			expected: mongo.CommandError{Code: 2, Name: "BadValue"},
+		},
+		"NonExistentKeyPattern": {
+			hint:     bson.D{{"foo", 1}},
+			expected: mongo.CommandError{Code: 2, Name: "BadValue"},
+		},
+		"Hidden": {
+			hint:     "v_1",
+			expected: mongo.CommandError{Code: 2, Name: "BadValue"},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Find(ctx, bson.D{}, options.Find().SetHint(tc.hint))
+			AssertMatchesCommandError(t, tc.expected, err)
+
+			_, err = collection.DeleteOne(ctx, bson.D{}, options.Delete().SetHint(tc.hint))
+			AssertMatchesWriteError(t, mongo.WriteException{WriteErrors: []mongo.WriteError{{
+				Code: int(tc.expected.Code),
+			}}}, err)
+		})
+	}
+}
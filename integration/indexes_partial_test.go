@@ -0,0 +1,217 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// assertDuplicateKeyError checks that the error is a write exception with a single duplicate key error.
+func assertDuplicateKeyError(t *testing.T, err error) {
+	t.Helper()
+
+	var we mongo.WriteException
+	require.ErrorAs(t, err, &we)
+	require.Len(t, we.WriteErrors, 1)
+	assert.Equal(t, 11000, we.WriteErrors[0].Code)
+}
+
+func TestIndexesPartialUnique(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	filter := bson.D{{"email", bson.D{{"$exists", true}}}}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"email", 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(filter),
+	})
+	require.NoError(t, err)
+
+	spec := listIndexSpec(t, ctx, collection, "email_1")
+	assert.Equal(t, true, specValue(spec, "unique"))
+	assert.Equal(t, filter, specValue(spec, "partialFilterExpression"))
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "a"}, {"email", "a@example.com"}},
+		bson.D{{"_id", "b"}},
+		bson.D{{"_id", "c"}},
+		bson.D{{"_id", "d"}, {"email", "d@example.com"}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "e"}, {"email", "a@example.com"}})
+	assertDuplicateKeyError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "e"}})
+	require.NoError(t, err)
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, count)
+}
+
+func TestIndexesPartialUniqueConditions(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	filter := bson.D{{"$and", bson.A{
+		bson.D{{"age", bson.D{{"$gte", int32(18)}}}},
+		bson.D{{"status", "active"}},
+	}}}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"name", 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(filter),
+	})
+	require.NoError(t, err)
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"name", "alice"}, {"age", int32(30)}, {"status", "active"}},
+		bson.D{{"_id", int32(2)}, {"name", "alice"}, {"age", int32(17)}, {"status", "active"}},
+		bson.D{{"_id", int32(3)}, {"name", "alice"}, {"age", 40.5}, {"status", "inactive"}},
+		bson.D{{"_id", int32(4)}, {"name", "alice"}, {"age", "40"}, {"status", "active"}},
+	})
+	require.NoError(t, err)
+
+	for name, age := range map[string]any{"Int32": int32(18), "Int64": int64(50), "Double": 18.5} {
+		age := age
+		t.Run(name, func(t *testing.T) {
+			_, err := collection.InsertOne(ctx, bson.D{{"name", "alice"}, {"age", age}, {"status", "active"}})
+			assertDuplicateKeyError(t, err)
+		})
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{{"name", "alice"}}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	assert.Len(t, res, 4)
+}
+
+func TestIndexesSparse(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"email", 1}},
+		Options: options.Index().SetUnique(true).SetSparse(true),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, specValue(listIndexSpec(t, ctx, collection, "email_1"), "sparse"))
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "a"}, {"email", "a@example.com"}},
+		bson.D{{"_id", "b"}},
+		bson.D{{"_id", "c"}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "d"}, {"email", "a@example.com"}})
+	assertDuplicateKeyError(t, err)
+}
+
+func TestIndexesPartialErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		index    bson.D
+		expected mongo.CommandError
+	}{
+		"SparseAndPartial": {
+			index: bson.D{
+				{"key", bson.D{{"v", 1}}},
+				{"name", "v_1"},
+				{"sparse", true},
+				{"partialFilterExpression", bson.D{{"v", bson.D{{"$exists", true}}}}},
+			},
+			expected: mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: `cannot mix "partialFilterExpression" and "sparse" options`,
+			},
+		},
+		"NotObject": {
+			index: bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}, {"partialFilterExpression", "v"}},
+			expected: mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "The field 'partialFilterExpression' must be an object, but got string",
+			},
+		},
+		"Ne": {
+			index: bson.D{
+				{"key", bson.D{{"v", 1}}},
+				{"name", "v_1"},
+				{"partialFilterExpression", bson.D{{"v", bson.D{{"$ne", int32(1)}}}}},
+			},
+			expected: mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "Expression not supported in partial index: $ne",
+			},
+		},
+		"ExistsFalse": {
+			index: bson.D{
+				{"key", bson.D{{"v", 1}}},
+				{"name", "v_1"},
+				{"partialFilterExpression", bson.D{{"v", bson.D{{"$exists", false}}}}},
+			},
+			expected: mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "Expression not supported in partial index: $exists",
+			},
+		},
+		"NestedAnd": {
+			index: bson.D{
+				{"key", bson.D{{"v", 1}}},
+				{"name", "v_1"},
+				{"partialFilterExpression", bson.D{{"$and", bson.A{bson.D{{"$and", bson.A{}}}}}}},
+			},
+			expected: mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "Expression not supported in partial index: $and",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := collection.Database().RunCommand(ctx, bson.D{
+				{"createIndexes", collection.Name()},
+				{"indexes", bson.A{tc.index}},
+			}).Err()
+			AssertEqualCommandError(t, tc.expected, err)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestIndexesTTL(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB's TTL monitor runs every 60 seconds")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"expiresAt", 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{"createdAt", -1}},
+			Options: options.Index().SetExpireAfterSeconds(3600),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 0, specValue(listIndexSpec(t, ctx, collection, "expiresAt_1"), "expireAfterSeconds"))
+	assert.EqualValues(t, 3600, specValue(listIndexSpec(t, ctx, collection, "createdAt_-1"), "expireAfterSeconds"))
+
+	now := time.Now()
+	past := primitive.NewDateTimeFromTime(now.Add(-time.Minute))
+	future := primitive.NewDateTimeFromTime(now.Add(time.Hour))
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "expired"}, {"expiresAt", past}},
+		bson.D{{"_id", "expired-array"}, {"expiresAt", bson.A{future, past}}},
+		bson.D{{"_id", "expired-created"}, {"createdAt", primitive.NewDateTimeFromTime(now.Add(-2 * time.Hour))}},
+		bson.D{{"_id", "future"}, {"expiresAt", future}},
+		bson.D{{"_id", "created"}, {"createdAt", past}},
+		bson.D{{"_id", "string"}, {"expiresAt", "2000-01-01"}},
+		bson.D{{"_id", "array-without-dates"}, {"expiresAt", bson.A{int32(1), "a"}}},
+		bson.D{{"_id", "missing"}},
+	})
+	require.NoError(t, err)
+
+	expected := []any{"array-without-dates", "created", "future", "missing", "string"}
+
+	var actual []any
+
+	require.Eventually(t, func() bool {
+		actual, err = collection.Distinct(ctx, "_id", bson.D{})
+		require.NoError(t, err)
+
+		return len(actual) == len(expected)
+	}, 10*setup.TTLMonitorInterval, setup.TTLMonitorInterval/10)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestIndexesTTLPartial(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB's TTL monitor runs every 60 seconds")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{"expiresAt", 1}},
+		Options: options.Index().
+			SetExpireAfterSeconds(0).
+			SetPartialFilterExpression(bson.D{{"v", bson.D{{"$eq", "expire"}}}}),
+	})
+	require.NoError(t, err)
+
+	past := primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute))
+
+	// more than the TTL monitor deletes at once when it can't delete them by filter
+	const n = 2500
+
+	docs := make([]any, n, n+1)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"expiresAt", past}, {"v", "expire"}}
+	}
+
+	docs = append(docs, bson.D{{"_id", "not-in-index"}, {"expiresAt", past}, {"v", "keep"}})
+
+	_, err = collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	var actual []any
+
+	require.Eventually(t, func() bool {
+		actual, err = collection.Distinct(ctx, "_id", bson.D{})
+		require.NoError(t, err)
+
+		return len(actual) == 1
+	}, 10*setup.TTLMonitorInterval, setup.TTLMonitorInterval/10)
+
+	assert.Equal(t, []any{"not-in-index"}, actual)
+}
+
+func TestIndexesTTLErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		index    bson.D
+		expected mongo.CommandError
+	}{
+		"Negative": {
+			index: bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}, {"expireAfterSeconds", int32(-1)}},
+			expected: mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "TTL index 'expireAfterSeconds' option must be within an acceptable range, try a lower number",
+			},
+		},
+		"String": {
+			index: bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}, {"expireAfterSeconds", "1"}},
+			expected: mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: `TTL index 'expireAfterSeconds' option must be numeric, but received a type of 'string'. ` +
+					`Index spec: { key: { v: 1 }, name: "v_1", expireAfterSeconds: "1" }`,
+			},
+		},
+		"Compound": {
+			index: bson.D{{"key", bson.D{{"a", 1}, {"b", 1}}}, {"name", "a_1_b_1"}, {"expireAfterSeconds", int32(1)}},
+			expected: mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL indexes are single-field indexes, compound indexes do not support TTL. " +
+					`Index spec: { key: { a: 1, b: 1 }, name: "a_1_b_1", expireAfterSeconds: 1 }`,
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := collection.Database().RunCommand(ctx, bson.D{
+				{"createIndexes", collection.Name()},
+				{"indexes", bson.A{tc.index}},
+			}).Err()
+			AssertEqualCommandError(t, tc.expected, err)
+		})
+	}
+}
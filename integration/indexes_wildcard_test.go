@@ -0,0 +1,214 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestIndexesWildcard(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	oid := primitive.NewObjectID()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"color", "red"}, {"size", int32(42)}, {"tag", oid}},
+		bson.D{{"_id", int32(2)}, {"color", bson.A{"blue", "red"}}, {"size", 42.0}},
+		bson.D{{"_id", int32(3)}, {"color", "blue"}, {"size", int64(43)}, {"flag", true}},
+		bson.D{{"_id", int32(4)}, {"color", bson.D{{"v", "red"}}}, {"size", "42"}, {"flag", int32(1)}},
+		bson.D{{"_id", int32(5)}, {"attrs", bson.D{{"color", "red"}}}},
+		bson.D{{"_id", int32(6)}, {"color", "[red]"}, {"size", bson.A{int32(41), int64(42)}}},
+	})
+	require.NoError(t, err)
+
+	projection := bson.D{{"color", int32(1)}, {"size", true}, {"tag", int32(1)}, {"flag", int32(1)}}
+
+	for name, tc := range map[string]struct {
+		index    mongo.IndexModel
+		expected bson.D // spec fields after key and name
+	}{
+		"All": {
+			index: mongo.IndexModel{Keys: bson.D{{"$**", 1}}},
+		},
+		"Inclusion": {
+			index: mongo.IndexModel{
+				Keys:    bson.D{{"$**", 1}},
+				Options: options.Index().SetName("inclusion").SetWildcardProjection(projection),
+			},
+			expected: bson.D{{"wildcardProjection", projection}},
+		},
+		"Exclusion": {
+			index: mongo.IndexModel{
+				Keys:    bson.D{{"$**", 1}},
+				Options: options.Index().SetName("exclusion").SetWildcardProjection(bson.D{{"attrs", false}}),
+			},
+			expected: bson.D{{"wildcardProjection", bson.D{{"attrs", false}}}},
+		},
+		"Path": {
+			index: mongo.IndexModel{Keys: bson.D{{"color.$**", 1}}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			// indexes are created in the same collection
+			indexName, err := collection.Indexes().CreateOne(ctx, tc.index)
+			require.NoError(t, err)
+
+			spec := listIndexSpec(t, ctx, collection, indexName)
+			for _, e := range tc.expected {
+				assert.Equal(t, e.Value, specValue(spec, e.Key), e.Key)
+			}
+
+			for filter, expected := range map[string]struct {
+				filter bson.D
+				ids    []any
+			}{
+				"String":      {bson.D{{"color", "red"}}, []any{int32(1), int32(2)}},
+				"Eq":          {bson.D{{"color", bson.D{{"$eq", "blue"}}}}, []any{int32(2), int32(3)}},
+				"Brackets":    {bson.D{{"color", "[red]"}}, []any{int32(6)}},
+				"Int32":       {bson.D{{"size", int32(42)}}, []any{int32(1), int32(2), int32(6)}},
+				"Double":      {bson.D{{"size", 43.0}}, []any{int32(3)}},
+				"Bool":        {bson.D{{"flag", true}}, []any{int32(3)}},
+				"ObjectID":    {bson.D{{"tag", oid}}, []any{int32(1)}},
+				"Nested":      {bson.D{{"attrs.color", "red"}}, []any{int32(5)}},
+				"WithID":      {bson.D{{"_id", int32(2)}, {"color", "red"}}, []any{int32(2)}},
+				"NotIndexed":  {bson.D{{"missing", "red"}}, []any{}},
+				"StringToInt": {bson.D{{"size", "42"}}, []any{int32(4)}},
+			} {
+				ids, err := collection.Distinct(ctx, "_id", expected.filter)
+				require.NoError(t, err)
+				assert.ElementsMatch(t, expected.ids, ids, filter)
+			}
+
+			_, err = collection.Indexes().DropOne(ctx, indexName)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIndexesWildcardExplain(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"$**", 1}},
+		Options: options.Index().SetWildcardProjection(bson.D{{"custom", int32(1)}}),
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"explain", bson.D{{"find", collection.Name()}, {"filter", bson.D{{"custom", "v"}}}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	plan := fmt.Sprint(res.Map()["queryPlanner"])
+
+	switch {
+	case setup.IsMongoDB(t):
+		assert.Contains(t, plan, "$**_1")
+	case setup.IsSQLite(t) && !setup.FilterPushdownDisabled():
+		// the index is used only for the pushed down filter
+		assert.Contains(t, plan, "USING INDEX")
+	}
+}
+
+func TestIndexesWildcardErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		index    bson.D
+		expected mongo.CommandError
+	}{
+		"Compound": {
+			index: bson.D{{"key", bson.D{{"$**", 1}, {"v", 1}}}, {"name", "compound"}},
+			expected: mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "wildcard indexes do not allow compounding",
+			},
+		},
+		"Unique": {
+			index: bson.D{{"key", bson.D{{"$**", 1}}}, {"name", "unique"}, {"unique", true}},
+			expected: mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "Index type 'wildcard' does not support the unique option",
+			},
+		},
+		"ProjectionNotWildcard": {
+			index: bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}, {"wildcardProjection", bson.D{{"v", 1}}}},
+			expected: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "The field 'wildcardProjection' is only allowed in an '$**' index",
+			},
+		},
+		"ProjectionPath": {
+			index: bson.D{{"key", bson.D{{"v.$**", 1}}}, {"name", "v"}, {"wildcardProjection", bson.D{{"v", 1}}}},
+			expected: mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: `The field 'wildcardProjection' is only allowed when 'key' is {"$**": ±1}`,
+			},
+		},
+		"ProjectionMixed": {
+			index: bson.D{
+				{"key", bson.D{{"$**", 1}}},
+				{"name", "mixed"},
+				{"wildcardProjection", bson.D{{"a", 1}, {"b", 0}}},
+			},
+			expected: mongo.CommandError{
+				Code:    31254,
+				Name:    "Location31254",
+				Message: "Cannot do exclusion on field b in inclusion projection",
+			},
+		},
+		"ProjectionString": {
+			index: bson.D{{"key", bson.D{{"$**", 1}}}, {"name", "string"}, {"wildcardProjection", "a"}},
+			expected: mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "The field 'wildcardProjection' must be a non-empty object, but got string",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := collection.Database().RunCommand(ctx, bson.D{
+				{"createIndexes", collection.Name()},
+				{"indexes", bson.A{tc.index}},
+			}).Err()
+			AssertEqualCommandError(t, tc.expected, err)
+		})
+	}
+}
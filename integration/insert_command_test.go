@@ -15,6 +15,7 @@
 package integration
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -195,3 +197,99 @@ func TestInsertIDDifferentTypes(t *testing.T) {
 		err,
 	)
 }
+
+// TestInsertBulk checks batches large enough to be inserted with COPY by PostgreSQL backend.
+func TestInsertBulk(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 250)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", fmt.Sprintf("foo%d", i)}}
+	}
+
+	res, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+	require.Len(t, res.InsertedIDs, len(docs))
+
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var actual []bson.D
+	require.NoError(t, cursor.All(ctx, &actual))
+
+	require.Len(t, actual, len(docs))
+
+	for i, doc := range actual {
+		AssertEqualDocuments(t, docs[i].(bson.D), doc)
+	}
+}
+
+// TestInsertBulkDuplicateID checks that documents are inserted one by one
+// when the batch insert (COPY for PostgreSQL backend) fails on a duplicate _id.
+func TestInsertBulkDuplicateID(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		ordered bool
+
+		indexes []int // expected indexes of write errors
+		count   int64 // expected number of documents in the collection
+	}{
+		"Ordered": {
+			ordered: true,
+			indexes: []int{50},
+			count:   51,
+		},
+		"Unordered": {
+			ordered: false,
+			indexes: []int{50, 150},
+			count:   199,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(50)}, {"v", "existing"}})
+			require.NoError(t, err)
+
+			// the document at index 50 duplicates the existing document,
+			// and the one at index 150 duplicates the document at index 10
+			docs := make([]any, 200)
+			for i := range docs {
+				id := int32(i)
+				if i == 150 {
+					id = 10
+				}
+
+				docs[i] = bson.D{{"_id", id}, {"v", fmt.Sprintf("foo%d", i)}}
+			}
+
+			_, err = collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(tc.ordered))
+
+			var we mongo.BulkWriteException
+			require.ErrorAs(t, err, &we)
+
+			indexes := make([]int, len(we.WriteErrors))
+			for i, e := range we.WriteErrors {
+				assert.Equal(t, 11000, e.Code)
+				indexes[i] = e.Index
+			}
+
+			assert.Equal(t, tc.indexes, indexes)
+
+			count, err := collection.CountDocuments(ctx, bson.D{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.count, count)
+
+			var doc bson.D
+			err = collection.FindOne(ctx, bson.D{{"_id", int32(50)}}).Decode(&doc)
+			require.NoError(t, err)
+			AssertEqualDocuments(t, bson.D{{"_id", int32(50)}, {"v", "existing"}}, doc)
+		})
+	}
+}
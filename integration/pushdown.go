@@ -59,6 +59,26 @@ func (res resultPushdown) SortPushdownExpected(t testtb.TB) bool {
 	return res.pushdownExpected(t)
 }
 
+// GroupPushdownExpected returns true if the $group pushdown is expected for currently running backend.
+// It checks if filter pushdown is disabled by flag, as $group pushdown is disabled too in that case.
+func (res resultPushdown) GroupPushdownExpected(t testtb.TB) bool {
+	if setup.FilterPushdownDisabled() {
+		res = noPushdown
+	}
+
+	return res.pushdownExpected(t)
+}
+
+// UpdatePushdownExpected returns true if the update pushdown is expected for currently running backend.
+// It checks if filter pushdown is disabled by flag, as update pushdown is disabled too in that case.
+func (res resultPushdown) UpdatePushdownExpected(t testtb.TB) bool {
+	if setup.FilterPushdownDisabled() {
+		res = noPushdown
+	}
+
+	return res.pushdownExpected(t)
+}
+
 // pushdownExpected returns true if pushdown is expected for currently running backend.
 func (res resultPushdown) pushdownExpected(t testtb.TB) bool {
 	switch {
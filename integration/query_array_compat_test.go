@@ -89,7 +89,8 @@ func TestQueryArrayCompatDotNotation(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"Field": {
-			filter: bson.D{{"v.array", int32(42)}},
+			filter:         bson.D{{"v.array", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 		"FieldPosition": {
 			filter: bson.D{{"v.array.0", int32(42)}},
@@ -108,24 +109,31 @@ func TestQueryArrayCompatDotNotation(t *testing.T) {
 			filter: bson.D{
 				{"v.foo.bar", bson.D{{"$nin", bson.A{"baz"}}}},
 			},
+			resultPushdown: allPushdown,
 		},
 		"DocumentDotNotationArrayDocumentNoIndex": {
-			filter: bson.D{{"v.foo.bar", "hello"}},
+			filter:         bson.D{{"v.foo.bar", "hello"}},
+			resultPushdown: allPushdown,
 		},
 		"FieldArrayIndex": {
-			filter: bson.D{{"v.foo[0]", int32(42)}},
+			filter:         bson.D{{"v.foo[0]", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 		"FieldArrayAsterix": {
-			filter: bson.D{{"v.foo[*]", int32(42)}},
+			filter:         bson.D{{"v.foo[*]", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 		"FieldAsterix": {
-			filter: bson.D{{"v.*", int32(42)}},
+			filter:         bson.D{{"v.*", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 		"FieldAt": {
-			filter: bson.D{{"v.@", int32(42)}},
+			filter:         bson.D{{"v.@", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 		"FieldComma": {
-			filter: bson.D{{"v.f,oo", int32(42)}},
+			filter:         bson.D{{"v.f,oo", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -142,7 +150,7 @@ func TestQueryArrayCompatElemMatch(t *testing.T) {
 				{"v", bson.D{{"$elemMatch", bson.D{{"$gt", int32(0)}}}}},
 			},
 			resultType:     emptyResult,
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"GtZero": {
 			filter: bson.D{{"v", bson.D{{"$elemMatch", bson.D{{"$gt", int32(0)}}}}}},
@@ -50,11 +50,13 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", int32(42)}},
+			filter:         bson.D{{"v.foo", int32(42)}},
+			resultPushdown: allPushdown,
 		},
 		"DocumentDotNotationNoSuchField": {
-			filter:     bson.D{{"no-such-field.some", 42}},
-			resultType: emptyResult,
+			filter:         bson.D{{"no-such-field.some", 42}},
+			resultPushdown: allPushdown,
+			resultType:     emptyResult,
 		},
 		"ArrayNoSuchField": {
 			filter:     bson.D{{"no-such-field", bson.A{42}}},
@@ -70,147 +72,147 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 		},
 		"Int32": {
 			filter:         bson.D{{"v", int32(42)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64": {
 			filter:         bson.D{{"v", int64(42)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Double": {
 			filter:         bson.D{{"v", 42.13}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleMax": {
 			filter:         bson.D{{"v", math.MaxFloat64}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleSmallest": {
 			filter:         bson.D{{"v", math.SmallestNonzeroFloat64}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBig": {
 			filter:         bson.D{{"v", float64(1 << 61)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBigPlus": {
 			filter:         bson.D{{"v", float64((1 << 61) + 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBigMinus": {
 			filter:         bson.D{{"v", float64((1 << 61) - 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBig": {
 			filter:         bson.D{{"v", -float64(1 << 61)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBigPlus": {
 			filter:         bson.D{{"v", -float64(1<<61) + 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBigMinus": {
 			filter:         bson.D{{"v", -float64(1<<61) - 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Max": {
 			filter:         bson.D{{"v", int64(math.MaxInt64)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Min": {
 			filter:         bson.D{{"v", int64(math.MinInt64)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"Float64PrecMax": {
 			filter:         bson.D{{"v", float64(1 << 53)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Float64PrecMaxPlusOne": {
 			filter:         bson.D{{"v", float64(1<<53 + 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Float64PrecMaxMinusOne": {
 			filter:         bson.D{{"v", float64(1<<53 - 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Float64PrecMin": {
 			filter:         bson.D{{"v", -float64(1<<53 - 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Float64PrecMinPlus": {
 			filter:         bson.D{{"v", -float64(1<<53-1) + 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Float64PrecMinMinus": {
 			filter:         bson.D{{"v", -float64(1<<53-1) - 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"Int64PrecMax": {
 			filter:         bson.D{{"v", int64(1 << 53)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMaxPlusOne": {
 			filter:         bson.D{{"v", int64(1<<53 + 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMaxMinusOne": {
 			filter:         bson.D{{"v", int64(1<<53 - 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMin": {
 			filter:         bson.D{{"v", -int64(1<<53 - 1)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMinPlus": {
 			filter:         bson.D{{"v", -int64(1<<53-1) + 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMinMinus": {
 			filter:         bson.D{{"v", -int64(1<<53-1) - 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"Int64Big": {
 			filter:         bson.D{{"v", int64(1 << 61)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64BigPlus": {
 			filter:         bson.D{{"v", int64(1<<61) + 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64BigMinus": {
 			filter:         bson.D{{"v", int64(1<<61) - 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBig": {
 			filter:         bson.D{{"v", -int64(1 << 61)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigPlus": {
 			filter:         bson.D{{"v", -int64(1<<61) + 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigMinus": {
 			filter:         bson.D{{"v", -int64(1<<61) - 1}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"String": {
 			filter:         bson.D{{"v", "foo"}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringInt": {
 			filter:         bson.D{{"v", "42"}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringDouble": {
 			filter:         bson.D{{"v", "42.13"}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
 			filter:         bson.D{{"v", ""}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", primitive.Binary{Subtype: 0x80, Data: []byte{42, 0, 13}}}},
@@ -220,27 +222,27 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 		},
 		"BoolFalse": {
 			filter:         bson.D{{"v", false}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"BoolTrue": {
 			filter:         bson.D{{"v", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Datetime": {
 			filter:         bson.D{{"v", primitive.NewDateTimeFromTime(time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC))}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeEpoch": {
 			filter:         bson.D{{"v", primitive.NewDateTimeFromTime(time.Unix(0, 0))}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeYearMin": {
 			filter:         bson.D{{"v", primitive.NewDateTimeFromTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeYearMax": {
 			filter:         bson.D{{"v", primitive.NewDateTimeFromTime(time.Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC))}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"IDNull": {
 			filter:     bson.D{{"_id", nil}},
@@ -249,17 +251,17 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 		"IDInt32": {
 			filter:         bson.D{{"_id", int32(1)}},
 			resultType:     emptyResult,
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"IDInt64": {
 			filter:         bson.D{{"_id", int64(1)}},
 			resultType:     emptyResult,
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"IDDouble": {
 			filter:         bson.D{{"_id", 4.2}},
 			resultType:     emptyResult,
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"IDString": {
 			filter:         bson.D{{"_id", "string"}},
@@ -277,7 +279,7 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 		},
 		"ValueNumber": {
 			filter:         bson.D{{"v", 42}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"ValueRegex": {
 			filter: bson.D{{"v", primitive.Regex{Pattern: "^fo"}}},
@@ -286,7 +288,7 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 		"EmptyKey": {
 			filter:         bson.D{{"", "foo"}},
 			resultType:     emptyResult,
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -314,7 +316,8 @@ func TestQueryComparisonCompatEq(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", bson.D{{"$eq", int32(42)}}}},
+			filter:         bson.D{{"v.foo", bson.D{{"$eq", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"DocumentReverse": {
 			filter: bson.D{{"v", bson.D{
@@ -347,90 +350,90 @@ func TestQueryComparisonCompatEq(t *testing.T) {
 		},
 		"Double": {
 			filter:         bson.D{{"v", bson.D{{"$eq", 42.13}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleWhole": {
 			filter:         bson.D{{"v", bson.D{{"$eq", 42.0}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleZero": {
 			filter:         bson.D{{"v", bson.D{{"$eq", 0.0}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleMax": {
 			filter:         bson.D{{"v", bson.D{{"$eq", math.MaxFloat64}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleSmallest": {
 			filter:         bson.D{{"v", bson.D{{"$eq", math.SmallestNonzeroFloat64}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"DoubleBig": {
 			filter:         bson.D{{"v", bson.D{{"$eq", float64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBigPlus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", float64((1 << 61) + 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBigMinus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", float64((1 << 61) - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBig": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -float64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBigPlus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -float64((1 << 61) + 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBigMinus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -float64((1 << 61) - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"DoublePrecMax": {
 			filter:         bson.D{{"v", bson.D{{"$eq", float64(1 << 53)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMaxPlus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", float64(1<<53) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMaxMinus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", float64(1<<53) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMin": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -float64(1<<53 - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMinPlus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -float64(1<<53-1) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMinMinus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -float64(1<<53-1) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"String": {
 			filter:         bson.D{{"v", bson.D{{"$eq", "foo"}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringDouble": {
 			filter:         bson.D{{"v", bson.D{{"$eq", "42.13"}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringWhole": {
 			filter:         bson.D{{"v", bson.D{{"$eq", "42"}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
 			filter:         bson.D{{"v", bson.D{{"$eq", ""}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", bson.D{{"$eq", primitive.Binary{Subtype: 0x80, Data: []byte{42, 0, 13}}}}}},
@@ -440,35 +443,35 @@ func TestQueryComparisonCompatEq(t *testing.T) {
 		},
 		"ObjectID": {
 			filter:         bson.D{{"v", bson.D{{"$eq", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091011"))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"ObjectIDEmpty": {
 			filter:         bson.D{{"v", bson.D{{"$eq", primitive.NilObjectID}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"BoolFalse": {
 			filter:         bson.D{{"v", bson.D{{"$eq", false}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"BoolTrue": {
 			filter:         bson.D{{"v", bson.D{{"$eq", true}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Datetime": {
 			filter:         bson.D{{"v", bson.D{{"$eq", primitive.NewDateTimeFromTime(time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeEpoch": {
 			filter:         bson.D{{"v", bson.D{{"$eq", primitive.NewDateTimeFromTime(time.Unix(0, 0))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeYearMin": {
 			filter:         bson.D{{"v", bson.D{{"$eq", primitive.NewDateTimeFromTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeYearMax": {
 			filter:         bson.D{{"v", bson.D{{"$eq", primitive.NewDateTimeFromTime(time.Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter: bson.D{{"v", bson.D{{"$eq", nil}}}},
@@ -485,19 +488,19 @@ func TestQueryComparisonCompatEq(t *testing.T) {
 		},
 		"Int32": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int32(42)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32Zero": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int32(0)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32Max": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int32(math.MaxInt32)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32Min": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int32(math.MinInt32)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Timestamp": {
 			filter: bson.D{{"v", bson.D{{"$eq", primitive.Timestamp{T: 42, I: 13}}}}},
@@ -507,69 +510,69 @@ func TestQueryComparisonCompatEq(t *testing.T) {
 		},
 		"Int64": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(42)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Zero": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(0)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Max": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(math.MaxInt64)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Min": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(math.MinInt64)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"Int64Big": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64BigPlusOne": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(1<<61) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64BigMinusOne": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(1<<61) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBig": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -int64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigPlusOne": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -int64(1<<61) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigMinusOne": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -int64(1<<61) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"Int64PrecMax": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(1 << 53)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMaxPlusOne": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(1<<53 + 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMaxMinusOne": {
 			filter:         bson.D{{"v", bson.D{{"$eq", int64(1<<53 - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMin": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -int64(1<<53 - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMinPlus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -int64(1<<53-1) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMinMinus": {
 			filter:         bson.D{{"v", bson.D{{"$eq", -int64(1<<53-1) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"IDNull": {
@@ -603,7 +606,8 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			}}},
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", bson.D{{"$gt", int32(41)}}}},
+			filter:         bson.D{{"v.foo", bson.D{{"$gt", int32(41)}}}},
+			resultPushdown: allPushdown,
 		},
 		"DocumentReverse": {
 			filter: bson.D{
@@ -642,20 +646,25 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gt", bson.A{"foo", nil, int32(42)}}}}},
 		},
 		"Double": {
-			filter: bson.D{{"v", bson.D{{"$gt", 41.13}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 41.13}}}},
+			resultPushdown: allPushdown,
 		},
 		"DoubleMax": {
-			filter:     bson.D{{"v", bson.D{{"$gt", math.MaxFloat64}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"v", bson.D{{"$gt", math.MaxFloat64}}}},
+			resultPushdown: allPushdown,
+			resultType:     emptyResult,
 		},
 		"String": {
-			filter: bson.D{{"v", bson.D{{"$gt", "boo"}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", "boo"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringWhole": {
-			filter: bson.D{{"v", bson.D{{"$gt", "42"}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", "42"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
-			filter: bson.D{{"v", bson.D{{"$gt", ""}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", ""}}}},
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", bson.D{{"$gt", primitive.Binary{Subtype: 0x80, Data: []byte{42}}}}}},
@@ -667,16 +676,19 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gt", primitive.Binary{}}}}},
 		},
 		"ObjectID": {
-			filter: bson.D{{"v", bson.D{{"$gt", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091010"))}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091010"))}}}},
+			resultPushdown: allPushdown,
 		},
 		"ObjectIDEmpty": {
-			filter: bson.D{{"v", bson.D{{"$gt", primitive.NilObjectID}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", primitive.NilObjectID}}}},
+			resultPushdown: allPushdown,
 		},
 		"Bool": {
 			filter: bson.D{{"v", bson.D{{"$gt", false}}}},
 		},
 		"Datetime": {
-			filter: bson.D{{"v", bson.D{{"$gt", time.Date(2021, 11, 1, 10, 18, 41, 123000000, time.UTC)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", time.Date(2021, 11, 1, 10, 18, 41, 123000000, time.UTC)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter:     bson.D{{"v", bson.D{{"$gt", nil}}}},
@@ -687,10 +699,12 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"Int32": {
-			filter: bson.D{{"v", bson.D{{"$gt", int32(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int32Max": {
-			filter: bson.D{{"v", bson.D{{"$gt", int32(math.MaxInt32)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int32(math.MaxInt32)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Timestamp": {
 			filter: bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{T: 41, I: 12}}}}},
@@ -702,28 +716,36 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{I: 12}}}}},
 		},
 		"Int64": {
-			filter: bson.D{{"v", bson.D{{"$gt", int64(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int64(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64Max": {
-			filter: bson.D{{"v", bson.D{{"$gt", int64(math.MaxInt64)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int64(math.MaxInt64)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64Big": {
-			filter: bson.D{{"v", bson.D{{"$gt", int64(1 << 61)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int64(1 << 61)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64BigPlusOne": {
-			filter: bson.D{{"v", bson.D{{"$gt", int64(1<<61) + 1}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int64(1<<61) + 1}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64BigMinusOne": {
-			filter: bson.D{{"v", bson.D{{"$gt", int64(1<<61) - 1}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", int64(1<<61) - 1}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBig": {
-			filter: bson.D{{"v", bson.D{{"$gt", -int64(1 << 61)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", -int64(1 << 61)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigPlusOne": {
-			filter: bson.D{{"v", bson.D{{"$gt", -int64(1<<61) + 1}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", -int64(1<<61) + 1}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigMinusOne": {
-			filter: bson.D{{"v", bson.D{{"$gt", -int64(1<<61) - 1}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", -int64(1<<61) - 1}}}},
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -741,7 +763,8 @@ func TestQueryComparisonCompatGte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gte", bson.D{{"42", "foo"}, {"array", bson.A{int32(42), "foo", nil}}, {"foo", int32(42)}}}}}},
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", bson.D{{"$gte", int32(42)}}}},
+			filter:         bson.D{{"v.foo", bson.D{{"$gte", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"DocumentReverse": {
 			filter: bson.D{{"v", bson.D{{"$gte", bson.D{{"array", bson.A{int32(42), "foo", nil}}, {"42", "foo"}, {"foo", int32(42)}}}}}},
@@ -774,19 +797,24 @@ func TestQueryComparisonCompatGte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gte", bson.A{"foo", nil, int32(42)}}}}},
 		},
 		"Double": {
-			filter: bson.D{{"v", bson.D{{"$gte", 41.13}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", 41.13}}}},
+			resultPushdown: allPushdown,
 		},
 		"DoubleMax": {
-			filter: bson.D{{"v", bson.D{{"$gte", math.MaxFloat64}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", math.MaxFloat64}}}},
+			resultPushdown: allPushdown,
 		},
 		"String": {
-			filter: bson.D{{"v", bson.D{{"$gte", "foo"}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", "foo"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringWhole": {
-			filter: bson.D{{"v", bson.D{{"$gte", "42"}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", "42"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
-			filter: bson.D{{"v", bson.D{{"$gte", ""}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", ""}}}},
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", bson.D{{"$gte", primitive.Binary{Subtype: 0x80, Data: []byte{42}}}}}},
@@ -798,16 +826,19 @@ func TestQueryComparisonCompatGte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gte", primitive.Binary{}}}}},
 		},
 		"ObjectID": {
-			filter: bson.D{{"v", bson.D{{"$gte", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091011"))}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091011"))}}}},
+			resultPushdown: allPushdown,
 		},
 		"ObjectIDEmpty": {
-			filter: bson.D{{"v", bson.D{{"$gte", primitive.NilObjectID}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", primitive.NilObjectID}}}},
+			resultPushdown: allPushdown,
 		},
 		"Bool": {
 			filter: bson.D{{"v", bson.D{{"$gte", false}}}},
 		},
 		"Datetime": {
-			filter: bson.D{{"v", bson.D{{"$gte", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter: bson.D{{"v", bson.D{{"$gte", nil}}}},
@@ -817,13 +848,16 @@ func TestQueryComparisonCompatGte(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"Int32": {
-			filter: bson.D{{"v", bson.D{{"$gte", int32(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int32Max": {
-			filter: bson.D{{"v", bson.D{{"$gte", int32(math.MaxInt32)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", int32(math.MaxInt32)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int32Desc": {
-			filter: bson.D{{"v", bson.D{{"$gte", int32(45)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", int32(45)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Timestamp": {
 			filter: bson.D{{"v", bson.D{{"$gte", primitive.Timestamp{T: 41, I: 12}}}}},
@@ -835,10 +869,12 @@ func TestQueryComparisonCompatGte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gte", primitive.Timestamp{I: 13}}}}},
 		},
 		"Int64": {
-			filter: bson.D{{"v", bson.D{{"$gte", int64(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", int64(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64Max": {
-			filter: bson.D{{"v", bson.D{{"$gte", int64(math.MaxInt64)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gte", int64(math.MaxInt64)}}}},
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -856,7 +892,8 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lt", bson.D{{"42", "foo"}, {"array", bson.A{int32(42), "foo", nil}}, {"foo", int32(42)}}}}}},
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", bson.D{{"$lt", int32(43)}}}},
+			filter:         bson.D{{"v.foo", bson.D{{"$lt", int32(43)}}}},
+			resultPushdown: allPushdown,
 		},
 		"DocumentReverse": {
 			filter: bson.D{{"v", bson.D{{"$lt", bson.D{{"array", bson.A{int32(42), "foo", nil}}, {"42", "foo"}, {"foo", int32(42)}}}}}},
@@ -891,23 +928,29 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lt", bson.A{"foo", nil, int32(42)}}}}},
 		},
 		"Double": {
-			filter: bson.D{{"v", bson.D{{"$lt", 43.13}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", 43.13}}}},
+			resultPushdown: allPushdown,
 		},
 		"DoubleSmallest": {
-			filter: bson.D{{"v", bson.D{{"$lt", math.SmallestNonzeroFloat64}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", math.SmallestNonzeroFloat64}}}},
+			resultPushdown: allPushdown,
 		},
 		"String": {
-			filter: bson.D{{"v", bson.D{{"$lt", "goo"}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", "goo"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringWhole": {
-			filter: bson.D{{"v", bson.D{{"$lt", "42"}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", "42"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
-			filter:     bson.D{{"v", bson.D{{"$lt", ""}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"v", bson.D{{"$lt", ""}}}},
+			resultPushdown: allPushdown,
+			resultType:     emptyResult,
 		},
 		"StringAsc": {
-			filter: bson.D{{"v", bson.D{{"$lt", "b"}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", "b"}}}},
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", bson.D{{"$lt", primitive.Binary{Subtype: 0x80, Data: []byte{43}}}}}},
@@ -920,17 +963,20 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"ObjectID": {
-			filter: bson.D{{"v", bson.D{{"$lt", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091012"))}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091012"))}}}},
+			resultPushdown: allPushdown,
 		},
 		"ObjectIDEmpty": {
-			filter:     bson.D{{"v", bson.D{{"$lt", primitive.NilObjectID}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"v", bson.D{{"$lt", primitive.NilObjectID}}}},
+			resultPushdown: allPushdown,
+			resultType:     emptyResult,
 		},
 		"Bool": {
 			filter: bson.D{{"v", bson.D{{"$lt", true}}}},
 		},
 		"Datetime": {
-			filter: bson.D{{"v", bson.D{{"$lt", time.Date(2021, 11, 1, 10, 18, 43, 123000000, time.UTC)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", time.Date(2021, 11, 1, 10, 18, 43, 123000000, time.UTC)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter:     bson.D{{"v", bson.D{{"$lt", nil}}}},
@@ -941,10 +987,12 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"Int32": {
-			filter: bson.D{{"v", bson.D{{"$lt", int32(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int32Min": {
-			filter: bson.D{{"v", bson.D{{"$lt", int32(math.MinInt32)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", int32(math.MinInt32)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Timestamp": {
 			filter: bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{T: 43, I: 14}}}}},
@@ -956,13 +1004,16 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{I: 14}}}}},
 		},
 		"Int64": {
-			filter: bson.D{{"v", bson.D{{"$lt", int64(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", int64(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64Min": {
-			filter: bson.D{{"v", bson.D{{"$lt", int64(math.MinInt64)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", int64(math.MinInt64)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64Big": {
-			filter: bson.D{{"v", bson.D{{"$lt", int64(1<<61 + 1)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", int64(1<<61 + 1)}}}},
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -980,7 +1031,8 @@ func TestQueryComparisonCompatLte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lte", bson.D{{"42", "foo"}, {"array", bson.A{int32(42), "foo", nil}}, {"foo", int32(42)}}}}}},
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", bson.D{{"$lte", int32(42)}}}},
+			filter:         bson.D{{"v.foo", bson.D{{"$lte", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"DocumentReverse": {
 			filter: bson.D{{"v", bson.D{{"$lte", bson.D{{"array", bson.A{int32(42), "foo", nil}}, {"42", "foo"}, {"foo", int32(42)}}}}}},
@@ -1013,22 +1065,28 @@ func TestQueryComparisonCompatLte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lte", bson.A{"foo", nil, int32(42)}}}}},
 		},
 		"Double": {
-			filter: bson.D{{"v", bson.D{{"$lte", 42.13}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", 42.13}}}},
+			resultPushdown: allPushdown,
 		},
 		"DoubleSmallest": {
-			filter: bson.D{{"v", bson.D{{"$lte", math.SmallestNonzeroFloat64}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", math.SmallestNonzeroFloat64}}}},
+			resultPushdown: allPushdown,
 		},
 		"String": {
-			filter: bson.D{{"v", bson.D{{"$lte", "foo"}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", "foo"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringWhole": {
-			filter: bson.D{{"v", bson.D{{"$lte", "42"}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", "42"}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
-			filter: bson.D{{"v", bson.D{{"$lte", ""}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", ""}}}},
+			resultPushdown: allPushdown,
 		},
 		"StringAsc": {
-			filter: bson.D{{"v", bson.D{{"$lte", "a"}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", "a"}}}},
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", bson.D{{"$lte", primitive.Binary{Subtype: 0x80, Data: []byte{42}}}}}},
@@ -1040,16 +1098,19 @@ func TestQueryComparisonCompatLte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lte", primitive.Binary{}}}}},
 		},
 		"ObjectID": {
-			filter: bson.D{{"v", bson.D{{"$lte", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091011"))}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", must.NotFail(primitive.ObjectIDFromHex("000102030405060708091011"))}}}},
+			resultPushdown: allPushdown,
 		},
 		"ObjectIDEmpty": {
-			filter: bson.D{{"v", bson.D{{"$lte", primitive.NilObjectID}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", primitive.NilObjectID}}}},
+			resultPushdown: allPushdown,
 		},
 		"Bool": {
 			filter: bson.D{{"v", bson.D{{"$lte", true}}}},
 		},
 		"Datetime": {
-			filter: bson.D{{"v", bson.D{{"$lte", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter: bson.D{{"v", bson.D{{"$lte", nil}}}},
@@ -1059,10 +1120,12 @@ func TestQueryComparisonCompatLte(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"Int32": {
-			filter: bson.D{{"v", bson.D{{"$lte", int32(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", int32(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int32Min": {
-			filter: bson.D{{"v", bson.D{{"$lte", int32(math.MinInt32)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", int32(math.MinInt32)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Timestamp": {
 			filter: bson.D{{"v", bson.D{{"$lte", primitive.Timestamp{T: 42, I: 13}}}}},
@@ -1074,10 +1137,12 @@ func TestQueryComparisonCompatLte(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lte", primitive.Timestamp{I: 13}}}}},
 		},
 		"Int64": {
-			filter: bson.D{{"v", bson.D{{"$lte", int64(42)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", int64(42)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64Min": {
-			filter: bson.D{{"v", bson.D{{"$lte", int64(math.MinInt64)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lte", int64(math.MinInt64)}}}},
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -1186,75 +1251,75 @@ func TestQueryComparisonCompatNe(t *testing.T) {
 		},
 		"Double": {
 			filter:         bson.D{{"v", bson.D{{"$ne", 41.13}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleMax": {
 			filter:         bson.D{{"v", bson.D{{"$ne", math.MaxFloat64}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleSmallest": {
 			filter:         bson.D{{"v", bson.D{{"$ne", math.SmallestNonzeroFloat64}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleZero": {
 			filter:         bson.D{{"v", bson.D{{"$ne", 0.0}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBig": {
 			filter:         bson.D{{"v", bson.D{{"$ne", float64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBigPlus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", float64(1<<61) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleBigMinus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", float64(1<<61) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBig": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -float64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBigPlus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -float64(1<<61) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoubleNegBigMinus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -float64(1<<61) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMax": {
 			filter:         bson.D{{"v", bson.D{{"$ne", float64(1 << 53)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMaxPlus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", float64(1<<53) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMaxMinus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", float64(1<<53) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMin": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -float64(1<<53 - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMinPlus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -float64(1<<53-1) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DoublePrecMinMinus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -float64(1<<53-1) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"String": {
 			filter:         bson.D{{"v", bson.D{{"$ne", "foo"}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"StringEmpty": {
 			filter:         bson.D{{"v", bson.D{{"$ne", ""}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Binary": {
 			filter: bson.D{{"v", bson.D{{"$ne", primitive.Binary{Subtype: 0x80, Data: []byte{42, 0, 13}}}}}},
@@ -1264,27 +1329,27 @@ func TestQueryComparisonCompatNe(t *testing.T) {
 		},
 		"BoolFalse": {
 			filter:         bson.D{{"v", bson.D{{"$ne", false}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"BoolTrue": {
 			filter:         bson.D{{"v", bson.D{{"$ne", true}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Datetime": {
 			filter:         bson.D{{"v", bson.D{{"$ne", primitive.NewDateTimeFromTime(time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeEpoch": {
 			filter:         bson.D{{"v", bson.D{{"$ne", primitive.NewDateTimeFromTime(time.Unix(0, 0))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeYearMin": {
 			filter:         bson.D{{"v", bson.D{{"$ne", primitive.NewDateTimeFromTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"DatetimeYearMax": {
 			filter:         bson.D{{"v", bson.D{{"$ne", primitive.NewDateTimeFromTime(time.Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC))}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Timestamp": {
 			filter: bson.D{{"v", bson.D{{"$ne", primitive.Timestamp{T: 42, I: 13}}}}},
@@ -1297,84 +1362,84 @@ func TestQueryComparisonCompatNe(t *testing.T) {
 		},
 		"Int32": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int32(42)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32Zero": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int32(0)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32Max": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int32(math.MaxInt32)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32Min": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int32(math.MinInt32)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64(42)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Zero": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64(0)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Max": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64(math.MaxInt64)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Min": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64(math.MinInt64)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64Big": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64BigPlusOne": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64((1 << 61) + 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64BigMinusOne": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64((1 << 61) - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBig": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -int64(1 << 61)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigPlusOne": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -int64(1<<61) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64NegBigMinusOne": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -int64(1<<61) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 
 		"Int64PrecMax": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64(1 << 53)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMaxPlusOne": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64((1 << 53) + 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMaxMinusOne": {
 			filter:         bson.D{{"v", bson.D{{"$ne", int64((1 << 53) - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMin": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -int64(1<<53 - 1)}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMinPlus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -int64(1<<53-1) + 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int64PrecMinMinus": {
 			filter:         bson.D{{"v", bson.D{{"$ne", -int64(1<<53-1) - 1}}}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Regex": {
 			filter:     bson.D{{"v", bson.D{{"$ne", primitive.Regex{Pattern: "foo"}}}}},
@@ -1410,19 +1475,20 @@ func TestQueryComparisonCompatMultipleOperators(t *testing.T) {
 				{"_id", bson.D{{"$in", bson.A{"int32"}}}},
 				{"v", bson.D{{"$lte", int32(42)}, {"$gte", int32(0)}}},
 			},
+			resultPushdown: allPushdown,
 		},
 		"NinEqNe": {
 			filter: bson.D{
 				{"_id", bson.D{{"$nin", bson.A{"int64"}}, {"$ne", "int32"}}},
 				{"v", bson.D{{"$eq", int32(42)}}},
 			},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"EqNe": {
 			filter: bson.D{
 				{"v", bson.D{{"$eq", int32(42)}, {"$ne", int32(0)}}},
 			},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 	}
 
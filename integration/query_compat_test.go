@@ -253,17 +253,15 @@ func TestQueryCappedCollectionCompat(t *testing.T) {
 		},
 		"Sort": {
 			sort:         bson.D{{"_id", int32(-1)}},
-			sortPushdown: pgPushdown,
+			sortPushdown: allPushdown,
 		},
 		"FilterSort": {
 			filter:       bson.D{{"v", int32(42)}},
 			sort:         bson.D{{"_id", int32(-1)}},
-			sortPushdown: pgPushdown,
+			sortPushdown: allPushdown,
 		},
 		"MultipleSortFields": {
-			sort: bson.D{{"v", 1}, {"_id", int32(-1)}},
-			// multiple sort fields are skipped by handler and no sort pushdown
-			// is set on handler, so record ID pushdown is done.
+			sort:         bson.D{{"v", 1}, {"_id", int32(-1)}},
 			sortPushdown: allPushdown,
 		},
 	} {
@@ -335,11 +333,11 @@ func TestQueryCompatFilter(t *testing.T) {
 		},
 		"String": {
 			filter:         bson.D{{"v", "foo"}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Int32": {
 			filter:         bson.D{{"v", int32(42)}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"IDString": {
 			filter:         bson.D{{"_id", "string"}},
@@ -355,7 +353,7 @@ func TestQueryCompatFilter(t *testing.T) {
 		},
 		"ObjectID": {
 			filter:         bson.D{{"v", primitive.NilObjectID}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"UnknownFilterOperator": {
 			filter:     bson.D{{"v", bson.D{{"$someUnknownOperator", 42}}}},
@@ -394,6 +392,17 @@ func TestQueryCompatSort(t *testing.T) {
 			filter: bson.D{},
 			sort:   bson.D{{"_id", -1}},
 		},
+		"AscLimit": {
+			filter: bson.D{},
+			sort:   bson.D{{"v", 1}, {"_id", 1}},
+			limit:  pointer.ToInt64(5),
+		},
+		"DescSkipLimit": {
+			filter:  bson.D{},
+			sort:    bson.D{{"v", -1}, {"_id", 1}},
+			optSkip: pointer.ToInt64(3),
+			limit:   pointer.ToInt64(5),
+		},
 
 		"Bad": {
 			filter:     bson.D{},
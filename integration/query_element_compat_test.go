@@ -26,21 +26,26 @@ func TestQueryElementCompatExist(t *testing.T) {
 
 	testCases := map[string]queryCompatTestCase{
 		"IDExistsTrue": {
-			filter: bson.D{{"_id", bson.D{{"$exists", true}}}},
+			filter:         bson.D{{"_id", bson.D{{"$exists", true}}}},
+			resultPushdown: allPushdown,
 		},
 		"IDExistsFalse": {
-			filter:     bson.D{{"_id", bson.D{{"$exists", false}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"_id", bson.D{{"$exists", false}}}},
+			resultType:     emptyResult,
+			resultPushdown: allPushdown,
 		},
 		"ExistsSecondField": {
-			filter: bson.D{{"v", bson.D{{"$exists", true}}}},
+			filter:         bson.D{{"v", bson.D{{"$exists", true}}}},
+			resultPushdown: allPushdown,
 		},
 		"NonExistentField": {
-			filter:     bson.D{{"non-existent", bson.D{{"$exists", true}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"non-existent", bson.D{{"$exists", true}}}},
+			resultType:     emptyResult,
+			resultPushdown: allPushdown,
 		},
 		"ExistsFalse": {
-			filter: bson.D{{"field", bson.D{{"$exists", false}}}},
+			filter:         bson.D{{"field", bson.D{{"$exists", false}}}},
+			resultPushdown: allPushdown,
 		},
 		"NonBool": {
 			filter: bson.D{{"_id", bson.D{{"$exists", -123}}}},
@@ -225,3 +225,158 @@ func TestQueryEvaluationExprErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryEvaluationText(t *testing.T) {
+	t.Parallel()
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"subject", "coffee"}},
+		bson.D{{"_id", int32(2)}, {"subject", "Coffee Shopper"}},
+		bson.D{{"_id", int32(3)}, {"subject", "Baking a cake"}},
+		bson.D{{"_id", int32(4)}, {"subject", "baking"}},
+		bson.D{{"_id", int32(5)}, {"subject", "Cafe Con Leche"}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"subject", "text"}}})
+	require.NoError(t, err)
+
+	score := bson.D{{"$meta", "textScore"}}
+
+	for name, tc := range map[string]struct {
+		search   bson.D   // required, $text operator value
+		expected []bson.D // required
+	}{
+		"Term": {
+			search: bson.D{{"$search", "coffee"}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"subject", "coffee"}, {"score", 1.0}},
+				{{"_id", int32(2)}, {"subject", "Coffee Shopper"}, {"score", 0.75}},
+			},
+		},
+		"Stemming": {
+			search: bson.D{{"$search", "bake"}},
+			expected: []bson.D{
+				{{"_id", int32(4)}, {"subject", "baking"}, {"score", 1.0}},
+				{{"_id", int32(3)}, {"subject", "Baking a cake"}, {"score", 0.75}},
+			},
+		},
+		"Negation": {
+			search: bson.D{{"$search", "coffee -shopper"}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"subject", "coffee"}, {"score", 1.0}},
+			},
+		},
+		"Phrase": {
+			search: bson.D{{"$search", `"coffee shop"`}},
+			expected: []bson.D{
+				{{"_id", int32(2)}, {"subject", "Coffee Shopper"}, {"score", 0.75}},
+			},
+		},
+		"CaseSensitive": {
+			search: bson.D{{"$search", "Coffee"}, {"$caseSensitive", true}},
+			expected: []bson.D{
+				{{"_id", int32(2)}, {"subject", "Coffee Shopper"}, {"score", 0.75}},
+			},
+		},
+		"NoMatch": {
+			search:   bson.D{{"$search", "tea"}},
+			expected: []bson.D{},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := options.Find().SetProjection(bson.D{{"score", score}}).SetSort(bson.D{{"score", score}})
+
+			cursor, err := collection.Find(ctx, bson.D{{"$text", tc.search}}, opts)
+			require.NoError(t, err)
+
+			AssertEqualDocumentsSlice(t, tc.expected, FetchAll(t, ctx, cursor))
+
+			pipeline := bson.A{
+				bson.D{{"$match", bson.D{{"$text", tc.search}}}},
+				bson.D{{"$sort", bson.D{{"score", score}}}},
+				bson.D{{"$project", bson.D{{"subject", 1}, {"score", score}}}},
+			}
+
+			cursor, err = collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			AssertEqualDocumentsSlice(t, tc.expected, FetchAll(t, ctx, cursor))
+		})
+	}
+}
+
+func TestQueryEvaluationTextErrors(t *testing.T) {
+	t.Parallel()
+	ctx, collection := setup.Setup(t, shareddata.Strings)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for test only
+		filter     bson.D // required
+		projection bson.D // optional
+
+		err        *mongo.CommandError // required
+		altMessage string              // optional, alternative error message
+	}{
+		"NoIndex": {
+			filter: bson.D{{"$text", bson.D{{"$search", "foo"}}}},
+			err: &mongo.CommandError{
+				Code:    27,
+				Name:    "IndexNotFound",
+				Message: "text index required for $text query",
+			},
+		},
+		"SearchMissing": {
+			filter: bson.D{{"$text", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "$search required",
+			},
+		},
+		"SearchTypeMismatch": {
+			filter: bson.D{{"$text", bson.D{{"$search", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "$search requires a string value",
+			},
+		},
+		"UnsupportedLanguage": {
+			filter: bson.D{{"$text", bson.D{{"$search", "foo"}, {"$language", "klingon"}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `unsupported language: "klingon" for text index version 3`,
+			},
+		},
+		"ScoreWithoutText": {
+			filter:     bson.D{},
+			projection: bson.D{{"score", bson.D{{"$meta", "textScore"}}}},
+			err: &mongo.CommandError{
+				Code:    40218,
+				Name:    "Location40218",
+				Message: "query requires text score metadata, but it is not available",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.filter, "filter must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			opts := options.Find()
+			if tc.projection != nil {
+				opts.SetProjection(tc.projection)
+			}
+
+			_, err := collection.Find(ctx, tc.filter, opts)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
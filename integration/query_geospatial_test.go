@@ -0,0 +1,313 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// geoPoint returns GeoJSON Point.
+func geoPoint(lng, lat float64) bson.D {
+	return bson.D{{"type", "Point"}, {"coordinates", bson.A{lng, lat}}}
+}
+
+// paris is the center of Paris used by geospatial tests.
+var paris = geoPoint(2.3522, 48.8566)
+
+// setupGeoStores inserts stores around Paris and creates the given index on their locations.
+func setupGeoStores(t *testing.T, index string) (context.Context, *mongo.Collection) {
+	t.Helper()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"name", "Louvre"}, {"loc", geoPoint(2.3376, 48.8606)}},                  // 1.16 km
+		bson.D{{"_id", int32(2)}, {"name", "Eiffel Tower"}, {"loc", geoPoint(2.2945, 48.8584)}},            // 4.23 km
+		bson.D{{"_id", int32(3)}, {"name", "Versailles"}, {"loc", geoPoint(2.1204, 48.8049)}},              // 17.93 km
+		bson.D{{"_id", int32(4)}, {"name", "Online"}},                                                      // no location
+		bson.D{{"_id", int32(5)}, {"name", "Notre-Dame"}, {"loc", bson.A{2.3499, 48.8530}}},                // 0.43 km, legacy
+		bson.D{{"_id", int32(6)}, {"name", "Invalid"}, {"loc", bson.D{{"type", "Point"}, {"foo", "bar"}}}}, // ignored
+	})
+	require.NoError(t, err)
+
+	if index != "" {
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"loc", index}}})
+		require.NoError(t, err)
+	}
+
+	return ctx, collection
+}
+
+// fetchIDs returns _id values of all documents of the cursor.
+func fetchIDs(t *testing.T, ctx context.Context, cursor *mongo.Cursor) []int32 {
+	t.Helper()
+
+	var res []int32
+
+	for _, doc := range FetchAll(t, ctx, cursor) {
+		res = append(res, doc.Map()["_id"].(int32))
+	}
+
+	return res
+}
+
+func TestQueryGeospatial(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setupGeoStores(t, "2dsphere")
+
+	square := bson.D{{"type", "Polygon"}, {"coordinates", bson.A{bson.A{
+		bson.A{2.3, 48.85}, bson.A{2.36, 48.85}, bson.A{2.36, 48.87}, bson.A{2.3, 48.87}, bson.A{2.3, 48.85},
+	}}}}
+
+	for name, tc := range map[string]struct {
+		filter    bson.D  // required
+		expected  []int32 // required
+		unordered bool    // if true, the order of expected documents is not checked
+	}{
+		"NearMaxDistance": {
+			filter:   bson.D{{"loc", bson.D{{"$near", bson.D{{"$geometry", paris}, {"$maxDistance", 5000}}}}}},
+			expected: []int32{5, 1, 2},
+		},
+		"NearMinDistance": {
+			filter:   bson.D{{"loc", bson.D{{"$near", bson.D{{"$geometry", paris}, {"$minDistance", 1000}}}}}},
+			expected: []int32{1, 2, 3},
+		},
+		"NearSphereLegacy": {
+			filter: bson.D{{"loc", bson.D{
+				{"$nearSphere", bson.A{2.3522, 48.8566}},
+				{"$maxDistance", 2.0 / 6378.1},
+			}}},
+			expected: []int32{5, 1},
+		},
+		"NearWithOtherConditions": {
+			filter: bson.D{
+				{"loc", bson.D{{"$near", bson.D{{"$geometry", paris}}}}},
+				{"name", bson.D{{"$ne", "Louvre"}}},
+			},
+			expected: []int32{5, 2, 3},
+		},
+		"WithinCenterSphere": {
+			filter:    bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$centerSphere", bson.A{bson.A{2.3522, 48.8566}, 5.0 / 6378.1}}}}}}},
+			expected:  []int32{1, 2, 5},
+			unordered: true,
+		},
+		"WithinPolygon": {
+			filter:    bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$geometry", square}}}}}},
+			expected:  []int32{1, 5},
+			unordered: true,
+		},
+		"WithinBox": {
+			filter:    bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$box", bson.A{bson.A{2.1, 48.8}, bson.A{2.3, 48.9}}}}}}}},
+			expected:  []int32{2, 3},
+			unordered: true,
+		},
+		"Intersects": {
+			filter:    bson.D{{"loc", bson.D{{"$geoIntersects", bson.D{{"$geometry", square}}}}}},
+			expected:  []int32{1, 5},
+			unordered: true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Find(ctx, tc.filter)
+			require.NoError(t, err)
+
+			actual := fetchIDs(t, ctx, cursor)
+
+			if tc.unordered {
+				assert.ElementsMatch(t, tc.expected, actual)
+				return
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestQueryGeospatial2D(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"loc", bson.A{int32(1), int32(1)}}},
+		bson.D{{"_id", int32(2)}, {"loc", bson.A{int32(3), int32(4)}}},
+		bson.D{{"_id", int32(3)}, {"loc", bson.D{{"x", 0.5}, {"y", 0.0}}}},
+		bson.D{{"_id", int32(4)}, {"loc", bson.A{int32(10), int32(10)}}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"loc", "2d"}}})
+	require.NoError(t, err)
+
+	cursor, err := collection.Find(ctx, bson.D{{"loc", bson.D{{"$near", bson.A{0, 0}}, {"$maxDistance", 5}}}})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{3, 1, 2}, fetchIDs(t, ctx, cursor))
+
+	cursor, err = collection.Find(ctx, bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$center", bson.A{bson.A{0, 0}, 2}}}}}}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int32{1, 3}, fetchIDs(t, ctx, cursor))
+
+	cursor, err = collection.Find(ctx, bson.D{{"loc", bson.D{{"$geoWithin", bson.D{
+		{"$polygon", bson.A{bson.A{0, 0}, bson.A{8, 0}, bson.A{0, 8}}},
+	}}}}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int32{1, 2, 3}, fetchIDs(t, ctx, cursor))
+}
+
+func TestQueryGeospatialErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setupGeoStores(t, "")
+
+	for name, tc := range map[string]struct {
+		filter bson.D              // required
+		err    *mongo.CommandError // required
+	}{
+		"NearNoIndex": {
+			filter: bson.D{{"loc", bson.D{{"$near", bson.D{{"$geometry", paris}}}}}},
+			err: &mongo.CommandError{
+				Code:    291,
+				Name:    "NoQueryExecutionPlans",
+				Message: "error processing query: planner returned error :: caused by :: unable to find index for $geoNear query",
+			},
+		},
+		"NearNested": {
+			filter: bson.D{{"$or", bson.A{bson.D{{"loc", bson.D{{"$near", bson.D{{"$geometry", paris}}}}}}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "$geoNear, $near, and $nearSphere are not allowed in this context",
+			},
+		},
+		"WithinUnknownShape": {
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$circle", bson.A{}}}}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "unknown geo specifier: $circle: []",
+			},
+		},
+		"WithinPoint": {
+			filter: bson.D{{"loc", bson.D{{"$geoWithin", bson.D{{"$geometry", geoPoint(2.5, 48.5)}}}}}},
+			err: &mongo.CommandError{
+				Code: 2,
+				Name: "BadValue",
+				Message: `$geoWithin not supported with provided geometry: ` +
+					`{ type: "Point", coordinates: [ 2.5, 48.5 ] }`,
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Find(ctx, tc.filter)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+func TestAggregateGeoNear(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setupGeoStores(t, "2dsphere")
+
+	pipeline := bson.A{
+		bson.D{{"$geoNear", bson.D{
+			{"near", paris},
+			{"distanceField", "dist.km"},
+			{"distanceMultiplier", 0.001},
+			{"maxDistance", 5000},
+			{"includeLocs", "dist.loc"},
+			{"query", bson.D{{"name", bson.D{{"$ne", "Eiffel Tower"}}}}},
+		}}},
+		bson.D{{"$project", bson.D{{"dist", 1}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	require.NoError(t, err)
+
+	actual := FetchAll(t, ctx, cursor)
+	require.Len(t, actual, 2)
+
+	for i, expected := range []struct {
+		id  int32
+		km  float64
+		loc any
+	}{
+		{5, 0.4347, bson.A{2.3499, 48.8530}},
+		{1, 1.1583, geoPoint(2.3376, 48.8606)},
+	} {
+		m := actual[i].Map()
+		assert.Equal(t, expected.id, m["_id"])
+
+		dist := m["dist"].(bson.D).Map()
+		assert.InDelta(t, expected.km, dist["km"], 0.001)
+		assert.Equal(t, expected.loc, dist["loc"])
+	}
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A              // required
+		err      *mongo.CommandError // required
+	}{
+		"NotFirst": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{}}},
+				bson.D{{"$geoNear", bson.D{{"near", paris}, {"distanceField", "d"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    40603,
+				Name:    "Location40603",
+				Message: "$geoNear is only valid as the first stage in a pipeline",
+			},
+		},
+		"DistanceFieldMissing": {
+			pipeline: bson.A{bson.D{{"$geoNear", bson.D{{"near", paris}}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "$geoNear requires a 'distanceField' option",
+			},
+		},
+		"KeyNoIndex": {
+			pipeline: bson.A{bson.D{{"$geoNear", bson.D{{"near", paris}, {"distanceField", "d"}, {"key", "other"}}}}},
+			err: &mongo.CommandError{
+				Code:    27,
+				Name:    "IndexNotFound",
+				Message: "$geoNear requires a 2d or 2dsphere index, but none were found",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -37,6 +37,7 @@ func TestQueryLogicalCompatAnd(t *testing.T) {
 					bson.D{{"v", bson.D{{"$gt", int32(0)}}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"Two": {
 			filter: bson.D{{
@@ -45,6 +46,7 @@ func TestQueryLogicalCompatAnd(t *testing.T) {
 					bson.D{{"v", bson.D{{"$lt", int64(42)}}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"AndOr": {
 			filter: bson.D{{
@@ -56,6 +58,7 @@ func TestQueryLogicalCompatAnd(t *testing.T) {
 					}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"AndAnd": {
 			filter: bson.D{{
@@ -67,6 +70,7 @@ func TestQueryLogicalCompatAnd(t *testing.T) {
 					bson.D{{"v", bson.D{{"$type", "int"}}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"BadInput": {
 			filter:     bson.D{{"$and", nil}},
@@ -79,7 +83,8 @@ func TestQueryLogicalCompatAnd(t *testing.T) {
 					true,
 				},
 			}},
-			resultType: emptyResult,
+			resultType:     emptyResult,
+			resultPushdown: allPushdown,
 		},
 	}
 
@@ -102,6 +107,7 @@ func TestQueryLogicalCompatOr(t *testing.T) {
 					bson.D{{"v", bson.D{{"$lt", int32(0)}}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"Two": {
 			filter: bson.D{{
@@ -110,6 +116,7 @@ func TestQueryLogicalCompatOr(t *testing.T) {
 					bson.D{{"v", bson.D{{"$gt", int64(42)}}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"OrAnd": {
 			filter: bson.D{{
@@ -121,6 +128,7 @@ func TestQueryLogicalCompatOr(t *testing.T) {
 					}}},
 				},
 			}},
+			resultPushdown: allPushdown,
 		},
 		"BadInput": {
 			filter:     bson.D{{"$or", nil}},
@@ -165,6 +173,23 @@ func TestQueryLogicalCompatNor(t *testing.T) {
 				},
 			}},
 		},
+		"Eq": {
+			filter: bson.D{{
+				"$nor", bson.A{
+					bson.D{{"v", int32(42)}},
+					bson.D{{"v", bson.D{{"$in", bson.A{"foo", int64(0)}}}}},
+				},
+			}},
+			resultPushdown: allPushdown,
+		},
+		"Exists": {
+			filter: bson.D{{
+				"$nor", bson.A{
+					bson.D{{"v", bson.D{{"$exists", false}}}},
+				},
+			}},
+			resultPushdown: allPushdown,
+		},
 		"BadInput": {
 			filter:     bson.D{{"$nor", nil}},
 			resultType: emptyResult,
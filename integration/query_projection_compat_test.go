@@ -240,58 +240,65 @@ func TestQueryProjectionPositionalOperatorCompat(t *testing.T) {
 		"Implicit": {
 			filter:         bson.D{{"v", float64(42)}},
 			projection:     bson.D{{"v.$", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"ImplicitNoMatch": {
 			filter:         bson.D{{"v", "non-existent"}},
 			projection:     bson.D{{"v.$", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 			resultType:     emptyResult,
 		},
 		"Eq": {
 			filter:         bson.D{{"v", bson.D{{"$eq", 45.5}}}},
 			projection:     bson.D{{"v.$", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"Gt": {
-			filter:     bson.D{{"v", bson.D{{"$gt", 42}}}},
-			projection: bson.D{{"v.$", true}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 42}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v.$", true}},
 		},
 		"GtNoMatch": {
-			filter:     bson.D{{"v", bson.D{{"$gt", math.MaxFloat64}}}},
-			projection: bson.D{{"v.$", true}},
-			resultType: emptyResult,
+			filter:         bson.D{{"v", bson.D{{"$gt", math.MaxFloat64}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v.$", true}},
+			resultType:     emptyResult,
 		},
 		"DollarEndingKey": {
-			filter:     bson.D{{"v", bson.D{{"$gt", 42}}}},
-			projection: bson.D{{"v$", true}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 42}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v$", true}},
 		},
 		"DollarPartOfKey": {
-			filter:     bson.D{{"v", bson.D{{"$gt", 42}}}},
-			projection: bson.D{{"v$v", true}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 42}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v$v", true}},
 		},
 		"ImplicitDotNotation": {
 			filter:         bson.D{{"v", float64(42)}},
 			projection:     bson.D{{"v.foo.$", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"ImplicitDotNoMatch": {
 			filter:         bson.D{{"v", "non-existent"}},
 			projection:     bson.D{{"v.foo.$", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 			resultType:     emptyResult,
 		},
 		"GtDotNotation": {
-			filter:     bson.D{{"v", bson.D{{"$gt", 42}}}},
-			projection: bson.D{{"v.foo.$", true}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 42}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v.foo.$", true}},
 		},
 		"GtDotNoMatch": {
-			filter:     bson.D{{"v", bson.D{{"$gt", 42}}}},
-			projection: bson.D{{"v.foo.$", true}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 42}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v.foo.$", true}},
 		},
 		"DotNotationDollarEndingKey": {
-			filter:     bson.D{{"v", bson.D{{"$gt", 42}}}},
-			projection: bson.D{{"v.foo$", true}},
+			filter:         bson.D{{"v", bson.D{{"$gt", 42}}}},
+			resultPushdown: allPushdown,
+			projection:     bson.D{{"v.foo$", true}},
 		},
 		"IDValueFilters": {
 			filter: bson.D{
@@ -299,43 +306,48 @@ func TestQueryProjectionPositionalOperatorCompat(t *testing.T) {
 				{"v", bson.D{{"$gt", 41}}},
 			},
 			projection:     bson.D{{"v.$", true}},
-			resultPushdown: pgPushdown,
+			resultPushdown: allPushdown,
 		},
 		"TwoFilter": {
 			filter: bson.D{
 				{"v", bson.D{{"$lt", 43}}},
 				{"v", bson.D{{"$gt", 41}}},
 			},
-			projection: bson.D{{"v.$", true}},
+			projection:     bson.D{{"v.$", true}},
+			resultPushdown: allPushdown,
 		},
 		"TwoConflictingLtGt": {
 			filter: bson.D{
 				{"v", bson.D{{"$lt", 42}}},
 				{"v", bson.D{{"$gt", 42}}},
 			},
-			projection: bson.D{{"v.$", true}},
-			skip:       "https://github.com/FerretDB/FerretDB/issues/2522",
+			projection:     bson.D{{"v.$", true}},
+			skip:           "https://github.com/FerretDB/FerretDB/issues/2522",
+			resultPushdown: allPushdown,
 		},
 		"TwoConflictingGtLt": {
 			filter: bson.D{
 				{"v", bson.D{{"$gt", 42}}},
 				{"v", bson.D{{"$lt", 42}}},
 			},
-			projection: bson.D{{"v.$", true}},
-			skip:       "https://github.com/FerretDB/FerretDB/issues/2522",
+			projection:     bson.D{{"v.$", true}},
+			skip:           "https://github.com/FerretDB/FerretDB/issues/2522",
+			resultPushdown: allPushdown,
 		},
 		"PartialProjection": {
 			filter: bson.D{
 				{"v.foo", bson.D{{"$gt", 42}}},
 			},
-			projection: bson.D{{"v.$", true}},
-			resultType: emptyResult,
+			projection:     bson.D{{"v.$", true}},
+			resultType:     emptyResult,
+			resultPushdown: allPushdown,
 		},
 		"PartialFilter": {
 			filter: bson.D{
 				{"v", bson.D{{"$gt", 42}}},
 			},
-			projection: bson.D{{"v.foo.$", true}},
+			projection:     bson.D{{"v.foo.$", true}},
+			resultPushdown: allPushdown,
 		},
 		"TypeOperator": {
 			filter:     bson.D{},
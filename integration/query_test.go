@@ -570,6 +570,22 @@ func TestDotNotation(t *testing.T) {
 			filter:      bson.D{{"_id.foo", "bar"}},
 			expectedIDs: []any{bson.D{{"foo", "bar"}}},
 		},
+		"FieldArrayTraversal": {
+			filter:      bson.D{{"foo.qaz.baz", int32(1)}},
+			expectedIDs: []any{"document-deeply-nested"},
+		},
+		"FieldArrayFieldArrayTraversal": {
+			filter:      bson.D{{"wsx.edc.rfv", int32(1)}},
+			expectedIDs: []any{"document-deeply-nested"},
+		},
+		"FieldArrayTraversalNe": {
+			filter:      bson.D{{"foo.qaz.baz", bson.D{{"$ne", int32(1)}}}},
+			expectedIDs: []any{bson.D{{"foo", "bar"}}},
+		},
+		"FieldNe": {
+			filter:      bson.D{{"foo.bar.baz.qux.quz", bson.D{{"$ne", int32(42)}}}},
+			expectedIDs: []any{bson.D{{"foo", "bar"}}},
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -877,21 +893,21 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 			limit:          3,
 			len:            1,
 			filterPushdown: allPushdown,
-			limitPushdown:  noPushdown,
+			limitPushdown:  pgPushdown,
 		},
 		"ValueFilter": {
 			filter:         bson.D{{"v", 42}},
 			sort:           bson.D{{"_id", 1}},
 			limit:          3,
 			len:            3,
-			filterPushdown: pgPushdown,
+			filterPushdown: allPushdown,
 			limitPushdown:  noPushdown,
 		},
 		"DotNotationFilter": {
 			filter:         bson.D{{"v.foo", 42}},
 			limit:          3,
 			len:            3,
-			filterPushdown: noPushdown,
+			filterPushdown: allPushdown,
 			limitPushdown:  noPushdown,
 		},
 		"ObjectFilter": {
@@ -921,7 +937,7 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 			sort:           bson.D{{"_id", 1}},
 			limit:          3,
 			len:            3,
-			filterPushdown: pgPushdown,
+			filterPushdown: allPushdown,
 			limitPushdown:  noPushdown,
 		},
 		"DotNotationFilterSort": {
@@ -929,7 +945,7 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 			sort:           bson.D{{"_id", 1}},
 			limit:          3,
 			len:            3,
-			filterPushdown: noPushdown,
+			filterPushdown: allPushdown,
 			limitPushdown:  noPushdown,
 		},
 		"ObjectFilterSort": {
@@ -944,7 +960,15 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 			optSkip:       pointer.ToInt64(1),
 			limit:         2,
 			len:           2,
-			limitPushdown: noPushdown,
+			limitPushdown: allPushdown,
+		},
+		"SkipValueFilter": {
+			filter:         bson.D{{"v", 42}},
+			optSkip:        pointer.ToInt64(1),
+			limit:          2,
+			len:            2,
+			filterPushdown: allPushdown,
+			limitPushdown:  allPushdown,
 		},
 	} {
 		tc, name := tc, name
@@ -993,17 +1017,22 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 
 				assert.NoError(t, err)
 
-				doc := ConvertDocument(t, res)
-				limitPushdown, _ := doc.Get("limitPushdown")
-				assert.Equal(t, tc.limitPushdown.SortPushdownExpected(t), limitPushdown)
-
 				var msg string
 
 				if setup.FilterPushdownDisabled() {
 					tc.filterPushdown = noPushdown
 					msg = "Filter pushdown is disabled, but target resulted with pushdown"
+
+					// documents should be filtered by the handler before skipping and limiting them
+					if len(tc.filter) > 0 {
+						tc.limitPushdown = noPushdown
+					}
 				}
 
+				doc := ConvertDocument(t, res)
+				limitPushdown, _ := doc.Get("limitPushdown")
+				assert.Equal(t, tc.limitPushdown.SortPushdownExpected(t), limitPushdown, msg)
+
 				filterPushdown, _ := ConvertDocument(t, res).Get("filterPushdown")
 				assert.Equal(t, tc.filterPushdown.FilterPushdownExpected(t), filterPushdown, msg)
 			})
@@ -1214,3 +1243,63 @@ func TestQueryTailableCursors(t *testing.T) {
 	_, err = collection.Find(ctx, bson.D{{}}, options.Find().SetCursorType(options.Tailable))
 	AssertEqualAltCommandError(t, expectedErr, "tailable cursor requested on non capped collection", err)
 }
+
+func TestQueryNonFiniteFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"a", int32(1)}},
+		bson.D{{"_id", int32(2)}, {"a", bson.A{int32(1), int32(2)}}},
+		bson.D{{"_id", int32(3)}, {"a", bson.D{{"b", int32(1)}}}},
+	})
+	require.NoError(t, err)
+
+	for name, v := range map[string]float64{
+		"NaN":         math.NaN(),
+		"Infinity":    math.Inf(1),
+		"NegInfinity": math.Inf(-1),
+	} {
+		for _, key := range []string{"a", "a.b"} {
+			name, v, key := name, v, key
+			t.Run(name+"/"+key, func(t *testing.T) {
+				t.Parallel()
+
+				filter := bson.D{{key, v}}
+
+				// checkErr returns true if the command should succeed;
+				// FerretDB does not support NaN values at all.
+				checkErr := func(t *testing.T, err error) bool {
+					t.Helper()
+
+					if math.IsNaN(v) && !setup.IsMongoDB(t) {
+						assert.ErrorContains(t, err, "NaN is not supported")
+						return false
+					}
+
+					require.NoError(t, err)
+
+					return true
+				}
+
+				cursor, err := collection.Find(ctx, filter, options.Find().SetLimit(1))
+				if checkErr(t, err) {
+					var res []bson.D
+					require.NoError(t, cursor.All(ctx, &res))
+					assert.Empty(t, res)
+				}
+
+				updateRes, err := collection.UpdateMany(ctx, filter, bson.D{{"$set", bson.D{{"c", int32(1)}}}})
+				if checkErr(t, err) {
+					assert.Equal(t, int64(0), updateRes.MatchedCount)
+				}
+
+				deleteRes, err := collection.DeleteMany(ctx, filter)
+				if checkErr(t, err) {
+					assert.Equal(t, int64(0), deleteRes.DeletedCount)
+				}
+			})
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
@@ -33,6 +34,11 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/testutil/testtb"
 )
 
+// TTLMonitorInterval is the interval between TTL monitor passes of in-process FerretDB.
+//
+// It is much shorter than the default one so tests could wait for expired documents to be deleted.
+const TTLMonitorInterval = time.Second
+
 // unixSocketPath returns temporary Unix domain socket path for that test.
 func unixSocketPath(tb testtb.TB) string {
 	tb.Helper()
@@ -178,6 +184,8 @@ func setupListener(tb testtb.TB, ctx context.Context, logger *zap.Logger) string
 		MySQLURL:      mysqlURL,
 		HANAURL:       *hanaURLF,
 
+		TTLMonitorInterval: TTLMonitorInterval,
+
 		TestOpts: registry.TestOpts{
 			DisableFilterPushdown: *disableFilterPushdownF,
 			EnableOplog:           true,
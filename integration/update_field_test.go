@@ -16,6 +16,7 @@ package integration
 
 import (
 	"math"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,7 @@ import (
 	"github.com/FerretDB/FerretDB/integration/shareddata"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil/teststress"
 )
 
 func TestUpdateFieldSet(t *testing.T) {
@@ -87,6 +89,118 @@ func TestUpdateFieldSet(t *testing.T) {
 	}
 }
 
+// TestUpdateFieldPushdown checks updates that could be applied by the backend.
+func TestUpdateFieldPushdown(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter bson.D // required, used for filter parameter
+		update bson.D // required, used for update parameter
+		multi  bool   // optional, updates all matching documents if set
+
+		res            *mongo.UpdateResult // required, expected response from update
+		findRes        []bson.D            // required, expected documents after update
+		updatePushdown resultPushdown      // optional, defaults to noPushdown
+	}{
+		"Inc": {
+			filter: bson.D{{"_id", "counter"}},
+			update: bson.D{{"$inc", bson.D{{"v", int32(1)}, {"n", int64(2)}}}},
+			res:    &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1},
+			findRes: []bson.D{
+				{{"_id", "counter"}, {"v", int32(2)}, {"o", bson.D{{"a", "x"}}}, {"n", int64(2)}},
+				{{"_id", "string"}, {"v", "foo"}},
+			},
+			updatePushdown: allPushdown,
+		},
+		"SetUnsetDotNotation": {
+			filter: bson.D{{"_id", "counter"}},
+			update: bson.D{
+				{"$set", bson.D{{"o.b", int64(2)}}},
+				{"$unset", bson.D{{"o.a", ""}}},
+			},
+			res: &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1},
+			findRes: []bson.D{
+				{{"_id", "counter"}, {"v", int32(1)}, {"o", bson.D{{"b", int64(2)}}}},
+				{{"_id", "string"}, {"v", "foo"}},
+			},
+			updatePushdown: allPushdown,
+		},
+		"SetSameValue": {
+			filter: bson.D{{"_id", "counter"}},
+			update: bson.D{{"$set", bson.D{{"v", int32(1)}}}},
+			res:    &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 0},
+			findRes: []bson.D{
+				{{"_id", "counter"}, {"v", int32(1)}, {"o", bson.D{{"a", "x"}}}},
+				{{"_id", "string"}, {"v", "foo"}},
+			},
+			updatePushdown: allPushdown,
+		},
+		"Multi": {
+			filter: bson.D{},
+			update: bson.D{{"$set", bson.D{{"s", true}}}},
+			multi:  true,
+			res:    &mongo.UpdateResult{MatchedCount: 2, ModifiedCount: 2},
+			findRes: []bson.D{
+				{{"_id", "counter"}, {"v", int32(1)}, {"o", bson.D{{"a", "x"}}}, {"s", true}},
+				{{"_id", "string"}, {"v", "foo"}, {"s", true}},
+			},
+			updatePushdown: allPushdown,
+		},
+		"Array": {
+			filter: bson.D{{"_id", "string"}},
+			update: bson.D{{"$push", bson.D{{"a", int32(1)}}}},
+			res:    &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1},
+			findRes: []bson.D{
+				{{"_id", "counter"}, {"v", int32(1)}, {"o", bson.D{{"a", "x"}}}},
+				{{"_id", "string"}, {"v", "foo"}, {"a", bson.A{int32(1)}}},
+			},
+			updatePushdown: noPushdown,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			_, err := collection.InsertMany(ctx, []any{
+				bson.D{{"_id", "counter"}, {"v", int32(1)}, {"o", bson.D{{"a", "x"}}}},
+				bson.D{{"_id", "string"}, {"v", "foo"}},
+			})
+			require.NoError(t, err)
+
+			t.Run("Explain", func(t *testing.T) {
+				setup.SkipForMongoDB(t, "pushdown is FerretDB specific feature")
+
+				command := bson.D{
+					{"update", collection.Name()},
+					{"updates", bson.A{bson.D{{"q", tc.filter}, {"u", tc.update}, {"multi", tc.multi}}}},
+				}
+
+				var res bson.D
+				err := collection.Database().RunCommand(ctx, bson.D{{"explain", command}}).Decode(&res)
+				require.NoError(t, err)
+
+				updatePushdown, _ := ConvertDocument(t, res).Get("updatePushdown")
+				assert.Equal(t, tc.updatePushdown.UpdatePushdownExpected(t), updatePushdown)
+			})
+
+			var res *mongo.UpdateResult
+			if tc.multi {
+				res, err = collection.UpdateMany(ctx, tc.filter, tc.update)
+			} else {
+				res, err = collection.UpdateOne(ctx, tc.filter, tc.update)
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.res, res)
+
+			actual := FindAll(t, ctx, collection)
+			AssertEqualDocumentsSlice(t, tc.findRes, actual)
+		})
+	}
+}
+
 // TestUpdateFieldSetIDDoc checks that the order of fields in the _id document matters.
 func TestUpdateFieldSetIDDoc(t *testing.T) {
 	t.Parallel()
@@ -362,6 +476,75 @@ func TestUpdateCommandUpsert(t *testing.T) {
 	}
 }
 
+func TestUpdateFieldUpsertStress(t *testing.T) {
+	// no t.Parallel() for stress tests
+
+	ctx, collection := setup.Setup(t)
+
+	var upserted atomic.Int32
+
+	n := teststress.Stress(t, func(ready chan<- struct{}, start <-chan struct{}) {
+		ready <- struct{}{}
+		<-start
+
+		res, err := collection.UpdateOne(
+			ctx,
+			bson.D{{"_id", "upsert"}},
+			bson.D{{"$inc", bson.D{{"v", int32(1)}}}},
+			options.Update().SetUpsert(true),
+		)
+		require.NoError(t, err)
+
+		if res.UpsertedID != nil {
+			upserted.Add(1)
+		}
+	})
+
+	assert.Equal(t, int32(1), upserted.Load())
+
+	var res []bson.D
+	cursor, err := collection.Find(ctx, bson.D{})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &res))
+
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", "upsert"}, {"v", int32(n)}}}, res)
+}
+
+func TestUpdateFieldUpsertNonIDStress(t *testing.T) {
+	// no t.Parallel() for stress tests
+
+	ctx, collection := setup.Setup(t)
+
+	var upserted atomic.Int32
+
+	// there is no unique index on k, so only atomic upserts prevent duplicates
+	teststress.Stress(t, func(ready chan<- struct{}, start <-chan struct{}) {
+		ready <- struct{}{}
+		<-start
+
+		res, err := collection.UpdateOne(
+			ctx,
+			bson.D{{"k", "v"}},
+			bson.D{{"$set", bson.D{{"v", "upsert"}}}},
+			options.Update().SetUpsert(true),
+		)
+		require.NoError(t, err)
+
+		if res.UpsertedID != nil {
+			upserted.Add(1)
+		}
+	})
+
+	assert.Equal(t, int32(1), upserted.Load())
+
+	var res []bson.D
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetProjection(bson.D{{"_id", false}}))
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &res))
+
+	AssertEqualDocumentsSlice(t, []bson.D{{{"k", "v"}, {"v", "upsert"}}}, res)
+}
+
 func TestUpdateFieldErrors(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,348 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// requiredNameValidator is a validator that requires a string `name` field.
+var requiredNameValidator = bson.D{{"$jsonSchema", bson.D{
+	{"required", bson.A{"name"}},
+	{"properties", bson.D{{"name", bson.D{{"bsonType", "string"}}}}},
+}}}
+
+// requiredNameErrInfo returns `errInfo` of requiredNameValidator validation failure for the document without `name`.
+func requiredNameErrInfo(id any) bson.D {
+	return bson.D{
+		{"failingDocumentId", id},
+		{"details", bson.D{
+			{"operatorName", "$jsonSchema"},
+			{"schemaRulesNotSatisfied", bson.A{bson.D{
+				{"operatorName", "required"},
+				{"specifiedAs", bson.D{{"required", bson.A{"name"}}}},
+				{"missingProperties", bson.A{"name"}},
+			}}},
+		}},
+	}
+}
+
+// createValidatedCollection creates a collection with the given validation options.
+func createValidatedCollection(t *testing.T, ctx context.Context, db *mongo.Database, name string, opts ...bson.E) *mongo.Collection {
+	t.Helper()
+
+	command := append(bson.D{{"create", name}}, opts...)
+	require.NoError(t, db.RunCommand(ctx, command).Err())
+
+	return db.Collection(name)
+}
+
+// writeErrorInfo returns `errInfo` of the single write error.
+func writeErrorInfo(t *testing.T, err error) bson.D {
+	t.Helper()
+
+	var we mongo.WriteException
+	require.ErrorAs(t, err, &we)
+	require.Len(t, we.WriteErrors, 1)
+	assert.Equal(t, 121, we.WriteErrors[0].Code)
+	assert.Equal(t, "Document failed validation", we.WriteErrors[0].Message)
+
+	var errInfo bson.D
+	require.NoError(t, bson.Unmarshal(we.WriteErrors[0].Details, &errInfo))
+
+	return errInfo
+}
+
+func TestValidationInsert(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	coll := createValidatedCollection(t, ctx, db, "insert", bson.E{"validator", requiredNameValidator})
+
+	_, err := coll.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"name", "foo"}})
+	require.NoError(t, err)
+
+	_, err = coll.InsertOne(ctx, bson.D{{"_id", int32(2)}})
+	AssertEqualDocuments(t, requiredNameErrInfo(int32(2)), writeErrorInfo(t, err))
+
+	_, err = coll.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(3)}, {"name", "bar"}},
+		bson.D{{"_id", int32(4)}, {"name", int32(42)}},
+		bson.D{{"_id", int32(5)}, {"name", "baz"}},
+	}, options.InsertMany().SetOrdered(false))
+
+	var bwe mongo.BulkWriteException
+	require.ErrorAs(t, err, &bwe)
+	require.Len(t, bwe.WriteErrors, 1)
+	assert.Equal(t, 1, bwe.WriteErrors[0].Index)
+	assert.Equal(t, 121, bwe.WriteErrors[0].Code)
+
+	_, err = coll.InsertOne(ctx, bson.D{{"_id", int32(6)}}, options.InsertOne().SetBypassDocumentValidation(true))
+	require.NoError(t, err)
+
+	count, err := coll.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+}
+
+func TestValidationUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	coll := createValidatedCollection(t, ctx, db, "update", bson.E{"validator", requiredNameValidator})
+
+	_, err := coll.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"name", "foo"}})
+	require.NoError(t, err)
+
+	_, err = coll.UpdateOne(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$unset", bson.D{{"name", ""}}}})
+	AssertEqualDocuments(t, requiredNameErrInfo(int32(1)), writeErrorInfo(t, err))
+
+	_, err = coll.UpdateOne(
+		ctx, bson.D{{"_id", int32(2)}}, bson.D{{"$set", bson.D{{"v", int32(42)}}}}, options.Update().SetUpsert(true),
+	)
+	AssertEqualDocuments(t, requiredNameErrInfo(int32(2)), writeErrorInfo(t, err))
+
+	res, err := coll.UpdateOne(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$set", bson.D{{"name", "bar"}}}})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, res.ModifiedCount)
+
+	_, err = coll.UpdateOne(
+		ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$unset", bson.D{{"name", ""}}}},
+		options.Update().SetBypassDocumentValidation(true),
+	)
+	require.NoError(t, err)
+}
+
+func TestValidationFindAndModify(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	coll := createValidatedCollection(t, ctx, db, "findAndModify", bson.E{"validator", requiredNameValidator})
+
+	_, err := coll.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"name", "foo"}})
+	require.NoError(t, err)
+
+	err = coll.FindOneAndUpdate(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$unset", bson.D{{"name", ""}}}}).Err()
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(121), ce.Code)
+	assert.Equal(t, "Document failed validation", ce.Message)
+
+	var errInfo bson.D
+	require.NoError(t, ce.Raw.Lookup("errInfo").Unmarshal(&errInfo))
+	AssertEqualDocuments(t, requiredNameErrInfo(int32(1)), errInfo)
+}
+
+func TestValidationLevelAndAction(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	t.Run("Moderate", func(t *testing.T) {
+		coll := db.Collection("moderate")
+
+		_, err := coll.InsertMany(ctx, []any{
+			bson.D{{"_id", int32(1)}},
+			bson.D{{"_id", int32(2)}, {"name", "foo"}},
+		})
+		require.NoError(t, err)
+
+		err = db.RunCommand(ctx, bson.D{
+			{"collMod", "moderate"},
+			{"validator", requiredNameValidator},
+			{"validationLevel", "moderate"},
+		}).Err()
+		require.NoError(t, err)
+
+		// existing invalid document could be updated
+		_, err = coll.UpdateOne(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$set", bson.D{{"v", int32(42)}}}})
+		require.NoError(t, err)
+
+		// existing valid document could not be made invalid
+		_, err = coll.UpdateOne(ctx, bson.D{{"_id", int32(2)}}, bson.D{{"$unset", bson.D{{"name", ""}}}})
+		AssertEqualDocuments(t, requiredNameErrInfo(int32(2)), writeErrorInfo(t, err))
+
+		_, err = coll.InsertOne(ctx, bson.D{{"_id", int32(3)}})
+		AssertEqualDocuments(t, requiredNameErrInfo(int32(3)), writeErrorInfo(t, err))
+	})
+
+	t.Run("Off", func(t *testing.T) {
+		coll := createValidatedCollection(
+			t, ctx, db, "off", bson.E{"validator", requiredNameValidator}, bson.E{"validationLevel", "off"},
+		)
+
+		_, err := coll.InsertOne(ctx, bson.D{{"_id", int32(1)}})
+		require.NoError(t, err)
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		coll := createValidatedCollection(
+			t, ctx, db, "warn", bson.E{"validator", requiredNameValidator}, bson.E{"validationAction", "warn"},
+		)
+
+		_, err := coll.InsertOne(ctx, bson.D{{"_id", int32(1)}})
+		require.NoError(t, err)
+
+		// switch to error action, keeping the validator
+		err = db.RunCommand(ctx, bson.D{{"collMod", "warn"}, {"validationAction", "error"}}).Err()
+		require.NoError(t, err)
+
+		_, err = coll.InsertOne(ctx, bson.D{{"_id", int32(2)}})
+		AssertEqualDocuments(t, requiredNameErrInfo(int32(2)), writeErrorInfo(t, err))
+	})
+}
+
+func TestValidationQueryOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	validator := bson.D{{"v", bson.D{{"$gt", int32(0)}}}}
+	coll := createValidatedCollection(t, ctx, db, "query", bson.E{"validator", validator})
+
+	_, err := coll.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", int32(42)}})
+	require.NoError(t, err)
+
+	_, err = coll.InsertOne(ctx, bson.D{{"_id", int32(2)}, {"v", int32(-1)}})
+	expected := bson.D{
+		{"failingDocumentId", int32(2)},
+		{"details", bson.D{
+			{"operatorName", "$gt"},
+			{"specifiedAs", bson.D{{"v", bson.D{{"$gt", int32(0)}}}}},
+			{"reason", "comparison failed"},
+			{"consideredValue", int32(-1)},
+		}},
+	}
+	AssertEqualDocuments(t, expected, writeErrorInfo(t, err))
+}
+
+func TestValidationListCollections(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	createValidatedCollection(
+		t, ctx, db, "list", bson.E{"validator", requiredNameValidator}, bson.E{"validationLevel", "moderate"},
+	)
+
+	cursor, err := db.ListCollections(ctx, bson.D{{"name", "list"}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, 1)
+
+	actual, err := ConvertDocument(t, res[0]).Get("options")
+	require.NoError(t, err)
+
+	expected := ConvertDocument(t, bson.D{
+		{"validator", requiredNameValidator},
+		{"validationLevel", "moderate"},
+		{"validationAction", "error"},
+	})
+	assert.Equal(t, expected, actual)
+}
+
+func TestValidationFind(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"name", "foo"}},
+		bson.D{{"_id", int32(2)}, {"name", int32(42)}},
+		bson.D{{"_id", int32(3)}},
+	})
+	require.NoError(t, err)
+
+	cursor, err := collection.Find(ctx, bson.D{{"$jsonSchema", requiredNameValidator[0].Value}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	AssertEqualDocumentsSlice(t, []bson.D{{{"_id", int32(1)}, {"name", "foo"}}}, res)
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		command  bson.D
+		expected mongo.CommandError
+	}{
+		"Where": {
+			command: bson.D{{"create", "where"}, {"validator", bson.D{{"$where", "true"}}}},
+			expected: mongo.CommandError{
+				Code:    224,
+				Name:    "QueryFeatureNotAllowed",
+				Message: "$where is not allowed in collection validators",
+			},
+		},
+		"Level": {
+			command: bson.D{{"create", "level"}, {"validationLevel", "foo"}},
+			expected: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Enumeration value 'foo' for field 'create.validationLevel' is not a valid value.",
+			},
+		},
+		"Schema": {
+			command: bson.D{{"create", "schema"}, {"validator", bson.D{{"$jsonSchema", bson.D{{"foo", "bar"}}}}}},
+			expected: mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Unknown $jsonSchema keyword: foo",
+			},
+		},
+		"CollModNotFound": {
+			command: bson.D{{"collMod", "notFound"}, {"validator", requiredNameValidator}},
+			expected: mongo.CommandError{
+				Code:    26,
+				Name:    "NamespaceNotFound",
+				Message: "ns does not exist: " + db.Name() + ".notFound",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.RunCommand(ctx, tc.command).Err()
+			AssertEqualCommandError(t, tc.expected, err)
+		})
+	}
+}
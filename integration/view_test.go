@@ -0,0 +1,291 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// createView creates a view on the given collection or view.
+func createView(t *testing.T, ctx context.Context, db *mongo.Database, name, viewOn string, pipeline bson.A) *mongo.Collection {
+	t.Helper()
+
+	require.NoError(t, db.RunCommand(ctx, bson.D{{"create", name}, {"viewOn", viewOn}, {"pipeline", pipeline}}).Err())
+
+	return db.Collection(name)
+}
+
+// setupViewSource inserts documents with `name`, `age` and `ssn` fields into the collection.
+func setupViewSource(t *testing.T, ctx context.Context, collection *mongo.Collection) {
+	t.Helper()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"name", "alice"}, {"age", int32(30)}, {"ssn", "111"}},
+		bson.D{{"_id", int32(2)}, {"name", "bob"}, {"age", int32(17)}, {"ssn", "222"}},
+		bson.D{{"_id", int32(3)}, {"name", "carol"}, {"age", int32(45)}, {"ssn", "333"}},
+		bson.D{{"_id", int32(4)}, {"name", "dave"}, {"age", int32(30)}, {"ssn", "444"}},
+	})
+	require.NoError(t, err)
+}
+
+// adultsPipeline is a view pipeline that hides `ssn` field and documents of minors.
+var adultsPipeline = bson.A{
+	bson.D{{"$match", bson.D{{"age", bson.D{{"$gte", int32(18)}}}}}},
+	bson.D{{"$project", bson.D{{"ssn", int32(0)}}}},
+}
+
+func TestViewRead(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	setupViewSource(t, ctx, collection)
+	view := createView(t, ctx, db, "adults", collection.Name(), adultsPipeline)
+
+	t.Run("ListCollections", func(t *testing.T) {
+		t.Parallel()
+
+		specs, err := db.ListCollectionSpecifications(ctx, bson.D{{"name", "adults"}})
+		require.NoError(t, err)
+		require.Len(t, specs, 1)
+		assert.Equal(t, "view", specs[0].Type)
+		assert.True(t, specs[0].ReadOnly)
+
+		var actual bson.D
+		require.NoError(t, bson.Unmarshal(specs[0].Options, &actual))
+
+		expected := bson.D{
+			{"viewOn", collection.Name()},
+			{"pipeline", bson.A{
+				bson.D{{"$match", bson.D{{"age", bson.D{{"$gte", int32(18)}}}}}},
+				bson.D{{"$project", bson.D{{"ssn", int32(0)}}}},
+			}},
+		}
+		AssertEqualDocuments(t, expected, actual)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Find().SetSort(bson.D{{"name", -1}}).SetLimit(2).SetProjection(bson.D{{"age", int32(0)}})
+
+		cursor, err := view.Find(ctx, bson.D{{"age", int32(30)}}, opts)
+		require.NoError(t, err)
+
+		var actual []bson.D
+		require.NoError(t, cursor.All(ctx, &actual))
+
+		expected := []bson.D{
+			{{"_id", int32(4)}, {"name", "dave"}},
+			{{"_id", int32(1)}, {"name", "alice"}},
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		require.NoError(t, db.RunCommand(ctx, bson.D{{"count", "adults"}}).Decode(&res))
+		AssertEqualDocuments(t, bson.D{{"n", int32(3)}, {"ok", float64(1)}}, res)
+
+		err := db.RunCommand(ctx, bson.D{{"count", "adults"}, {"query", bson.D{{"name", "bob"}}}}).Decode(&res)
+		require.NoError(t, err)
+		AssertEqualDocuments(t, bson.D{{"n", int32(0)}, {"ok", float64(1)}}, res)
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := view.Distinct(ctx, "ssn", bson.D{})
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+
+		actual, err = view.Distinct(ctx, "age", bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, []any{int32(30), int32(45)}, actual)
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		pipeline := bson.A{
+			bson.D{{"$group", bson.D{{"_id", "$age"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			bson.D{{"$sort", bson.D{{"_id", 1}}}},
+		}
+
+		cursor, err := view.Aggregate(ctx, pipeline)
+		require.NoError(t, err)
+
+		var actual []bson.D
+		require.NoError(t, cursor.All(ctx, &actual))
+
+		expected := []bson.D{
+			{{"_id", int32(30)}, {"count", int32(2)}},
+			{{"_id", int32(45)}, {"count", int32(1)}},
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		t.Parallel()
+
+		names := createView(t, ctx, db, "adultNames", "adults", bson.A{
+			bson.D{{"$project", bson.D{{"_id", int32(0)}, {"name", int32(1)}}}},
+			bson.D{{"$sort", bson.D{{"name", 1}}}},
+		})
+
+		cursor, err := names.Find(ctx, bson.D{})
+		require.NoError(t, err)
+
+		var actual []bson.D
+		require.NoError(t, cursor.All(ctx, &actual))
+
+		expected := []bson.D{{{"name", "alice"}}, {{"name", "carol"}}, {{"name", "dave"}}}
+		assert.Equal(t, expected, actual)
+	})
+}
+
+func TestViewWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	setupViewSource(t, ctx, collection)
+	view := createView(t, ctx, db, "adults", collection.Name(), adultsPipeline)
+
+	expected := mongo.CommandError{
+		Code:    166,
+		Name:    "CommandNotSupportedOnView",
+		Message: "Namespace " + db.Name() + ".adults is a view, not a collection",
+	}
+
+	commands := map[string]func() error{
+		"Insert": func() error {
+			_, err := view.InsertOne(ctx, bson.D{{"_id", int32(5)}})
+			return err
+		},
+		"Update": func() error {
+			_, err := view.UpdateOne(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$set", bson.D{{"age", int32(31)}}}})
+			return err
+		},
+		"Delete": func() error {
+			_, err := view.DeleteOne(ctx, bson.D{{"_id", int32(1)}})
+			return err
+		},
+		"FindAndModify": func() error {
+			return view.FindOneAndDelete(ctx, bson.D{{"_id", int32(1)}}).Err()
+		},
+		"CreateIndexes": func() error {
+			_, err := view.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"name", 1}}})
+			return err
+		},
+		"ListIndexes": func() error {
+			_, err := view.Indexes().List(ctx)
+			return err
+		},
+	}
+
+	// run parallel subtests before the view is dropped
+	t.Run("Errors", func(t *testing.T) {
+		for name, f := range commands {
+			name, f := name, f
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				AssertEqualCommandError(t, expected, f())
+			})
+		}
+	})
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+
+	t.Run("Drop", func(t *testing.T) {
+		require.NoError(t, view.Drop(ctx))
+
+		names, err := db.ListCollectionNames(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{collection.Name()}, names)
+	})
+}
+
+func TestViewCreateErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	createView(t, ctx, db, "v1", "v2", bson.A{})
+
+	for name, tc := range map[string]struct {
+		command  bson.D
+		expected mongo.CommandError
+	}{
+		"PipelineWithoutViewOn": {
+			command: bson.D{{"create", "v"}, {"pipeline", bson.A{}}},
+			expected: mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "'pipeline' requires 'viewOn' to also be specified",
+			},
+		},
+		"PipelineStage": {
+			command: bson.D{{"create", "v"}, {"viewOn", "c"}, {"pipeline", bson.A{"$match"}}},
+			expected: mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "Each element of the 'pipeline' array must be an object",
+			},
+		},
+		"Cycle": {
+			command: bson.D{{"create", "v2"}, {"viewOn", "v1"}},
+			expected: mongo.CommandError{
+				Code: 160,
+				Name: "GraphContainsCycle",
+				Message: "View cycle detected: " +
+					db.Name() + ".v2 => " + db.Name() + ".v1 => " + db.Name() + ".v2",
+			},
+		},
+		"Exists": {
+			command: bson.D{{"create", "v1"}, {"viewOn", collection.Name()}},
+			expected: mongo.CommandError{
+				Code:    48,
+				Name:    "NamespaceExists",
+				Message: "Collection " + db.Name() + ".v1 already exists.",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.RunCommand(ctx, tc.command).Err()
+			AssertEqualCommandError(t, tc.expected, err)
+		})
+	}
+}
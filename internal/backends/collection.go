@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -48,6 +49,7 @@ type Collection interface {
 	InsertAll(context.Context, *InsertAllParams) (*InsertAllResult, error)
 	UpdateAll(context.Context, *UpdateAllParams) (*UpdateAllResult, error)
 	DeleteAll(context.Context, *DeleteAllParams) (*DeleteAllResult, error)
+	ModifyAll(context.Context, *ModifyAllParams) (*ModifyAllResult, error)
 
 	Stats(context.Context, *CollectionStatsParams) (*CollectionStatsResult, error)
 	Compact(context.Context, *CompactParams) (*CompactResult, error)
@@ -55,6 +57,7 @@ type Collection interface {
 	ListIndexes(context.Context, *ListIndexesParams) (*ListIndexesResult, error)
 	CreateIndexes(context.Context, *CreateIndexesParams) (*CreateIndexesResult, error)
 	DropIndexes(context.Context, *DropIndexesParams) (*DropIndexesResult, error)
+	ModifyIndex(context.Context, *ModifyIndexParams) (*ModifyIndexResult, error)
 }
 
 // collectionContract implements Collection interface.
@@ -81,11 +84,56 @@ type QueryParams struct {
 	Limit         int64
 	OnlyRecordIDs bool
 	Comment       string
+	Group         *GroupParams
+
+	// TextSearch is set for `$text` queries.
+	TextSearch *TextSearchParams
+
+	// Geo is set for geospatial queries.
+	Geo *GeoParams
+
+	// Hint is the name of the index that the backend should use, if possible.
+	// The handler checks that the index exists and is not hidden.
+	Hint string
+
+	// Skip is the number of documents to skip before Limit is applied (see Query).
+	Skip int64
+
+	// Projection contains top-level fields that should be returned; other fields may be omitted.
+	// All fields are returned if it is empty.
+	// The handler includes all fields used by Filter and Sort, and applies the projection itself.
+	Projection []string
+}
+
+// TextSearchParams represents the part of `$text` query operator that backends could apply
+// using the collection's text index.
+//
+// Backends may skip documents that don't contain any of Words.
+// The handler matches and scores returned documents itself.
+type TextSearchParams struct {
+	// Words contains unique lowercase words without stop words.
+	Words []string
+}
+
+// GeoParams represents the part of geospatial query operators that backends could apply
+// using the collection's geospatial index on Field.
+//
+// Backends may skip documents with point locations outside of the box.
+// The handler matches returned documents itself.
+type GeoParams struct {
+	Field      string
+	MinX, MinY float64
+	MaxX, MaxY float64
 }
 
 // QueryResult represents the results of Collection.Query method.
 type QueryResult struct {
-	Iter types.DocumentsIterator
+	Iter          types.DocumentsIterator
+	GroupPushdown bool
+	SkipPushdown  bool
+
+	// Index is the name of the index used by the backend to filter or sort documents, if any.
+	Index string
 }
 
 // Query executes a query against the collection.
@@ -97,6 +145,16 @@ type QueryResult struct {
 // but doing so is not necessary - the handler will do that anyway.
 //
 // Passed sort document should be already validated. If sort document is invalid, function panics.
+//
+// If Group is set, Sort and Limit should not be set, and Filter should contain the whole `$match` stage.
+// If the backend could apply both filtering and grouping, the QueryResult's GroupPushdown field is set to true,
+// and the iterator returns grouped documents (in any order).
+// Otherwise, that field is set to false, and the iterator returns documents as if Group was not set.
+//
+// If Skip or Limit is set, Sort should not be set.
+// Backends apply Skip and Limit only if they could apply the whole Filter exactly;
+// in that case, the QueryResult's SkipPushdown field is set to true.
+// Otherwise, that field is set to false, and the iterator returns documents as if Skip and Limit were not set.
 func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*QueryResult, error) {
 	defer observability.FuncCall(ctx)()
 
@@ -104,6 +162,15 @@ func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*
 		params = new(QueryParams)
 	}
 
+	if params.Group != nil {
+		must.BeTrue(params.Sort.Len() == 0 && params.Limit == 0 && params.Skip == 0 && !params.OnlyRecordIDs)
+		must.BeTrue(params.Group.Key != "" || !params.Group.SkipMissingKey)
+	}
+
+	if params.Skip != 0 || params.Limit != 0 {
+		must.BeTrue(params.Sort.Len() == 0)
+	}
+
 	if params.Sort.Len() != 0 {
 		iter := params.Sort.Iterator()
 		defer iter.Close()
@@ -136,7 +203,15 @@ func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*
 type ExplainParams struct {
 	Filter *types.Document
 	Sort   *types.Document
+	Skip   int64
 	Limit  int64
+	Group  *GroupParams
+
+	// Update contains update operators that could be pushed down to the backend (see UpdateAllParams).
+	Update []UpdateOperator
+
+	// Hint is the name of the index that the backend should use, if possible (see QueryParams).
+	Hint string
 }
 
 // ExplainResult represents the results of Collection.Explain method.
@@ -144,7 +219,14 @@ type ExplainResult struct {
 	QueryPlanner   *types.Document
 	FilterPushdown bool
 	SortPushdown   bool
+	SkipPushdown   bool
 	LimitPushdown  bool
+	GroupPushdown  bool
+	GroupFallback  bool
+	UpdatePushdown bool
+
+	// Index is the name of the index the backend would use to filter or sort documents, if any.
+	Index string
 }
 
 // Explain return a backend-specific execution plan for the given query.
@@ -158,6 +240,17 @@ type ExplainResult struct {
 //
 // The ExplainResult's SortPushdown field is set to true if the backend could have applied the whole requested sorting.
 // If it was possible to apply it only partially or not at all, that field should be set to false.
+//
+// The ExplainResult's SkipPushdown and LimitPushdown fields are set to true if the backend could have applied
+// the requested skipping and limiting (see Query).
+//
+// The ExplainResult's GroupPushdown field is set to true if the backend could have applied
+// both the whole requested filtering and grouping.
+// Even then, Query may return ungrouped documents if their values can't be grouped by the backend
+// (see GroupParams.MayFallBack); the ExplainResult's GroupFallback field is set to true in that case.
+//
+// The ExplainResult's UpdatePushdown field is set to true if the backend could have applied
+// both the whole requested filtering and all update operators, provided that documents are suitable for them.
 func (cc *collectionContract) Explain(ctx context.Context, params *ExplainParams) (*ExplainResult, error) {
 	defer observability.FuncCall(ctx)()
 
@@ -229,11 +322,26 @@ func (cc *collectionContract) InsertAll(ctx context.Context, params *InsertAllPa
 // UpdateAllParams represents the parameters of Collection.Update method.
 type UpdateAllParams struct {
 	Docs []*types.Document
+
+	// Filter, Update and Multi are set instead of Docs for updates that could be pushed down to the backend.
+	Filter *types.Document
+	Update []UpdateOperator
+	Multi  bool
+}
+
+// UpdateOperator represents a single field of `$set`, `$unset` or `$inc` update operator
+// that could be pushed down to the backend.
+type UpdateOperator struct {
+	Operator string     // $set, $unset or $inc
+	Path     types.Path // top-level or dotted field path, not _id
+	Value    any        // scalar value for $set, int32 or int64 value for $inc, nil for $unset
 }
 
 // UpdateAllResult represents the results of Collection.Update method.
 type UpdateAllResult struct {
-	Updated int32
+	Updated        int32
+	Matched        int32
+	UpdatePushdown bool
 }
 
 // UpdateAll updates documents in collection.
@@ -245,10 +353,22 @@ type UpdateAllResult struct {
 // All documents are expected to be valid and include _id fields.
 // They will be frozen.
 //
+// If Update is set, Docs should not be set.
+// Update operators are applied in order to documents matching the whole Filter
+// (only to the first one if Multi is false), and never conflict with each other.
+// Backends apply them only if they could apply both filtering and all operators exactly,
+// the same way as the handler does; in that case, the UpdateAllResult's UpdatePushdown field is set to true,
+// Matched contains the number of matching documents, and Updated contains the number of changed ones.
+// Otherwise, that field is set to false, nothing is updated, and the handler updates documents itself.
+//
 // Database or collection may not exist; that's not an error.
 func (cc *collectionContract) UpdateAll(ctx context.Context, params *UpdateAllParams) (*UpdateAllResult, error) {
 	defer observability.FuncCall(ctx)()
 
+	if len(params.Update) > 0 {
+		must.BeTrue(len(params.Docs) == 0)
+	}
+
 	for _, doc := range params.Docs {
 		doc.Freeze()
 	}
@@ -263,17 +383,27 @@ func (cc *collectionContract) UpdateAll(ctx context.Context, params *UpdateAllPa
 type DeleteAllParams struct {
 	IDs       []any
 	RecordIDs []int64
+
+	// Filter and Limited are set instead of IDs and RecordIDs for deletes that could be pushed down to the backend.
+	Filter  *types.Document
+	Limited bool
 }
 
 // DeleteAllResult represents the results of Collection.Delete method.
 type DeleteAllResult struct {
-	Deleted int32
+	Deleted        int32
+	FilterPushdown bool
 }
 
 // DeleteAll deletes documents in collection.
 //
 // Passed IDs may contain duplicates or point to non-existing documents.
 //
+// If Filter is set, documents matching the whole filter are deleted (only the first one if Limited is true).
+// Backends delete them only if they could apply filtering exactly, the same way as the handler does;
+// in that case, the DeleteAllResult's FilterPushdown field is set to true.
+// Otherwise, that field is set to false, nothing is deleted, and the handler deletes documents by IDs.
+//
 // The operation should be atomic.
 // If some documents cannot be deleted, the operation should be rolled back,
 // and the first encountered error should be returned.
@@ -282,7 +412,14 @@ type DeleteAllResult struct {
 func (cc *collectionContract) DeleteAll(ctx context.Context, params *DeleteAllParams) (*DeleteAllResult, error) {
 	defer observability.FuncCall(ctx)()
 
-	must.BeTrue((params.IDs == nil) != (params.RecordIDs == nil))
+	var set int
+	for _, b := range []bool{params.IDs != nil, params.RecordIDs != nil, params.Filter != nil} {
+		if b {
+			set++
+		}
+	}
+
+	must.BeTrue(set == 1)
 
 	res, err := cc.c.DeleteAll(ctx, params)
 	checkError(err)
@@ -290,6 +427,90 @@ func (cc *collectionContract) DeleteAll(ctx context.Context, params *DeleteAllPa
 	return res, err
 }
 
+// ModifyAllParams represents the parameters of Collection.ModifyAll method.
+type ModifyAllParams struct {
+	Filter *types.Document
+	Hint   string
+
+	// Modify returns changes for documents returned by the query.
+	// It may use the given function to query other documents in the same transaction.
+	Modify func(iter types.DocumentsIterator, query ModifyQueryFunc) (*ModifyChanges, error)
+}
+
+// ModifyQueryFunc returns documents matching the filter as seen by the ModifyAll transaction.
+//
+// As with Query, the filter may be applied partially or not at all,
+// so the caller should filter returned documents itself.
+// The returned iterator should be closed before the next call.
+type ModifyQueryFunc func(filter *types.Document) (types.DocumentsIterator, error)
+
+// ModifyChanges represents changes returned by ModifyAllParams.Modify function.
+type ModifyChanges struct {
+	Insert []*types.Document
+	Update []*types.Document
+	Delete []any // _id values
+}
+
+// ModifyAllResult represents the results of Collection.ModifyAll method.
+type ModifyAllResult struct {
+	Index    string
+	Inserted int32
+	Updated  int32
+	Deleted  int32
+}
+
+// ModifyAll atomically finds documents and changes them.
+//
+// Documents returned by the query with the given Filter and Hint (see Query for details)
+// are passed to the Modify function, and changes returned by it are applied in the same transaction.
+// Concurrent ModifyAll calls can't change those documents or insert new ones
+// until that transaction ends, so read-modify-write cycles like upserts are not interleaved.
+// The Modify function may be called more than once; only changes returned by the last call are applied.
+// It should not call backend methods, as they could wait for the transaction to end;
+// the query function passed to it should be used instead.
+// Concurrent ModifyAll calls that use that function are not interleaved either.
+// Errors returned by it are returned as is, and nothing is changed in that case.
+//
+// Inserted and updated documents are expected to be valid and include _id fields.
+// They will be frozen.
+// If some inserted document has the same _id as the existing one, ErrorCodeInsertDuplicateID is returned.
+//
+// Database or collection may not exist; they are created automatically if there are documents to insert.
+func (cc *collectionContract) ModifyAll(ctx context.Context, params *ModifyAllParams) (*ModifyAllResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	modify := params.Modify
+	p := *params
+
+	p.Modify = func(iter types.DocumentsIterator, query ModifyQueryFunc) (*ModifyChanges, error) {
+		changes, err := modify(iter, query)
+		if err != nil {
+			return nil, err
+		}
+
+		if changes == nil {
+			changes = new(ModifyChanges)
+		}
+
+		now := time.Now()
+		for _, doc := range changes.Insert {
+			doc.SetRecordID(types.NextTimestamp(now).Signed())
+			doc.Freeze()
+		}
+
+		for _, doc := range changes.Update {
+			doc.Freeze()
+		}
+
+		return changes, nil
+	}
+
+	res, err := cc.c.ModifyAll(ctx, &p)
+	checkError(err, ErrorCodeInsertDuplicateID)
+
+	return res, err
+}
+
 // CollectionStatsParams represents the parameters of Collection.Stats method.
 type CollectionStatsParams struct {
 	Refresh bool
@@ -359,12 +580,230 @@ type IndexInfo struct {
 	Name   string
 	Key    []IndexKeyPair
 	Unique bool
+
+	// Text contains options of the text index; it is nil for other indexes.
+	Text *TextIndexOptions
+
+	// Geo contains options of the geospatial index; it is nil for other indexes.
+	Geo *GeoIndexOptions
+
+	// ExpireAfterSeconds is set for TTL indexes.
+	ExpireAfterSeconds *int32
+
+	// Hidden is set for indexes that are maintained, but not used by queries.
+	Hidden bool
+
+	// PrepareUnique is set when the index is being converted to the unique one;
+	// new duplicate keys are rejected, but existing ones are allowed.
+	PrepareUnique bool
+
+	// Sparse is set for indexes that contain only documents with at least one of the indexed fields.
+	Sparse bool
+
+	// PartialFilterExpression is set for indexes that contain only documents matching it.
+	// See PartialFilterConditions for the supported subset of query operators.
+	PartialFilterExpression *types.Document
+
+	// WildcardProjection is set for wildcard indexes with `$**` key that include or exclude some fields.
+	// See WildcardIndexCovers for details.
+	WildcardProjection *types.Document
+}
+
+// PartialFilterCondition represents a single condition of the partial index filter expression.
+type PartialFilterCondition struct {
+	Path     types.Path
+	Operator string // $eq, $exists, $gt, $gte, $lt, or $lte
+	Value    any    // string, bool, int32, int64, or float64; not set for $exists
+}
+
+// PartialFilterConditions returns conditions of the partial index filter expression;
+// the document matches it when all conditions are true.
+//
+// The expression should be validated by the handler; it may contain only:
+//   - field conditions with explicit or implicit $eq with string, bool, or number values;
+//   - field conditions with $gt, $gte, $lt, or $lte with number values;
+//   - field conditions with {$exists: true};
+//   - top-level $and of the above.
+//
+// Backends translate conditions to SQL; like with index keys, array elements are not matched.
+func PartialFilterConditions(filter *types.Document) []PartialFilterCondition {
+	var res []PartialFilterCondition
+
+	values := filter.Values()
+
+	for i, field := range filter.Keys() {
+		if field == "$and" {
+			iter := values[i].(*types.Array).Iterator()
+
+			for _, v := range must.NotFail(iterator.ConsumeValues(iter)) {
+				res = append(res, PartialFilterConditions(v.(*types.Document))...)
+			}
+
+			continue
+		}
+
+		path := must.NotFail(types.NewPathFromString(field))
+
+		expr, ok := values[i].(*types.Document)
+		if !ok {
+			res = append(res, PartialFilterCondition{Path: path, Operator: "$eq", Value: values[i]})
+			continue
+		}
+
+		exprValues := expr.Values()
+
+		for j, op := range expr.Keys() {
+			c := PartialFilterCondition{Path: path, Operator: op}
+			if op != "$exists" {
+				c.Value = exprValues[j]
+			}
+
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+// IsWildcardIndexKey returns true if the given index key field is `$**` or ends with `.$**`.
+func IsWildcardIndexKey(field string) bool {
+	return field == "$**" || strings.HasSuffix(field, ".$**")
+}
+
+// WildcardIndexCovers returns true if the wildcard index with the given key field and projection
+// contains all values of the given top-level field, including array elements.
+//
+// `_id` is never covered, as it is always indexed by the default index.
+func WildcardIndexCovers(keyField string, projection *types.Document, field string) bool {
+	if field == "_id" || strings.ContainsRune(field, '.') {
+		return false
+	}
+
+	if keyField != "$**" {
+		return keyField == field+".$**"
+	}
+
+	if projection == nil {
+		return true
+	}
+
+	// projection with `_id` only includes or excludes it
+	inclusion, included := false, false
+	if id, _ := projection.Get("_id"); id != nil {
+		inclusion = wildcardProjectionValue(id)
+	}
+
+	values := projection.Values()
+
+	for i, k := range projection.Keys() {
+		if k == "_id" {
+			continue
+		}
+
+		v := wildcardProjectionValue(values[i])
+		inclusion = v
+
+		if k == field {
+			included = v
+			continue
+		}
+
+		// excluding a subfield excludes some values of the field
+		if !v && strings.HasPrefix(k, field+".") {
+			return false
+		}
+	}
+
+	if inclusion {
+		return included
+	}
+
+	return !projection.Has(field)
+}
+
+// WildcardIndexFields returns top-level fields that are covered by the wildcard index
+// with the given key field and projection and could be listed explicitly.
+//
+// It returns nil for indexes that cover all fields except excluded ones.
+func WildcardIndexFields(keyField string, projection *types.Document) []string {
+	if keyField != "$**" {
+		if field := strings.TrimSuffix(keyField, ".$**"); WildcardIndexCovers(keyField, projection, field) {
+			return []string{field}
+		}
+
+		return nil
+	}
+
+	if projection == nil {
+		return nil
+	}
+
+	var res []string
+
+	for _, k := range projection.Keys() {
+		if WildcardIndexCovers(keyField, projection, k) {
+			res = append(res, k)
+		}
+	}
+
+	return res
+}
+
+// wildcardProjectionValue returns true if the given wildcardProjection value includes the field.
+func wildcardProjectionValue(v any) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+
+	return types.Compare(v, int32(0)) != types.Equal
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
+//
+// For special index keys, Type is set and Descending is not used.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+	Type       IndexKeyType
+}
+
+// IndexKeyType represents a type of special index key.
+type IndexKeyType string
+
+const (
+	// IndexKeyTypeText is a type of text index key.
+	IndexKeyTypeText IndexKeyType = "text"
+
+	// IndexKeyType2DSphere is a type of geospatial index key with spherical geometry.
+	IndexKeyType2DSphere IndexKeyType = "2dsphere"
+
+	// IndexKeyType2D is a type of geospatial index key with flat geometry.
+	IndexKeyType2D IndexKeyType = "2d"
+)
+
+// GeoIndexOptions represents options of the geospatial index.
+type GeoIndexOptions struct {
+	// Version is 2dsphereIndexVersion of 2dsphere index; it is 0 for 2d index.
+	Version int32
+
+	// Bits, Min, and Max are set only if specified for 2d index.
+	Bits int32
+	Min  *float64
+	Max  *float64
+}
+
+// TextIndexOptions represents options of the text index.
+type TextIndexOptions struct {
+	// Weights contains all indexed fields sorted by name.
+	Weights          []TextIndexWeight
+	DefaultLanguage  string
+	LanguageOverride string
+}
+
+// TextIndexWeight represents a weight of the field in the text index.
+type TextIndexWeight struct {
+	Field  string
+	Weight int32
 }
 
 // ListIndexes returns a list of collection indexes.
@@ -433,6 +872,35 @@ func (cc *collectionContract) DropIndexes(ctx context.Context, params *DropIndex
 	return res, err
 }
 
+// ModifyIndexParams represents the parameters of Collection.ModifyIndex method.
+type ModifyIndexParams struct {
+	Name               string
+	ExpireAfterSeconds *int32 // nil leaves it unchanged
+	Hidden             *bool  // nil leaves it unchanged
+	PrepareUnique      *bool  // nil leaves it unchanged
+	Unique             bool   // true converts the index to the unique one
+}
+
+// ModifyIndexResult represents the results of Collection.ModifyIndex method.
+type ModifyIndexResult struct{}
+
+// ModifyIndex modifies options of the existing index.
+//
+// Converting the index to the unique one fails if there are duplicate keys;
+// the handler is responsible for checking that beforehand.
+//
+// The operation should be atomic.
+// If the collection or index does not exist, it returns
+// ErrorCodeCollectionDoesNotExist or ErrorCodeIndexDoesNotExist error.
+func (cc *collectionContract) ModifyIndex(ctx context.Context, params *ModifyIndexParams) (*ModifyIndexResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	res, err := cc.c.ModifyIndex(ctx, params)
+	checkError(err, ErrorCodeCollectionDoesNotExist, ErrorCodeIndexDoesNotExist)
+
+	return res, err
+}
+
 // check interfaces
 var (
 	_ Collection = (*collectionContract)(nil)
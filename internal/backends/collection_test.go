@@ -18,6 +18,7 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/AlekSi/pointer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -113,10 +114,6 @@ func TestCollectionInsertAllQueryExplain(t *testing.T) {
 			})
 
 			t.Run("CappedCollectionSortAsc", func(t *testing.T) {
-				if name == "sqlite" {
-					t.Skip("https://github.com/FerretDB/FerretDB/issues/3181")
-				}
-
 				t.Parallel()
 
 				sort := must.NotFail(types.NewDocument("_id", int64(1)))
@@ -137,10 +134,6 @@ func TestCollectionInsertAllQueryExplain(t *testing.T) {
 			})
 
 			t.Run("CappedCollectionSortDesc", func(t *testing.T) {
-				if name == "sqlite" {
-					t.Skip("https://github.com/FerretDB/FerretDB/issues/3181")
-				}
-
 				t.Parallel()
 
 				sort := must.NotFail(types.NewDocument("_id", int64(-1)))
@@ -200,6 +193,61 @@ func TestCollectionInsertAllQueryExplain(t *testing.T) {
 	}
 }
 
+func TestCollectionQuerySkipLimitPushdown(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			docs := []*types.Document{
+				must.NotFail(types.NewDocument("_id", int32(1), "v", int32(42))),
+				must.NotFail(types.NewDocument("_id", int32(2), "v", "42")),
+				must.NotFail(types.NewDocument("_id", int32(3), "v", 42.0)),
+				must.NotFail(types.NewDocument("_id", int32(4), "v", must.NotFail(types.NewArray(int64(42))))),
+				must.NotFail(types.NewDocument("_id", int32(5), "v", int32(43))),
+				must.NotFail(types.NewDocument("_id", int32(6), "v", int64(42))),
+			}
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+			require.NoError(t, err)
+
+			filter := must.NotFail(types.NewDocument("v", int32(42)))
+
+			res, err := coll.Query(ctx, &backends.QueryParams{Filter: filter, Skip: 1, Limit: 2})
+			require.NoError(t, err)
+			assert.True(t, res.SkipPushdown)
+
+			actual, err := iterator.ConsumeValues(res.Iter)
+			require.NoError(t, err)
+
+			ids := make([]any, len(actual))
+			for i, doc := range actual {
+				ids[i] = must.NotFail(doc.Get("_id"))
+			}
+
+			assert.Equal(t, []any{int32(3), int32(4)}, ids)
+
+			explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: filter, Skip: 1, Limit: 2})
+			require.NoError(t, err)
+			assert.True(t, explainRes.SkipPushdown)
+			assert.True(t, explainRes.LimitPushdown)
+		})
+	}
+}
+
 func TestCappedCollectionInsertAllDeleteAll(t *testing.T) {
 	t.Parallel()
 
@@ -256,6 +304,54 @@ func TestCappedCollectionInsertAllDeleteAll(t *testing.T) {
 	}
 }
 
+func TestCollectionInsertAllLargeBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			docs := make([]*types.Document, 1000)
+			for i := range docs {
+				docs[i] = must.NotFail(types.NewDocument("_id", int32(i), "v", "foo"))
+			}
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+			require.NoError(t, err)
+
+			// the whole batch is not inserted if the last document is a duplicate
+			dups := make([]*types.Document, 500)
+			for i := range dups {
+				dups[i] = must.NotFail(types.NewDocument("_id", int32(len(docs)+i)))
+			}
+
+			dups[len(dups)-1] = must.NotFail(types.NewDocument("_id", int32(0)))
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: dups})
+			assertErrorCode(t, err, backends.ErrorCodeInsertDuplicateID)
+
+			res, err := coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			actual, err := iterator.ConsumeValues[struct{}, *types.Document](res.Iter)
+			require.NoError(t, err)
+			require.Len(t, actual, len(docs))
+		})
+	}
+}
+
 func TestCollectionUpdateAll(t *testing.T) {
 	t.Parallel()
 
@@ -495,3 +591,125 @@ func TestCollectionCompact(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectionModifyIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName, collName := testutil.DatabaseName(t), testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			_, err = coll.ModifyIndex(ctx, &backends.ModifyIndexParams{Name: "v_1", Hidden: pointer.ToBool(true)})
+			assertErrorCode(t, err, backends.ErrorCodeCollectionDoesNotExist)
+
+			_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+				Indexes: []backends.IndexInfo{{
+					Name: "v_1",
+					Key:  []backends.IndexKeyPair{{Field: "v"}},
+				}},
+			})
+			require.NoError(t, err)
+
+			_, err = coll.ModifyIndex(ctx, &backends.ModifyIndexParams{Name: "foo", Hidden: pointer.ToBool(true)})
+			assertErrorCode(t, err, backends.ErrorCodeIndexDoesNotExist)
+
+			_, err = coll.ModifyIndex(ctx, &backends.ModifyIndexParams{
+				Name:               "v_1",
+				ExpireAfterSeconds: pointer.ToInt32(60),
+				Hidden:             pointer.ToBool(true),
+				PrepareUnique:      pointer.ToBool(true),
+			})
+			require.NoError(t, err)
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{must.NotFail(types.NewDocument("_id", int32(1), "v", int32(42)))},
+			})
+			require.NoError(t, err)
+
+			_, err = coll.ModifyIndex(ctx, &backends.ModifyIndexParams{Name: "v_1", Unique: true})
+			require.NoError(t, err)
+
+			res, err := coll.ListIndexes(ctx, nil)
+			require.NoError(t, err)
+
+			expected := backends.IndexInfo{
+				Name:               "v_1",
+				Key:                []backends.IndexKeyPair{{Field: "v"}},
+				Unique:             true,
+				ExpireAfterSeconds: pointer.ToInt32(60),
+				Hidden:             true,
+				PrepareUnique:      true,
+			}
+			require.Len(t, res.Indexes, 2)
+			assert.Equal(t, expected, res.Indexes[1])
+
+			// the index is unique now
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{must.NotFail(types.NewDocument("_id", int32(2), "v", int32(42)))},
+			})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestWildcardIndexCovers(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		keyField   string
+		projection *types.Document
+		covered    []string
+	}{
+		"All": {
+			keyField: "$**",
+			covered:  []string{"a", "b", "c"},
+		},
+		"Path": {
+			keyField: "a.$**",
+			covered:  []string{"a"},
+		},
+		"NestedPath": {
+			keyField: "a.b.$**",
+		},
+		"Inclusion": {
+			keyField:   "$**",
+			projection: must.NotFail(types.NewDocument("a", int32(1), "b.c", true, "_id", false)),
+			covered:    []string{"a"},
+		},
+		"Exclusion": {
+			keyField:   "$**",
+			projection: must.NotFail(types.NewDocument("a", false, "b.c", int32(0))),
+			covered:    []string{"c"},
+		},
+		"IDOnly": {
+			keyField:   "$**",
+			projection: must.NotFail(types.NewDocument("_id", int32(1))),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+
+			for _, field := range []string{"_id", "a", "b", "c", "a.b"} {
+				if backends.WildcardIndexCovers(tc.keyField, tc.projection, field) {
+					actual = append(actual, field)
+				}
+			}
+
+			assert.Equal(t, tc.covered, actual)
+		})
+	}
+}
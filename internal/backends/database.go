@@ -19,6 +19,7 @@ import (
 	"context"
 	"slices"
 
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/observability"
 )
@@ -39,6 +40,7 @@ type Database interface {
 	CreateCollection(context.Context, *CreateCollectionParams) error
 	DropCollection(context.Context, *DropCollectionParams) error
 	RenameCollection(context.Context, *RenameCollectionParams) error
+	ModifyCollection(context.Context, *ModifyCollectionParams) error
 
 	Stats(context.Context, *DatabaseStatsParams) (*DatabaseStatsResult, error)
 }
@@ -92,7 +94,41 @@ type CollectionInfo struct {
 	UUID            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+	Validation      *CollectionValidation
+	View            *CollectionView // nil for regular collections
+	_               struct{}        // prevent unkeyed literals
+}
+
+// CollectionView represents the definition of the read-only view.
+//
+// Backends only store it; views are resolved by the handler.
+type CollectionView struct {
+	ViewOn   string       // name of the collection or view the view is defined on
+	Pipeline *types.Array // aggregation pipeline applied to documents of ViewOn
+}
+
+// CollectionValidation represents document validation settings of the collection.
+//
+// Backends only store them; documents are validated by the handler.
+type CollectionValidation struct {
+	Validator *types.Document
+	Level     string
+	Action    string
+}
+
+// DeepCopy returns a deep copy.
+func (cv *CollectionValidation) DeepCopy() *CollectionValidation {
+	if cv == nil {
+		return nil
+	}
+
+	res := *cv
+
+	if cv.Validator != nil {
+		res.Validator = cv.Validator.DeepCopy()
+	}
+
+	return &res
 }
 
 // Capped returns true if collection is capped.
@@ -131,7 +167,9 @@ type CreateCollectionParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+	Validation      *CollectionValidation
+	View            *CollectionView // creates a view instead of a regular collection
+	_               struct{}        // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -149,6 +187,12 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 	must.BeTrue(params.CappedSize%256 == 0)
 	must.BeTrue(params.CappedDocuments >= 0)
 
+	if params.View != nil {
+		must.BeTrue(!params.Capped())
+		must.BeTrue(params.View.ViewOn != "")
+		must.NotBeZero(params.View.Pipeline)
+	}
+
 	err := validateCollectionName(params.Name)
 	if err == nil {
 		err = dbc.db.CreateCollection(ctx, params)
@@ -207,6 +251,43 @@ func (dbc *databaseContract) RenameCollection(ctx context.Context, params *Renam
 	return err
 }
 
+// ModifyCollectionParams represents the parameters of Database.ModifyCollection method.
+type ModifyCollectionParams struct {
+	Name       string
+	Validation *CollectionValidation // nil leaves validation settings unchanged
+
+	// For capped collections only.
+	CappedSize      *int64 // nil leaves it unchanged
+	CappedDocuments *int64 // nil leaves it unchanged
+
+	_ struct{} // prevent unkeyed literals
+}
+
+// ModifyCollection modifies settings of existing collection with valid name in the database.
+//
+// The errors for non-existing database and non-existing collection are the same.
+func (dbc *databaseContract) ModifyCollection(ctx context.Context, params *ModifyCollectionParams) error {
+	defer observability.FuncCall(ctx)()
+
+	if params.CappedSize != nil {
+		must.BeTrue(*params.CappedSize > 0)
+		must.BeTrue(*params.CappedSize%256 == 0)
+	}
+
+	if params.CappedDocuments != nil {
+		must.BeTrue(*params.CappedDocuments >= 0)
+	}
+
+	err := validateCollectionName(params.Name)
+	if err == nil {
+		err = dbc.db.ModifyCollection(ctx, params)
+	}
+
+	checkError(err, ErrorCodeCollectionNameIsInvalid, ErrorCodeCollectionDoesNotExist)
+
+	return err
+}
+
 // DatabaseStatsParams represents the parameters of Database.Stats method.
 type DatabaseStatsParams struct {
 	Refresh bool
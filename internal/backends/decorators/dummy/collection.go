@@ -50,6 +50,11 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	return c.c.DeleteAll(ctx, params)
 }
 
+// ModifyAll implements backends.Collection interface.
+func (c *collection) ModifyAll(ctx context.Context, params *backends.ModifyAllParams) (*backends.ModifyAllResult, error) {
+	return c.c.ModifyAll(ctx, params)
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	return c.c.Explain(ctx, params)
@@ -80,6 +85,11 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return c.c.DropIndexes(ctx, params)
 }
 
+// ModifyIndex implements backends.Collection interface.
+func (c *collection) ModifyIndex(ctx context.Context, params *backends.ModifyIndexParams) (*backends.ModifyIndexResult, error) {
+	return c.c.ModifyIndex(ctx, params)
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -62,6 +62,11 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return db.db.RenameCollection(ctx, params)
 }
 
+// ModifyCollection implements backends.Database interface.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	return db.db.ModifyCollection(ctx, params)
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	return db.db.Stats(ctx, params)
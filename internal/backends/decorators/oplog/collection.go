@@ -103,6 +103,11 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
 	defer observability.FuncCall(ctx)()
 
+	// updated documents are needed for OpLog entries, so the handler should update them
+	if len(params.Update) > 0 && c.oplogCollection(ctx) != nil {
+		return new(backends.UpdateAllResult), nil
+	}
+
 	res, err := c.origC.UpdateAll(ctx, params)
 	if err != nil {
 		return nil, err
@@ -143,6 +148,11 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
 	defer observability.FuncCall(ctx)()
 
+	// deleted IDs are needed for OpLog entries, so the handler should delete documents by them
+	if params.Filter != nil && c.oplogCollection(ctx) != nil {
+		return new(backends.DeleteAllResult), nil
+	}
+
 	res, err := c.origC.DeleteAll(ctx, params)
 	if err != nil {
 		return nil, err
@@ -184,6 +194,66 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	return res, nil
 }
 
+// ModifyAll implements backends.Collection interface.
+func (c *collection) ModifyAll(ctx context.Context, params *backends.ModifyAllParams) (*backends.ModifyAllResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	// only changes returned by the last call are applied
+	var changes *backends.ModifyChanges
+
+	p := *params
+	p.Modify = func(iter types.DocumentsIterator, query backends.ModifyQueryFunc) (*backends.ModifyChanges, error) {
+		var err error
+		changes, err = params.Modify(iter, query)
+
+		return changes, err
+	}
+
+	res, err := c.origC.ModifyAll(ctx, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes == nil {
+		return res, nil
+	}
+
+	if oplogC := c.oplogCollection(ctx); oplogC != nil {
+		ns := c.dbName + "." + c.name
+		ds := make([]*document, 0, len(changes.Delete)+len(changes.Update)+len(changes.Insert))
+
+		for _, id := range changes.Delete {
+			ds = append(ds, &document{o: must.NotFail(types.NewDocument("_id", id)), ns: ns, op: "d"})
+		}
+
+		for _, doc := range changes.Update {
+			ds = append(ds, &document{o: doc, ns: ns, op: "u"})
+		}
+
+		for _, doc := range changes.Insert {
+			ds = append(ds, &document{o: doc, ns: ns, op: "i"})
+		}
+
+		oplogDocs := make([]*types.Document, len(ds))
+		now := time.Now()
+
+		for i, d := range ds {
+			if oplogDocs[i], err = d.marshal(now); err != nil {
+				c.l.Error("Failed to create document", zap.Error(err))
+				return res, nil
+			}
+		}
+
+		if len(oplogDocs) > 0 {
+			if _, err = oplogC.InsertAll(ctx, &backends.InsertAllParams{Docs: oplogDocs}); err != nil {
+				c.l.Error("Failed to insert documents", zap.Error(err))
+			}
+		}
+	}
+
+	return res, nil
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	return c.origC.Explain(ctx, params)
@@ -214,6 +284,11 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return c.origC.DropIndexes(ctx, params)
 }
 
+// ModifyIndex implements backends.Collection interface.
+func (c *collection) ModifyIndex(ctx context.Context, params *backends.ModifyIndexParams) (*backends.ModifyIndexResult, error) {
+	return c.origC.ModifyIndex(ctx, params)
+}
+
 // oplogCollection returns the OpLog collection if it exist.
 func (c *collection) oplogCollection(ctx context.Context) backends.Collection {
 	db := must.NotFail(c.origB.Database(oplogDatabase))
@@ -72,6 +72,11 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return db.origDB.RenameCollection(ctx, params)
 }
 
+// ModifyCollection implements backends.Database interface.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	return db.origDB.ModifyCollection(ctx, params)
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	return db.origDB.Stats(ctx, params)
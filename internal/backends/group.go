@@ -0,0 +1,264 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backends
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// GroupParams represents the parameters of `$group` stage that could be pushed down to the backend.
+type GroupParams struct {
+	// Key is a top-level field which values are used as group keys.
+	// If empty, all documents belong to a single group with null key.
+	Key string
+
+	// SkipMissingKey is true if documents without Key field should not be grouped,
+	// as `distinct` command does. Otherwise, they belong to the group with null key.
+	SkipMissingKey bool
+
+	Accumulators []GroupAccumulator
+}
+
+// GroupAccumulator represents a single accumulator of `$group` stage that could be pushed down to the backend.
+type GroupAccumulator struct {
+	Output   string // output field name
+	Operator string // $sum, $avg, $min or $max
+	Field    string // top-level field name used as an accumulator argument
+	Value    any    // int32 or int64 constant for $sum without Field
+}
+
+// MayFallBack returns true if the backend could push grouping down
+// but still may return ungrouped documents, depending on their values.
+//
+// That happens when group keys or arguments of $min and $max have types
+// that backends can't handle (for example, arrays and documents).
+func (gp *GroupParams) MayFallBack() bool {
+	if gp.Key != "" {
+		return true
+	}
+
+	for _, acc := range gp.Accumulators {
+		if acc.Field != "" && (acc.Operator == "$min" || acc.Operator == "$max") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GroupPartial contains values of a single accumulator aggregated by the backend
+// for some documents of the group.
+type GroupPartial struct {
+	IntSum     *big.Int // sum of int32 and int64 values
+	FloatSum   float64  // sum of float64 values
+	Count      int64    // number of numbers for $sum and $avg, or number of documents for constant $sum
+	HasInt64   bool     // true if at least one int64 value was summed
+	HasFloat64 bool     // true if at least one float64 value was summed
+	Values     []any    // candidate values for $min and $max
+}
+
+// GroupMerger merges backend's partially aggregated group rows into `$group` stage results.
+//
+// Backends group documents by keys' values and types, so documents with equal keys of different types
+// (for example, int32 1 and float64 1.0) are returned in different rows; GroupMerger combines them.
+type GroupMerger struct {
+	params *GroupParams
+	groups map[string]*mergedGroup
+	keys   []string // to return groups in a stable order
+}
+
+// mergedGroup represents a group being merged.
+type mergedGroup struct {
+	key      any
+	partials []GroupPartial
+}
+
+// NewGroupMerger creates a new GroupMerger for the given parameters.
+func NewGroupMerger(params *GroupParams) *GroupMerger {
+	return &GroupMerger{
+		params: params,
+		groups: map[string]*mergedGroup{},
+	}
+}
+
+// Add adds a group row with the given key and accumulators' partial values.
+//
+// Partial values should be in the same order as GroupParams.Accumulators.
+func (m *GroupMerger) Add(key any, partials []GroupPartial) {
+	if len(partials) != len(m.params.Accumulators) {
+		panic(fmt.Sprintf("expected %d partials, got %d", len(m.params.Accumulators), len(partials)))
+	}
+
+	k := groupMapKey(key)
+
+	g, ok := m.groups[k]
+	if !ok {
+		g = &mergedGroup{
+			key:      key,
+			partials: make([]GroupPartial, len(partials)),
+		}
+
+		for i := range g.partials {
+			g.partials[i].IntSum = new(big.Int)
+		}
+
+		m.groups[k] = g
+		m.keys = append(m.keys, k)
+	}
+
+	for i, p := range partials {
+		gp := &g.partials[i]
+
+		if p.IntSum != nil {
+			gp.IntSum.Add(gp.IntSum, p.IntSum)
+		}
+
+		gp.FloatSum += p.FloatSum
+		gp.Count += p.Count
+		gp.HasInt64 = gp.HasInt64 || p.HasInt64
+		gp.HasFloat64 = gp.HasFloat64 || p.HasFloat64
+		gp.Values = append(gp.Values, p.Values...)
+	}
+}
+
+// Documents returns resulting documents, one for each group.
+func (m *GroupMerger) Documents() []*types.Document {
+	res := make([]*types.Document, 0, len(m.keys))
+
+	for _, k := range m.keys {
+		g := m.groups[k]
+
+		doc := types.MakeDocument(1 + len(m.params.Accumulators))
+		doc.Set("_id", g.key)
+
+		for i, acc := range m.params.Accumulators {
+			doc.Set(acc.Output, finalizeGroupPartial(&acc, &g.partials[i]))
+		}
+
+		res = append(res, doc)
+	}
+
+	return res
+}
+
+// finalizeGroupPartial returns the accumulator's result for the given merged partial values.
+func finalizeGroupPartial(acc *GroupAccumulator, p *GroupPartial) any {
+	switch acc.Operator {
+	case "$sum":
+		if acc.Field == "" {
+			p.IntSum = new(big.Int).Mul(big.NewInt(p.Count), big.NewInt(groupConstant(acc.Value)))
+			_, p.HasInt64 = acc.Value.(int64)
+		}
+
+		return sumGroupPartial(p)
+
+	case "$avg":
+		if p.Count == 0 {
+			return types.Null
+		}
+
+		intSum, _ := new(big.Float).SetInt(p.IntSum).Float64()
+
+		return (intSum + p.FloatSum) / float64(p.Count)
+
+	case "$min", "$max":
+		cmp := types.Less
+		if acc.Operator == "$max" {
+			cmp = types.Greater
+		}
+
+		var res any
+
+		for _, v := range p.Values {
+			if res == nil || types.CompareOrder(v, res, types.Ascending) == cmp {
+				res = v
+			}
+		}
+
+		if res == nil {
+			return types.Null
+		}
+
+		return res
+
+	default:
+		panic(fmt.Sprintf("unexpected accumulator %q", acc.Operator))
+	}
+}
+
+// sumGroupPartial returns the sum the same way as `$sum` accumulator does.
+func sumGroupPartial(p *GroupPartial) any {
+	if p.HasFloat64 || !p.IntSum.IsInt64() {
+		intSum, _ := new(big.Float).SetInt(p.IntSum).Float64()
+		return intSum + p.FloatSum
+	}
+
+	sum := p.IntSum.Int64()
+
+	if !p.HasInt64 && sum <= math.MaxInt32 && sum >= math.MinInt32 {
+		return int32(sum)
+	}
+
+	return sum
+}
+
+// groupConstant returns the value of int32 or int64 constant.
+func groupConstant(v any) int64 {
+	switch v := v.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		panic(fmt.Sprintf("unexpected constant type %T", v))
+	}
+}
+
+// groupMapKey returns a string that is equal for equal group keys.
+//
+// Numbers of different types are equal if they represent the same value.
+func groupMapKey(key any) string {
+	switch key := key.(type) {
+	case types.NullType:
+		return "null"
+	case float64:
+		if key == math.Trunc(key) && !math.IsInf(key, 0) {
+			i, _ := big.NewFloat(key).Int(nil)
+			return "n:" + i.String()
+		}
+
+		return "f:" + strconv.FormatFloat(key, 'g', -1, 64)
+	case int32:
+		return "n:" + strconv.FormatInt(int64(key), 10)
+	case int64:
+		return "n:" + strconv.FormatInt(key, 10)
+	case string:
+		return "s:" + key
+	case types.ObjectID:
+		return fmt.Sprintf("o:%x", key[:])
+	case bool:
+		return "b:" + strconv.FormatBool(key)
+	case time.Time:
+		return "t:" + strconv.FormatInt(key.UnixMilli(), 10)
+	default:
+		panic(fmt.Sprintf("unexpected group key type %T", key))
+	}
+}
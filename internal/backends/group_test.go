@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backends
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestGroupMerger(t *testing.T) {
+	t.Parallel()
+
+	m := NewGroupMerger(&GroupParams{
+		Key: "k",
+		Accumulators: []GroupAccumulator{
+			{Output: "sum", Operator: "$sum", Field: "v"},
+			{Output: "count", Operator: "$sum", Value: int32(1)},
+			{Output: "avg", Operator: "$avg", Field: "v"},
+			{Output: "min", Operator: "$min", Field: "v"},
+		},
+	})
+
+	m.Add(int32(1), []GroupPartial{
+		{IntSum: big.NewInt(math.MaxInt32), Count: 1},
+		{Count: 1},
+		{IntSum: big.NewInt(math.MaxInt32), Count: 1},
+		{Values: []any{int32(math.MaxInt32)}},
+	})
+	m.Add("a", []GroupPartial{
+		{},
+		{Count: 2},
+		{},
+		{Values: []any{"x", "b"}},
+	})
+	m.Add(1.0, []GroupPartial{
+		{IntSum: big.NewInt(1), Count: 1},
+		{Count: 1},
+		{IntSum: big.NewInt(1), Count: 1},
+		{Values: []any{"s"}},
+	})
+	m.Add(types.Null, []GroupPartial{
+		{IntSum: big.NewInt(1), FloatSum: 0.5, Count: 2, HasFloat64: true},
+		{Count: 2},
+		{IntSum: big.NewInt(1), FloatSum: 0.5, Count: 2, HasFloat64: true},
+		{},
+	})
+
+	expected := []*types.Document{
+		must.NotFail(types.NewDocument(
+			"_id", int32(1),
+			"sum", int64(math.MaxInt32+1),
+			"count", int32(2),
+			"avg", float64(math.MaxInt32+1)/2,
+			"min", int32(math.MaxInt32),
+		)),
+		must.NotFail(types.NewDocument(
+			"_id", "a",
+			"sum", int32(0),
+			"count", int32(2),
+			"avg", types.Null,
+			"min", "b",
+		)),
+		must.NotFail(types.NewDocument(
+			"_id", types.Null,
+			"sum", 1.5,
+			"count", int32(2),
+			"avg", 0.75,
+			"min", types.Null,
+		)),
+	}
+	testutil.AssertEqualSlices(t, expected, m.Documents())
+}
+
+func TestGroupParamsMayFallBack(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		params   *GroupParams
+		expected bool
+	}{
+		"Count": {
+			params: &GroupParams{Accumulators: []GroupAccumulator{
+				{Output: "n", Operator: "$sum", Value: int32(1)},
+			}},
+		},
+		"SumAvg": {
+			params: &GroupParams{Accumulators: []GroupAccumulator{
+				{Output: "sum", Operator: "$sum", Field: "v"},
+				{Output: "avg", Operator: "$avg", Field: "v"},
+			}},
+		},
+		"Key": {
+			params: &GroupParams{Key: "k", Accumulators: []GroupAccumulator{
+				{Output: "n", Operator: "$sum", Value: int32(1)},
+			}},
+			expected: true,
+		},
+		"Min": {
+			params: &GroupParams{Accumulators: []GroupAccumulator{
+				{Output: "min", Operator: "$min", Field: "v"},
+			}},
+			expected: true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.params.MayFallBack())
+		})
+	}
+}
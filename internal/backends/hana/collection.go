@@ -44,6 +44,11 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	return nil, lazyerrors.New("not implemented yet")
 }
 
+// ModifyAll implements backends.Collection interface.
+func (c *collection) ModifyAll(ctx context.Context, params *backends.ModifyAllParams) (*backends.ModifyAllResult, error) {
+	return nil, lazyerrors.New("not implemented yet")
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	return nil, lazyerrors.New("not implemented yet")
@@ -74,6 +79,11 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return nil, lazyerrors.New("not implemented yet")
 }
 
+// ModifyIndex implements backends.Collection interface.
+func (c *collection) ModifyIndex(ctx context.Context, params *backends.ModifyIndexParams) (*backends.ModifyIndexResult, error) {
+	return nil, lazyerrors.New("not implemented yet")
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -51,6 +51,11 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return lazyerrors.New("not implemented yet")
 }
 
+// ModifyCollection implements backends.Database interface.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	return lazyerrors.New("not implemented yet")
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	return nil, lazyerrors.New("not implemented yet")
@@ -30,6 +30,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -74,40 +75,40 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		}, nil
 	}
 
-	q := prepareSelectClause(&selectParams{
-		Schema:        c.dbName,
-		Table:         meta.TableName,
-		Comment:       params.Comment,
-		Capped:        meta.Capped(),
-		OnlyRecordIDs: params.OnlyRecordIDs,
-	})
+	if params.Group != nil {
+		if gq, ok := prepareGroupQuery(c.dbName, meta.TableName, params.Filter, params.Group); ok {
+			docs, err := queryGroup(ctx, p, gq, params.Group)
 
-	var placeholder metadata.Placeholder
+			switch {
+			case err == nil:
+				return &backends.QueryResult{
+					Iter:          iterator.Values(iterator.ForSlice(docs)),
+					GroupPushdown: true,
+				}, nil
 
-	where, args, err := prepareWhereClause(&placeholder, params.Filter)
-	if err != nil {
-		return nil, lazyerrors.Error(err)
-	}
-
-	q += where
-
-	sort, sortArgs := prepareOrderByClause(&placeholder, params.Sort, meta.Capped())
-	q += sort
+			case errors.Is(err, errGroupUnsupported):
+				// fallback to fetching all documents
 
-	args = append(args, sortArgs...)
+			default:
+				return nil, lazyerrors.Error(err)
+			}
+		}
+	}
 
-	if params.Limit != 0 {
-		q += fmt.Sprintf(` LIMIT %s`, placeholder.Next())
-		args = append(args, params.Limit)
+	sq, err := prepareQuery(c.dbName, meta, params)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
 	}
 
-	rows, err := p.Query(ctx, q, args...)
+	rows, err := p.Query(ctx, sq.q, sq.args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	return &backends.QueryResult{
-		Iter: newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		Iter:         newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		SkipPushdown: sq.skipPushdown,
+		Index:        sq.index,
 	}, nil
 }
 
@@ -131,6 +132,21 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 	}
 
 	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+		// the whole batch fails on the first duplicate;
+		// the handler then inserts documents one by one to report per-document errors
+		if len(params.Docs) >= copyMinDocs {
+			if err = copyInsert(ctx, tx, c.dbName, meta.TableName, meta.Capped(), params.Docs); err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+					return backends.NewError(backends.ErrorCodeInsertDuplicateID, err)
+				}
+
+				return lazyerrors.Error(err)
+			}
+
+			return nil
+		}
+
 		// TODO https://github.com/FerretDB/FerretDB/issues/3708
 		const batchSize = 100
 
@@ -190,6 +206,15 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		return &res, nil
 	}
 
+	if len(params.Update) > 0 {
+		uq, ok := prepareUpdateQuery(c.dbName, meta, params.Filter, params.Update, params.Multi)
+		if !ok {
+			return &res, nil
+		}
+
+		return execUpdateQuery(ctx, p, uq, params.Multi)
+	}
+
 	q := fmt.Sprintf(
 		`UPDATE %s SET %s = $1 WHERE %s = $2`,
 		pgx.Identifier{c.dbName, meta.TableName}.Sanitize(),
@@ -246,6 +271,23 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 		return &backends.DeleteAllResult{Deleted: 0}, nil
 	}
 
+	if params.Filter != nil {
+		q, args, ok := prepareDeleteQuery(c.dbName, meta, params.Filter, params.Limited)
+		if !ok {
+			return new(backends.DeleteAllResult), nil
+		}
+
+		res, err := p.Exec(ctx, q, args...)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return &backends.DeleteAllResult{
+			Deleted:        int32(res.RowsAffected()),
+			FilterPushdown: true,
+		}, nil
+	}
+
 	var column string
 	var placeholders []string
 	var args []any
@@ -290,6 +332,71 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	}, nil
 }
 
+// ModifyAll implements backends.Collection interface.
+func (c *collection) ModifyAll(ctx context.Context, params *backends.ModifyAllParams) (*backends.ModifyAllResult, error) {
+	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var meta *metadata.Collection
+
+	if p != nil {
+		if meta, err = c.r.CollectionGet(ctx, c.dbName, c.name); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if meta == nil {
+		var changes *backends.ModifyChanges
+
+		// there are no documents to query
+		query := func(*types.Document) (types.DocumentsIterator, error) {
+			return newQueryIterator(ctx, nil, false), nil
+		}
+
+		iter := newQueryIterator(ctx, nil, false)
+		changes, err = params.Modify(iter, query)
+		iter.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(changes.Insert) == 0 {
+			return new(backends.ModifyAllResult), nil
+		}
+
+		if _, err = c.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{DBName: c.dbName, Name: c.name}); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if p, err = c.r.DatabaseGetExisting(ctx, c.dbName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if meta, err = c.r.CollectionGet(ctx, c.dbName, c.name); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	sq, err := prepareQuery(c.dbName, meta, &backends.QueryParams{Filter: params.Filter, Hint: params.Hint})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := &backends.ModifyAllResult{Index: sq.index}
+
+	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+		return modifyInTransaction(ctx, tx, c.dbName, meta, sq, params, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
@@ -328,25 +435,69 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	var placeholder metadata.Placeholder
 
-	where, args, err := prepareWhereClause(&placeholder, params.Filter)
+	where, args, exact, err := prepareLimitedWhereClause(&placeholder, params.Filter, params.Skip, params.Limit)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	res.FilterPushdown = where != ""
 
+	plan := planQuery(&placeholder, meta, params.Filter, params.Sort, params.Hint)
+
+	for _, cond := range plan.conditions {
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+	}
+
+	args = append(args, plan.args...)
+	res.Index = plan.index
+
+	if cond, condArgs, index := prepareWildcardCondition(&placeholder, meta.Indexes, params.Filter); cond != "" {
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+
+		args = append(args, condArgs...)
+
+		if res.Index == "" {
+			res.Index = index
+		}
+	}
+
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(&placeholder, params.Sort, meta.Capped())
+	var sort string
+	sort, res.SortPushdown = prepareSortClause(plan, params.Sort, meta.Capped())
 	q += sort
 
-	args = append(args, sortArgs...)
-	res.SortPushdown = sort != ""
+	if exact {
+		limit, limitArgs := prepareLimitClause(&placeholder, params.Skip, params.Limit)
+		q += limit
+		args = append(args, limitArgs...)
+		res.SkipPushdown = params.Skip != 0
+		res.LimitPushdown = params.Limit != 0
+	}
 
-	if params.Limit != 0 {
-		q += fmt.Sprintf(` LIMIT %s`, placeholder.Next())
-		args = append(args, params.Limit)
-		res.LimitPushdown = true
+	if params.Group != nil {
+		if gq, ok := prepareGroupQuery(c.dbName, meta.TableName, params.Filter, params.Group); ok {
+			q = `EXPLAIN (VERBOSE true, FORMAT JSON) ` + gq.q
+			args = gq.args
+			res.GroupPushdown = true
+			res.GroupFallback = params.Group.MayFallBack()
+		}
+	}
+
+	if len(params.Update) > 0 {
+		if uq, ok := prepareUpdateQuery(c.dbName, meta, params.Filter, params.Update, true); ok {
+			q = `EXPLAIN (VERBOSE true, FORMAT JSON) ` + uq.update
+			args = uq.updateArgs
+			res.UpdatePushdown = true
+		}
 	}
 
 	var b []byte
@@ -524,15 +675,47 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:               index.Name,
+			Unique:             index.Unique,
+			Key:                make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
+			Hidden:             index.Hidden,
+			PrepareUnique:      index.PrepareUnique,
+			Sparse:             index.Sparse,
+
+			PartialFilterExpression: index.PartialFilterExpression,
+			WildcardProjection:      index.WildcardProjection,
 		}
 
 		for j, key := range index.Key {
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Type:       backends.IndexKeyType(key.Type),
+			}
+		}
+
+		if index.Text != nil {
+			res.Indexes[i].Text = &backends.TextIndexOptions{
+				Weights:          make([]backends.TextIndexWeight, len(index.Text.Weights)),
+				DefaultLanguage:  index.Text.DefaultLanguage,
+				LanguageOverride: index.Text.LanguageOverride,
+			}
+
+			for j, w := range index.Text.Weights {
+				res.Indexes[i].Text.Weights[j] = backends.TextIndexWeight{
+					Field:  w.Field,
+					Weight: w.Weight,
+				}
+			}
+		}
+
+		if index.Geo != nil {
+			res.Indexes[i].Geo = &backends.GeoIndexOptions{
+				Version: index.Geo.Version,
+				Bits:    index.Geo.Bits,
+				Min:     index.Geo.Min,
+				Max:     index.Geo.Max,
 			}
 		}
 	}
@@ -549,15 +732,47 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Key:                make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
+			Hidden:             index.Hidden,
+			PrepareUnique:      index.PrepareUnique,
+			Sparse:             index.Sparse,
+
+			PartialFilterExpression: index.PartialFilterExpression,
+			WildcardProjection:      index.WildcardProjection,
 		}
 
 		for j, key := range index.Key {
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Type:       string(key.Type),
+			}
+		}
+
+		if index.Text != nil {
+			indexes[i].Text = &metadata.TextIndexOptions{
+				Weights:          make([]metadata.TextIndexWeight, len(index.Text.Weights)),
+				DefaultLanguage:  index.Text.DefaultLanguage,
+				LanguageOverride: index.Text.LanguageOverride,
+			}
+
+			for j, w := range index.Text.Weights {
+				indexes[i].Text.Weights[j] = metadata.TextIndexWeight{
+					Field:  w.Field,
+					Weight: w.Weight,
+				}
+			}
+		}
+
+		if index.Geo != nil {
+			indexes[i].Geo = &metadata.GeoIndexOptions{
+				Version: index.Geo.Version,
+				Bits:    index.Geo.Bits,
+				Min:     index.Geo.Min,
+				Max:     index.Geo.Max,
 			}
 		}
 	}
@@ -580,6 +795,43 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return new(backends.DropIndexesResult), nil
 }
 
+// ModifyIndex implements backends.Collection interface.
+func (c *collection) ModifyIndex(ctx context.Context, params *backends.ModifyIndexParams) (*backends.ModifyIndexResult, error) {
+	coll, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	modified, err := c.r.IndexModify(ctx, &metadata.IndexModifyParams{
+		DBName:             c.dbName,
+		CollectionName:     c.name,
+		Name:               params.Name,
+		ExpireAfterSeconds: params.ExpireAfterSeconds,
+		Hidden:             params.Hidden,
+		PrepareUnique:      params.PrepareUnique,
+		Unique:             params.Unique,
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !modified {
+		return nil, backends.NewError(
+			backends.ErrorCodeIndexDoesNotExist,
+			lazyerrors.Errorf("index %q does not exist in %s.%s", params.Name, c.dbName, c.name),
+		)
+	}
+
+	return new(backends.ModifyIndexResult), nil
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -58,6 +58,21 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 			CappedSize:      c.CappedSize,
 			CappedDocuments: c.CappedDocuments,
 		}
+
+		if v := c.Validation; v != nil {
+			res[i].Validation = &backends.CollectionValidation{
+				Validator: v.Validator,
+				Level:     v.Level,
+				Action:    v.Action,
+			}
+		}
+
+		if v := c.View; v != nil {
+			res[i].View = &backends.CollectionView{
+				ViewOn:   v.ViewOn,
+				Pipeline: v.Pipeline,
+			}
+		}
 	}
 
 	return &backends.ListCollectionsResult{
@@ -72,6 +87,8 @@ func (db *database) CreateCollection(ctx context.Context, params *backends.Creat
 		Name:            params.Name,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		Validation:      metadataValidation(params.Validation),
+		View:            metadataView(params.View),
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -136,6 +153,29 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return nil
 }
 
+// ModifyCollection implements backends.Database interface.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	modified, err := db.r.CollectionModify(ctx, &metadata.CollectionModifyParams{
+		DBName:          db.name,
+		Name:            params.Name,
+		Validation:      metadataValidation(params.Validation),
+		CappedSize:      params.CappedSize,
+		CappedDocuments: params.CappedDocuments,
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !modified {
+		return backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("database %q or collection %q does not exist", db.name, params.Name),
+		)
+	}
+
+	return nil
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	if params == nil {
@@ -186,6 +226,31 @@ func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsPar
 	}, nil
 }
 
+// metadataValidation converts validation settings to metadata representation.
+func metadataValidation(v *backends.CollectionValidation) *metadata.Validation {
+	if v == nil {
+		return nil
+	}
+
+	return &metadata.Validation{
+		Validator: v.Validator,
+		Level:     v.Level,
+		Action:    v.Action,
+	}
+}
+
+// metadataView converts the view definition to metadata representation.
+func metadataView(v *backends.CollectionView) *metadata.View {
+	if v == nil {
+		return nil
+	}
+
+	return &metadata.View{
+		ViewOn:   v.ViewOn,
+		Pipeline: v.Pipeline,
+	}
+}
+
 // check interfaces
 var (
 	_ backends.Database = (*database)(nil)
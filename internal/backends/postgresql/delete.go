@@ -0,0 +1,51 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// prepareDeleteQuery returns the query with arguments that deletes documents matching the filter.
+//
+// If limited is true, only the first matching document in the natural order is deleted.
+// It returns false if the filter can't be pushed down completely.
+func prepareDeleteQuery(schema string, meta *metadata.Collection, filter *types.Document, limited bool) (string, []any, bool) {
+	var placeholder metadata.Placeholder
+
+	where, args, ok := prepareGroupWhereClause(&placeholder, filter)
+	if !ok {
+		return "", nil, false
+	}
+
+	table := pgx.Identifier{schema, meta.TableName}.Sanitize()
+
+	if !limited {
+		return fmt.Sprintf(`DELETE FROM %s%s`, table, where), args, true
+	}
+
+	// PostgreSQL does not support DELETE ... LIMIT
+	q := fmt.Sprintf(
+		`DELETE FROM %[1]s WHERE ctid IN (SELECT ctid FROM %[1]s%[2]s%[3]s LIMIT 1 FOR UPDATE)`,
+		table, where, prepareOrderByClause(nil, meta.Capped()),
+	)
+
+	return q, args, true
+}
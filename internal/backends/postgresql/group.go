@@ -0,0 +1,527 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// minMaxTypes contains sjson types of values for which $min and $max are computed by PostgreSQL.
+var minMaxTypes = []string{"int", "long", "double", "string", "objectId", "bool", "date"}
+
+// dateOperators maps comparison operators that prepareGroupWhereClause supports for dates to SQL ones.
+var dateOperators = map[string]string{
+	"$lt":  "<",
+	"$lte": "<=",
+	"$gt":  ">",
+	"$gte": ">=",
+}
+
+// errGroupUnsupported is returned when grouped values can't be handled by pushdown.
+var errGroupUnsupported = errors.New("unsupported values for $group pushdown")
+
+// fieldExprs returns SQL expressions for the value, text value and sjson type of the top-level field,
+// which name is passed as the given placeholder.
+func fieldExprs(placeholder string) (value, text, typ string) {
+	value = fmt.Sprintf(`%s->%s`, metadata.DefaultColumn, placeholder)
+	text = fmt.Sprintf(`%s->>%s`, metadata.DefaultColumn, placeholder)
+	typ = fmt.Sprintf(`%s->'$s'->'p'->%s->>'t'`, metadata.DefaultColumn, placeholder)
+
+	return
+}
+
+// prepareGroupWhereClause returns WHERE clause that applies the whole given filter, and its arguments.
+//
+// Only equality for top-level fields and comparison of them with dates are supported.
+// Unlike prepareWhereClause, the result matches exactly the same documents as the filter does.
+// If that is not possible, it returns false.
+func prepareGroupWhereClause(p *metadata.Placeholder, filter *types.Document) (string, []any, bool) {
+	var filters []string
+	var args []any
+
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return "", nil, false
+		}
+
+		if k == "$comment" {
+			continue
+		}
+
+		if k == "" || strings.HasPrefix(k, "$") || strings.ContainsRune(k, '.') {
+			return "", nil, false
+		}
+
+		op := "="
+
+		if d, ok := v.(*types.Document); ok {
+			if d.Len() != 1 {
+				return "", nil, false
+			}
+
+			operator := d.Command()
+			v = must.NotFail(d.Get(operator))
+
+			switch _, isDate := v.(time.Time); {
+			case operator == "$eq":
+			case isDate && dateOperators[operator] != "":
+				op = dateOperators[operator]
+			default:
+				return "", nil, false
+			}
+		}
+
+		// for example, NaN and infinities can't be marshaled
+		arg, err := sjson.MarshalSingleValue(v)
+		if err != nil {
+			return "", nil, false
+		}
+
+		kp := p.Next()
+		vp := p.Next()
+		value, _, typ := fieldExprs(kp)
+		items := fmt.Sprintf(`%s->'$s'->'p'->%s->'i'->(e.i::int - 1)->>'t'`, metadata.DefaultColumn, kp)
+
+		var scalar, element string
+
+		switch v := v.(type) {
+		case string, types.ObjectID, bool:
+			t := sjson.GetTypeOfValue(v)
+			scalar = fmt.Sprintf(`(%s = '%s' AND %s = %s::jsonb)`, typ, t, value, vp)
+			element = fmt.Sprintf(`%s = '%s' AND e.v = %s::jsonb`, items, t, vp)
+
+		case time.Time:
+			// dates are stored as milliseconds since epoch
+			scalar = fmt.Sprintf(`CASE WHEN %s = 'date' THEN (%s)::numeric %s %s::text::numeric ELSE false END`, typ, value, op, vp)
+			element = fmt.Sprintf(`CASE WHEN %s = 'date' THEN e.v::numeric %s %s::text::numeric ELSE false END`, items, op, vp)
+
+		case int32, int64, float64:
+			numbers := `('int', 'long', 'double')`
+			scalar = fmt.Sprintf(`CASE WHEN %s IN %s THEN (%s)::numeric = %s::text::numeric ELSE false END`, typ, numbers, value, vp)
+			element = fmt.Sprintf(`CASE WHEN %s IN %s THEN e.v::numeric = %s::text::numeric ELSE false END`, items, numbers, vp)
+
+		default:
+			return "", nil, false
+		}
+
+		filters = append(filters, fmt.Sprintf(
+			`(%s OR CASE WHEN %s = 'array' THEN EXISTS (`+
+				`SELECT 1 FROM jsonb_array_elements(%s) WITH ORDINALITY AS e(v, i) WHERE %s`+
+				`) ELSE false END)`,
+			scalar, typ, value, element,
+		))
+		args = append(args, k, string(arg))
+
+		// the _id index is used for suitable values, see indexEqualCondition
+		if k == "_id" && op == "=" {
+			if cond, arg := indexEqualCondition(p, filter, k); cond != "" {
+				filters = append(filters, cond)
+				args = append(args, arg)
+			}
+		}
+	}
+
+	var where string
+	if len(filters) > 0 {
+		where = ` WHERE ` + strings.Join(filters, " AND ")
+	}
+
+	return where, args, true
+}
+
+// groupColumn represents a single column of the group query.
+type groupColumn struct {
+	expr string
+	dest any
+}
+
+// groupQuery represents the query that groups documents and partially aggregates accumulators.
+type groupQuery struct {
+	q    string
+	args []any
+
+	keyType  *string
+	keyValue *string
+	count    int64
+
+	// columns for each accumulator
+	columns [][]groupColumn
+}
+
+// prepareGroupQuery returns the query that groups documents by the key and partially aggregates accumulators.
+//
+// It returns false if the filter or grouping can't be pushed down completely.
+func prepareGroupQuery(schema, table string, filter *types.Document, group *backends.GroupParams) (*groupQuery, bool) {
+	var placeholder metadata.Placeholder
+	var args []any
+
+	gq := &groupQuery{
+		columns: make([][]groupColumn, len(group.Accumulators)),
+	}
+
+	selects := []string{`count(*)`}
+
+	if group.Key == "" {
+		selects = append(selects, `NULL::text`, `NULL::text`)
+	} else {
+		kp := placeholder.Next()
+		args = append(args, group.Key)
+
+		_, text, typ := fieldExprs(kp)
+		selects = append(selects, typ, text)
+	}
+
+	for i, acc := range group.Accumulators {
+		if acc.Field == "" {
+			if acc.Operator != "$sum" {
+				return nil, false
+			}
+
+			gq.columns[i] = []groupColumn{{expr: `count(*)`, dest: new(int64)}}
+			selects = append(selects, gq.columns[i][0].expr)
+
+			continue
+		}
+
+		fp := placeholder.Next()
+		args = append(args, acc.Field)
+		value, text, typ := fieldExprs(fp)
+
+		switch acc.Operator {
+		case "$sum", "$avg":
+			gq.columns[i] = []groupColumn{
+				{expr: fmt.Sprintf(`(sum((%s)::numeric) FILTER (WHERE %s IN ('int', 'long')))::text`, value, typ), dest: new(*string)},
+				{expr: fmt.Sprintf(`count(*) FILTER (WHERE %s IN ('int', 'long'))`, typ), dest: new(int64)},
+				{expr: fmt.Sprintf(`count(*) FILTER (WHERE %s = 'long')`, typ), dest: new(int64)},
+				{expr: fmt.Sprintf(`sum((%s)::float8) FILTER (WHERE %s = 'double')`, value, typ), dest: new(*float64)},
+				{expr: fmt.Sprintf(`count(*) FILTER (WHERE %s = 'double')`, typ), dest: new(int64)},
+			}
+
+		case "$min", "$max":
+			f := strings.TrimPrefix(acc.Operator, "$")
+
+			for _, t := range minMaxTypes {
+				var expr string
+				var dest any
+
+				switch t {
+				case "int", "long", "date":
+					expr, dest = fmt.Sprintf(`%s((%s)::int8)`, f, value), new(*int64)
+				case "double":
+					expr, dest = fmt.Sprintf(`%s((%s)::float8)`, f, value), new(*float64)
+				case "string", "objectId":
+					expr, dest = fmt.Sprintf(`%s(%s COLLATE "C")`, f, text), new(*string)
+				case "bool":
+					agg := "bool_and"
+					if f == "max" {
+						agg = "bool_or"
+					}
+
+					expr, dest = fmt.Sprintf(`%s((%s)::bool)`, agg, value), new(*bool)
+				}
+
+				gq.columns[i] = append(gq.columns[i], groupColumn{
+					expr: fmt.Sprintf(`%s FILTER (WHERE %s = '%s')`, expr, typ, t),
+					dest: dest,
+				})
+			}
+
+			gq.columns[i] = append(gq.columns[i], groupColumn{
+				expr: fmt.Sprintf(`count(*) FILTER (WHERE %s NOT IN ('null', '%s'))`, typ, strings.Join(minMaxTypes, "', '")),
+				dest: new(int64),
+			})
+
+		default:
+			return nil, false
+		}
+
+		for _, col := range gq.columns[i] {
+			selects = append(selects, col.expr)
+		}
+	}
+
+	where, whereArgs, ok := prepareGroupWhereClause(&placeholder, filter)
+	if !ok {
+		return nil, false
+	}
+
+	args = append(args, whereArgs...)
+
+	gq.q = fmt.Sprintf(
+		`SELECT %s FROM %s%s`,
+		strings.Join(selects, ", "),
+		pgx.Identifier{schema, table}.Sanitize(),
+		where,
+	)
+
+	// groups are ordered by their first documents, so merged groups have keys of the same types
+	// as without pushdown (see GroupMerger)
+	if group.Key != "" {
+		gq.q += ` GROUP BY 2, 3 ORDER BY min(ctid)`
+	}
+
+	gq.args = args
+
+	return gq, true
+}
+
+// dest returns scan destinations for all columns.
+func (gq *groupQuery) dest() []any {
+	res := []any{&gq.count, &gq.keyType, &gq.keyValue}
+
+	for _, cols := range gq.columns {
+		for _, col := range cols {
+			res = append(res, col.dest)
+		}
+	}
+
+	return res
+}
+
+// queryGroup runs the group query and returns grouped documents.
+//
+// It returns errGroupUnsupported if some values can't be handled by pushdown.
+func queryGroup(ctx context.Context, p *pgxpool.Pool, gq *groupQuery, group *backends.GroupParams) ([]*types.Document, error) {
+	rows, err := p.Query(ctx, gq.q, gq.args...)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer rows.Close()
+
+	merger := backends.NewGroupMerger(group)
+
+	for rows.Next() {
+		if err = rows.Scan(gq.dest()...); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if gq.count == 0 || (group.SkipMissingKey && gq.keyType == nil) {
+			continue
+		}
+
+		var key any
+
+		if key, err = groupKey(gq.keyType, gq.keyValue); err != nil {
+			return nil, err
+		}
+
+		partials := make([]backends.GroupPartial, len(group.Accumulators))
+
+		for i, acc := range group.Accumulators {
+			if partials[i], err = groupPartial(&acc, gq.columns[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		merger.Add(key, partials)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return merger.Documents(), nil
+}
+
+// groupKey converts group key's sjson type and text value to the key.
+func groupKey(typ, text *string) (any, error) {
+	if typ == nil || *typ == "null" {
+		return types.Null, nil
+	}
+
+	if text == nil {
+		return nil, lazyerrors.Errorf("no value for type %q", *typ)
+	}
+
+	switch *typ {
+	case "int":
+		v, err := strconv.ParseInt(*text, 10, 32)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return int32(v), nil
+
+	case "long":
+		v, err := strconv.ParseInt(*text, 10, 64)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return v, nil
+
+	case "double":
+		v, err := strconv.ParseFloat(*text, 64)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return v, nil
+
+	case "string":
+		return *text, nil
+
+	case "objectId":
+		return parseObjectID(*text)
+
+	case "bool":
+		return *text == "true", nil
+
+	case "date":
+		v, err := strconv.ParseInt(*text, 10, 64)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return time.UnixMilli(v).UTC(), nil
+
+	default:
+		return nil, errGroupUnsupported
+	}
+}
+
+// parseObjectID converts hex-encoded ObjectID to the value.
+func parseObjectID(s string) (types.ObjectID, error) {
+	var res types.ObjectID
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return res, lazyerrors.Error(err)
+	}
+
+	if len(b) != len(res) {
+		return res, lazyerrors.Errorf("invalid ObjectID %q", s)
+	}
+
+	copy(res[:], b)
+
+	return res, nil
+}
+
+// groupPartial converts scanned columns of the accumulator to its partial value.
+func groupPartial(acc *backends.GroupAccumulator, cols []groupColumn) (backends.GroupPartial, error) {
+	var res backends.GroupPartial
+
+	if acc.Field == "" {
+		res.Count = *cols[0].dest.(*int64)
+		return res, nil
+	}
+
+	switch acc.Operator {
+	case "$sum", "$avg":
+		if s := *cols[0].dest.(**string); s != nil {
+			var ok bool
+			if res.IntSum, ok = new(big.Int).SetString(*s, 10); !ok {
+				return res, lazyerrors.Errorf("invalid sum %q", *s)
+			}
+		}
+
+		if f := *cols[3].dest.(**float64); f != nil {
+			res.FloatSum = *f
+		}
+
+		doubles := *cols[4].dest.(*int64)
+
+		res.Count = *cols[1].dest.(*int64) + doubles
+		res.HasInt64 = *cols[2].dest.(*int64) > 0
+		res.HasFloat64 = doubles > 0
+
+	default:
+		if *cols[len(minMaxTypes)].dest.(*int64) > 0 {
+			return res, errGroupUnsupported
+		}
+
+		for i, t := range minMaxTypes {
+			var v any
+
+			switch d := cols[i].dest.(type) {
+			case **int64:
+				if *d == nil {
+					continue
+				}
+
+				switch t {
+				case "int":
+					v = int32(**d)
+				case "date":
+					v = time.UnixMilli(**d).UTC()
+				default:
+					v = **d
+				}
+
+			case **float64:
+				if *d == nil {
+					continue
+				}
+
+				v = **d
+
+			case **string:
+				if *d == nil {
+					continue
+				}
+
+				if t == "objectId" {
+					id, err := parseObjectID(**d)
+					if err != nil {
+						return res, err
+					}
+
+					v = id
+				} else {
+					v = **d
+				}
+
+			case **bool:
+				if *d == nil {
+					continue
+				}
+
+				v = **d
+			}
+
+			res.Values = append(res.Values, v)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestPrepareGroupQuery(t *testing.T) {
+	t.Parallel()
+
+	group := &backends.GroupParams{
+		Key: "k",
+		Accumulators: []backends.GroupAccumulator{
+			{Output: "count", Operator: "$sum", Value: int32(1)},
+			{Output: "sum", Operator: "$sum", Field: "v"},
+			{Output: "max", Operator: "$max", Field: "v"},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		filter *types.Document
+		group  *backends.GroupParams
+
+		expectedArgs []any
+		expectedNo   bool
+	}{
+		"NoFilter": {
+			group:        group,
+			expectedArgs: []any{"k", "v", "v"},
+		},
+		"NoKey": {
+			group: &backends.GroupParams{
+				Accumulators: []backends.GroupAccumulator{{Output: "avg", Operator: "$avg", Field: "v"}},
+			},
+			expectedArgs: []any{"v"},
+		},
+		"Filter": {
+			filter: must.NotFail(types.NewDocument(
+				"v", int32(42),
+				"s", must.NotFail(types.NewDocument("$eq", "foo")),
+				"$comment", "test",
+			)),
+			group:        group,
+			expectedArgs: []any{"k", "v", "v", "v", "42", "s", `"foo"`},
+		},
+		"FilterOperator": {
+			filter:     must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", int32(42))))),
+			group:      group,
+			expectedNo: true,
+		},
+		"FilterDotNotation": {
+			filter:     must.NotFail(types.NewDocument("v.foo", int32(42))),
+			group:      group,
+			expectedNo: true,
+		},
+		"FilterNaN": {
+			filter:     must.NotFail(types.NewDocument("v", math.NaN())),
+			group:      group,
+			expectedNo: true,
+		},
+		"FilterInfinity": {
+			filter:     must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$eq", math.Inf(1))))),
+			group:      group,
+			expectedNo: true,
+		},
+		"FilterNull": {
+			filter:     must.NotFail(types.NewDocument("v", types.Null)),
+			group:      group,
+			expectedNo: true,
+		},
+		"FilterOr": {
+			filter: must.NotFail(types.NewDocument("$or", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("v", int32(42))),
+			)))),
+			group:      group,
+			expectedNo: true,
+		},
+		"UnsupportedAccumulator": {
+			group: &backends.GroupParams{
+				Accumulators: []backends.GroupAccumulator{{Output: "avg", Operator: "$avg", Value: int32(1)}},
+			},
+			expectedNo: true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			gq, ok := prepareGroupQuery("schema", "table", tc.filter, tc.group)
+			if tc.expectedNo {
+				assert.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedArgs, gq.args)
+			assert.Len(t, gq.columns, len(tc.group.Accumulators))
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package postgresql
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -26,6 +27,11 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
+// copyMinDocs is the minimal number of documents that are inserted with COPY instead of INSERT statements.
+//
+// COPY has a higher overhead for small batches, but it is much faster for large ones.
+const copyMinDocs = 100
+
 // prepareInsertStatement returns a statement and arguments for inserting the given documents.
 //
 // If capped is true, it returns a statement and arguments for inserting record IDs and documents.
@@ -63,3 +69,32 @@ func prepareInsertStatement(schema, tableName string, capped bool, docs []*types
 		strings.Join(rows, ", "),
 	), args, nil
 }
+
+// copyInsert inserts the given documents with COPY FROM STDIN.
+//
+// If capped is true, it inserts record IDs and documents.
+// COPY is aborted on the first unique violation, and returned error is not wrapped
+// so the caller can check it.
+func copyInsert(ctx context.Context, tx pgx.Tx, schema, tableName string, capped bool, docs []*types.Document) error {
+	columns := []string{metadata.DefaultColumn}
+	if capped {
+		columns = []string{metadata.RecordIDColumn, metadata.DefaultColumn}
+	}
+
+	src := pgx.CopyFromSlice(len(docs), func(i int) ([]any, error) {
+		b, err := sjson.Marshal(docs[i])
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if capped {
+			return []any{docs[i].RecordID(), b}, nil
+		}
+
+		return []any{b}, nil
+	})
+
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{schema, tableName}, columns, src)
+
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// geoWorldBound is the bound of the box used for documents without a point location.
+//
+// Such documents are always returned by the query, so the handler could match them itself.
+const geoWorldBound = 3e38
+
+// GeoField returns the field of the geospatial index key, or an empty string for other indexes.
+func (index *IndexInfo) GeoField() string {
+	for _, key := range index.Key {
+		switch key.Type {
+		case "2dsphere", "2d":
+			return key.Field
+		}
+	}
+
+	return ""
+}
+
+// GeoBox returns SQL expression of the box of the given field's location.
+//
+// GeoJSON points and legacy coordinate pairs are points;
+// other values (including arrays of points) are the box covering the whole plane.
+// The same expression is used for the GiST index and for queries, so the index could be used by them.
+func GeoBox(field string) string {
+	fs := strings.Split(field, ".")
+	for i, f := range fs {
+		fs[i] = quoteString(f)
+	}
+
+	legacy := strings.Join(fs, ", ")
+	geoJSON := legacy + ", 'coordinates'"
+
+	number := func(path string, i int) string {
+		return fmt.Sprintf("(%s #> ARRAY[%s, '%d'])", DefaultColumn, path, i)
+	}
+
+	isPoint := func(path string) string {
+		return fmt.Sprintf(
+			"jsonb_typeof(%s) = 'number' AND jsonb_typeof(%s) = 'number'",
+			number(path, 0), number(path, 1),
+		)
+	}
+
+	value := func(i int, bound float64) string {
+		return fmt.Sprintf(
+			"CASE WHEN %s THEN %s::float8 WHEN %s THEN %s::float8 ELSE %g END",
+			isPoint(legacy), number(legacy, i), isPoint(geoJSON), number(geoJSON, i), bound,
+		)
+	}
+
+	return fmt.Sprintf(
+		"box(point(%s, %s), point(%s, %s))",
+		value(0, -geoWorldBound), value(1, -geoWorldBound), value(0, geoWorldBound), value(1, geoWorldBound),
+	)
+}
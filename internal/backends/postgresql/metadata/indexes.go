@@ -18,6 +18,8 @@ import (
 	"errors"
 	"slices"
 
+	"github.com/AlekSi/pointer"
+
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -29,16 +31,85 @@ type Indexes []IndexInfo
 
 // IndexInfo represents information about a single index.
 type IndexInfo struct {
-	Name    string
-	PgIndex string
-	Key     []IndexKeyPair
-	Unique  bool
+	Name               string
+	PgIndex            string
+	Key                []IndexKeyPair
+	Unique             bool
+	Text               *TextIndexOptions
+	Geo                *GeoIndexOptions
+	ExpireAfterSeconds *int32
+	Hidden             bool
+	PrepareUnique      bool
+	Sparse             bool
+
+	PartialFilterExpression *types.Document
+	WildcardProjection      *types.Document
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
+//
+// For special index keys, Type is set and Descending is not used.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+	Type       string
+}
+
+// TextIndexOptions represents options of the text index.
+//
+// Text index is a GIN index on tsvector of all indexed fields.
+type TextIndexOptions struct {
+	Weights          []TextIndexWeight
+	DefaultLanguage  string
+	LanguageOverride string
+}
+
+// TextIndexWeight represents a weight of the field in the text index.
+type TextIndexWeight struct {
+	Field  string
+	Weight int32
+}
+
+// GeoIndexOptions represents options of the geospatial index.
+//
+// Geospatial index is a GiST index on the box of the indexed field's location.
+type GeoIndexOptions struct {
+	Version int32
+	Bits    int32
+	Min     *float64
+	Max     *float64
+}
+
+// deepCopy returns a deep copy.
+func (g *GeoIndexOptions) deepCopy() *GeoIndexOptions {
+	if g == nil {
+		return nil
+	}
+
+	res := *g
+
+	if g.Min != nil {
+		res.Min = pointer.To(*g.Min)
+	}
+
+	if g.Max != nil {
+		res.Max = pointer.To(*g.Max)
+	}
+
+	return &res
+}
+
+// deepCopy returns a deep copy.
+func (t *TextIndexOptions) deepCopy() *TextIndexOptions {
+	if t == nil {
+		return nil
+	}
+
+	return &TextIndexOptions{
+		Weights:          slices.Clone(t.Weights),
+		DefaultLanguage:  t.DefaultLanguage,
+		LanguageOverride: t.LanguageOverride,
+	}
 }
 
 // deepCopy returns a deep copy.
@@ -47,10 +118,27 @@ func (indexes Indexes) deepCopy() Indexes {
 
 	for i, index := range indexes {
 		res[i] = IndexInfo{
-			Name:    index.Name,
-			PgIndex: index.PgIndex,
-			Key:     slices.Clone(index.Key),
-			Unique:  index.Unique,
+			Name:          index.Name,
+			PgIndex:       index.PgIndex,
+			Key:           slices.Clone(index.Key),
+			Unique:        index.Unique,
+			Text:          index.Text.deepCopy(),
+			Geo:           index.Geo.deepCopy(),
+			Hidden:        index.Hidden,
+			PrepareUnique: index.PrepareUnique,
+			Sparse:        index.Sparse,
+		}
+
+		if index.PartialFilterExpression != nil {
+			res[i].PartialFilterExpression = index.PartialFilterExpression.DeepCopy()
+		}
+
+		if index.WildcardProjection != nil {
+			res[i].WildcardProjection = index.WildcardProjection.DeepCopy()
+		}
+
+		if index.ExpireAfterSeconds != nil {
+			res[i].ExpireAfterSeconds = pointer.To(*index.ExpireAfterSeconds)
 		}
 	}
 
@@ -65,6 +153,11 @@ func (indexes Indexes) marshal() *types.Array {
 		key := types.MakeDocument(len(index.Key))
 
 		for _, pair := range index.Key {
+			if pair.Type != "" {
+				key.Set(pair.Field, pair.Type)
+				continue
+			}
+
 			order := int32(1)
 			if pair.Descending {
 				order = int32(-1)
@@ -73,12 +166,66 @@ func (indexes Indexes) marshal() *types.Array {
 			key.Set(pair.Field, order)
 		}
 
-		res.Append(must.NotFail(types.NewDocument(
+		doc := must.NotFail(types.NewDocument(
 			"pgindex", index.PgIndex,
 			"name", index.Name,
 			"key", key,
 			"unique", index.Unique,
-		)))
+		))
+
+		if index.Text != nil {
+			weights := types.MakeDocument(len(index.Text.Weights))
+			for _, w := range index.Text.Weights {
+				weights.Set(w.Field, w.Weight)
+			}
+
+			doc.Set("weights", weights)
+			doc.Set("default_language", index.Text.DefaultLanguage)
+			doc.Set("language_override", index.Text.LanguageOverride)
+		}
+
+		if index.Geo != nil {
+			geo := must.NotFail(types.NewDocument(
+				"version", index.Geo.Version,
+				"bits", index.Geo.Bits,
+			))
+
+			if index.Geo.Min != nil {
+				geo.Set("min", *index.Geo.Min)
+			}
+
+			if index.Geo.Max != nil {
+				geo.Set("max", *index.Geo.Max)
+			}
+
+			doc.Set("geo", geo)
+		}
+
+		if index.ExpireAfterSeconds != nil {
+			doc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+		}
+
+		if index.Hidden {
+			doc.Set("hidden", true)
+		}
+
+		if index.PrepareUnique {
+			doc.Set("prepareUnique", true)
+		}
+
+		if index.Sparse {
+			doc.Set("sparse", true)
+		}
+
+		if index.PartialFilterExpression != nil {
+			doc.Set("partialFilterExpression", index.PartialFilterExpression)
+		}
+
+		if index.WildcardProjection != nil {
+			doc.Set("wildcardProjection", index.WildcardProjection)
+		}
+
+		res.Append(doc)
 	}
 
 	return res
@@ -109,6 +256,15 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 		key := make([]IndexKeyPair, keyDoc.Len())
 
 		for j, f := range fields {
+			if t, ok := orders[j].(string); ok {
+				key[j] = IndexKeyPair{
+					Field: f,
+					Type:  t,
+				}
+
+				continue
+			}
+
 			descending := false
 			if orders[j].(int32) == -1 {
 				descending = true
@@ -130,6 +286,62 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 			Key:     key,
 			Unique:  unique,
 		}
+
+		if v, _ = index.Get("weights"); v != nil {
+			weightsDoc := v.(*types.Document)
+			weights := make([]TextIndexWeight, weightsDoc.Len())
+
+			for j, f := range weightsDoc.Keys() {
+				weights[j] = TextIndexWeight{
+					Field:  f,
+					Weight: must.NotFail(weightsDoc.Get(f)).(int32),
+				}
+			}
+
+			res[i].Text = &TextIndexOptions{
+				Weights:          weights,
+				DefaultLanguage:  must.NotFail(index.Get("default_language")).(string),
+				LanguageOverride: must.NotFail(index.Get("language_override")).(string),
+			}
+		}
+
+		if v, _ = index.Get("geo"); v != nil {
+			geoDoc := v.(*types.Document)
+
+			res[i].Geo = &GeoIndexOptions{
+				Version: must.NotFail(geoDoc.Get("version")).(int32),
+				Bits:    must.NotFail(geoDoc.Get("bits")).(int32),
+			}
+
+			if v, _ = geoDoc.Get("min"); v != nil {
+				res[i].Geo.Min = pointer.To(v.(float64))
+			}
+
+			if v, _ = geoDoc.Get("max"); v != nil {
+				res[i].Geo.Max = pointer.To(v.(float64))
+			}
+		}
+
+		if v, _ = index.Get("expireAfterSeconds"); v != nil {
+			res[i].ExpireAfterSeconds = pointer.To(v.(int32))
+		}
+
+		v, _ = index.Get("hidden")
+		res[i].Hidden, _ = v.(bool)
+
+		v, _ = index.Get("prepareUnique")
+		res[i].PrepareUnique, _ = v.(bool)
+
+		v, _ = index.Get("sparse")
+		res[i].Sparse, _ = v.(bool)
+
+		if v, _ = index.Get("partialFilterExpression"); v != nil {
+			res[i].PartialFilterExpression = v.(*types.Document)
+		}
+
+		if v, _ = index.Get("wildcardProjection"); v != nil {
+			res[i].WildcardProjection = v.(*types.Document)
+		}
 	}
 
 	*s = res
@@ -48,6 +48,48 @@ type Collection struct {
 	Indexes         Indexes
 	CappedSize      int64
 	CappedDocuments int64
+	Validation      *Validation
+	View            *View // nil for regular collections
+}
+
+// View represents the definition of the view.
+type View struct {
+	ViewOn   string
+	Pipeline *types.Array
+}
+
+// deepCopy returns a deep copy.
+func (v *View) deepCopy() *View {
+	if v == nil {
+		return nil
+	}
+
+	return &View{
+		ViewOn:   v.ViewOn,
+		Pipeline: v.Pipeline.DeepCopy(),
+	}
+}
+
+// Validation represents document validation settings of the collection.
+type Validation struct {
+	Validator *types.Document
+	Level     string
+	Action    string
+}
+
+// deepCopy returns a deep copy.
+func (v *Validation) deepCopy() *Validation {
+	if v == nil {
+		return nil
+	}
+
+	res := *v
+
+	if v.Validator != nil {
+		res.Validator = v.Validator.DeepCopy()
+	}
+
+	return &res
 }
 
 // deepCopy returns a deep copy.
@@ -63,6 +105,8 @@ func (c *Collection) deepCopy() *Collection {
 		Indexes:         c.Indexes.deepCopy(),
 		CappedSize:      c.CappedSize,
 		CappedDocuments: c.CappedDocuments,
+		Validation:      c.Validation.deepCopy(),
+		View:            c.View.deepCopy(),
 	}
 }
 
@@ -111,7 +155,7 @@ func (c *Collection) Scan(src any) error {
 
 // marshal returns [*types.Document] for that collection.
 func (c *Collection) marshal() *types.Document {
-	return must.NotFail(types.NewDocument(
+	doc := must.NotFail(types.NewDocument(
 		"_id", c.Name,
 		"uuid", c.UUID,
 		"table", c.TableName,
@@ -119,6 +163,28 @@ func (c *Collection) marshal() *types.Document {
 		"cappedSize", c.CappedSize,
 		"cappedDocs", c.CappedDocuments,
 	))
+
+	if v := c.Validation; v != nil {
+		validation := must.NotFail(types.NewDocument(
+			"level", v.Level,
+			"action", v.Action,
+		))
+
+		if v.Validator != nil {
+			validation.Set("validator", v.Validator)
+		}
+
+		doc.Set("validation", validation)
+	}
+
+	if v := c.View; v != nil {
+		doc.Set("view", must.NotFail(types.NewDocument(
+			"viewOn", v.ViewOn,
+			"pipeline", v.Pipeline,
+		)))
+	}
+
+	return doc
 }
 
 // unmarshal sets collection metadata from [*types.Document].
@@ -162,6 +228,28 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		c.CappedDocuments = v.(int64)
 	}
 
+	if v, _ := doc.Get("validation"); v != nil {
+		validation := v.(*types.Document)
+
+		c.Validation = &Validation{
+			Level:  must.NotFail(validation.Get("level")).(string),
+			Action: must.NotFail(validation.Get("action")).(string),
+		}
+
+		if v, _ := validation.Get("validator"); v != nil {
+			c.Validation.Validator = v.(*types.Document)
+		}
+	}
+
+	if v, _ := doc.Get("view"); v != nil {
+		view := v.(*types.Document)
+
+		c.View = &View{
+			ViewOn:   must.NotFail(view.Get("viewOn")).(string),
+			Pipeline: must.NotFail(view.Get("pipeline")).(*types.Array),
+		}
+	}
+
 	return nil
 }
 
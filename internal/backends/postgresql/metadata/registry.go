@@ -24,6 +24,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/AlekSi/pointer"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -445,6 +446,8 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
+	Validation      *Validation
+	View            *View    // set for views only
 	_               struct{} // prevent unkeyed literals
 }
 
@@ -527,6 +530,8 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 		TableName:       tableName,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		Validation:      params.Validation.deepCopy(),
+		View:            params.View.deepCopy(),
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s (`, pgx.Identifier{dbName, tableName}.Sanitize())
@@ -733,6 +738,78 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	return true, nil
 }
 
+// CollectionModifyParams contains parameters for CollectionModify.
+type CollectionModifyParams struct {
+	DBName     string
+	Name       string
+	Validation *Validation // nil leaves validation settings unchanged
+
+	CappedSize      *int64 // nil leaves it unchanged
+	CappedDocuments *int64 // nil leaves it unchanged
+
+	_ struct{} // prevent unkeyed literals
+}
+
+// CollectionModify modifies settings of the collection in the database.
+//
+// Returned boolean value indicates whether the collection was modified.
+// If database or collection did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionModify(ctx context.Context, params *CollectionModifyParams) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.Name)
+	if c == nil {
+		return false, nil
+	}
+
+	if params.Validation != nil {
+		c.Validation = params.Validation.deepCopy()
+	}
+
+	if params.CappedSize != nil {
+		c.CappedSize = *params.CappedSize
+	}
+
+	if params.CappedDocuments != nil {
+		c.CappedDocuments = *params.CappedDocuments
+	}
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(c.Name)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	q := fmt.Sprintf(
+		`UPDATE %s SET %s = $1 WHERE %s = $2`,
+		pgx.Identifier{params.DBName, metadataTableName}.Sanitize(),
+		DefaultColumn,
+		IDColumn,
+	)
+
+	if _, err = p.Exec(ctx, q, string(b), arg); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.Name] = c
+
+	return true, nil
+}
+
 // IndexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
@@ -827,39 +904,7 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 
 		index.PgIndex = pgIndexName
 
-		q := "CREATE "
-
-		if index.Unique {
-			q += "UNIQUE "
-		}
-
-		q += "INDEX %s ON %s (%s)"
-
-		columns := make([]string, len(index.Key))
-
-		for i, key := range index.Key {
-			// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
-			fs := strings.Split(key.Field, ".")
-			transformedParts := make([]string, len(fs))
-
-			for j, f := range fs {
-				// It's important to sanitize field.Field data here, as it's a user-provided value.
-				transformedParts[j] = quoteString(f)
-			}
-
-			columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
-			if key.Descending {
-				columns[i] += " DESC"
-			}
-		}
-
-		q = fmt.Sprintf(
-			q,
-			pgx.Identifier{index.PgIndex}.Sanitize(),
-			pgx.Identifier{dbName, c.TableName}.Sanitize(),
-			strings.Join(columns, ", "),
-		)
-
+		q := indexCreateQuery(dbName, c.TableName, &index)
 		if _, err = p.Exec(ctx, q); err != nil {
 			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
 			return lazyerrors.Error(err)
@@ -897,6 +942,272 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 	return nil
 }
 
+// indexCreateQuery returns a query that creates the index.
+func indexCreateQuery(dbName, tableName string, index *IndexInfo) string {
+	q := "CREATE "
+
+	if index.Unique {
+		q += "UNIQUE "
+	}
+
+	q += "INDEX %s ON %s (%s)"
+
+	columns := make([]string, len(index.Key))
+
+	for i, key := range index.Key {
+		columns[i] = "(" + IndexKeyExpression(key.Field) + ")"
+		if key.Descending {
+			columns[i] += " DESC"
+		}
+	}
+
+	// text index covers all text fields at once
+	if index.Text != nil {
+		q = "CREATE INDEX %s ON %s USING GIN (%s)"
+		columns = []string{TextSearchVector(index.Text)}
+	}
+
+	// geospatial index covers only the location field
+	if index.Geo != nil {
+		q = "CREATE INDEX %s ON %s USING GIST (%s)"
+		columns = []string{"(" + GeoBox(index.GeoField()) + ")"}
+	}
+
+	// wildcard index covers all values of the document or its part
+	if index.IsWildcard() {
+		q = "CREATE INDEX %s ON %s USING GIN (%s)"
+		columns = []string{index.WildcardColumn() + " jsonb_path_ops"}
+	}
+
+	q = fmt.Sprintf(
+		q,
+		pgx.Identifier{index.PgIndex}.Sanitize(),
+		pgx.Identifier{dbName, tableName}.Sanitize(),
+		strings.Join(columns, ", "),
+	)
+
+	if where := index.WhereClause(); where != "" {
+		q += " WHERE " + where
+	}
+
+	return q
+}
+
+// IndexKeyExpression returns SQL expression of the field value that is indexed by regular indexes.
+//
+// Queries should use the same expression to be able to use such indexes.
+func IndexKeyExpression(field string) string {
+	// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
+	fs := strings.Split(field, ".")
+	transformedParts := make([]string, len(fs))
+
+	for j, f := range fs {
+		// It's important to sanitize field.Field data here, as it's a user-provided value.
+		transformedParts[j] = quoteString(f)
+	}
+
+	return fmt.Sprintf("(%s->%s)", DefaultColumn, strings.Join(transformedParts, " -> "))
+}
+
+// IndexKeyTypeExpression returns SQL expression of the schema type of the field value
+// that is indexed by regular indexes; see IndexKeyExpression.
+func IndexKeyTypeExpression(field string) string {
+	return "(" + typeExpression(strings.Split(field, ".")) + ")"
+}
+
+// WhereClause returns the condition of the sparse or partial index, or an empty string for other indexes.
+//
+// Queries should contain that condition to be able to use such index.
+func (index *IndexInfo) WhereClause() string {
+	var conditions []string
+
+	if sparse := index.SparseWhereClause(); sparse != "" {
+		conditions = append(conditions, sparse)
+	}
+
+	if index.PartialFilterExpression != nil {
+		for _, c := range backends.PartialFilterConditions(index.PartialFilterExpression) {
+			conditions = append(conditions, partialFilterCondition(&c))
+		}
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// SparseWhereClause returns the condition of the sparse index, or an empty string for non-sparse indexes.
+//
+// Unlike the condition of the partial index, it selects exactly the documents that MongoDB indexes.
+func (index *IndexInfo) SparseWhereClause() string {
+	if !index.Sparse {
+		return ""
+	}
+
+	exists := make([]string, len(index.Key))
+	for i, key := range index.Key {
+		exists[i] = fmt.Sprintf("(%s) IS NOT NULL", valueExpression(strings.Split(key.Field, ".")))
+	}
+
+	return "(" + strings.Join(exists, " OR ") + ")"
+}
+
+// partialFilterCondition returns SQL condition for the given partial index filter condition.
+func partialFilterCondition(c *backends.PartialFilterCondition) string {
+	path := c.Path.Slice()
+	value := valueExpression(path)
+
+	if c.Operator == "$exists" {
+		return fmt.Sprintf("(%s) IS NOT NULL", value)
+	}
+
+	op := map[string]string{"$eq": "=", "$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[c.Operator]
+	must.BeTrue(op != "")
+
+	typs := "('double', 'int', 'long')"
+
+	switch c.Value.(type) {
+	case string:
+		typs = "('string')"
+	case bool:
+		typs = "('bool')"
+	case int32, int64, float64:
+		// numbers of all types are compared
+	default:
+		panic(fmt.Sprintf("unexpected partial filter value type %T", c.Value))
+	}
+
+	return fmt.Sprintf(
+		"(%s) IN %s AND (%s) %s %s::jsonb",
+		typeExpression(path), typs, value, op, quoteString(string(must.NotFail(sjson.MarshalSingleValue(c.Value)))),
+	)
+}
+
+// valueExpression returns SQL expression for the SJSON document's field value with the given path.
+func valueExpression(path []string) string {
+	elems := make([]string, len(path))
+	for i, e := range path {
+		// It's important to sanitize field names here, as they are user-provided values.
+		elems[i] = quoteString(e)
+	}
+
+	return DefaultColumn + "->" + strings.Join(elems, "->")
+}
+
+// typeExpression returns SQL expression for the SJSON document's schema type of the field with the given path.
+func typeExpression(path []string) string {
+	elems := make([]string, len(path))
+	for i, e := range path {
+		elems[i] = "'$s'->'p'->" + quoteString(e)
+	}
+
+	return DefaultColumn + "->" + strings.Join(elems, "->") + "->>'t'"
+}
+
+// IndexModifyParams contains parameters for IndexModify.
+type IndexModifyParams struct {
+	DBName             string
+	CollectionName     string
+	Name               string
+	ExpireAfterSeconds *int32 // nil leaves it unchanged
+	Hidden             *bool  // nil leaves it unchanged
+	PrepareUnique      *bool  // nil leaves it unchanged
+	Unique             bool   // true converts the index to the unique one
+	_                  struct{}
+}
+
+// IndexModify modifies options of the existing index.
+//
+// Returned boolean value indicates whether the index was modified.
+// If database, collection or index did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) IndexModify(ctx context.Context, params *IndexModifyParams) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.CollectionName)
+	if c == nil {
+		return false, nil
+	}
+
+	i := slices.IndexFunc(c.Indexes, func(i IndexInfo) bool { return params.Name == i.Name })
+	if i < 0 {
+		return false, nil
+	}
+
+	index := &c.Indexes[i]
+
+	if params.ExpireAfterSeconds != nil {
+		index.ExpireAfterSeconds = pointer.To(*params.ExpireAfterSeconds)
+	}
+
+	if params.Hidden != nil {
+		index.Hidden = *params.Hidden
+	}
+
+	if params.PrepareUnique != nil {
+		index.PrepareUnique = *params.PrepareUnique
+	}
+
+	convert := params.Unique && !index.Unique
+	if convert {
+		if index.Text != nil || index.Geo != nil {
+			return false, lazyerrors.Errorf("index %q can't be unique", index.Name)
+		}
+
+		index.Unique = true
+	}
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(c.Name)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+		if convert {
+			q := fmt.Sprintf("DROP INDEX %s", pgx.Identifier{params.DBName, index.PgIndex}.Sanitize())
+			if _, err = tx.Exec(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			if _, err = tx.Exec(ctx, indexCreateQuery(params.DBName, c.TableName, index)); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf(
+			`UPDATE %s SET %s = $1 WHERE %s = $2`,
+			pgx.Identifier{params.DBName, metadataTableName}.Sanitize(),
+			DefaultColumn,
+			IDColumn,
+		)
+
+		if _, err = tx.Exec(ctx, q, string(b), arg); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.CollectionName] = c
+
+	return true, nil
+}
+
 // IndexesDrop removes given connection's indexes.
 //
 // Non-existing indexes are ignored.
@@ -0,0 +1,60 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextSearchConfig returns PostgreSQL text search configuration for the text index language.
+//
+// Only English words are stemmed, the same way as the handler does that.
+func TextSearchConfig(language string) string {
+	switch language {
+	case "english", "en":
+		return "english"
+	default:
+		return "simple"
+	}
+}
+
+// TextSearchVector returns SQL expression of tsvector for all fields of the text index.
+//
+// The same expression is used for the index and for queries, so the index could be used by them.
+func TextSearchVector(opts *TextIndexOptions) string {
+	config := quoteString(TextSearchConfig(opts.DefaultLanguage)) + "::regconfig"
+
+	vectors := make([]string, len(opts.Weights))
+
+	for i, w := range opts.Weights {
+		value := DefaultColumn
+
+		if w.Field != "$**" {
+			// lax mode of JSON path unwraps arrays, so values of array documents' fields are indexed too
+			fs := strings.Split(w.Field, ".")
+			for j, f := range fs {
+				fs[j] = `."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(f) + `"`
+			}
+
+			value = fmt.Sprintf("jsonb_path_query_array(%s, %s)", DefaultColumn, quoteString("$"+strings.Join(fs, "")))
+		}
+
+		// only string values are indexed, including strings in arrays
+		vectors[i] = fmt.Sprintf(`coalesce(jsonb_to_tsvector(%s, %s, '["string"]'), ''::tsvector)`, config, value)
+	}
+
+	return "(" + strings.Join(vectors, " || ") + ")"
+}
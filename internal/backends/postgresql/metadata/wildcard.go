@@ -0,0 +1,46 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+)
+
+// IsWildcard returns true for wildcard indexes.
+func (index *IndexInfo) IsWildcard() bool {
+	return len(index.Key) == 1 && index.Key[0].Type == "" && backends.IsWildcardIndexKey(index.Key[0].Field)
+}
+
+// WildcardColumn returns SQL expression indexed by GIN index of the wildcard index:
+// the whole document for `$**` key, or the value of the given path for `path.$**` key.
+//
+// Wildcard index with projection covers the whole document; the projection only limits fields used by queries.
+func (index *IndexInfo) WildcardColumn() string {
+	prefix := strings.TrimSuffix(strings.TrimSuffix(index.Key[0].Field, "$**"), ".")
+	if prefix == "" {
+		return DefaultColumn
+	}
+
+	fs := strings.Split(prefix, ".")
+	parts := make([]string, len(fs))
+
+	for i, f := range fs {
+		parts[i] = quoteString(f)
+	}
+
+	return "(" + DefaultColumn + "->" + strings.Join(parts, " -> ") + ")"
+}
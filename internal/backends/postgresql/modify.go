@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// modifyInTransaction calls ModifyAllParams.Modify function for documents locked by SELECT ... FOR UPDATE query
+// and applies returned changes in the given transaction, adding the numbers of changed documents to the result.
+//
+// Documents inserted concurrently are not locked by that query,
+// and other documents could be changed concurrently after they were queried by Modify.
+// So transactions that insert documents into the same collection or query other documents
+// are serialized with an advisory lock, and Modify is called again after acquiring it.
+//
+// Documents are read before calling Modify, as the connection can't run other queries
+// while there are unread rows.
+func modifyInTransaction(ctx context.Context, tx pgx.Tx, schema string, meta *metadata.Collection, sq *selectQuery, params *backends.ModifyAllParams, res *backends.ModifyAllResult) error { //nolint:lll // for readability
+	var locked, queried bool
+
+	query := func(filter *types.Document) (types.DocumentsIterator, error) {
+		queried = true
+
+		q, err := prepareQuery(schema, meta, &backends.QueryParams{Filter: filter})
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		rows, err := tx.Query(ctx, q.q, q.args...)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return newQueryIterator(ctx, rows, false), nil
+	}
+
+	for {
+		rows, err := tx.Query(ctx, sq.q+` FOR UPDATE`, sq.args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		docs, err := iterator.ConsumeValues(newQueryIterator(ctx, rows, false))
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		iter := iterator.Values(iterator.ForSlice(docs))
+		changes, err := params.Modify(iter, query)
+		iter.Close()
+
+		if err != nil {
+			return err
+		}
+
+		if (len(changes.Insert) > 0 || queried) && !locked {
+			if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, schema+"."+meta.TableName); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			locked = true
+
+			continue
+		}
+
+		return applyChanges(ctx, tx, schema, meta, changes, res)
+	}
+}
+
+// applyChanges applies changes returned by ModifyAllParams.Modify function in the given transaction,
+// and adds the numbers of changed documents to the result.
+func applyChanges(ctx context.Context, tx pgx.Tx, schema string, meta *metadata.Collection, changes *backends.ModifyChanges, res *backends.ModifyAllResult) error { //nolint:lll // for readability
+	table := pgx.Identifier{schema, meta.TableName}.Sanitize()
+
+	if len(changes.Delete) > 0 {
+		var placeholder metadata.Placeholder
+		placeholders := make([]string, len(changes.Delete))
+		args := make([]any, len(changes.Delete))
+
+		for i, id := range changes.Delete {
+			placeholders[i] = placeholder.Next()
+			args[i] = string(must.NotFail(sjson.MarshalSingleValue(id)))
+		}
+
+		q := fmt.Sprintf(`DELETE FROM %s WHERE %s IN (%s)`, table, metadata.IDColumn, strings.Join(placeholders, ", "))
+
+		tag, err := tx.Exec(ctx, q, args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		res.Deleted += int32(tag.RowsAffected())
+	}
+
+	q := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE %s = $2`, table, metadata.DefaultColumn, metadata.IDColumn)
+
+	for _, doc := range changes.Update {
+		b, err := sjson.Marshal(doc)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		id, _ := doc.Get("_id")
+		must.NotBeZero(id)
+
+		tag, err := tx.Exec(ctx, q, b, must.NotFail(sjson.MarshalSingleValue(id)))
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		res.Updated += int32(tag.RowsAffected())
+	}
+
+	if len(changes.Insert) > 0 {
+		q, args, err := prepareInsertStatement(schema, meta.TableName, meta.Capped(), changes.Insert)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		// conflicting documents are skipped instead of aborting the transaction
+		tag, err := tx.Exec(ctx, q+` ON CONFLICT DO NOTHING`, args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if n := tag.RowsAffected(); n != int64(len(changes.Insert)) {
+			err = fmt.Errorf("%d of %d documents were not inserted due to conflicts", int64(len(changes.Insert))-n, len(changes.Insert))
+			return backends.NewError(backends.ErrorCodeInsertDuplicateID, err)
+		}
+
+		res.Inserted += int32(len(changes.Insert))
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// queryPlan describes how the query uses the collection's regular (not text, geospatial, or wildcard) index.
+type queryPlan struct {
+	// index is the name of the used index, or an empty string if no regular index is used.
+	index string
+
+	// conditions are SQL conditions with arguments that allow PostgreSQL to use the index.
+	// They don't exclude any documents matching the filter and should be combined with AND.
+	conditions []string
+	args       []any
+
+	// orderBy is the ORDER BY clause that allows PostgreSQL to scan the index in order,
+	// or an empty string. See indexOrderBy.
+	orderBy string
+}
+
+// planQuery returns the plan of using the collection's regular index for the given filter and sort.
+//
+// PostgreSQL can't be forced to use the particular index, so only the shape of the query is changed.
+// If hint is the name of the visible regular index, the query is built to use that index.
+// Like MongoDB, the hinted sparse index skips documents without indexed fields.
+// The condition of the hinted partial index is not added, because it does not match array elements
+// and may exclude documents matching the filter; PostgreSQL may not use such index then.
+// Otherwise, the first visible non-sparse non-partial index which first key is a top-level field
+// compared for equality with a suitable value is used, then the one which leading keys match the sort keys.
+func planQuery(p *metadata.Placeholder, meta *metadata.Collection, filter, sort *types.Document, hint string) *queryPlan {
+	plan := new(queryPlan)
+
+	if hint != "" {
+		for _, index := range meta.Indexes {
+			if index.Name != hint || index.Hidden || !isRegularIndex(&index) {
+				continue
+			}
+
+			plan.index = index.Name
+
+			if where := index.SparseWhereClause(); where != "" {
+				plan.conditions = append(plan.conditions, where)
+			}
+
+			if cond, arg := indexEqualCondition(p, filter, index.Key[0].Field); cond != "" {
+				plan.conditions = append(plan.conditions, cond)
+				plan.args = append(plan.args, arg)
+
+				return plan
+			}
+
+			// capped collections without sort are sorted by record ID
+			if sort.Len() > 0 || !meta.Capped() {
+				plan.orderBy = indexOrderBy(&index, sort)
+			}
+
+			return plan
+		}
+	}
+
+	for _, field := range filter.Keys() {
+		for _, index := range meta.Indexes {
+			if index.Hidden || !isRegularIndex(&index) || index.WhereClause() != "" || index.Key[0].Field != field {
+				continue
+			}
+
+			cond, arg := indexEqualCondition(p, filter, field)
+			if cond == "" {
+				break
+			}
+
+			plan.index = index.Name
+			plan.conditions = []string{cond}
+			plan.args = []any{arg}
+
+			return plan
+		}
+	}
+
+	if sort.Len() == 0 {
+		return plan
+	}
+
+	for _, index := range meta.Indexes {
+		if index.Hidden || !isRegularIndex(&index) || index.WhereClause() != "" {
+			continue
+		}
+
+		if orderBy := indexOrderBy(&index, sort); orderBy != "" {
+			plan.index = index.Name
+			plan.orderBy = orderBy
+
+			return plan
+		}
+	}
+
+	return plan
+}
+
+// indexOrderBy returns ORDER BY clause on the index key expressions for the given sort document,
+// or an empty string if the index can't be used for that sort.
+//
+// Sort keys should be the leading keys of the index, with the same or all opposite directions,
+// so PostgreSQL could scan the index forward or backward.
+// Unlike prepareOrderByClause, it uses jsonb order that differs from BSON order for values of different types
+// and, depending on the collation, for strings; the handler sorts documents again anyway.
+// If the sort document is empty, it returns ORDER BY on all index keys.
+func indexOrderBy(index *metadata.IndexInfo, sort *types.Document) string {
+	if sort.Len() == 0 {
+		columns := make([]string, len(index.Key))
+
+		for i, key := range index.Key {
+			columns[i] = metadata.IndexKeyExpression(key.Field)
+			if key.Descending {
+				columns[i] += " DESC"
+			}
+		}
+
+		return " ORDER BY " + strings.Join(columns, ", ")
+	}
+
+	if sort.Len() > len(index.Key) {
+		return ""
+	}
+
+	columns := make([]string, sort.Len())
+	var reversed bool
+
+	for i, field := range sort.Keys() {
+		key := index.Key[i]
+		descending := sort.Values()[i].(int64) == -1
+
+		if key.Field != field {
+			return ""
+		}
+
+		if i == 0 {
+			reversed = key.Descending != descending
+		}
+
+		if reversed != (key.Descending != descending) {
+			return ""
+		}
+
+		columns[i] = metadata.IndexKeyExpression(field)
+		if descending {
+			columns[i] += " DESC"
+		}
+	}
+
+	return " ORDER BY " + strings.Join(columns, ", ")
+}
+
+// isRegularIndex returns true if the given index is not text, geospatial, or wildcard one.
+func isRegularIndex(index *metadata.IndexInfo) bool {
+	return index.Text == nil && index.Geo == nil && !index.IsWildcard()
+}
+
+// indexEqualCondition returns SQL condition with argument that selects documents where the value
+// of the given top-level field (or one of its array elements) may be equal to the filter value,
+// using the index expression of regular indexes.
+//
+// It returns an empty string if the filter does not compare that field for equality with a value
+// which could be compared as jsonb.
+func indexEqualCondition(p *metadata.Placeholder, filter *types.Document, field string) (string, any) {
+	if strings.ContainsRune(field, '.') || strings.HasPrefix(field, "$") {
+		return "", nil
+	}
+
+	v, _ := filter.Get(field)
+
+	if doc, ok := v.(*types.Document); ok {
+		if doc.Len() != 1 || doc.Keys()[0] != "$eq" {
+			return "", nil
+		}
+
+		v = doc.Values()[0]
+	}
+
+	switch v := v.(type) {
+	case string, types.ObjectID, time.Time, bool, int32:
+		// always safe for comparison
+	case int64:
+		if v > int64(types.MaxSafeDouble) || v < -int64(types.MaxSafeDouble) {
+			return "", nil
+		}
+	case float64:
+		// that also excludes NaN and infinities
+		if !(v <= types.MaxSafeDouble && v >= -types.MaxSafeDouble) {
+			return "", nil
+		}
+	default:
+		return "", nil
+	}
+
+	expr := metadata.IndexKeyExpression(field)
+	arg := string(must.NotFail(sjson.MarshalSingleValue(v)))
+
+	// _id can't be an array
+	if field == "_id" {
+		return fmt.Sprintf(`%s = %s`, expr, p.Next()), arg
+	}
+
+	// jsonb arrays are ordered after all scalars and before all objects,
+	// so include all non-empty arrays as they may contain the value
+	cond := fmt.Sprintf(`(%[1]s = %[2]s OR (%[1]s >= '[null]'::jsonb AND %[1]s < '{}'::jsonb))`, expr, p.Next())
+
+	return cond, arg
+}
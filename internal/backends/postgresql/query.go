@@ -15,13 +15,18 @@
 package postgresql
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -39,6 +44,118 @@ type selectParams struct {
 
 	Capped        bool
 	OnlyRecordIDs bool
+
+	// Projection is SQL expression of the selected document (see prepareProjection).
+	// The default column is selected if it is empty.
+	Projection string
+}
+
+// selectQuery represents the query that selects documents.
+type selectQuery struct {
+	q            string
+	args         []any
+	index        string // the first used index, if any
+	skipPushdown bool
+}
+
+// prepareQuery returns the query that selects documents for the given parameters, except grouping.
+func prepareQuery(schema string, meta *metadata.Collection, params *backends.QueryParams) (*selectQuery, error) {
+	var placeholder metadata.Placeholder
+
+	projection, args := prepareProjection(&placeholder, params.Projection)
+
+	q := prepareSelectClause(&selectParams{
+		Schema:        schema,
+		Table:         meta.TableName,
+		Comment:       params.Comment,
+		Capped:        meta.Capped(),
+		OnlyRecordIDs: params.OnlyRecordIDs,
+		Projection:    projection,
+	})
+
+	where, whereArgs, exact, err := prepareLimitedWhereClause(&placeholder, params.Filter, params.Skip, params.Limit)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	args = append(args, whereArgs...)
+
+	var indexes []string
+
+	plan := planQuery(&placeholder, meta, params.Filter, params.Sort, params.Hint)
+
+	for _, cond := range plan.conditions {
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+	}
+
+	args = append(args, plan.args...)
+
+	if plan.index != "" {
+		indexes = append(indexes, plan.index)
+	}
+
+	if cond, condArgs, index := prepareTextSearchCondition(&placeholder, meta.Indexes, params.TextSearch); cond != "" {
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+
+		args = append(args, condArgs...)
+		indexes = append(indexes, index)
+	}
+
+	if cond, condArgs, index := prepareGeoCondition(&placeholder, meta.Indexes, params.Geo); cond != "" {
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+
+		args = append(args, condArgs...)
+		indexes = append(indexes, index)
+	}
+
+	if cond, condArgs, index := prepareWildcardCondition(&placeholder, meta.Indexes, params.Filter); cond != "" {
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+
+		args = append(args, condArgs...)
+		indexes = append(indexes, index)
+	}
+
+	q += where
+
+	sort, _ := prepareSortClause(plan, params.Sort, meta.Capped())
+	q += sort
+
+	var skipPushdown bool
+
+	if exact {
+		limit, limitArgs := prepareLimitClause(&placeholder, params.Skip, params.Limit)
+		q += limit
+		args = append(args, limitArgs...)
+		skipPushdown = limit != ""
+	}
+
+	res := &selectQuery{
+		q:            q,
+		args:         args,
+		skipPushdown: skipPushdown,
+	}
+
+	if len(indexes) > 0 {
+		res.index = indexes[0]
+	}
+
+	return res, nil
 }
 
 // prepareSelectClause returns SELECT clause for default column of provided schema and table name.
@@ -66,12 +183,17 @@ func prepareSelectClause(params *selectParams) string {
 		)
 	}
 
+	column := metadata.DefaultColumn
+	if params.Projection != "" {
+		column = params.Projection
+	}
+
 	if params.Capped {
 		return fmt.Sprintf(
 			`SELECT %s %s, %s FROM %s`,
 			params.Comment,
 			metadata.RecordIDColumn,
-			metadata.DefaultColumn,
+			column,
 			pgx.Identifier{params.Schema, params.Table}.Sanitize(),
 		)
 	}
@@ -79,13 +201,104 @@ func prepareSelectClause(params *selectParams) string {
 	return fmt.Sprintf(
 		`SELECT %s %s FROM %s`,
 		params.Comment,
-		metadata.DefaultColumn,
+		column,
 		pgx.Identifier{params.Schema, params.Table}.Sanitize(),
 	)
 }
 
+// prepareProjection returns SQL expression with arguments of the document with the given top-level fields only.
+//
+// The document's sjson schema is reduced to those fields too.
+// The expression is named as the default column.
+// It returns an empty string if fields are empty.
+func prepareProjection(p *metadata.Placeholder, fields []string) (string, []any) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	keys := p.Next() + "::text[]"
+
+	expr := fmt.Sprintf(
+		`jsonb_build_object('$s', jsonb_build_object(`+
+			`'$k', COALESCE((SELECT jsonb_agg(k ORDER BY i) `+
+			`FROM jsonb_array_elements_text(%[1]s->'$s'->'$k') WITH ORDINALITY AS e(k, i) WHERE k = ANY(%[2]s)), '[]'), `+
+			`'p', COALESCE((SELECT jsonb_object_agg(key, value) `+
+			`FROM jsonb_each(%[1]s->'$s'->'p') WHERE key = ANY(%[2]s)), '{}')`+
+			`)) || COALESCE((SELECT jsonb_object_agg(key, value) FROM jsonb_each(%[1]s) WHERE key = ANY(%[2]s)), '{}') `+
+			`AS %[1]s`,
+		metadata.DefaultColumn, keys,
+	)
+
+	return expr, []any{fields}
+}
+
+// prepareLimitClause returns LIMIT and OFFSET clause with arguments for the given skip and limit.
+//
+// It returns an empty string if both are zero.
+func prepareLimitClause(p *metadata.Placeholder, skip, limit int64) (string, []any) {
+	var clause string
+	var args []any
+
+	if limit != 0 {
+		clause += ` LIMIT ` + p.Next()
+		args = append(args, limit)
+	}
+
+	if skip != 0 {
+		clause += ` OFFSET ` + p.Next()
+		args = append(args, skip)
+	}
+
+	return clause, args
+}
+
+// prepareLimitedWhereClause returns WHERE clause with arguments for the given filter,
+// and true if it selects exactly the documents matching the filter,
+// so skip and limit could be applied by PostgreSQL.
+//
+// Exact clause (see prepareGroupWhereClause) is used only if skip or limit is set;
+// otherwise, or if that is not possible, the clause may select extra documents (see prepareWhereClause).
+func prepareLimitedWhereClause(p *metadata.Placeholder, filter *types.Document, skip, limit int64) (string, []any, bool, error) { //nolint:lll // for readability
+	if skip != 0 || limit != 0 {
+		placeholder := *p
+
+		if where, args, ok := prepareGroupWhereClause(&placeholder, filter); ok {
+			*p = placeholder
+			return where, args, true, nil
+		}
+	}
+
+	where, args, err := prepareWhereClause(p, filter)
+	if err != nil {
+		return "", nil, false, lazyerrors.Error(err)
+	}
+
+	return where, args, false, nil
+}
+
 // prepareWhereClause adds WHERE clause with given filters to the query and returns the query and arguments.
+//
+// Filter expressions that can't be translated to SQL are skipped, so the clause may select extra documents,
+// and the filter should be applied again.
 func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (string, []any, error) {
+	filters, args, err := prepareFilters(p, sqlFilters)
+	if err != nil {
+		return "", nil, lazyerrors.Error(err)
+	}
+
+	var filter string
+	if len(filters) > 0 {
+		filter = ` WHERE ` + strings.Join(filters, " AND ")
+	}
+
+	return filter, args, nil
+}
+
+// prepareFilters returns SQL conditions with arguments that select documents matching the given filter
+// (and maybe some extra documents). Conditions should be combined with AND.
+//
+// Logical operators are translated recursively.
+func prepareFilters(p *metadata.Placeholder, sqlFilters *types.Document) ([]string, []any, error) {
 	var filters []string
 	var args []any
 
@@ -100,12 +313,64 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 				break
 			}
 
-			return "", nil, lazyerrors.Error(err)
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		switch rootKey {
+		case "$and":
+			exprs, _ := rootVal.(*types.Array)
+
+			for i := 0; i < exprs.Len(); i++ {
+				expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+				if !ok {
+					continue
+				}
+
+				f, a, err := prepareFilters(p, expr)
+				if err != nil {
+					return nil, nil, lazyerrors.Error(err)
+				}
+
+				filters = append(filters, f...)
+				args = append(args, a...)
+			}
+
+			continue
+
+		case "$or":
+			exprs, _ := rootVal.(*types.Array)
+
+			f, a, err := filterOr(p, exprs)
+			if err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+
+			if f != "" {
+				filters = append(filters, f)
+				args = append(args, a...)
+			}
+
+			continue
+
+		case "$nor":
+			exprs, _ := rootVal.(*types.Array)
+
+			f, a, err := filterNor(p, exprs)
+			if err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+
+			if f != "" {
+				filters = append(filters, f)
+				args = append(args, a...)
+			}
+
+			continue
 		}
 
 		// don't pushdown $comment, as it's attached to query with select clause
 		//
-		// all of the other top-level operators such as `$or` do not support pushdown yet
+		// all of the other top-level operators such as `$expr` do not support pushdown
 		if strings.HasPrefix(rootKey, "$") {
 			continue
 		}
@@ -116,15 +381,21 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 
 		switch {
 		case err == nil:
-			// Handle dot notation.
-			// TODO https://github.com/FerretDB/FerretDB/issues/2069
 			if path.Len() > 1 {
+				f, a, err := prepareDotNotationFilters(p, path, rootVal)
+				if err != nil {
+					return nil, nil, lazyerrors.Error(err)
+				}
+
+				filters = append(filters, f...)
+				args = append(args, a...)
+
 				continue
 			}
 		case errors.As(err, &pe):
 			// ignore empty key error, otherwise return error
 			if pe.Code() != types.ErrPathElementEmpty {
-				return "", nil, lazyerrors.Error(err)
+				return nil, nil, lazyerrors.Error(err)
 			}
 		default:
 			panic("Invalid error type: PathError expected")
@@ -143,66 +414,48 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 						break
 					}
 
-					return "", nil, lazyerrors.Error(err)
+					return nil, nil, lazyerrors.Error(err)
 				}
 
+				var f string
+				var a []any
+
 				switch k {
 				case "$eq":
-					if f, a := filterEqual(p, rootKey, v); f != "" {
-						filters = append(filters, f)
-						args = append(args, a...)
-					}
+					f, a = filterEqual(p, rootKey, v)
 
 				case "$ne":
-					sql := `NOT ( ` +
-						// does document contain the key,
-						// it is necessary, as NOT won't work correctly if the key does not exist.
-						`%[1]s ? %[2]s AND ` +
-						// does the value under the key is equal to filter value
-						`%[1]s->%[2]s @> %[3]s AND ` +
-						// does the value type is equal to the filter's one
-						`%[1]s->'$s'->'p'->%[2]s->'t' = '"%[4]s"' )`
-
-					switch v := v.(type) {
-					case *types.Document, *types.Array, types.Binary,
-						types.NullType, types.Regex, types.Timestamp:
-						// type not supported for pushdown
-
-					case float64, bool, int32, int64:
-						filters = append(filters, fmt.Sprintf(
-							sql,
-							metadata.DefaultColumn,
-							p.Next(),
-							p.Next(),
-							sjson.GetTypeOfValue(v),
-						))
-
-						// merge with the case below?
-						// TODO https://github.com/FerretDB/FerretDB/issues/3626
-						args = append(args, rootKey, v)
-
-					case string, types.ObjectID, time.Time:
-						filters = append(filters, fmt.Sprintf(
-							sql,
-							metadata.DefaultColumn,
-							p.Next(),
-							p.Next(),
-							sjson.GetTypeOfValue(v),
-						))
-
-						// merge with the case above?
-						// TODO https://github.com/FerretDB/FerretDB/issues/3626
-						args = append(args, rootKey, string(must.NotFail(sjson.MarshalSingleValue(v))))
-
-					default:
-						panic(fmt.Sprintf("Unexpected type of value: %v", v))
+					f, a = filterNotEqual(p, rootKey, v)
+
+				case "$gt", "$gte", "$lt", "$lte":
+					f, a = filterRange(p, rootKey, k, v)
+
+				case "$in":
+					values, _ := v.(*types.Array)
+					f, a = filterIn(p, rootKey, values)
+
+				case "$nin":
+					values, _ := v.(*types.Array)
+
+					for i := 0; i < values.Len(); i++ {
+						if f, a := filterNotEqual(p, rootKey, must.NotFail(values.Get(i))); f != "" {
+							filters = append(filters, f)
+							args = append(args, a...)
+						}
 					}
 
+				case "$exists":
+					f, a = filterExists(p, rootKey, v)
+
 				default:
-					// $gt and $lt
 					// TODO https://github.com/FerretDB/FerretDB/issues/1875
 					continue
 				}
+
+				if f != "" {
+					filters = append(filters, f)
+					args = append(args, a...)
+				}
 			}
 
 		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp:
@@ -219,113 +472,995 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 		}
 	}
 
-	var filter string
-	if len(filters) > 0 {
-		filter = ` WHERE ` + strings.Join(filters, " AND ")
+	return filters, args, nil
+}
+
+// filterOr returns SQL filter with arguments for `$or` operator expressions.
+//
+// It returns an empty string if any expression can't be translated,
+// as skipping it would exclude documents matching only that expression.
+func filterOr(p *metadata.Placeholder, exprs *types.Array) (string, []any, error) {
+	placeholder := *p
+
+	var filters []string
+	var args []any
+
+	for i := 0; i < exprs.Len(); i++ {
+		expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+		if !ok {
+			*p = placeholder
+			return "", nil, nil
+		}
+
+		f, a, err := prepareFilters(p, expr)
+		if err != nil {
+			return "", nil, lazyerrors.Error(err)
+		}
+
+		if len(f) == 0 {
+			*p = placeholder
+			return "", nil, nil
+		}
+
+		filters = append(filters, "("+strings.Join(f, " AND ")+")")
+		args = append(args, a...)
 	}
 
-	return filter, args, nil
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+
+	return "(" + strings.Join(filters, " OR ") + ")", args, nil
 }
 
-// prepareOrderByClause returns ORDER BY clause with arguments for given sort document.
+// filterNor returns SQL filter with arguments for `$nor` operator expressions.
 //
-// The provided sort document should be already validated.
-//
-// For capped collection, it returns ORDER BY recordID only if sort document is empty.
-// For more than one sort fields, it sorts only by the first key provided.
-func prepareOrderByClause(p *metadata.Placeholder, sort *types.Document, capped bool) (string, []any) {
-	if sort.Len() == 0 {
-		if capped {
-			return fmt.Sprintf(" ORDER BY %s", metadata.RecordIDColumn), nil
+// Only documents strictly matching one of expressions are excluded (see prepareStrictFilters).
+// It returns an empty string if any expression can't be translated that way.
+func filterNor(p *metadata.Placeholder, exprs *types.Array) (string, []any, error) {
+	placeholder := *p
+
+	var filters []string
+	var args []any
+
+	for i := 0; i < exprs.Len(); i++ {
+		expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+		if !ok {
+			*p = placeholder
+			return "", nil, nil
 		}
 
-		return "", nil
-	}
+		f, a, err := prepareStrictFilters(p, expr)
+		if err != nil {
+			return "", nil, lazyerrors.Error(err)
+		}
 
-	k := sort.Keys()[0]
-	v := sort.Values()[0].(int64)
+		if len(f) == 0 {
+			*p = placeholder
+			return "", nil, nil
+		}
 
-	// Skip sorting dot notation
-	if strings.ContainsRune(k, '.') {
-		return "", nil
+		filters = append(filters, "("+strings.Join(f, " AND ")+")")
+		args = append(args, a...)
 	}
 
-	var order string
-	if v == -1 {
-		order = " DESC"
+	if len(filters) == 0 {
+		return "", nil, nil
 	}
 
-	return fmt.Sprintf(" ORDER BY %s->%s%s", metadata.DefaultColumn, p.Next(), order), []any{k}
+	return "NOT ( " + strings.Join(filters, " OR ") + " )", args, nil
 }
 
-// filterEqual returns the proper SQL filter with arguments that filters documents
-// where the value under k is equal to v.
-func filterEqual(p *metadata.Placeholder, k string, v any) (filter string, args []any) {
-	// Select if value under the key is equal to provided value.
-	sql := `%[1]s->%[2]s @> %[3]s`
+// prepareStrictFilters returns SQL conditions with arguments that select only documents matching the given filter
+// (but maybe not all of them), so they could be negated. Conditions should be combined with AND.
+//
+// Only implicit equality, `$eq`, `$in` and `$exists` operators for top-level fields are supported.
+// It returns nil if any expression of the filter can't be translated.
+func prepareStrictFilters(p *metadata.Placeholder, sqlFilters *types.Document) ([]string, []any, error) {
+	placeholder := *p
 
-	switch v := v.(type) {
-	case *types.Document, *types.Array, types.Binary,
-		types.NullType, types.Regex, types.Timestamp:
-		// type not supported for pushdown
+	var filters []string
+	var args []any
 
-	case float64:
-		// If value is not safe double, fetch all numbers out of safe range.
-		// TODO https://github.com/FerretDB/FerretDB/issues/3626
-		switch {
-		case v > types.MaxSafeDouble:
-			sql = `%[1]s->%[2]s > %[3]s`
-			v = types.MaxSafeDouble
+	iter := sqlFilters.Iterator()
+	defer iter.Close()
 
-		case v < -types.MaxSafeDouble:
-			sql = `%[1]s->%[2]s < %[3]s`
-			v = -types.MaxSafeDouble
-		default:
-			// don't change the default eq query
+	for {
+		key, val, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, nil, lazyerrors.Error(err)
 		}
 
-		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
-		args = append(args, k, v)
+		var f string
+		var a []any
 
-	case string, types.ObjectID, time.Time:
-		// merge with the case below?
-		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		if path, err := types.NewPathFromString(key); err == nil && path.Len() == 1 && !strings.HasPrefix(key, "$") {
+			if f, a, err = filterFieldStrict(p, key, val); err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+		}
 
-		// don't change the default eq query
-		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
-		args = append(args, k, string(must.NotFail(sjson.MarshalSingleValue(v))))
+		if f == "" {
+			*p = placeholder
+			return nil, nil, nil
+		}
 
-	case bool, int32:
-		// merge with the case above?
-		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		filters = append(filters, f)
+		args = append(args, a...)
+	}
 
-		// don't change the default eq query
-		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
-		args = append(args, k, v)
+	return filters, args, nil
+}
 
-	case int64:
-		// TODO https://github.com/FerretDB/FerretDB/issues/3626
-		maxSafeDouble := int64(types.MaxSafeDouble)
+// filterFieldStrict returns SQL filter with arguments that selects only documents
+// where the value under top-level key k matches v (implicit equality or operators).
+//
+// It returns an empty string if that can't be done.
+func filterFieldStrict(p *metadata.Placeholder, k string, v any) (string, []any, error) {
+	placeholder := *p
 
-		// If value cannot be safe double, fetch all numbers out of the safe range.
-		switch {
-		case v > maxSafeDouble:
-			sql = `%[1]s->%[2]s > %[3]s`
-			v = maxSafeDouble
+	doc, ok := v.(*types.Document)
+	if !ok {
+		f, a := filterEqualStrict(p, k, v)
+		return f, a, nil
+	}
 
-		case v < -maxSafeDouble:
-			sql = `%[1]s->%[2]s < %[3]s`
-			v = -maxSafeDouble
-		default:
-			// don't change the default eq query
+	var filters []string
+	var args []any
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		op, opVal, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return "", nil, lazyerrors.Error(err)
 		}
 
-		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
-		args = append(args, k, v)
+		var f string
+		var a []any
 
-	default:
-		panic(fmt.Sprintf("Unexpected type of value: %v", v))
-	}
+		switch op {
+		case "$eq":
+			f, a = filterEqualStrict(p, k, opVal)
 
-	return
+		case "$in":
+			values, _ := opVal.(*types.Array)
+
+			var in []string
+
+			for i := 0; i < values.Len(); i++ {
+				inF, inA := filterEqualStrict(p, k, must.NotFail(values.Get(i)))
+				if inF == "" {
+					in = nil
+					break
+				}
+
+				in = append(in, "("+inF+")")
+				a = append(a, inA...)
+			}
+
+			if len(in) > 0 {
+				f = "(" + strings.Join(in, " OR ") + ")"
+			}
+
+		case "$exists":
+			f, a = filterExists(p, k, opVal)
+		}
+
+		if f == "" {
+			*p = placeholder
+			return "", nil, nil
+		}
+
+		filters = append(filters, f)
+		args = append(args, a...)
+	}
+
+	if len(filters) == 0 {
+		*p = placeholder
+		return "", nil, nil
+	}
+
+	return strings.Join(filters, " AND "), args, nil
+}
+
+// prepareTextSearchCondition returns SQL condition with arguments that selects documents
+// containing any of the given words, using the collection's text index.
+//
+// It also returns the name of that index.
+// It returns an empty string if the collection has no visible text index or there are no words.
+func prepareTextSearchCondition(p *metadata.Placeholder, indexes metadata.Indexes, params *backends.TextSearchParams) (string, []any, string) { //nolint:lll // for readability
+	if params == nil || len(params.Words) == 0 {
+		return "", nil, ""
+	}
+
+	for _, index := range indexes {
+		if index.Text == nil || index.Hidden {
+			continue
+		}
+
+		cond := fmt.Sprintf(
+			`%s @@ to_tsquery(%s::regconfig, %s)`,
+			metadata.TextSearchVector(index.Text), p.Next(), p.Next(),
+		)
+
+		args := []any{metadata.TextSearchConfig(index.Text.DefaultLanguage), strings.Join(params.Words, " | ")}
+
+		return cond, args, index.Name
+	}
+
+	return "", nil, ""
+}
+
+// prepareGeoCondition returns SQL condition with arguments that selects documents
+// with locations intersecting the given box, using the collection's geospatial index.
+//
+// It also returns the name of that index.
+// It returns an empty string if the collection has no visible geospatial index on the given field.
+func prepareGeoCondition(p *metadata.Placeholder, indexes metadata.Indexes, params *backends.GeoParams) (string, []any, string) { //nolint:lll // for readability
+	if params == nil {
+		return "", nil, ""
+	}
+
+	for _, index := range indexes {
+		if index.Geo == nil || index.Hidden || index.GeoField() != params.Field {
+			continue
+		}
+
+		cond := fmt.Sprintf(
+			`%s && box(point(%s, %s), point(%s, %s))`,
+			metadata.GeoBox(params.Field), p.Next(), p.Next(), p.Next(), p.Next(),
+		)
+
+		return cond, []any{params.MinX, params.MinY, params.MaxX, params.MaxY}, index.Name
+	}
+
+	return "", nil, ""
+}
+
+// prepareWildcardCondition returns SQL condition with arguments that selects documents
+// where the value of top-level field (or one of its array elements) is equal to the filter value,
+// using the GIN index of the collection's wildcard index.
+//
+// The first filter field covered by a visible wildcard index is used; the name of that index is also returned.
+// It returns an empty string if there is no such field.
+func prepareWildcardCondition(p *metadata.Placeholder, indexes metadata.Indexes, filter *types.Document) (string, []any, string) { //nolint:lll // for readability
+	if filter == nil {
+		return "", nil, ""
+	}
+
+	values := filter.Values()
+
+	for i, field := range filter.Keys() {
+		v := values[i]
+
+		if doc, ok := v.(*types.Document); ok {
+			if doc.Len() != 1 || doc.Keys()[0] != "$eq" {
+				continue
+			}
+
+			v = doc.Values()[0]
+		}
+
+		switch v := v.(type) {
+		case string, types.ObjectID, time.Time, bool, int32:
+			// always safe for containment
+		case int64:
+			if v > int64(types.MaxSafeDouble) || v < -int64(types.MaxSafeDouble) {
+				continue
+			}
+		case float64:
+			// that also excludes NaN and infinities
+			if !(v <= types.MaxSafeDouble && v >= -types.MaxSafeDouble) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		value := must.NotFail(sjson.MarshalSingleValue(v))
+
+		for _, index := range indexes {
+			if index.Hidden || !index.IsWildcard() {
+				continue
+			}
+
+			if !backends.WildcardIndexCovers(index.Key[0].Field, index.WildcardProjection, field) {
+				continue
+			}
+
+			// top-level array contains its scalar elements
+			if index.Key[0].Field != "$**" {
+				return fmt.Sprintf(`%s @> %s`, index.WildcardColumn(), p.Next()), []any{string(value)}, index.Name
+			}
+
+			// nested array does not contain its scalar elements, so check the array with the single element too
+			scalar := must.NotFail(json.Marshal(map[string]json.RawMessage{field: value}))
+			array := must.NotFail(json.Marshal(map[string][]json.RawMessage{field: {value}}))
+
+			cond := fmt.Sprintf(`(%[1]s @> %[2]s OR %[1]s @> %[3]s)`, metadata.DefaultColumn, p.Next(), p.Next())
+
+			return cond, []any{string(scalar), string(array)}, index.Name
+		}
+	}
+
+	return "", nil, ""
+}
+
+// sortTypeRanks contains the order of sjson types values used for sorting.
+// It is the same as BSON comparison order; all numbers have the same rank.
+// Missing fields and nulls have zero rank.
+var sortTypeRanks = [][]string{
+	{"double", "int", "long"},
+	{"string"},
+	{"object"},
+	{"array"},
+	{"binData"},
+	{"objectId"},
+	{"bool"},
+	{"date"},
+	{"timestamp"},
+	{"regex"},
+}
+
+// prepareSortClause returns ORDER BY clause for the query with the given plan,
+// and true if it applies the whole sort document.
+//
+// If the plan scans the index in order, its ORDER BY clause is used.
+// Otherwise, documents are sorted by type ranks (see prepareOrderByClause).
+func prepareSortClause(plan *queryPlan, sort *types.Document, capped bool) (string, bool) {
+	if plan.orderBy != "" {
+		return plan.orderBy, sort.Len() > 0 || capped
+	}
+
+	orderBy := prepareOrderByClause(sort, capped)
+
+	return orderBy, orderBy != ""
+}
+
+// prepareOrderByClause returns ORDER BY clause for given sort document.
+//
+// The provided sort document should be already validated.
+//
+// For capped collection, it returns ORDER BY recordID only if sort document is empty.
+//
+// For each sort key, documents are sorted by the type rank of the value first (see sortTypeRanks),
+// then by strings in binary order, then by jsonb value.
+// That gives the correct order for scalar values; the handler sorts documents again anyway.
+func prepareOrderByClause(sort *types.Document, capped bool) string {
+	if sort.Len() == 0 {
+		if capped {
+			return fmt.Sprintf(" ORDER BY %s", metadata.RecordIDColumn)
+		}
+
+		return ""
+	}
+
+	var ranks string
+
+	for i, typs := range sortTypeRanks {
+		for _, t := range typs {
+			ranks += fmt.Sprintf(" WHEN '%s' THEN %d", t, i+1)
+		}
+	}
+
+	terms := make([]string, 0, sort.Len()*3)
+
+	for i, k := range sort.Keys() {
+		var order string
+		if sort.Values()[i].(int64) == -1 {
+			order = " DESC"
+		}
+
+		value := metadata.IndexKeyExpression(k)
+
+		terms = append(
+			terms,
+			fmt.Sprintf("CASE %s%s ELSE 0 END%s", metadata.IndexKeyTypeExpression(k), ranks, order),
+			fmt.Sprintf(`(CASE WHEN jsonb_typeof(%[1]s) = 'string' THEN %[1]s #>> '{}' END) COLLATE "C"%[2]s`, value, order),
+			value+order,
+		)
+	}
+
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
+// filterEqual returns the proper SQL filter with arguments that filters documents
+// where the value under k is equal to v.
+func filterEqual(p *metadata.Placeholder, k string, v any) (filter string, args []any) {
+	// Select if value under the key is equal to provided value.
+	sql := `%[1]s->%[2]s @> %[3]s`
+
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp:
+		// type not supported for pushdown
+
+	case float64:
+		// If value is not safe double, fetch all numbers out of safe range.
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		switch {
+		case v > types.MaxSafeDouble:
+			sql = `%[1]s->%[2]s > %[3]s`
+			v = types.MaxSafeDouble
+
+		case v < -types.MaxSafeDouble:
+			sql = `%[1]s->%[2]s < %[3]s`
+			v = -types.MaxSafeDouble
+		default:
+			// don't change the default eq query
+		}
+
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
+		args = append(args, k, v)
+
+	case string, types.ObjectID, time.Time:
+		// merge with the case below?
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+
+		// don't change the default eq query
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
+		args = append(args, k, string(must.NotFail(sjson.MarshalSingleValue(v))))
+
+	case bool, int32:
+		// merge with the case above?
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+
+		// don't change the default eq query
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
+		args = append(args, k, v)
+
+	case int64:
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		maxSafeDouble := int64(types.MaxSafeDouble)
+
+		// If value cannot be safe double, fetch all numbers out of the safe range.
+		switch {
+		case v > maxSafeDouble:
+			sql = `%[1]s->%[2]s > %[3]s`
+			v = maxSafeDouble
+
+		case v < -maxSafeDouble:
+			sql = `%[1]s->%[2]s < %[3]s`
+			v = -maxSafeDouble
+		default:
+			// don't change the default eq query
+		}
+
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next())
+		args = append(args, k, v)
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+
+	return
+}
+
+// filterEqualStrict returns SQL filter with arguments that selects only documents
+// where the value under k is equal to v and has the same type.
+//
+// It returns an empty string if v type is not supported.
+func filterEqualStrict(p *metadata.Placeholder, k string, v any) (filter string, args []any) {
+	// does document contain the key,
+	// it is necessary, as NOT won't work correctly if the key does not exist.
+	sql := `%[1]s ? %[2]s AND ` +
+		// does the value under the key is equal to filter value
+		`%[1]s->%[2]s @> %[3]s AND ` +
+		// does the value type is equal to the filter's one
+		`%[1]s->'$s'->'p'->%[2]s->'t' = '"%[4]s"'`
+
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp:
+		// type not supported for pushdown
+
+	case float64, bool, int32, int64:
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next(), sjson.GetTypeOfValue(v))
+
+		// merge with the case below?
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		args = append(args, k, v)
+
+	case string, types.ObjectID, time.Time:
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next(), sjson.GetTypeOfValue(v))
+
+		// merge with the case above?
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		args = append(args, k, string(must.NotFail(sjson.MarshalSingleValue(v))))
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+
+	return
+}
+
+// filterNotEqual returns SQL filter with arguments that filters documents
+// where the value under k is not equal to v.
+//
+// Documents with the value of other type are not excluded, so the filter should be applied again.
+func filterNotEqual(p *metadata.Placeholder, k string, v any) (string, []any) {
+	filter, args := filterEqualStrict(p, k, v)
+	if filter == "" {
+		return "", nil
+	}
+
+	return `NOT ( ` + filter + ` )`, args
+}
+
+// filterIn returns SQL filter with arguments that filters documents
+// where the value under k is equal to one of the given values.
+//
+// It returns an empty string if any of values is not supported.
+func filterIn(p *metadata.Placeholder, k string, values *types.Array) (string, []any) {
+	placeholder := *p
+
+	var filters []string
+	var args []any
+
+	for i := 0; i < values.Len(); i++ {
+		f, a := filterEqual(p, k, must.NotFail(values.Get(i)))
+		if f == "" {
+			*p = placeholder
+			return "", nil
+		}
+
+		filters = append(filters, f)
+		args = append(args, a...)
+	}
+
+	if len(filters) == 0 {
+		return "", nil
+	}
+
+	return "(" + strings.Join(filters, " OR ") + ")", args
+}
+
+// filterExists returns SQL filter with arguments that filters documents
+// where top-level key k exists or does not exist.
+//
+// It returns an empty string if v is not a boolean.
+func filterExists(p *metadata.Placeholder, k string, v any) (string, []any) {
+	exists, ok := v.(bool)
+	if !ok {
+		return "", nil
+	}
+
+	filter := fmt.Sprintf(`%s ? %s`, metadata.DefaultColumn, p.Next())
+	if !exists {
+		filter = `NOT ( ` + filter + ` )`
+	}
+
+	return filter, []any{k}
+}
+
+// rangeOperators maps range query operators to SQL and SQL/JSON path comparison operators.
+var rangeOperators = map[string]string{
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+// numberBound returns the SQL comparison operator and the bound for comparing numbers with v
+// using the given range query operator.
+//
+// Numbers out of safe range may be compared inexactly, so such bounds are replaced
+// with the safe ones that select all numbers that may match.
+// If all numbers may match, it returns an empty operator.
+// If no number can be compared (v is NaN), it returns false.
+func numberBound(op string, v float64) (string, float64, bool) {
+	sqlOp := rangeOperators[op]
+	greater := op == "$gt" || op == "$gte"
+
+	switch {
+	case math.IsNaN(v):
+		return "", 0, false
+
+	case v > types.MaxSafeDouble:
+		if !greater {
+			return "", 0, true
+		}
+
+		return ">", types.MaxSafeDouble, true
+
+	case v < -types.MaxSafeDouble:
+		if greater {
+			return "", 0, true
+		}
+
+		return "<", -types.MaxSafeDouble, true
+
+	default:
+		return sqlOp, v, true
+	}
+}
+
+// filterRange returns the proper SQL filter with arguments that filters documents
+// where the value under k (or one of its array elements) matches range operator op with v.
+//
+// Values of other BSON types never match, as for MongoDB.
+// Numbers and dates are compared as jsonb values, so B-tree indexes could be used.
+// Strings and ObjectIDs are compared as text in the "C" collation that matches BSON comparison order.
+// All non-empty arrays are selected, so the filter should be applied again.
+func filterRange(p *metadata.Placeholder, k, op string, v any) (filter string, args []any) {
+	sqlOp := rangeOperators[op]
+	key := p.Next()
+	args = append(args, k)
+
+	var cond string
+
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp, bool:
+		// type not supported for pushdown
+		return "", nil
+
+	case float64, int32, int64:
+		var f float64
+
+		switch v := v.(type) {
+		case float64:
+			f = v
+		case int32:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		}
+
+		var ok bool
+		if sqlOp, f, ok = numberBound(op, f); !ok {
+			return "", nil
+		}
+
+		cond = fmt.Sprintf(
+			`%[1]s->'$s'->'p'->%[2]s->'t' IN ('"int"', '"long"', '"double"')`,
+			metadata.DefaultColumn, key,
+		)
+
+		if sqlOp != "" {
+			cond = fmt.Sprintf(`%s->%s %s %s AND `, metadata.DefaultColumn, key, sqlOp, p.Next()) + cond
+			args = append(args, f)
+		}
+
+	case time.Time:
+		cond = fmt.Sprintf(
+			`%[1]s->%[2]s %[3]s %[4]s AND %[1]s->'$s'->'p'->%[2]s->'t' = '"date"'`,
+			metadata.DefaultColumn, key, sqlOp, p.Next(),
+		)
+		args = append(args, v.UnixMilli())
+
+	case string, types.ObjectID:
+		cond = fmt.Sprintf(
+			`(%[1]s->>%[2]s) COLLATE "C" %[3]s %[4]s AND %[1]s->'$s'->'p'->%[2]s->'t' = '"%[5]s"'`,
+			metadata.DefaultColumn, key, sqlOp, p.Next(), sjson.GetTypeOfValue(v),
+		)
+
+		if id, ok := v.(types.ObjectID); ok {
+			args = append(args, hex.EncodeToString(id[:]))
+		} else {
+			args = append(args, v)
+		}
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+
+	// non-empty arrays are greater than booleans and less than objects;
+	// that condition could use B-tree index too
+	filter = fmt.Sprintf(`((%[1]s) OR (%[2]s->%[3]s > 'true' AND %[2]s->%[3]s < '{}'))`, cond, metadata.DefaultColumn, key)
+
+	return filter, args
+}
+
+// prepareDotNotationFilters returns SQL conditions with arguments for the filter value of the given dot notation path.
+//
+// Only implicit equality, `$eq`, `$ne`, range, `$in`, `$nin` and `$exists` operators are pushed down.
+// Paths containing elements that could be array indexes are not pushed down,
+// as it depends on the document whether such element refers to an array element or a field.
+func prepareDotNotationFilters(p *metadata.Placeholder, path types.Path, filterVal any) ([]string, []any, error) {
+	for _, e := range path.Slice() {
+		if _, err := strconv.ParseUint(e, 10, 64); err == nil {
+			return nil, nil, nil
+		}
+	}
+
+	var filters []string
+	var args []any
+
+	doc, ok := filterVal.(*types.Document)
+	if !ok {
+		if f, a := filterDotNotationEqual(p, path, filterVal); f != "" {
+			filters = append(filters, f)
+			args = append(args, a...)
+		}
+
+		return filters, args, nil
+	}
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		var f string
+		var a []any
+
+		switch k {
+		case "$eq":
+			f, a = filterDotNotationEqual(p, path, v)
+		case "$ne":
+			f, a = filterDotNotationNotEqual(p, path, v)
+		case "$gt", "$gte", "$lt", "$lte":
+			f, a = filterDotNotationRange(p, path, k, v)
+		case "$in":
+			values, _ := v.(*types.Array)
+			f, a = filterDotNotationIn(p, path, values)
+		case "$nin":
+			values, _ := v.(*types.Array)
+
+			for i := 0; i < values.Len(); i++ {
+				if f, a := filterDotNotationNotEqual(p, path, must.NotFail(values.Get(i))); f != "" {
+					filters = append(filters, f)
+					args = append(args, a...)
+				}
+			}
+		case "$exists":
+			f, a = filterDotNotationExists(p, path, v)
+		default:
+			// TODO https://github.com/FerretDB/FerretDB/issues/1875
+			continue
+		}
+
+		if f != "" {
+			filters = append(filters, f)
+			args = append(args, a...)
+		}
+	}
+
+	return filters, args, nil
+}
+
+// filterDotNotationEqual returns the filter selecting documents with the value under the given dot notation path
+// being equal to the provided value.
+//
+// JSON path in lax mode is used, so arrays on the path (including the last element) are traversed
+// like MongoDB does. It may select some extra documents (for example, dates for the numeric value),
+// so the filter should be applied again.
+func filterDotNotationEqual(p *metadata.Placeholder, path types.Path, v any) (filter string, args []any) {
+	cond := dotNotationEqualCondition(v)
+	if cond == "" {
+		return
+	}
+
+	filter = fmt.Sprintf(`%s @? %s`, metadata.DefaultColumn, p.Next())
+	args = append(args, fmt.Sprintf(`lax %s ? (%s)`, jsonPath(path), cond))
+
+	return
+}
+
+// filterDotNotationIn returns the filter selecting documents with the value under the given dot notation path
+// being equal to one of the provided values.
+//
+// See filterDotNotationEqual for details.
+// It returns an empty string if any of values is not supported.
+func filterDotNotationIn(p *metadata.Placeholder, path types.Path, values *types.Array) (filter string, args []any) {
+	conds := make([]string, values.Len())
+
+	for i := range conds {
+		if conds[i] = dotNotationEqualCondition(must.NotFail(values.Get(i))); conds[i] == "" {
+			return
+		}
+	}
+
+	if len(conds) == 0 {
+		return
+	}
+
+	filter = fmt.Sprintf(`%s @? %s`, metadata.DefaultColumn, p.Next())
+	args = append(args, fmt.Sprintf(`lax %s ? (%s)`, jsonPath(path), strings.Join(conds, " || ")))
+
+	return
+}
+
+// filterDotNotationExists returns the filter selecting documents with the value under the given dot notation path.
+//
+// It returns an empty string if v is not true, as documents with the value can't be excluded precisely.
+func filterDotNotationExists(p *metadata.Placeholder, path types.Path, v any) (filter string, args []any) {
+	if exists, _ := v.(bool); !exists {
+		return
+	}
+
+	filter = fmt.Sprintf(`%s @? %s`, metadata.DefaultColumn, p.Next())
+	args = append(args, `lax `+jsonPath(path))
+
+	return
+}
+
+// dotNotationEqualCondition returns SQL/JSON path filter condition for the current item being equal to v.
+//
+// It returns an empty string if v type is not supported.
+func dotNotationEqualCondition(v any) string {
+	cond := `@ == %s`
+
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp:
+		// type not supported for pushdown
+		return ""
+
+	case float64:
+		// If value is not safe double, fetch all numbers out of safe range.
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		switch {
+		case math.IsNaN(v):
+			// NaN can't be marshaled and compared in SQL/JSON path
+			return ""
+
+		case v > types.MaxSafeDouble:
+			cond = `@ > %s`
+			v = types.MaxSafeDouble
+
+		case v < -types.MaxSafeDouble:
+			cond = `@ < %s`
+			v = -types.MaxSafeDouble
+		default:
+			// don't change the default eq condition
+		}
+
+		return fmt.Sprintf(cond, must.NotFail(sjson.MarshalSingleValue(v)))
+
+	case int64:
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		maxSafeDouble := int64(types.MaxSafeDouble)
+
+		// If value cannot be safe double, fetch all numbers out of the safe range.
+		switch {
+		case v > maxSafeDouble:
+			cond = `@ > %s`
+			v = maxSafeDouble
+
+		case v < -maxSafeDouble:
+			cond = `@ < %s`
+			v = -maxSafeDouble
+		default:
+			// don't change the default eq condition
+		}
+
+		return fmt.Sprintf(cond, must.NotFail(sjson.MarshalSingleValue(v)))
+
+	case string, types.ObjectID, time.Time, bool, int32:
+		return fmt.Sprintf(cond, must.NotFail(sjson.MarshalSingleValue(v)))
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+}
+
+// filterDotNotationNotEqual returns the filter excluding documents with the value under the given dot notation path
+// being equal to the provided value.
+//
+// Only documents with embedded documents (but not arrays) on the path and the value of the same type
+// (according to the `$s` schema) are excluded, so it may select some extra documents,
+// and the filter should be applied again.
+func filterDotNotationNotEqual(p *metadata.Placeholder, path types.Path, v any) (filter string, args []any) {
+	var arg any
+
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp:
+		// type not supported for pushdown
+		return
+
+	case float64:
+		// NaN and infinities can't be marshaled to jsonb
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+
+		arg = v
+
+	case bool, int32, int64:
+		arg = v
+
+	case string, types.ObjectID, time.Time:
+		arg = string(must.NotFail(sjson.MarshalSingleValue(v)))
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+
+	schemaPath := make([]string, 0, path.Len()*3+1)
+	for _, e := range path.Slice() {
+		schemaPath = append(schemaPath, "$s", "p", e)
+	}
+
+	schemaPath = append(schemaPath, "t")
+
+	// both paths return NULL if there is an array on the path
+	sql := `NOT COALESCE(%[1]s #> %[2]s @> %[3]s AND %[1]s #> %[4]s = '"%[5]s"', false)`
+
+	filter = fmt.Sprintf(sql, metadata.DefaultColumn, p.Next(), p.Next(), p.Next(), sjson.GetTypeOfValue(v))
+	args = append(args, path.Slice(), arg, schemaPath)
+
+	return
+}
+
+// filterDotNotationRange returns the filter selecting documents with the value under the given dot notation path
+// matching range operator op with v.
+//
+// JSON path in lax mode is used, so arrays on the path are traversed like MongoDB does.
+// SQL/JSON path comparison never matches values of different JSON types, and compares strings in code point order.
+// It may select some extra documents (for example, dates for the numeric value), so the filter should be applied again.
+func filterDotNotationRange(p *metadata.Placeholder, path types.Path, op string, v any) (filter string, args []any) {
+	var cond string
+
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp, bool:
+		// type not supported for pushdown
+		return
+
+	case float64, int32, int64:
+		var f float64
+
+		switch v := v.(type) {
+		case float64:
+			f = v
+		case int32:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		}
+
+		sqlOp, f, ok := numberBound(op, f)
+		if !ok {
+			return
+		}
+
+		cond = `@.type() == "number"`
+		if sqlOp != "" {
+			cond = fmt.Sprintf(`@ %s %s`, sqlOp, must.NotFail(sjson.MarshalSingleValue(f)))
+		}
+
+	case string, types.ObjectID, time.Time:
+		cond = fmt.Sprintf(`@ %s %s`, rangeOperators[op], must.NotFail(sjson.MarshalSingleValue(v)))
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+
+	filter = fmt.Sprintf(`%s @? %s`, metadata.DefaultColumn, p.Next())
+	args = append(args, fmt.Sprintf(`lax %s ? (%s)`, jsonPath(path), cond))
+
+	return
+}
+
+// jsonPath returns SQL/JSON path expression for the given dot notation path.
+func jsonPath(path types.Path) string {
+	res := "$"
+
+	for _, e := range path.Slice() {
+		res += "." + string(must.NotFail(json.Marshal(e)))
+	}
+
+	return res
 }
@@ -17,6 +17,7 @@ package postgresql
 import (
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -97,6 +98,9 @@ func TestPrepareWhereClause(t *testing.T) {
 	whereContain := " WHERE _jsonb->$1 @> $2"
 	whereGt := " WHERE _jsonb->$1 > $2"
 	whereNotEq := ` WHERE NOT ( _jsonb ? $1 AND _jsonb->$1 @> $2 AND _jsonb->'$s'->'p'->$1->'t' = `
+	whereJSONPath := " WHERE _jsonb @? $1"
+	whereArray := ` OR (_jsonb->$1 > 'true' AND _jsonb->$1 < '{}'))`
+	whereDotNotationNotEq := ` WHERE NOT COALESCE(_jsonb #> $1 @> $2 AND _jsonb #> $3 = `
 
 	for name, tc := range map[string]struct {
 		filter   *types.Document
@@ -117,15 +121,93 @@ func TestPrepareWhereClause(t *testing.T) {
 			expected: whereContain,
 		},
 		"IDDotNotation": {
-			filter: must.NotFail(types.NewDocument("_id.doc", "foo")),
+			filter:   must.NotFail(types.NewDocument("_id.doc", "foo")),
+			args:     []any{`lax $."_id"."doc" ? (@ == "foo")`},
+			expected: whereJSONPath,
 		},
 
 		"DotNotation": {
-			filter: must.NotFail(types.NewDocument("v.doc", "foo")),
+			filter:   must.NotFail(types.NewDocument("v.doc", "foo")),
+			args:     []any{`lax $."v"."doc" ? (@ == "foo")`},
+			expected: whereJSONPath,
 		},
 		"DotNotationArrayIndex": {
 			filter: must.NotFail(types.NewDocument("v.arr.0", "foo")),
 		},
+		"DotNotationQuotes": {
+			filter:   must.NotFail(types.NewDocument(`v.d"oc`, `f"oo`)),
+			args:     []any{`lax $."v"."d\"oc" ? (@ == "f\"oo")`},
+			expected: whereJSONPath,
+		},
+		"DotNotationInt32": {
+			filter:   must.NotFail(types.NewDocument("v.foo.bar", int32(42))),
+			args:     []any{`lax $."v"."foo"."bar" ? (@ == 42)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationMaxFloat64": {
+			filter:   must.NotFail(types.NewDocument("v.doc", math.MaxFloat64)),
+			args:     []any{`lax $."v"."doc" ? (@ > 9007199254740991)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationNaN": {
+			filter: must.NotFail(types.NewDocument("v.doc", math.NaN())),
+		},
+		"DotNotationNeNaN": {
+			filter: must.NotFail(types.NewDocument("v.doc", must.NotFail(types.NewDocument("$ne", math.NaN())))),
+		},
+		"DotNotationNeInfinity": {
+			filter: must.NotFail(types.NewDocument("v.doc", must.NotFail(types.NewDocument("$ne", math.Inf(1))))),
+		},
+		"DotNotationDatetime": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC),
+			)),
+			args:     []any{`lax $."v"."doc" ? (@ == 1635761922123)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationObjectID": {
+			filter:   must.NotFail(types.NewDocument("v.doc", objectID)),
+			args:     []any{`lax $."v"."doc" ? (@ == "6256c5ba0badc0ffeeffffff")`},
+			expected: whereJSONPath,
+		},
+		"DotNotationDocument": {
+			filter: must.NotFail(types.NewDocument("v.doc", must.NotFail(types.NewDocument("foo", "bar")))),
+		},
+		"DotNotationEqBool": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$eq", true)),
+			)),
+			args:     []any{`lax $."v"."doc" ? (@ == true)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationNeString": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$ne", "foo")),
+			)),
+			args: []any{
+				[]string{"v", "doc"},
+				`"foo"`,
+				[]string{"$s", "p", "v", "$s", "p", "doc", "t"},
+			},
+			expected: whereDotNotationNotEq + `'"string"', false)`,
+		},
+		"DotNotationNeInt64": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$ne", int64(42))),
+			)),
+			expected: whereDotNotationNotEq + `'"long"', false)`,
+		},
+		"DotNotationNeArrayIndex": {
+			filter: must.NotFail(types.NewDocument(
+				"v.0", must.NotFail(types.NewDocument("$ne", int64(42))),
+			)),
+		},
+		"DotNotationEqNe": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$eq", "foo", "$ne", "bar")),
+			)),
+			expected: ` WHERE _jsonb @? $1 AND NOT COALESCE(_jsonb #> $2 @> $3 AND _jsonb #> $4 = '"string"', false)`,
+		},
 
 		"ImplicitString": {
 			filter:   must.NotFail(types.NewDocument("v", "foo")),
@@ -291,6 +373,263 @@ func TestPrepareWhereClause(t *testing.T) {
 			expected: whereNotEq + `'"objectId"' )`,
 		},
 
+		"GtInt32": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", int32(42))),
+			)),
+			args: []any{`v`, float64(42)},
+			expected: ` WHERE ((_jsonb->$1 > $2 AND _jsonb->'$s'->'p'->$1->'t' IN ('"int"', '"long"', '"double"'))` +
+				whereArray,
+		},
+		"LteFloat64": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$lte", 42.13)),
+			)),
+			args: []any{`v`, 42.13},
+			expected: ` WHERE ((_jsonb->$1 <= $2 AND _jsonb->'$s'->'p'->$1->'t' IN ('"int"', '"long"', '"double"'))` +
+				whereArray,
+		},
+		"GteMaxInt64": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gte", int64(math.MaxInt64))),
+			)),
+			args: []any{`v`, types.MaxSafeDouble},
+			expected: ` WHERE ((_jsonb->$1 > $2 AND _jsonb->'$s'->'p'->$1->'t' IN ('"int"', '"long"', '"double"'))` +
+				whereArray,
+		},
+		"LtMaxFloat64": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$lt", math.MaxFloat64)),
+			)),
+			args:     []any{`v`},
+			expected: ` WHERE ((_jsonb->'$s'->'p'->$1->'t' IN ('"int"', '"long"', '"double"'))` + whereArray,
+		},
+		"GtNaN": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", math.NaN())),
+			)),
+		},
+		"LtDatetime": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument(
+					"$lt", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC),
+				)),
+			)),
+			args:     []any{`v`, int64(1635761922123)},
+			expected: ` WHERE ((_jsonb->$1 < $2 AND _jsonb->'$s'->'p'->$1->'t' = '"date"')` + whereArray,
+		},
+		"GtString": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", "foo")),
+			)),
+			args:     []any{`v`, `foo`},
+			expected: ` WHERE (((_jsonb->>$1) COLLATE "C" > $2 AND _jsonb->'$s'->'p'->$1->'t' = '"string"')` + whereArray,
+		},
+		"GteObjectID": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gte", objectID)),
+			)),
+			args:     []any{`v`, `6256c5ba0badc0ffeeffffff`},
+			expected: ` WHERE (((_jsonb->>$1) COLLATE "C" >= $2 AND _jsonb->'$s'->'p'->$1->'t' = '"objectId"')` + whereArray,
+		},
+		"GtBool": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", false)),
+			)),
+		},
+		"GtLt": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", int32(1), "$lt", int32(3))),
+			)),
+			args: []any{`v`, float64(1), `v`, float64(3)},
+			expected: ` WHERE ((_jsonb->$1 > $2 AND _jsonb->'$s'->'p'->$1->'t' IN ('"int"', '"long"', '"double"'))` +
+				` OR (_jsonb->$1 > 'true' AND _jsonb->$1 < '{}'))` +
+				` AND ((_jsonb->$3 < $4 AND _jsonb->'$s'->'p'->$3->'t' IN ('"int"', '"long"', '"double"'))` +
+				` OR (_jsonb->$3 > 'true' AND _jsonb->$3 < '{}'))`,
+		},
+		"DotNotationGtInt32": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$gt", int32(42))),
+			)),
+			args:     []any{`lax $."v"."foo" ? (@ > 42)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationLtMinFloat64": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$lt", -math.MaxFloat64)),
+			)),
+			args:     []any{`lax $."v"."foo" ? (@ < -9007199254740991)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationGtMinFloat64": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$gt", -math.MaxFloat64)),
+			)),
+			args:     []any{`lax $."v"."foo" ? (@.type() == "number")`},
+			expected: whereJSONPath,
+		},
+		"DotNotationLteString": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$lte", "foo")),
+			)),
+			args:     []any{`lax $."v"."foo" ? (@ <= "foo")`},
+			expected: whereJSONPath,
+		},
+
+		"InScalars": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", int32(42))))),
+			)),
+			args:     []any{`v`, `"foo"`, `v`, int32(42)},
+			expected: ` WHERE (_jsonb->$1 @> $2 OR _jsonb->$3 @> $4)`,
+		},
+		"InEmpty": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", types.MakeArray(0))),
+			)),
+		},
+		"InDocument": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument(
+					"$in", must.NotFail(types.NewArray("foo", must.NotFail(types.NewDocument()))),
+				)),
+			)),
+		},
+		"InDocumentWithEq": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument(
+					"$in", must.NotFail(types.NewArray("foo", must.NotFail(types.NewDocument()))),
+				)),
+				"w", "bar",
+			)),
+			args:     []any{`w`, `"bar"`},
+			expected: whereContain,
+		},
+		"Nin": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$nin", must.NotFail(types.NewArray("foo", types.Null)))),
+			)),
+			args:     []any{`v`, `"foo"`},
+			expected: whereNotEq + `'"string"' )`,
+		},
+		"ExistsTrue": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$exists", true)),
+			)),
+			args:     []any{`v`},
+			expected: ` WHERE _jsonb ? $1`,
+		},
+		"ExistsFalse": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$exists", false)),
+			)),
+			expected: ` WHERE NOT ( _jsonb ? $1 )`,
+		},
+		"ExistsInt32": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$exists", int32(1))),
+			)),
+		},
+		"DotNotationIn": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", int32(42))))),
+			)),
+			args:     []any{`lax $."v"."foo" ? (@ == "foo" || @ == 42)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationNin": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$nin", must.NotFail(types.NewArray("foo")))),
+			)),
+			expected: whereDotNotationNotEq + `'"string"', false)`,
+		},
+		"DotNotationExists": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$exists", true)),
+			)),
+			args:     []any{`lax $."v"."foo"`},
+			expected: whereJSONPath,
+		},
+		"DotNotationNotExists": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$exists", false)),
+			)),
+		},
+
+		"And": {
+			filter: must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", "foo")),
+					must.NotFail(types.NewDocument("w", must.NotFail(types.NewDocument("$exists", true)))),
+				)),
+			)),
+			args:     []any{`v`, `"foo"`, `w`},
+			expected: ` WHERE _jsonb->$1 @> $2 AND _jsonb ? $3`,
+		},
+		"AndPartial": {
+			filter: must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", types.Null)),
+					must.NotFail(types.NewDocument("w", "foo")),
+				)),
+			)),
+			args:     []any{`w`, `"foo"`},
+			expected: whereContain,
+		},
+		"Or": {
+			filter: must.NotFail(types.NewDocument(
+				"$or", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", "foo", "w", "bar")),
+					must.NotFail(types.NewDocument("$and", must.NotFail(types.NewArray(
+						must.NotFail(types.NewDocument("v.foo", true)),
+					)))),
+				)),
+			)),
+			args:     []any{`v`, `"foo"`, `w`, `"bar"`, `lax $."v"."foo" ? (@ == true)`},
+			expected: ` WHERE ((_jsonb->$1 @> $2 AND _jsonb->$3 @> $4) OR (_jsonb @? $5))`,
+		},
+		"OrPartial": {
+			filter: must.NotFail(types.NewDocument(
+				"$or", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", "foo")),
+					must.NotFail(types.NewDocument("w", types.Null)),
+				)),
+				"x", "bar",
+			)),
+			args:     []any{`x`, `"bar"`},
+			expected: whereContain,
+		},
+		"Nor": {
+			filter: must.NotFail(types.NewDocument(
+				"$nor", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", "foo")),
+					must.NotFail(types.NewDocument(
+						"w", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray(int32(1), int32(2))))),
+					)),
+				)),
+			)),
+			args: []any{`v`, `"foo"`, `w`, int32(1), `w`, int32(2)},
+			expected: ` WHERE NOT ( (_jsonb ? $1 AND _jsonb->$1 @> $2 AND _jsonb->'$s'->'p'->$1->'t' = '"string"')` +
+				` OR (((_jsonb ? $3 AND _jsonb->$3 @> $4 AND _jsonb->'$s'->'p'->$3->'t' = '"int"')` +
+				` OR (_jsonb ? $5 AND _jsonb->$5 @> $6 AND _jsonb->'$s'->'p'->$5->'t' = '"int"'))) )`,
+		},
+		"NorPartial": {
+			filter: must.NotFail(types.NewDocument(
+				"$nor", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", "foo")),
+					must.NotFail(types.NewDocument("w", must.NotFail(types.NewDocument("$gt", int32(1))))),
+				)),
+				"x", "bar",
+			)),
+			args:     []any{`x`, `"bar"`},
+			expected: whereContain,
+		},
+		"NorDotNotation": {
+			filter: must.NotFail(types.NewDocument(
+				"$nor", must.NotFail(types.NewArray(must.NotFail(types.NewDocument("v.foo", "bar")))),
+			)),
+		},
+
 		"Comment": {
 			filter: must.NotFail(types.NewDocument("$comment", "I'm comment")),
 		},
@@ -320,57 +659,160 @@ func TestPrepareWhereClause(t *testing.T) {
 func TestPrepareOrderByClause(t *testing.T) {
 	t.Parallel()
 
+	ranks := "WHEN 'double' THEN 1 WHEN 'int' THEN 1 WHEN 'long' THEN 1 WHEN 'string' THEN 2 WHEN 'object' THEN 3 " +
+		"WHEN 'array' THEN 4 WHEN 'binData' THEN 5 WHEN 'objectId' THEN 6 WHEN 'bool' THEN 7 WHEN 'date' THEN 8 " +
+		"WHEN 'timestamp' THEN 9 WHEN 'regex' THEN 10 ELSE 0 END"
+
+	field := `CASE (_jsonb->'$s'->'p'->'field'->>'t') ` + ranks + `, ` +
+		`(CASE WHEN jsonb_typeof((_jsonb->'field')) = 'string' THEN (_jsonb->'field') #>> '{}' END) COLLATE "C", ` +
+		`(_jsonb->'field')`
+
+	fieldDesc := `CASE (_jsonb->'$s'->'p'->'field'->>'t') ` + ranks + ` DESC, ` +
+		`(CASE WHEN jsonb_typeof((_jsonb->'field')) = 'string' THEN (_jsonb->'field') #>> '{}' END) COLLATE "C" DESC, ` +
+		`(_jsonb->'field') DESC`
+
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
 		sort   *types.Document
 		capped bool
 
 		orderBy string
-		args    []any
 	}{
 		"Ascending": {
 			sort:    must.NotFail(types.NewDocument("field", int64(1))),
-			orderBy: ` ORDER BY _jsonb->$1`,
-			args:    []any{"field"},
+			orderBy: ` ORDER BY ` + field,
 		},
 		"Descending": {
 			sort:    must.NotFail(types.NewDocument("field", int64(-1))),
-			orderBy: ` ORDER BY _jsonb->$1 DESC`,
-			args:    []any{"field"},
+			orderBy: ` ORDER BY ` + fieldDesc,
 		},
 		"SortNil": {
 			orderBy: "",
-			args:    nil,
 		},
 		"SortDotNotation": {
-			sort:    must.NotFail(types.NewDocument("field.embedded", int64(-1))),
-			orderBy: "",
-			args:    nil,
+			sort: must.NotFail(types.NewDocument("field.embedded", int64(1))),
+			orderBy: ` ORDER BY CASE (_jsonb->'$s'->'p'->'field'->'$s'->'p'->'embedded'->>'t') ` + ranks + `, ` +
+				`(CASE WHEN jsonb_typeof((_jsonb->'field' -> 'embedded')) = 'string' ` +
+				`THEN (_jsonb->'field' -> 'embedded') #>> '{}' END) COLLATE "C", ` +
+				`(_jsonb->'field' -> 'embedded')`,
+		},
+		"MultipleKeys": {
+			sort:    must.NotFail(types.NewDocument("field", int64(-1), "_id", int64(1))),
+			orderBy: ` ORDER BY ` + fieldDesc + `, ` + strings.ReplaceAll(field, "field", "_id"),
 		},
 		"Capped": {
 			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id`,
-			args:    nil,
 		},
 		"CappedWithSort": {
 			sort:    must.NotFail(types.NewDocument("field", int64(-1))),
 			capped:  true,
-			orderBy: ` ORDER BY _jsonb->$1 DESC`,
-			args:    []any{"field"},
+			orderBy: ` ORDER BY ` + fieldDesc,
 		},
-		"CappedWithSortDotNotation": {
-			sort:    must.NotFail(types.NewDocument("field.embedded", int64(-1))),
-			capped:  true,
-			orderBy: "",
-			args:    nil,
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			orderBy := prepareOrderByClause(tc.sort, tc.capped)
+
+			assert.Equal(t, tc.orderBy, orderBy)
+		})
+	}
+}
+
+func TestPrepareSortClause(t *testing.T) {
+	t.Parallel()
+
+	sort := must.NotFail(types.NewDocument("field", int64(1)))
+	indexOrderBy := ` ORDER BY ((_jsonb->'field'))`
+
+	for name, tc := range map[string]struct { //nolint:vet // used for test only
+		plan   *queryPlan
+		sort   *types.Document
+		capped bool
+
+		orderBy  string
+		pushdown bool
+	}{
+		"Index": {
+			plan:     &queryPlan{orderBy: indexOrderBy},
+			sort:     sort,
+			orderBy:  indexOrderBy,
+			pushdown: true,
+		},
+		"IndexNoSort": {
+			plan:    &queryPlan{orderBy: indexOrderBy},
+			orderBy: indexOrderBy,
+		},
+		"TypeRanks": {
+			plan:     new(queryPlan),
+			sort:     sort,
+			orderBy:  prepareOrderByClause(sort, false),
+			pushdown: true,
+		},
+		"CappedNoSort": {
+			plan:     new(queryPlan),
+			capped:   true,
+			orderBy:  ` ORDER BY _ferretdb_record_id`,
+			pushdown: true,
+		},
+		"CappedTypeRanks": {
+			plan:     new(queryPlan),
+			sort:     sort,
+			capped:   true,
+			orderBy:  prepareOrderByClause(sort, true),
+			pushdown: true,
+		},
+		"NoSort": {
+			plan: new(queryPlan),
 		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			orderBy, args := prepareOrderByClause(new(metadata.Placeholder), tc.sort, tc.capped)
+			orderBy, pushdown := prepareSortClause(tc.plan, tc.sort, tc.capped)
 
 			assert.Equal(t, tc.orderBy, orderBy)
+			assert.Equal(t, tc.pushdown, pushdown)
+		})
+	}
+}
+
+func TestPrepareLimitClause(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		skip  int64
+		limit int64
+
+		clause string
+		args   []any
+	}{
+		"None": {},
+		"Skip": {
+			skip:   1,
+			clause: ` OFFSET $1`,
+			args:   []any{int64(1)},
+		},
+		"Limit": {
+			limit:  2,
+			clause: ` LIMIT $1`,
+			args:   []any{int64(2)},
+		},
+		"SkipLimit": {
+			skip:   1,
+			limit:  2,
+			clause: ` LIMIT $1 OFFSET $2`,
+			args:   []any{int64(2), int64(1)},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			clause, args := prepareLimitClause(new(metadata.Placeholder), tc.skip, tc.limit)
+			assert.Equal(t, tc.clause, clause)
 			assert.Equal(t, tc.args, args)
 		})
 	}
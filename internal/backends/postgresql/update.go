@@ -0,0 +1,423 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// updateQuery represents queries that apply update operators pushed down to the backend.
+type updateQuery struct {
+	// check locks matching documents (at most one for single document updates) and returns their number,
+	// the number of them to which operators can't be applied exactly, and the largest ctid of them.
+	check     string
+	checkArgs []any
+
+	// update changes matching documents that are changed by operators.
+	// For single document updates, the ctid returned by check should be added as the last argument.
+	update     string
+	updateArgs []any
+}
+
+// updateArgs stores arguments of the update query.
+type updateArgs struct {
+	p    *metadata.Placeholder
+	args []any
+}
+
+// add adds an argument and returns its placeholder.
+func (ua *updateArgs) add(v any) string {
+	ua.args = append(ua.args, v)
+	return ua.p.Next()
+}
+
+// prepareUpdateQuery returns queries that apply update operators to documents matching the filter.
+//
+// Operators are applied only to documents where all parents of updated fields are objects,
+// and incremented fields are missing or contain integers that would not overflow;
+// other documents are updated by the handler the same way as before.
+//
+// It returns false if the filter or operators can't be pushed down completely.
+func prepareUpdateQuery(
+	schema string, meta *metadata.Collection, filter *types.Document, ops []backends.UpdateOperator, multi bool,
+) (*updateQuery, bool) {
+	// the handler does not check unique indexes for updated documents
+	for _, index := range meta.Indexes {
+		if index.Unique && index.Name != backends.DefaultIndexName {
+			return nil, false
+		}
+	}
+
+	for _, op := range ops {
+		switch op.Operator {
+		case "$set":
+			if _, err := sjson.MarshalSingleValue(op.Value); err != nil {
+				return nil, false
+			}
+		case "$inc":
+			switch op.Value.(type) {
+			case int32, int64:
+			default:
+				return nil, false
+			}
+		case "$unset":
+			// nothing
+		default:
+			return nil, false
+		}
+	}
+
+	table := pgx.Identifier{schema, meta.TableName}.Sanitize()
+
+	var limit string
+	if !multi {
+		limit = ` LIMIT 1`
+	}
+
+	var checkPlaceholder metadata.Placeholder
+
+	where, args, ok := prepareGroupWhereClause(&checkPlaceholder, filter)
+	if !ok {
+		return nil, false
+	}
+
+	check := &updateArgs{p: &checkPlaceholder, args: args}
+
+	uq := &updateQuery{
+		check: fmt.Sprintf(
+			`SELECT count(*), count(*) FILTER (WHERE (%s) IS NOT TRUE), max(ctid) FROM (SELECT ctid, %s FROM %s%s%s FOR UPDATE) AS d`,
+			updateSuitableCond(check, ops), metadata.DefaultColumn, table, where, limit,
+		),
+		checkArgs: check.args,
+	}
+
+	var updatePlaceholder metadata.Placeholder
+
+	update := &updateArgs{p: &updatePlaceholder}
+
+	if multi {
+		where, update.args, _ = prepareGroupWhereClause(&updatePlaceholder, filter)
+	}
+
+	uq.update = fmt.Sprintf(`UPDATE %s SET %s = %s`, table, metadata.DefaultColumn, updateDocExpr(update, ops))
+
+	changed := updateChangedCond(update, ops)
+
+	switch {
+	case !multi:
+		uq.update += ` WHERE ctid = ` + updatePlaceholder.Next() + ` AND ` + changed
+	case where == "":
+		uq.update += ` WHERE ` + changed
+	default:
+		uq.update += where + ` AND ` + changed
+	}
+
+	uq.updateArgs = update.args
+
+	return uq, true
+}
+
+// updateSuitableCond returns SQL condition that is true for documents to which operators can be applied exactly.
+func updateSuitableCond(ua *updateArgs, ops []backends.UpdateOperator) string {
+	conds := []string{`true`}
+	checked := map[string]bool{}
+
+	for _, op := range ops {
+		path := op.Path.Slice()
+
+		for i := 1; i < len(path); i++ {
+			if k := strings.Join(path[:i], "."); !checked[k] {
+				checked[k] = true
+				conds = append(conds, fmt.Sprintf(`%s = 'object'`, updateTypeExpr(ua, path[:i])))
+			}
+		}
+
+		if op.Operator != "$inc" {
+			continue
+		}
+
+		typ := updateTypeExpr(ua, path)
+		value := fmt.Sprintf(`(%s #>> %s::text[])::numeric`, metadata.DefaultColumn, ua.add(path))
+
+		// CASE guarantees that only numbers are cast
+		switch v := op.Value.(type) {
+		case int32:
+			n := int64(v)
+			conds = append(conds, fmt.Sprintf(
+				`CASE WHEN %[1]s IS NULL THEN true WHEN %[1]s = 'int' THEN %[2]s WHEN %[1]s = 'long' THEN %[3]s ELSE false END`,
+				typ, updateIncRange(value, n, math.MinInt32, math.MaxInt32), updateIncRange(value, n, math.MinInt64, math.MaxInt64),
+			))
+		case int64:
+			conds = append(conds, fmt.Sprintf(
+				`CASE WHEN %[1]s IS NULL THEN true WHEN %[1]s IN ('int', 'long') THEN %[2]s ELSE false END`,
+				typ, updateIncRange(value, v, math.MinInt64, math.MaxInt64),
+			))
+		}
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
+// updateDocExpr returns SQL expression for the document with applied operators.
+func updateDocExpr(ua *updateArgs, ops []backends.UpdateOperator) string {
+	doc := metadata.DefaultColumn
+
+	// schemas of empty objects don't have properties, and jsonb_set does not create missing parents
+	ensured := map[string]bool{}
+
+	for _, op := range ops {
+		path := op.Path.Slice()
+		if op.Operator == "$unset" || len(path) == 1 {
+			continue
+		}
+
+		if k := strings.Join(path[:len(path)-1], "."); !ensured[k] {
+			ensured[k] = true
+			propsPath := ua.add(append(updateSchemaPath(path[:len(path)-1]), "$s", "p")) + `::text[]`
+			doc = fmt.Sprintf(`jsonb_set(%s, %s, COALESCE(%s #> %s, '{}'))`, doc, propsPath, metadata.DefaultColumn, propsPath)
+		}
+	}
+
+	var parents []*updateParent
+
+	for _, op := range ops {
+		path := op.Path.Slice()
+		valuePath := ua.add(path) + `::text[]`
+		schemaPath := ua.add(updateSchemaPath(path)) + `::text[]`
+
+		parent := updateParentFor(&parents, path[:len(path)-1])
+		key := path[len(path)-1]
+
+		switch op.Operator {
+		case "$set":
+			v := ua.add(string(must.NotFail(sjson.MarshalSingleValue(op.Value))))
+			t := ua.add(sjson.GetTypeOfValue(op.Value))
+
+			doc = fmt.Sprintf(`jsonb_set(%s, %s, %s::jsonb)`, doc, valuePath, v)
+			doc = fmt.Sprintf(`jsonb_set(%s, %s, jsonb_build_object('t', %s::text))`, doc, schemaPath, t)
+			parent.added = append(parent.added, key)
+
+		case "$inc":
+			value := fmt.Sprintf(`coalesce((%s #>> %s)::numeric, 0)`, metadata.DefaultColumn, valuePath)
+
+			resType := `'long'`
+			if _, ok := op.Value.(int32); ok {
+				resType = fmt.Sprintf(`CASE WHEN %s = 'long' THEN 'long' ELSE 'int' END`, updateTypeExpr(ua, path))
+			}
+
+			doc = fmt.Sprintf(`jsonb_set(%s, %s, to_jsonb(%s + %s::bigint))`, doc, valuePath, value, ua.add(updateIncValue(op.Value)))
+			doc = fmt.Sprintf(`jsonb_set(%s, %s, jsonb_build_object('t', %s))`, doc, schemaPath, resType)
+			parent.added = append(parent.added, key)
+
+		case "$unset":
+			doc = fmt.Sprintf(`((%s #- %s) #- %s)`, doc, valuePath, schemaPath)
+			parent.removed = append(parent.removed, key)
+		}
+	}
+
+	// keys are kept in order, and added keys are appended in order of operators
+	for _, p := range parents {
+		keysPath := ua.add(updateKeysPath(p.path)) + `::text[]`
+
+		keys := fmt.Sprintf(
+			`SELECT 0 AS g, o, k FROM jsonb_array_elements_text(%s #> %s) WITH ORDINALITY AS e(k, o)`,
+			metadata.DefaultColumn, keysPath,
+		)
+
+		if len(p.removed) > 0 {
+			keys += fmt.Sprintf(` WHERE k <> ALL(%s::text[])`, ua.add(p.removed))
+		}
+
+		for i, k := range p.added {
+			keys += fmt.Sprintf(
+				` UNION ALL SELECT 1, %d, %s::text WHERE %s IS NULL`,
+				i, ua.add(k), updateTypeExpr(ua, append(slices.Clone(p.path), k)),
+			)
+		}
+
+		doc = fmt.Sprintf(
+			`jsonb_set(%s, %s, COALESCE((SELECT jsonb_agg(k ORDER BY g, o) FROM (%s) AS keys), '[]'))`,
+			doc, keysPath, keys,
+		)
+	}
+
+	return doc
+}
+
+// updateChangedCond returns SQL condition that is true for documents changed by operators.
+func updateChangedCond(ua *updateArgs, ops []backends.UpdateOperator) string {
+	conds := make([]string, len(ops))
+
+	for i, op := range ops {
+		path := op.Path.Slice()
+		typ := updateTypeExpr(ua, path)
+
+		switch op.Operator {
+		case "$set":
+			conds[i] = fmt.Sprintf(
+				`%s IS DISTINCT FROM %s::text OR %s #> %s::text[] IS DISTINCT FROM %s::jsonb`,
+				typ, ua.add(sjson.GetTypeOfValue(op.Value)),
+				metadata.DefaultColumn, ua.add(path), ua.add(string(must.NotFail(sjson.MarshalSingleValue(op.Value)))),
+			)
+
+		case "$inc":
+			conds[i] = `true`
+			if updateIncValue(op.Value) == 0 {
+				conds[i] = typ + ` IS NULL`
+			}
+
+		case "$unset":
+			conds[i] = typ + ` IS NOT NULL`
+		}
+	}
+
+	return `((` + strings.Join(conds, `) OR (`) + `))`
+}
+
+// updateParent represents the object containing fields that are added or removed by update operators.
+type updateParent struct {
+	path    []string
+	added   []string
+	removed []string
+}
+
+// updateParentFor returns the parent with the given path, adding it if needed.
+func updateParentFor(parents *[]*updateParent, path []string) *updateParent {
+	for _, p := range *parents {
+		if slices.Equal(p.path, path) {
+			return p
+		}
+	}
+
+	p := &updateParent{path: path}
+	*parents = append(*parents, p)
+
+	return p
+}
+
+// updateSchemaPath returns jsonb path to the sjson schema of the field with the given path.
+func updateSchemaPath(path []string) []string {
+	res := []string{"$s"}
+
+	for i, e := range path {
+		if i > 0 {
+			res = append(res, "$s")
+		}
+
+		res = append(res, "p", e)
+	}
+
+	return res
+}
+
+// updateKeysPath returns jsonb path to sjson keys of the object with the given path.
+func updateKeysPath(path []string) []string {
+	if len(path) == 0 {
+		return []string{"$s", "$k"}
+	}
+
+	return append(updateSchemaPath(path), "$s", "$k")
+}
+
+// updateTypeExpr returns SQL expression for the sjson type of the field with the given path,
+// or NULL if the field is missing.
+func updateTypeExpr(ua *updateArgs, path []string) string {
+	return fmt.Sprintf(`%s #>> %s::text[]`, metadata.DefaultColumn, ua.add(append(updateSchemaPath(path), "t")))
+}
+
+// updateIncValue returns the value of $inc operator.
+func updateIncValue(v any) int64 {
+	switch v := v.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		panic(fmt.Sprintf("unexpected $inc value type %T", v))
+	}
+}
+
+// updateIncRange returns SQL condition that is true if incrementing the value by n
+// does not overflow [minValue, maxValue] range.
+func updateIncRange(value string, n, minValue, maxValue int64) string {
+	if n > 0 {
+		return fmt.Sprintf(`%s <= %d`, value, maxValue-n)
+	}
+
+	return fmt.Sprintf(`%s >= %d`, value, minValue-n)
+}
+
+// execUpdateQuery applies update operators to documents matching the filter in a single transaction.
+//
+// If operators can't be applied exactly to some of them, nothing is updated,
+// and the result's UpdatePushdown field is set to false.
+func execUpdateQuery(ctx context.Context, p *pgxpool.Pool, uq *updateQuery, multi bool) (*backends.UpdateAllResult, error) {
+	var res backends.UpdateAllResult
+
+	err := pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+		var matched, unsuitable int64
+		var ctid pgtype.TID
+
+		if err := tx.QueryRow(ctx, uq.check, uq.checkArgs...).Scan(&matched, &unsuitable, &ctid); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if unsuitable > 0 {
+			return nil
+		}
+
+		res.Matched = int32(matched)
+		res.UpdatePushdown = true
+
+		if matched == 0 {
+			return nil
+		}
+
+		args := uq.updateArgs
+		if !multi {
+			args = append(slices.Clip(args), ctid)
+		}
+
+		tag, err := tx.Exec(ctx, uq.update, args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		res.Updated = int32(tag.RowsAffected())
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -65,36 +67,40 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		}, nil
 	}
 
-	var whereClause string
-	var args []any
+	if params.Group != nil {
+		if gq, ok := prepareGroupQuery(meta.TableName, params.Filter, params.Group); ok {
+			docs, err := queryGroup(ctx, db, gq, params.Group)
 
-	// that logic should exist in one place
-	// TODO https://github.com/FerretDB/FerretDB/issues/3235
-	if params.Filter.Len() == 1 {
-		v, _ := params.Filter.Get("_id")
-		switch v.(type) {
-		case string, types.ObjectID:
-			whereClause = fmt.Sprintf(` WHERE %s = ?`, metadata.IDColumn)
-			args = []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
-		}
-	}
+			switch {
+			case err == nil:
+				return &backends.QueryResult{
+					Iter:          iterator.Values(iterator.ForSlice(docs)),
+					GroupPushdown: true,
+				}, nil
 
-	q := prepareSelectClause(meta.TableName, params.Comment, meta.Capped(), params.OnlyRecordIDs) + whereClause
+			case errors.Is(err, errGroupUnsupported):
+				// fallback to fetching all documents
 
-	q += prepareOrderByClause(params.Sort, meta.Capped())
+			default:
+				return nil, lazyerrors.Error(err)
+			}
+		}
+	}
 
-	if params.Limit != 0 {
-		q += ` LIMIT ?`
-		args = append(args, params.Limit)
+	sq, err := prepareQuery(meta, params)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
 	}
 
-	rows, err := db.QueryContext(ctx, q, args...)
+	rows, err := db.QueryContext(ctx, sq.q, sq.args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	return &backends.QueryResult{
-		Iter: newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		Iter:         newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		SkipPushdown: sq.skipPushdown,
+		Index:        sq.index,
 	}, nil
 }
 
@@ -155,6 +161,15 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		return &res, nil
 	}
 
+	if len(params.Update) > 0 {
+		uq, ok := prepareUpdateQuery(meta, params.Filter, params.Update, params.Multi)
+		if !ok {
+			return &res, nil
+		}
+
+		return execUpdateQuery(ctx, db, uq, params.Multi)
+	}
+
 	q := fmt.Sprintf(`UPDATE %q SET %s = ? WHERE %s = ?`, meta.TableName, metadata.DefaultColumn, metadata.IDColumn)
 
 	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
@@ -203,6 +218,28 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 		return &backends.DeleteAllResult{Deleted: 0}, nil
 	}
 
+	if params.Filter != nil {
+		q, args, ok := prepareDeleteQuery(meta, params.Filter, params.Limited)
+		if !ok {
+			return new(backends.DeleteAllResult), nil
+		}
+
+		res, err := db.ExecContext(ctx, q, args...)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		ra, err := res.RowsAffected()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return &backends.DeleteAllResult{
+			Deleted:        int32(ra),
+			FilterPushdown: true,
+		}, nil
+	}
+
 	var column string
 	var placeholders []string
 	var args []any
@@ -246,6 +283,87 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	}, nil
 }
 
+// ModifyAll implements backends.Collection interface.
+func (c *collection) ModifyAll(ctx context.Context, params *backends.ModifyAllParams) (*backends.ModifyAllResult, error) {
+	var meta *metadata.Collection
+
+	db := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if db != nil {
+		meta = c.r.CollectionGet(ctx, c.dbName, c.name)
+	}
+
+	if meta == nil {
+		// there are no documents to query
+		query := func(*types.Document) (types.DocumentsIterator, error) {
+			return newQueryIterator(ctx, nil, false), nil
+		}
+
+		iter := newQueryIterator(ctx, nil, false)
+		changes, err := params.Modify(iter, query)
+		iter.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(changes.Insert) == 0 {
+			return new(backends.ModifyAllResult), nil
+		}
+
+		if _, err = c.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{DBName: c.dbName, Name: c.name}); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		db = c.r.DatabaseGetExisting(ctx, c.dbName)
+		meta = c.r.CollectionGet(ctx, c.dbName, c.name)
+	}
+
+	sq, err := prepareQuery(meta, &backends.QueryParams{Filter: params.Filter, Hint: params.Hint})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := &backends.ModifyAllResult{Index: sq.index}
+
+	// the write lock is acquired before documents are read
+	err = db.InTransactionImmediate(ctx, func(tx *fsql.Tx) error {
+		rows, err := tx.QueryContext(ctx, sq.q, sq.args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		// other writers wait for the transaction, so documents queried by Modify can't be changed concurrently
+		query := func(filter *types.Document) (types.DocumentsIterator, error) {
+			q, err := prepareQuery(meta, &backends.QueryParams{Filter: filter})
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			rows, err := tx.QueryContext(ctx, q.q, q.args...)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			return newQueryIterator(ctx, rows, false), nil
+		}
+
+		iter := newQueryIterator(ctx, rows, false)
+		changes, err := params.Modify(iter, query)
+		iter.Close()
+
+		if err != nil {
+			return err
+		}
+
+		return applyChanges(ctx, tx, meta, changes, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	db := c.r.DatabaseGetExisting(ctx, c.dbName)
@@ -262,35 +380,85 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		}, nil
 	}
 
-	selectClause := prepareSelectClause(meta.TableName, "", meta.Capped(), false)
-
-	var filterPushdown bool
 	var whereClause string
-	var args []any
 
-	// that logic should exist in one place
-	// TODO https://github.com/FerretDB/FerretDB/issues/3235
-	if params.Filter.Len() == 1 {
-		v, _ := params.Filter.Get("_id")
-		switch v.(type) {
-		case string, types.ObjectID:
-			filterPushdown = true
-			whereClause = fmt.Sprintf(` WHERE %s = ?`, metadata.IDColumn)
-			args = []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
+	filters, args, err := prepareFilters(params.Filter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	filterPushdown := len(filters) > 0
+
+	plan := planQuery(meta, params.Filter, params.Sort, params.Hint, slices.Contains(filters, idEqualCondition))
+	index := plan.index
+
+	filters = append(filters, plan.conditions...)
+	args = append(args, plan.args...)
+
+	if len(filters) > 0 {
+		whereClause = ` WHERE ` + strings.Join(filters, " AND ")
+	}
+
+	selectClause := prepareSelectClause(meta.TableName, "", meta.Capped(), false, nil) + plan.indexedBy
+
+	if cond, condArgs, wildcardIndex := prepareWildcardCondition(meta, params.Filter); cond != "" {
+		if whereClause == "" {
+			whereClause = " WHERE " + cond
+		} else {
+			whereClause += " AND " + cond
 		}
+
+		if index == "" {
+			index = wildcardIndex
+		}
+
+		args = append(args, condArgs...)
 	}
 
 	orderByClause := prepareOrderByClause(params.Sort, meta.Capped())
 	sortPushdown := orderByClause != ""
 
+	if plan.orderBy != "" {
+		orderByClause = plan.orderBy
+	}
+
 	q := `EXPLAIN QUERY PLAN ` + selectClause + whereClause + orderByClause
 
-	var limitPushdown bool
+	var skipPushdown, limitPushdown bool
+
+	if limitClause, limitArgs := prepareLimitClause(params.Skip, params.Limit); limitClause != "" {
+		exact, err := isExactFilter(params.Filter)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 
-	if params.Limit != 0 {
-		q += ` LIMIT ?`
-		args = append(args, params.Limit)
-		limitPushdown = true
+		if exact {
+			q += limitClause
+			args = append(args, limitArgs...)
+			skipPushdown = params.Skip != 0
+			limitPushdown = params.Limit != 0
+		}
+	}
+
+	var groupPushdown, groupFallback bool
+
+	if params.Group != nil {
+		if gq, ok := prepareGroupQuery(meta.TableName, params.Filter, params.Group); ok {
+			q = `EXPLAIN QUERY PLAN ` + gq.q
+			args = gq.args
+			groupPushdown = true
+			groupFallback = params.Group.MayFallBack()
+		}
+	}
+
+	var updatePushdown bool
+
+	if len(params.Update) > 0 {
+		if uq, ok := prepareUpdateQuery(meta, params.Filter, params.Update, true); ok {
+			q = `EXPLAIN QUERY PLAN ` + uq.update
+			args = uq.args
+			updatePushdown = true
+		}
 	}
 
 	rows, err := db.QueryContext(ctx, q, args...)
@@ -326,7 +494,12 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		QueryPlanner:   must.NotFail(types.NewDocument("Plan", queryPlan)),
 		FilterPushdown: filterPushdown,
 		SortPushdown:   sortPushdown,
+		SkipPushdown:   skipPushdown,
 		LimitPushdown:  limitPushdown,
+		GroupPushdown:  groupPushdown,
+		GroupFallback:  groupFallback,
+		UpdatePushdown: updatePushdown,
+		Index:          index,
 	}, nil
 }
 
@@ -357,9 +530,11 @@ func (c *collection) Stats(ctx context.Context, params *backends.CollectionStats
 	indexMap := map[string]string{}
 
 	for _, index := range coll.Settings.Indexes {
-		placeholders = append(placeholders, "?")
-		args = append(args, coll.TableName+"_"+index.Name)
-		indexMap[coll.TableName+"_"+index.Name] = index.Name
+		for _, name := range metadata.IndexStatNames(coll.TableName, &index) {
+			placeholders = append(placeholders, "?")
+			args = append(args, name)
+			indexMap[name] = index.Name
+		}
 	}
 
 	q := fmt.Sprintf(`
@@ -378,8 +553,7 @@ func (c *collection) Stats(ctx context.Context, params *backends.CollectionStats
 
 	defer rows.Close()
 
-	indexSizes := make([]backends.IndexSize, len(indexMap))
-	var i int
+	sizes := make(map[string]int64, len(coll.Settings.Indexes))
 
 	for rows.Next() {
 		var name string
@@ -395,17 +569,27 @@ func (c *collection) Stats(ctx context.Context, params *backends.CollectionStats
 			continue
 		}
 
-		indexSizes[i] = backends.IndexSize{
-			Name: indexName,
-			Size: size,
-		}
-		i++
+		sizes[indexName] += size
 	}
 
 	if rows.Err() != nil {
 		return nil, lazyerrors.Error(rows.Err())
 	}
 
+	indexSizes := make([]backends.IndexSize, 0, len(sizes))
+
+	for _, index := range coll.Settings.Indexes {
+		size, ok := sizes[index.Name]
+		if !ok {
+			continue
+		}
+
+		indexSizes = append(indexSizes, backends.IndexSize{
+			Name: index.Name,
+			Size: size,
+		})
+	}
+
 	return &backends.CollectionStatsResult{
 		CountDocuments:  stats.countDocuments,
 		SizeTotal:       stats.sizeTables + stats.sizeIndexes,
@@ -470,15 +654,62 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Settings.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:               index.Name,
+			Unique:             index.Unique,
+			Key:                make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
+			Hidden:             index.Hidden,
+			PrepareUnique:      index.PrepareUnique,
+			Sparse:             index.Sparse,
+		}
+
+		if len(index.PartialFilterExpression) > 0 {
+			filter, err := sjson.Unmarshal(index.PartialFilterExpression)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			res.Indexes[i].PartialFilterExpression = filter
+		}
+
+		if len(index.WildcardProjection) > 0 {
+			projection, err := sjson.Unmarshal(index.WildcardProjection)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			res.Indexes[i].WildcardProjection = projection
 		}
 
 		for j, key := range index.Key {
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Type:       backends.IndexKeyType(key.Type),
+			}
+		}
+
+		if index.Text != nil {
+			res.Indexes[i].Text = &backends.TextIndexOptions{
+				Weights:          make([]backends.TextIndexWeight, len(index.Text.Weights)),
+				DefaultLanguage:  index.Text.DefaultLanguage,
+				LanguageOverride: index.Text.LanguageOverride,
+			}
+
+			for j, w := range index.Text.Weights {
+				res.Indexes[i].Text.Weights[j] = backends.TextIndexWeight{
+					Field:  w.Field,
+					Weight: w.Weight,
+				}
+			}
+		}
+
+		if index.Geo != nil {
+			res.Indexes[i].Geo = &backends.GeoIndexOptions{
+				Version: index.Geo.Version,
+				Bits:    index.Geo.Bits,
+				Min:     index.Geo.Min,
+				Max:     index.Geo.Max,
 			}
 		}
 	}
@@ -495,15 +726,66 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Key:                make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
+			Hidden:             index.Hidden,
+			PrepareUnique:      index.PrepareUnique,
+			Sparse:             index.Sparse,
+		}
+
+		if index.PartialFilterExpression != nil {
+			b, err := sjson.Marshal(index.PartialFilterExpression)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			indexes[i].PartialFilterExpression = b
+		}
+
+		if index.WildcardProjection != nil {
+			b, err := sjson.Marshal(index.WildcardProjection)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			indexes[i].WildcardProjection = b
+		}
+
+		if len(index.Key) == 1 && index.Key[0].Type == "" && backends.IsWildcardIndexKey(index.Key[0].Field) {
+			indexes[i].WildcardFields = backends.WildcardIndexFields(index.Key[0].Field, index.WildcardProjection)
 		}
 
 		for j, key := range index.Key {
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Type:       string(key.Type),
+			}
+		}
+
+		if index.Text != nil {
+			indexes[i].Text = &metadata.TextIndexOptions{
+				Weights:          make([]metadata.TextIndexWeight, len(index.Text.Weights)),
+				DefaultLanguage:  index.Text.DefaultLanguage,
+				LanguageOverride: index.Text.LanguageOverride,
+			}
+
+			for j, w := range index.Text.Weights {
+				indexes[i].Text.Weights[j] = metadata.TextIndexWeight{
+					Field:  w.Field,
+					Weight: w.Weight,
+				}
+			}
+		}
+
+		if index.Geo != nil {
+			indexes[i].Geo = &metadata.GeoIndexOptions{
+				Version: index.Geo.Version,
+				Bits:    index.Geo.Bits,
+				Min:     index.Geo.Min,
+				Max:     index.Geo.Max,
 			}
 		}
 	}
@@ -526,6 +808,38 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return new(backends.DropIndexesResult), nil
 }
 
+// ModifyIndex implements backends.Collection interface.
+func (c *collection) ModifyIndex(ctx context.Context, params *backends.ModifyIndexParams) (*backends.ModifyIndexResult, error) {
+	if c.r.CollectionGet(ctx, c.dbName, c.name) == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	modified, err := c.r.IndexModify(ctx, &metadata.IndexModifyParams{
+		DBName:             c.dbName,
+		CollectionName:     c.name,
+		Name:               params.Name,
+		ExpireAfterSeconds: params.ExpireAfterSeconds,
+		Hidden:             params.Hidden,
+		PrepareUnique:      params.PrepareUnique,
+		Unique:             params.Unique,
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !modified {
+		return nil, backends.NewError(
+			backends.ErrorCodeIndexDoesNotExist,
+			lazyerrors.Errorf("index %q does not exist in %s.%s", params.Name, c.dbName, c.name),
+		)
+	}
+
+	return new(backends.ModifyIndexResult), nil
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -15,7 +15,11 @@
 package sqlite
 
 import (
+	"math"
+	"regexp"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,10 +32,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
 )
 
-func TestCappedCollectionInsertAllQueryExplain(t *testing.T) {
-	// remove this test
-	// TODO https://github.com/FerretDB/FerretDB/issues/3181
-
+func TestQueryGroup(t *testing.T) {
 	t.Parallel()
 
 	ctx := testutil.Ctx(t)
@@ -46,42 +47,1157 @@ func TestCappedCollectionInsertAllQueryExplain(t *testing.T) {
 	db, err := b.Database(testutil.DatabaseName(t))
 	require.NoError(t, err)
 
-	cName := testutil.CollectionName(t)
-	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
-		Name:       cName,
-		CappedSize: 8192,
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "f", true, "k", "a", "v", int32(1))),
+		must.NotFail(types.NewDocument("_id", int32(2), "f", true, "k", "a", "v", int64(2))),
+		must.NotFail(types.NewDocument("_id", int32(3), "f", true, "k", "b", "v", 1.5)),
+		must.NotFail(types.NewDocument("_id", int32(4), "f", true, "k", int32(1), "v", "s")),
+		must.NotFail(types.NewDocument("_id", int32(5), "f", true, "k", int32(1), "v", int64(math.MaxInt64))),
+		must.NotFail(types.NewDocument("_id", int32(6), "f", true, "v", int64(math.MaxInt64))),
+		must.NotFail(types.NewDocument("_id", int32(7), "f", true, "k", types.Null, "v", int64(math.MaxInt64))),
+		must.NotFail(types.NewDocument("_id", int32(8), "f", false, "k", "b", "v", must.NotFail(types.NewArray(int32(1))))),
+		must.NotFail(types.NewDocument("_id", int32(9), "f", must.NotFail(types.NewArray(false, true)), "k", "c")),
+	}
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+	require.NoError(t, err)
+
+	group := &backends.GroupParams{
+		Key: "k",
+		Accumulators: []backends.GroupAccumulator{
+			{Output: "sum", Operator: "$sum", Field: "v"},
+			{Output: "count", Operator: "$sum", Value: int32(1)},
+			{Output: "avg", Operator: "$avg", Field: "v"},
+			{Output: "min", Operator: "$min", Field: "v"},
+			{Output: "max", Operator: "$max", Field: "v"},
+		},
+	}
+
+	// query returns grouped documents sorted by _id
+	query := func(t *testing.T, params *backends.QueryParams) ([]*types.Document, bool) {
+		t.Helper()
+
+		res, err := coll.Query(ctx, params)
+		require.NoError(t, err)
+
+		docs, err := iterator.ConsumeValues(res.Iter)
+		require.NoError(t, err)
+
+		slices.SortFunc(docs, func(a, b *types.Document) int {
+			return int(types.CompareOrder(must.NotFail(a.Get("_id")), must.NotFail(b.Get("_id")), types.Ascending))
+		})
+
+		return docs, res.GroupPushdown
+	}
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+
+		actual, pushdown := query(t, &backends.QueryParams{
+			Filter: must.NotFail(types.NewDocument("f", true)),
+			Group:  group,
+		})
+		assert.True(t, pushdown)
+
+		expected := []*types.Document{
+			must.NotFail(types.NewDocument(
+				"_id", types.Null,
+				"sum", float64(math.MaxInt64)*2,
+				"count", int32(2),
+				"avg", float64(math.MaxInt64),
+				"min", int64(math.MaxInt64),
+				"max", int64(math.MaxInt64),
+			)),
+			must.NotFail(types.NewDocument(
+				"_id", int32(1),
+				"sum", int64(math.MaxInt64),
+				"count", int32(2),
+				"avg", float64(math.MaxInt64),
+				"min", int64(math.MaxInt64),
+				"max", "s",
+			)),
+			must.NotFail(types.NewDocument(
+				"_id", "a",
+				"sum", int64(3),
+				"count", int32(2),
+				"avg", 1.5,
+				"min", int32(1),
+				"max", int64(2),
+			)),
+			must.NotFail(types.NewDocument(
+				"_id", "b",
+				"sum", 1.5,
+				"count", int32(1),
+				"avg", 1.5,
+				"min", 1.5,
+				"max", 1.5,
+			)),
+			must.NotFail(types.NewDocument(
+				"_id", "c",
+				"sum", int32(0),
+				"count", int32(1),
+				"avg", types.Null,
+				"min", types.Null,
+				"max", types.Null,
+			)),
+		}
+		testutil.AssertEqualSlices(t, expected, actual)
+
+		explainRes, err := coll.Explain(ctx, &backends.ExplainParams{
+			Filter: must.NotFail(types.NewDocument("f", true)),
+			Group:  group,
+		})
+		require.NoError(t, err)
+		assert.True(t, explainRes.GroupPushdown)
+		assert.True(t, explainRes.GroupFallback)
+	})
+
+	t.Run("SkipMissingKey", func(t *testing.T) {
+		t.Parallel()
+
+		actual, pushdown := query(t, &backends.QueryParams{
+			Filter: must.NotFail(types.NewDocument("f", true)),
+			Group: &backends.GroupParams{
+				Key:            "k",
+				SkipMissingKey: true,
+				Accumulators: []backends.GroupAccumulator{
+					{Output: "count", Operator: "$sum", Value: int32(1)},
+				},
+			},
+		})
+		assert.True(t, pushdown)
+
+		expected := []*types.Document{
+			must.NotFail(types.NewDocument("_id", types.Null, "count", int32(1))),
+			must.NotFail(types.NewDocument("_id", int32(1), "count", int32(2))),
+			must.NotFail(types.NewDocument("_id", "a", "count", int32(2))),
+			must.NotFail(types.NewDocument("_id", "b", "count", int32(1))),
+			must.NotFail(types.NewDocument("_id", "c", "count", int32(1))),
+		}
+		testutil.AssertEqualSlices(t, expected, actual)
 	})
+
+	t.Run("NoKey", func(t *testing.T) {
+		t.Parallel()
+
+		actual, pushdown := query(t, &backends.QueryParams{
+			Filter: must.NotFail(types.NewDocument("k", must.NotFail(types.NewDocument("$eq", "a")))),
+			Group: &backends.GroupParams{
+				Accumulators: []backends.GroupAccumulator{
+					{Output: "sum", Operator: "$sum", Field: "v"},
+				},
+			},
+		})
+		assert.True(t, pushdown)
+
+		expected := []*types.Document{
+			must.NotFail(types.NewDocument("_id", types.Null, "sum", int64(3))),
+		}
+		testutil.AssertEqualSlices(t, expected, actual)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		actual, pushdown := query(t, &backends.QueryParams{
+			Filter: must.NotFail(types.NewDocument("k", "z")),
+			Group: &backends.GroupParams{
+				Accumulators: []backends.GroupAccumulator{
+					{Output: "count", Operator: "$sum", Value: int32(1)},
+				},
+			},
+		})
+		assert.True(t, pushdown)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("UnsupportedValues", func(t *testing.T) {
+		t.Parallel()
+
+		actual, pushdown := query(t, &backends.QueryParams{Group: group})
+		assert.False(t, pushdown)
+		assert.Len(t, actual, len(docs))
+	})
+
+	t.Run("UnsupportedFilter", func(t *testing.T) {
+		t.Parallel()
+
+		filter := must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", int32(0)))))
+
+		_, pushdown := query(t, &backends.QueryParams{Filter: filter, Group: group})
+		assert.False(t, pushdown)
+
+		explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: filter, Group: group})
+		require.NoError(t, err)
+		assert.False(t, explainRes.GroupPushdown)
+		assert.False(t, explainRes.GroupFallback)
+	})
+}
+
+func TestQueryTextSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
 	require.NoError(t, err)
 
-	cappedColl, err := db.Collection(cName)
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
 	require.NoError(t, err)
+	t.Cleanup(b.Close)
 
-	insertDocs := []*types.Document{
-		must.NotFail(types.NewDocument("_id", types.ObjectID{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})),
-		must.NotFail(types.NewDocument("_id", types.ObjectID{3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})),
-		must.NotFail(types.NewDocument("_id", types.ObjectID{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})),
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "subject", "Running shoes")),
+		must.NotFail(types.NewDocument("_id", int32(2), "subject", must.NotFail(types.NewArray("red", "shoe")))),
+		must.NotFail(types.NewDocument("_id", int32(3), "subject", "coffee", "other", "shoes")),
+		must.NotFail(types.NewDocument("_id", int32(4), "subject", "Baking")),
 	}
 
-	_, err = cappedColl.InsertAll(ctx, &backends.InsertAllParams{Docs: insertDocs})
+	// documents inserted before and after the index is created are indexed
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs[:2]})
 	require.NoError(t, err)
 
-	t.Run("CappedCollectionSort", func(t *testing.T) {
-		t.Parallel()
+	_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+		Indexes: []backends.IndexInfo{{
+			Name: "subject_text",
+			Key:  []backends.IndexKeyPair{{Field: "subject", Type: backends.IndexKeyTypeText}},
+			Text: &backends.TextIndexOptions{
+				Weights:          []backends.TextIndexWeight{{Field: "subject", Weight: 1}},
+				DefaultLanguage:  "english",
+				LanguageOverride: "language",
+			},
+		}},
+	})
+	require.NoError(t, err)
 
-		sort := must.NotFail(types.NewDocument("_id", int64(1)))
-		queryRes, err := cappedColl.Query(ctx, &backends.QueryParams{Sort: sort})
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs[2:]})
+	require.NoError(t, err)
+
+	// query returns _id values of matched documents
+	query := func(t *testing.T, words ...string) []any {
+		t.Helper()
+
+		res, err := coll.Query(ctx, &backends.QueryParams{
+			TextSearch: &backends.TextSearchParams{Words: words},
+		})
 		require.NoError(t, err)
 
-		docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+		docs, err := iterator.ConsumeValues(res.Iter)
 		require.NoError(t, err)
-		testutil.AssertEqualSlices(t, insertDocs, docs)
+
+		ids := make([]any, len(docs))
 		for i, doc := range docs {
-			assert.Equal(t, insertDocs[i].RecordID(), doc.RecordID())
-			assert.NotZero(t, doc.RecordID())
+			ids[i] = must.NotFail(doc.Get("_id"))
 		}
 
-		explainRes, err := cappedColl.Explain(ctx, &backends.ExplainParams{Sort: sort})
+		slices.SortFunc(ids, func(a, b any) int { return int(types.CompareOrder(a, b, types.Ascending)) })
+
+		return ids
+	}
+
+	assert.Equal(t, []any{int32(1), int32(2)}, query(t, "shoes"))
+	assert.Equal(t, []any{int32(1), int32(4)}, query(t, "run", "bake"))
+
+	_, err = coll.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(4), "subject", "coffee shoes")),
+	}})
+	require.NoError(t, err)
+
+	_, err = coll.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{int32(1)}})
+	require.NoError(t, err)
+
+	_, err = coll.Compact(ctx, &backends.CompactParams{Full: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []any{int32(2), int32(4)}, query(t, "shoes"))
+	assert.Empty(t, query(t, "bake"))
+
+	_, err = coll.DropIndexes(ctx, &backends.DropIndexesParams{Indexes: []string{"subject_text"}})
+	require.NoError(t, err)
+
+	// without the index, all documents are returned
+	assert.Len(t, query(t, "shoes"), 3)
+}
+
+func TestQueryFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	date := time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC)
+	id := types.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", int32(1))),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", int64(2))),
+		must.NotFail(types.NewDocument("_id", int32(3), "v", 1.5)),
+		must.NotFail(types.NewDocument("_id", int32(4), "v", "a")),
+		must.NotFail(types.NewDocument("_id", int32(5), "v", "b")),
+		must.NotFail(types.NewDocument("_id", int32(6), "v", true)),
+		must.NotFail(types.NewDocument("_id", int32(7), "v", date)),
+		must.NotFail(types.NewDocument("_id", int32(8), "v", must.NotFail(types.NewArray(int32(1), "b")))),
+		must.NotFail(types.NewDocument("_id", int32(9), "v", must.NotFail(types.NewDocument("a", int32(1))))),
+		must.NotFail(types.NewDocument("_id", int32(10))),
+		must.NotFail(types.NewDocument("_id", int32(11), "v", types.Null)),
+		must.NotFail(types.NewDocument(
+			"_id", int32(12), "v", must.NotFail(types.NewArray(must.NotFail(types.NewDocument("a", int32(1))))),
+		)),
+		must.NotFail(types.NewDocument("_id", int32(13), "v", id)),
+		must.NotFail(types.NewDocument("_id", "s", "v", "a")),
+	}
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+	require.NoError(t, err)
+
+	// filters return documents that match them and some extra documents,
+	// the filter is applied again in the handler
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		expected []any // _id values of documents returned by the query
+		pushdown bool
+	}{
+		"Eq": {
+			filter:   must.NotFail(types.NewDocument("v", int64(1))),
+			expected: []any{int32(1), int32(8)},
+			pushdown: true,
+		},
+		"EqString": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$eq", "b")))),
+			expected: []any{int32(5), int32(8)},
+			pushdown: true,
+		},
+		"EqBool": {
+			filter:   must.NotFail(types.NewDocument("v", true)),
+			expected: []any{int32(6)},
+			pushdown: true,
+		},
+		"EqDate": {
+			filter:   must.NotFail(types.NewDocument("v", date)),
+			expected: []any{int32(7)},
+			pushdown: true,
+		},
+		"EqObjectID": {
+			filter:   must.NotFail(types.NewDocument("v", id)),
+			expected: []any{int32(13)},
+			pushdown: true,
+		},
+		"EqID": {
+			filter:   must.NotFail(types.NewDocument("_id", "s")),
+			expected: []any{"s"},
+			pushdown: true,
+		},
+		"EqNull": {
+			filter: must.NotFail(types.NewDocument("v", types.Null)),
+			expected: []any{
+				int32(1), int32(2), int32(3), int32(4), int32(5), int32(6), int32(7),
+				int32(8), int32(9), int32(10), int32(11), int32(12), int32(13), "s",
+			},
+		},
+		"Ne": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$ne", 1.0)))),
+			expected: []any{
+				int32(2), int32(3), int32(4), int32(5), int32(6), int32(7),
+				int32(9), int32(10), int32(11), int32(12), int32(13), "s",
+			},
+			pushdown: true,
+		},
+		"Gt": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", int32(1))))),
+			expected: []any{int32(2), int32(3)},
+			pushdown: true,
+		},
+		"GteString": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gte", "b")))),
+			expected: []any{int32(5), int32(8)},
+			pushdown: true,
+		},
+		"LtDate": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$lt", date.Add(time.Second))))),
+			expected: []any{int32(7)},
+			pushdown: true,
+		},
+		"In": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("a", true)))),
+			)),
+			expected: []any{int32(4), int32(6), "s"},
+			pushdown: true,
+		},
+		"Nin": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$nin", must.NotFail(types.NewArray("a", true)))),
+			)),
+			expected: []any{
+				int32(1), int32(2), int32(3), int32(5), int32(7),
+				int32(8), int32(9), int32(10), int32(11), int32(12), int32(13),
+			},
+			pushdown: true,
+		},
+		"ExistsFalse": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", false)))),
+			expected: []any{int32(10)},
+			pushdown: true,
+		},
+		"DotNotation": {
+			filter:   must.NotFail(types.NewDocument("v.a", int32(1))),
+			expected: []any{int32(8), int32(9), int32(12)},
+			pushdown: true,
+		},
+		"DotNotationNe": {
+			filter: must.NotFail(types.NewDocument("v.a", must.NotFail(types.NewDocument("$ne", int32(1))))),
+			expected: []any{
+				int32(1), int32(2), int32(3), int32(4), int32(5), int32(6), int32(7),
+				int32(8), int32(10), int32(11), int32(12), int32(13), "s",
+			},
+			pushdown: true,
+		},
+		"EmptyKey": {
+			filter:   must.NotFail(types.NewDocument("", "a")),
+			expected: []any{},
+			pushdown: true,
+		},
+		"Or": {
+			filter: must.NotFail(types.NewDocument("$or", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("v", "a")),
+				must.NotFail(types.NewDocument("v", 1.5)),
+			)))),
+			expected: []any{int32(3), int32(4), "s"},
+			pushdown: true,
+		},
+		"Nor": {
+			filter: must.NotFail(types.NewDocument("$nor", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("v", "a")),
+				must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", false)))),
+			)))),
+			expected: []any{
+				int32(1), int32(2), int32(3), int32(5), int32(6), int32(7),
+				int32(8), int32(9), int32(11), int32(12), int32(13),
+			},
+			pushdown: true,
+		},
+		"And": {
+			filter: must.NotFail(types.NewDocument("$and", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("_id", "s")),
+				must.NotFail(types.NewDocument("v", "b")),
+			)))),
+			expected: []any{},
+			pushdown: true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := coll.Query(ctx, &backends.QueryParams{Filter: tc.filter})
+			require.NoError(t, err)
+
+			docs, err := iterator.ConsumeValues(res.Iter)
+			require.NoError(t, err)
+
+			actual := make([]any, len(docs))
+			for i, doc := range docs {
+				actual[i] = must.NotFail(doc.Get("_id"))
+			}
+
+			assert.ElementsMatch(t, tc.expected, actual)
+
+			explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: tc.filter})
+			require.NoError(t, err)
+			assert.Equal(t, tc.pushdown, explainRes.FilterPushdown)
+		})
+	}
+}
+
+func TestQueryIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", "a", "w", int32(1))),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", "b", "w", int32(2))),
+		must.NotFail(types.NewDocument("_id", int32(3), "v", must.NotFail(types.NewArray("a", "c")))),
+		must.NotFail(types.NewDocument("_id", int32(4), "v", true)),
+		must.NotFail(types.NewDocument("_id", "s", "v", int32(42))),
+	}
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+	require.NoError(t, err)
+
+	_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+		Indexes: []backends.IndexInfo{{
+			Name: "v_1",
+			Key:  []backends.IndexKeyPair{{Field: "v"}},
+		}, {
+			Name:   "w_-1",
+			Key:    []backends.IndexKeyPair{{Field: "w", Descending: true}},
+			Sparse: true,
+		}},
+	})
+	require.NoError(t, err)
+
+	// indexes select documents that may match the filter, the filter is applied again in the handler
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		hint     string
+		expected []any // _id values of documents returned by the query
+		index    string
+	}{
+		"NoIndex": {
+			filter:   must.NotFail(types.NewDocument("w", int32(1))),
+			expected: []any{int32(1)},
+		},
+		"ID": {
+			filter:   must.NotFail(types.NewDocument("_id", "s")),
+			expected: []any{"s"},
+			index:    "_id_",
+		},
+		"Equal": {
+			filter:   must.NotFail(types.NewDocument("v", "a")),
+			expected: []any{int32(1), int32(3)},
+			index:    "v_1",
+		},
+		"EqualOperator": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$eq", true)))),
+			expected: []any{int32(4)},
+			index:    "v_1",
+		},
+		"Number": {
+			filter:   must.NotFail(types.NewDocument("v", int32(42))),
+			expected: []any{"s"},
+		},
+		"Hint": {
+			filter:   must.NotFail(types.NewDocument("v", int32(42))),
+			hint:     "v_1",
+			expected: []any{"s"},
+			index:    "v_1",
+		},
+		"HintID": {
+			filter:   must.NotFail(types.NewDocument("v", "a")),
+			hint:     "_id_",
+			expected: []any{int32(1), int32(3)},
+			index:    "_id_",
+		},
+		"HintSparse": {
+			filter:   must.NotFail(types.NewDocument()),
+			hint:     "w_-1",
+			expected: []any{int32(1), int32(2)},
+			index:    "w_-1",
+		},
+		"HintNonExistent": {
+			filter:   must.NotFail(types.NewDocument("v", "b")),
+			hint:     "foo",
+			expected: []any{int32(2)},
+			index:    "v_1",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := coll.Query(ctx, &backends.QueryParams{Filter: tc.filter, Hint: tc.hint})
+			require.NoError(t, err)
+			assert.Equal(t, tc.index, res.Index)
+
+			docs, err := iterator.ConsumeValues(res.Iter)
+			require.NoError(t, err)
+
+			actual := make([]any, len(docs))
+			for i, doc := range docs {
+				actual[i] = must.NotFail(doc.Get("_id"))
+			}
+
+			assert.ElementsMatch(t, tc.expected, actual)
+
+			explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: tc.filter, Hint: tc.hint})
+			require.NoError(t, err)
+			assert.Equal(t, tc.index, explainRes.Index)
+
+			var plan string
+
+			planRows := must.NotFail(explainRes.QueryPlanner.Get("Plan")).(*types.Array)
+			for i := 0; i < planRows.Len(); i++ {
+				plan += must.NotFail(planRows.Get(i)).(string) + "\n"
+			}
+
+			if tc.index == "" {
+				assert.NotContains(t, plan, "USING INDEX")
+				return
+			}
+
+			assert.Regexp(t, `INDEX \S+_`+regexp.QuoteMeta(tc.index)+`\s`, plan)
+		})
+	}
+}
+
+func TestQuerySort(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	date := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	objectID := types.ObjectID{0x01}
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", date, "w", int32(1))),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", true, "w", int32(2))),
+		must.NotFail(types.NewDocument("_id", int32(3), "v", objectID, "w", int32(3))),
+		must.NotFail(types.NewDocument("_id", int32(4), "v", "b", "w", int32(4))),
+		must.NotFail(types.NewDocument("_id", int32(5), "v", "a", "w", int32(5))),
+		must.NotFail(types.NewDocument("_id", int32(6), "v", int32(10), "w", int32(6))),
+		must.NotFail(types.NewDocument("_id", int32(7), "v", 9.5, "w", int32(7))),
+		must.NotFail(types.NewDocument("_id", int32(8), "v", "a", "w", int32(8))),
+		must.NotFail(types.NewDocument("_id", int32(9), "v", types.Null, "w", int32(9))),
+		must.NotFail(types.NewDocument("_id", int32(10), "w", int32(10))),
+	}
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+	require.NoError(t, err)
+
+	_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+		Indexes: []backends.IndexInfo{{
+			Name: "w_1_v_1",
+			Key:  []backends.IndexKeyPair{{Field: "w"}, {Field: "v"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		sort     *types.Document
+		expected []any // _id values of documents returned by the query, in order; nil if order is not checked
+		index    string
+	}{
+		"Types": {
+			sort:     must.NotFail(types.NewDocument("v", int64(1), "w", int64(-1))),
+			expected: []any{int32(10), int32(9), int32(7), int32(6), int32(8), int32(5), int32(4), int32(3), int32(2), int32(1)},
+		},
+		"TypesDescending": {
+			sort:     must.NotFail(types.NewDocument("v", int64(-1), "w", int64(1))),
+			expected: []any{int32(1), int32(2), int32(3), int32(4), int32(5), int32(8), int32(6), int32(7), int32(9), int32(10)},
+		},
+		"Index": {
+			sort:  must.NotFail(types.NewDocument("w", int64(1), "v", int64(1))),
+			index: "w_1_v_1",
+		},
+		"IndexReversed": {
+			sort:  must.NotFail(types.NewDocument("w", int64(-1), "v", int64(-1))),
+			index: "w_1_v_1",
+		},
+		"IndexPrefix": {
+			sort:  must.NotFail(types.NewDocument("w", int64(-1))),
+			index: "w_1_v_1",
+		},
+		"IndexMixedDirections": {
+			sort: must.NotFail(types.NewDocument("w", int64(1), "v", int64(-1))),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := coll.Query(ctx, &backends.QueryParams{Sort: tc.sort})
+			require.NoError(t, err)
+			assert.Equal(t, tc.index, res.Index)
+
+			docs, err := iterator.ConsumeValues(res.Iter)
+			require.NoError(t, err)
+			require.Len(t, docs, 10)
+
+			if tc.expected != nil {
+				actual := make([]any, len(docs))
+				for i, doc := range docs {
+					actual[i] = must.NotFail(doc.Get("_id"))
+				}
+
+				assert.Equal(t, tc.expected, actual)
+			}
+
+			explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Sort: tc.sort})
+			require.NoError(t, err)
+			assert.True(t, explainRes.SortPushdown)
+			assert.Equal(t, tc.index, explainRes.Index)
+
+			var plan string
+
+			planRows := must.NotFail(explainRes.QueryPlanner.Get("Plan")).(*types.Array)
+			for i := 0; i < planRows.Len(); i++ {
+				plan += must.NotFail(planRows.Get(i)).(string) + "\n"
+			}
+
+			if tc.index == "" {
+				assert.Contains(t, plan, "USE TEMP B-TREE FOR ORDER BY")
+				return
+			}
+
+			assert.NotContains(t, plan, "USE TEMP B-TREE FOR ORDER BY")
+			assert.Regexp(t, `INDEX \S+_`+regexp.QuoteMeta(tc.index)+`\s`, plan)
+		})
+	}
+}
+
+func TestQuerySkipProjection(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument(
+			"_id", int32(1), "v", "a", "o", must.NotFail(types.NewDocument("x", types.Null, "y", 1.5)), "n", types.Null,
+		)),
+		must.NotFail(types.NewDocument("_id", int32(2), "n", int64(2), "v", must.NotFail(types.NewArray("a", int32(1))))),
+		must.NotFail(types.NewDocument("_id", int32(3), "v", "b", "o", true, "n", 0.1+0.2)),
+		must.NotFail(types.NewDocument("_id", int32(4), "v", "a")),
+		must.NotFail(types.NewDocument("_id", int32(5), "v", must.NotFail(types.NewDocument("a", int32(1))))),
+	}
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+	require.NoError(t, err)
+
+	t.Run("Projection", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := coll.Query(ctx, &backends.QueryParams{Projection: []string{"_id", "n", "o", "v"}})
 		require.NoError(t, err)
-		assert.False(t, explainRes.SortPushdown)
+
+		actual, err := iterator.ConsumeValues(res.Iter)
+		require.NoError(t, err)
+
+		testutil.AssertEqualSlices(t, docs, actual)
+
+		res, err = coll.Query(ctx, &backends.QueryParams{Projection: []string{"_id", "n", "o"}})
+		require.NoError(t, err)
+
+		actual, err = iterator.ConsumeValues(res.Iter)
+		require.NoError(t, err)
+
+		expected := []*types.Document{
+			must.NotFail(types.NewDocument("_id", int32(1), "o", must.NotFail(types.NewDocument("x", types.Null, "y", 1.5)), "n", types.Null)),
+			must.NotFail(types.NewDocument("_id", int32(2), "n", int64(2))),
+			must.NotFail(types.NewDocument("_id", int32(3), "o", true, "n", 0.1+0.2)),
+			must.NotFail(types.NewDocument("_id", int32(4))),
+			must.NotFail(types.NewDocument("_id", int32(5))),
+		}
+		testutil.AssertEqualSlices(t, expected, actual)
 	})
+
+	for name, tc := range map[string]struct {
+		filter       *types.Document
+		skip         int64
+		limit        int64
+		expected     []any // _id values of documents returned by the query
+		skipPushdown bool
+	}{
+		"Skip": {
+			skip:         3,
+			expected:     []any{int32(4), int32(5)},
+			skipPushdown: true,
+		},
+		"SkipLimit": {
+			skip:         1,
+			limit:        2,
+			expected:     []any{int32(2), int32(3)},
+			skipPushdown: true,
+		},
+		"ExactFilter": {
+			filter:       must.NotFail(types.NewDocument("v", "a", "$comment", "foo")),
+			skip:         1,
+			limit:        1,
+			expected:     []any{int32(2)},
+			skipPushdown: true,
+		},
+		"InexactFilter": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$ne", "b")))),
+			skip:     1,
+			limit:    1,
+			expected: []any{int32(1), int32(2), int32(4), int32(5)},
+		},
+		"DotNotationFilter": {
+			filter:   must.NotFail(types.NewDocument("v.a", int32(1))),
+			skip:     1,
+			expected: []any{int32(2), int32(5)},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := coll.Query(ctx, &backends.QueryParams{Filter: tc.filter, Skip: tc.skip, Limit: tc.limit})
+			require.NoError(t, err)
+			assert.Equal(t, tc.skipPushdown, res.SkipPushdown)
+
+			docs, err := iterator.ConsumeValues(res.Iter)
+			require.NoError(t, err)
+
+			actual := make([]any, len(docs))
+			for i, doc := range docs {
+				actual[i] = must.NotFail(doc.Get("_id"))
+			}
+
+			assert.ElementsMatch(t, tc.expected, actual)
+
+			explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: tc.filter, Skip: tc.skip, Limit: tc.limit})
+			require.NoError(t, err)
+			assert.Equal(t, tc.skipPushdown, explainRes.SkipPushdown)
+		})
+	}
+}
+
+func TestUpdateAllPushdown(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	path := func(s string) types.Path {
+		return must.NotFail(types.NewPathFromString(s))
+	}
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		update   []backends.UpdateOperator
+		multi    bool
+		docs     []*types.Document
+		expected []*types.Document        // nil if update is not pushed down
+		res      backends.UpdateAllResult // without UpdatePushdown
+	}{
+		"IncSet": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{
+				{Operator: "$inc", Path: path("v"), Value: int32(2)},
+				{Operator: "$inc", Path: path("n"), Value: int64(5)},
+				{Operator: "$set", Path: path("o.b"), Value: "y"},
+				{Operator: "$set", Path: path("o.a"), Value: 1.5},
+			},
+			docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", int32(1), "o", must.NotFail(types.NewDocument("a", "x")))),
+				must.NotFail(types.NewDocument("_id", "b", "v", int32(1))),
+			},
+			expected: []*types.Document{
+				must.NotFail(types.NewDocument(
+					"_id", "a", "v", int32(3), "o", must.NotFail(types.NewDocument("a", 1.5, "b", "y")), "n", int64(5),
+				)),
+				must.NotFail(types.NewDocument("_id", "b", "v", int32(1))),
+			},
+			res: backends.UpdateAllResult{Matched: 1, Updated: 1},
+		},
+		"Unset": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{
+				{Operator: "$unset", Path: path("v")},
+				{Operator: "$unset", Path: path("o.a")},
+				{Operator: "$unset", Path: path("missing")},
+			},
+			docs: []*types.Document{
+				must.NotFail(types.NewDocument(
+					"_id", "a", "v", int32(1), "o", must.NotFail(types.NewDocument("a", "x", "b", "y")), "w", true,
+				)),
+			},
+			expected: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "o", must.NotFail(types.NewDocument("b", "y")), "w", true)),
+			},
+			res: backends.UpdateAllResult{Matched: 1, Updated: 1},
+		},
+		"EmptyObject": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{
+				{Operator: "$set", Path: path("o.a"), Value: int32(1)},
+				{Operator: "$unset", Path: path("p.a")},
+			},
+			docs: []*types.Document{
+				must.NotFail(types.NewDocument(
+					"_id", "a", "o", must.NotFail(types.NewDocument()), "p", must.NotFail(types.NewDocument()),
+				)),
+			},
+			expected: []*types.Document{
+				must.NotFail(types.NewDocument(
+					"_id", "a", "o", must.NotFail(types.NewDocument("a", int32(1))), "p", must.NotFail(types.NewDocument()),
+				)),
+			},
+			res: backends.UpdateAllResult{Matched: 1, Updated: 1},
+		},
+		"Multi": {
+			filter: must.NotFail(types.NewDocument("v", int32(1))),
+			update: []backends.UpdateOperator{{Operator: "$inc", Path: path("v"), Value: int64(1)}},
+			multi:  true,
+			docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", int32(1))),
+				must.NotFail(types.NewDocument("_id", "b", "v", int64(1))),
+				must.NotFail(types.NewDocument("_id", "c", "v", int32(2))),
+			},
+			expected: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", int64(2))),
+				must.NotFail(types.NewDocument("_id", "b", "v", int64(2))),
+				must.NotFail(types.NewDocument("_id", "c", "v", int32(2))),
+			},
+			res: backends.UpdateAllResult{Matched: 2, Updated: 2},
+		},
+		"Single": {
+			filter: must.NotFail(types.NewDocument("v", int32(1))),
+			update: []backends.UpdateOperator{{Operator: "$set", Path: path("w"), Value: types.Null}},
+			docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", int32(1))),
+				must.NotFail(types.NewDocument("_id", "b", "v", int32(1))),
+			},
+			expected: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", int32(1), "w", types.Null)),
+				must.NotFail(types.NewDocument("_id", "b", "v", int32(1))),
+			},
+			res: backends.UpdateAllResult{Matched: 1, Updated: 1},
+		},
+		"NotChanged": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{
+				{Operator: "$set", Path: path("v"), Value: "x"},
+				{Operator: "$inc", Path: path("n"), Value: int32(0)},
+				{Operator: "$unset", Path: path("missing")},
+			},
+			docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", "x", "n", int32(1))),
+			},
+			expected: []*types.Document{
+				must.NotFail(types.NewDocument("_id", "a", "v", "x", "n", int32(1))),
+			},
+			res: backends.UpdateAllResult{Matched: 1},
+		},
+		"NoMatch": {
+			filter:   must.NotFail(types.NewDocument("_id", "z")),
+			update:   []backends.UpdateOperator{{Operator: "$inc", Path: path("v"), Value: int32(1)}},
+			docs:     []*types.Document{must.NotFail(types.NewDocument("_id", "a", "v", int32(1)))},
+			expected: []*types.Document{must.NotFail(types.NewDocument("_id", "a", "v", int32(1)))},
+		},
+		"Overflow": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{{Operator: "$inc", Path: path("v"), Value: int32(1)}},
+			docs:   []*types.Document{must.NotFail(types.NewDocument("_id", "a", "v", int32(math.MaxInt32)))},
+		},
+		"NonNumeric": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{{Operator: "$inc", Path: path("v"), Value: int32(1)}},
+			docs:   []*types.Document{must.NotFail(types.NewDocument("_id", "a", "v", 1.5))},
+		},
+		"NonObjectParent": {
+			filter: must.NotFail(types.NewDocument("_id", "a")),
+			update: []backends.UpdateOperator{{Operator: "$set", Path: path("v.a"), Value: int32(1)}},
+			docs:   []*types.Document{must.NotFail(types.NewDocument("_id", "a", "v", must.NotFail(types.NewArray())))},
+		},
+		"UnsupportedFilter": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", int32(0))))),
+			update: []backends.UpdateOperator{{Operator: "$inc", Path: path("v"), Value: int32(1)}},
+			docs:   []*types.Document{must.NotFail(types.NewDocument("_id", "a", "v", int32(1)))},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			coll, err := db.Collection(testutil.CollectionName(t))
+			require.NoError(t, err)
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: tc.docs})
+			require.NoError(t, err)
+
+			res, err := coll.UpdateAll(ctx, &backends.UpdateAllParams{Filter: tc.filter, Update: tc.update, Multi: tc.multi})
+			require.NoError(t, err)
+
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.docs
+			}
+
+			tc.res.UpdatePushdown = tc.expected != nil
+			assert.Equal(t, tc.res, *res)
+
+			queryRes, err := coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			actual, err := iterator.ConsumeValues(queryRes.Iter)
+			require.NoError(t, err)
+
+			testutil.AssertEqualSlices(t, expected, actual)
+
+			explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: tc.filter, Update: tc.update})
+			require.NoError(t, err)
+			assert.Equal(t, name != "UnsupportedFilter", explainRes.UpdatePushdown)
+		})
+	}
+}
+
+func TestDeleteAllFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", "a", "v", int32(1))),
+		must.NotFail(types.NewDocument("_id", "b", "v", int64(1))),
+		must.NotFail(types.NewDocument("_id", "c", "v", must.NotFail(types.NewArray(1.0, "x")))),
+		must.NotFail(types.NewDocument("_id", "d", "v", "x")),
+		must.NotFail(types.NewDocument("_id", "e", "v", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))),
+		must.NotFail(types.NewDocument("_id", "f", "v", must.NotFail(types.NewArray(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "x")))),
+	}
+
+	date := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		limited  bool
+		expected []string // IDs of remaining documents, nil if delete is not pushed down
+	}{
+		"All": {
+			filter:   new(types.Document),
+			expected: []string{},
+		},
+		"ID": {
+			filter:   must.NotFail(types.NewDocument("_id", "b")),
+			expected: []string{"a", "c", "d", "e", "f"},
+		},
+		"Numbers": {
+			filter:   must.NotFail(types.NewDocument("v", int32(1), "$comment", "numbers")),
+			expected: []string{"d", "e", "f"},
+		},
+		"Strings": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$eq", "x")))),
+			expected: []string{"a", "b", "e"},
+		},
+		"Limited": {
+			filter:   must.NotFail(types.NewDocument("v", int32(1))),
+			limited:  true,
+			expected: []string{"b", "c", "d", "e", "f"},
+		},
+		"NoMatch": {
+			filter:   must.NotFail(types.NewDocument("_id", "z")),
+			expected: []string{"a", "b", "c", "d", "e", "f"},
+		},
+		"DatesBefore": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$lte", date)))),
+			expected: []string{"a", "b", "c", "d", "f"},
+		},
+		"DatesAfter": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", date)))),
+			expected: []string{"a", "b", "c", "d", "e"},
+		},
+		"UnsupportedFilter": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", int32(0))))),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			coll, err := db.Collection(testutil.CollectionName(t))
+			require.NoError(t, err)
+
+			insertDocs := make([]*types.Document, len(docs))
+			for i, doc := range docs {
+				insertDocs[i] = doc.DeepCopy()
+			}
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: insertDocs})
+			require.NoError(t, err)
+
+			res, err := coll.DeleteAll(ctx, &backends.DeleteAllParams{Filter: tc.filter, Limited: tc.limited})
+			require.NoError(t, err)
+
+			expected := tc.expected
+			if expected == nil {
+				expected = []string{"a", "b", "c", "d", "e", "f"}
+			}
+
+			assert.Equal(t, tc.expected != nil, res.FilterPushdown)
+			assert.Equal(t, int32(len(docs)-len(expected)), res.Deleted)
+
+			queryRes, err := coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			actual, err := iterator.ConsumeValues(queryRes.Iter)
+			require.NoError(t, err)
+
+			ids := make([]string, len(actual))
+			for i, doc := range actual {
+				ids[i] = must.NotFail(doc.Get("_id")).(string)
+			}
+
+			assert.ElementsMatch(t, expected, ids)
+		})
+	}
 }
@@ -19,7 +19,10 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // database implements backends.Database interface.
@@ -52,11 +55,25 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 	res := make([]backends.CollectionInfo, len(list))
 	for i, c := range list {
+		var validation *backends.CollectionValidation
+
+		if validation, err = unmarshalValidation(c.Settings.Validation); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var view *backends.CollectionView
+
+		if view, err = unmarshalView(c.Settings.View); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
 		res[i] = backends.CollectionInfo{
 			Name:            c.Name,
 			UUID:            c.Settings.UUID,
 			CappedSize:      c.Settings.CappedSize,
 			CappedDocuments: c.Settings.CappedDocuments,
+			Validation:      validation,
+			View:            view,
 		}
 	}
 
@@ -67,11 +84,23 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	validation, err := marshalValidation(params.Validation)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	view, err := marshalView(params.View)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
 	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
 		DBName:          db.name,
 		Name:            params.Name,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		Validation:      validation,
+		View:            view,
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -126,6 +155,34 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return nil
 }
 
+// ModifyCollection implements backends.Database interface.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	validation, err := marshalValidation(params.Validation)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	modified, err := db.r.CollectionModify(ctx, &metadata.CollectionModifyParams{
+		DBName:          db.name,
+		Name:            params.Name,
+		Validation:      validation,
+		CappedSize:      params.CappedSize,
+		CappedDocuments: params.CappedDocuments,
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !modified {
+		return backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("database %q or collection %q does not exist", db.name, params.Name),
+		)
+	}
+
+	return nil
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	if params == nil {
@@ -168,6 +225,86 @@ func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsPar
 	}, nil
 }
 
+// marshalValidation converts validation settings to metadata representation.
+func marshalValidation(v *backends.CollectionValidation) (*metadata.Validation, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	res := &metadata.Validation{
+		Level:  v.Level,
+		Action: v.Action,
+	}
+
+	if v.Validator != nil {
+		b, err := sjson.Marshal(v.Validator)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Validator = b
+	}
+
+	return res, nil
+}
+
+// unmarshalValidation converts metadata representation of validation settings.
+func unmarshalValidation(v *metadata.Validation) (*backends.CollectionValidation, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	res := &backends.CollectionValidation{
+		Level:  v.Level,
+		Action: v.Action,
+	}
+
+	if len(v.Validator) > 0 {
+		doc, err := sjson.Unmarshal(v.Validator)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Validator = doc
+	}
+
+	return res, nil
+}
+
+// marshalView converts the view definition to metadata representation.
+func marshalView(v *backends.CollectionView) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := sjson.Marshal(must.NotFail(types.NewDocument(
+		"viewOn", v.ViewOn,
+		"pipeline", v.Pipeline,
+	)))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return b, nil
+}
+
+// unmarshalView converts metadata representation of the view definition.
+func unmarshalView(b []byte) (*backends.CollectionView, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	doc, err := sjson.Unmarshal(b)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &backends.CollectionView{
+		ViewOn:   must.NotFail(doc.Get("viewOn")).(string),
+		Pipeline: must.NotFail(doc.Get("pipeline")).(*types.Array),
+	}, nil
+}
+
 // check interfaces
 var (
 	_ backends.Database = (*database)(nil)
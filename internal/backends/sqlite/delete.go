@@ -0,0 +1,47 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// prepareDeleteQuery returns the query with arguments that deletes documents matching the filter.
+//
+// If limited is true, only the first matching document in the natural order is deleted.
+// It returns false if the filter can't be pushed down completely.
+func prepareDeleteQuery(meta *metadata.Collection, filter *types.Document, limited bool) (string, []any, bool) {
+	var ga groupArgs
+
+	where, ok := prepareGroupWhereClause(&ga, filter)
+	if !ok {
+		return "", nil, false
+	}
+
+	if !limited {
+		return fmt.Sprintf(`DELETE FROM %q%s`, meta.TableName, where), ga.args, true
+	}
+
+	// DELETE ... LIMIT requires SQLite to be compiled with SQLITE_ENABLE_UPDATE_DELETE_LIMIT
+	q := fmt.Sprintf(
+		`DELETE FROM %[1]q WHERE rowid IN (SELECT rowid FROM %[1]q%[2]s%[3]s LIMIT 1)`,
+		meta.TableName, where, prepareOrderByClause(nil, meta.Capped()),
+	)
+
+	return q, ga.args, true
+}
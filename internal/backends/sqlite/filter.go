@@ -0,0 +1,667 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// rangeOperators maps range query operators to SQL comparison operators.
+var rangeOperators = map[string]string{
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+// idEqualCondition is the SQL condition for the _id equality that uses the collection's _id index.
+var idEqualCondition = metadata.IDColumn + ` = ?`
+
+// prepareFilters returns SQL conditions with arguments that select documents matching the given filter
+// (and maybe some extra documents). Conditions should be combined with AND.
+//
+// Filter expressions that can't be translated to SQL are skipped, so the filter should be applied again.
+// Logical operators are translated recursively.
+func prepareFilters(filter *types.Document) ([]string, []any, error) {
+	var filters []string
+	var args []any
+
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		rootKey, rootVal, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		switch rootKey {
+		case "$and":
+			exprs, _ := rootVal.(*types.Array)
+
+			for i := 0; i < exprs.Len(); i++ {
+				expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+				if !ok {
+					continue
+				}
+
+				f, a, err := prepareFilters(expr)
+				if err != nil {
+					return nil, nil, lazyerrors.Error(err)
+				}
+
+				filters = append(filters, f...)
+				args = append(args, a...)
+			}
+
+			continue
+
+		case "$or":
+			exprs, _ := rootVal.(*types.Array)
+
+			f, a, err := filterOr(exprs)
+			if err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+
+			if f != "" {
+				filters = append(filters, f)
+				args = append(args, a...)
+			}
+
+			continue
+
+		case "$nor":
+			exprs, _ := rootVal.(*types.Array)
+
+			f, a, err := filterNor(exprs)
+			if err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+
+			if f != "" {
+				filters = append(filters, f)
+				args = append(args, a...)
+			}
+
+			continue
+		}
+
+		// don't pushdown $comment, as it's attached to query with select clause
+		//
+		// all of the other top-level operators such as `$expr` do not support pushdown
+		if strings.HasPrefix(rootKey, "$") {
+			continue
+		}
+
+		f, a, err := prepareFieldFilters(rootKey, rootVal)
+		if err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		filters = append(filters, f...)
+		args = append(args, a...)
+	}
+
+	return filters, args, nil
+}
+
+// prepareFieldFilters returns SQL conditions with arguments for the filter value of the given key
+// (top-level field or dot notation path).
+//
+// Only implicit equality, `$eq`, `$ne`, range, `$in`, `$nin` and `$exists` operators are pushed down.
+func prepareFieldFilters(key string, filterVal any) ([]string, []any, error) {
+	f, ok := newFilterField(key)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	var filters []string
+	var args []any
+
+	doc, ok := filterVal.(*types.Document)
+	if !ok {
+		if cond, a := filterEqual(f, filterVal); cond != "" {
+			filters = append(filters, cond)
+			args = append(args, a...)
+		}
+
+		return filters, args, nil
+	}
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		op, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		var cond string
+		var a []any
+
+		switch op {
+		case "$eq":
+			cond, a = filterEqual(f, v)
+
+		case "$ne":
+			cond, a = filterNotEqual(f, v)
+
+		case "$gt", "$gte", "$lt", "$lte":
+			cond, a = filterRange(f, op, v)
+
+		case "$in":
+			values, _ := v.(*types.Array)
+			cond, a = filterIn(f, values, false)
+
+		case "$nin":
+			values, _ := v.(*types.Array)
+
+			for i := 0; i < values.Len(); i++ {
+				if cond, a := filterNotEqual(f, must.NotFail(values.Get(i))); cond != "" {
+					filters = append(filters, cond)
+					args = append(args, a...)
+				}
+			}
+
+		case "$exists":
+			cond, a = filterExists(f, v, false)
+
+		default:
+			// TODO https://github.com/FerretDB/FerretDB/issues/1875
+			continue
+		}
+
+		if cond != "" {
+			filters = append(filters, cond)
+			args = append(args, a...)
+		}
+	}
+
+	return filters, args, nil
+}
+
+// filterOr returns SQL filter with arguments for `$or` operator expressions.
+//
+// It returns an empty string if any expression can't be translated,
+// as skipping it would exclude documents matching only that expression.
+func filterOr(exprs *types.Array) (string, []any, error) {
+	var filters []string
+	var args []any
+
+	for i := 0; i < exprs.Len(); i++ {
+		expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+		if !ok {
+			return "", nil, nil
+		}
+
+		f, a, err := prepareFilters(expr)
+		if err != nil {
+			return "", nil, lazyerrors.Error(err)
+		}
+
+		if len(f) == 0 {
+			return "", nil, nil
+		}
+
+		filters = append(filters, "("+strings.Join(f, " AND ")+")")
+		args = append(args, a...)
+	}
+
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+
+	return "(" + strings.Join(filters, " OR ") + ")", args, nil
+}
+
+// filterNor returns SQL filter with arguments for `$nor` operator expressions.
+//
+// Only documents strictly matching one of expressions are excluded (see prepareStrictFilters).
+// It returns an empty string if any expression can't be translated that way.
+func filterNor(exprs *types.Array) (string, []any, error) {
+	var filters []string
+	var args []any
+
+	for i := 0; i < exprs.Len(); i++ {
+		expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+		if !ok {
+			return "", nil, nil
+		}
+
+		f, a, err := prepareStrictFilters(expr)
+		if err != nil {
+			return "", nil, lazyerrors.Error(err)
+		}
+
+		if len(f) == 0 {
+			return "", nil, nil
+		}
+
+		filters = append(filters, "("+strings.Join(f, " AND ")+")")
+		args = append(args, a...)
+	}
+
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+
+	return "NOT COALESCE(" + strings.Join(filters, " OR ") + ", false)", args, nil
+}
+
+// prepareStrictFilters returns SQL conditions with arguments that select only documents matching the given filter
+// (but maybe not all of them), so they could be negated. Conditions should be combined with AND.
+//
+// Only implicit equality, `$eq`, `$in` and `$exists` operators are supported.
+// It returns nil if any expression of the filter can't be translated.
+func prepareStrictFilters(filter *types.Document) ([]string, []any, error) {
+	var filters []string
+	var args []any
+
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		key, val, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		var cond string
+		var a []any
+
+		if f, ok := newFilterField(key); ok && !strings.HasPrefix(key, "$") {
+			if cond, a, err = filterFieldStrict(f, val); err != nil {
+				return nil, nil, lazyerrors.Error(err)
+			}
+		}
+
+		if cond == "" {
+			return nil, nil, nil
+		}
+
+		filters = append(filters, cond)
+		args = append(args, a...)
+	}
+
+	return filters, args, nil
+}
+
+// isExactFilter returns true if SQL conditions returned by prepareFilters select exactly
+// the documents matching the given filter, so they could be skipped and limited by SQLite.
+//
+// That is the case for implicit equality, `$eq`, `$in` and `$exists` operators for top-level fields,
+// as conditions for them are the same as strict ones (see prepareStrictFilters).
+// The _id field is excluded, as its equality condition does not distinguish strings and ObjectIDs.
+func isExactFilter(filter *types.Document) (bool, error) {
+	if filter.Len() == 0 {
+		return true, nil
+	}
+
+	if filter.Has("$comment") {
+		filter = filter.DeepCopy()
+		filter.Remove("$comment")
+	}
+
+	for _, key := range filter.Keys() {
+		if key == "_id" || strings.ContainsRune(key, '.') {
+			return false, nil
+		}
+	}
+
+	filters, _, err := prepareStrictFilters(filter)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return len(filters) > 0, nil
+}
+
+// filterFieldStrict returns SQL filter with arguments that selects only documents
+// where the field value matches v (implicit equality or operators).
+//
+// It returns an empty string if that can't be done.
+func filterFieldStrict(f *filterField, v any) (string, []any, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		cond, a := filterCompare(f, "=", v, true)
+		return cond, a, nil
+	}
+
+	var filters []string
+	var args []any
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		op, opVal, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return "", nil, lazyerrors.Error(err)
+		}
+
+		var cond string
+		var a []any
+
+		switch op {
+		case "$eq":
+			cond, a = filterCompare(f, "=", opVal, true)
+
+		case "$in":
+			values, _ := opVal.(*types.Array)
+			cond, a = filterIn(f, values, true)
+
+		case "$exists":
+			cond, a = filterExists(f, opVal, true)
+		}
+
+		if cond == "" {
+			return "", nil, nil
+		}
+
+		filters = append(filters, cond)
+		args = append(args, a...)
+	}
+
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+
+	return strings.Join(filters, " AND "), args, nil
+}
+
+// filterField contains SQL expressions for the document field used by filters.
+type filterField struct {
+	path   string   // quoted JSON path of the value
+	value  string   // value, as returned by json_extract
+	typ    string   // sjson type of the value
+	items  string   // quoted prefix of JSON path to sjson types of array items
+	arrays []string // conditions selecting documents with arrays on the path before the field
+}
+
+// newFilterField returns filterField for the given top-level field or dot notation path.
+//
+// It returns false if the path can't be used in JSON path,
+// or if it contains elements that could be array indexes,
+// as it depends on the document whether such element refers to an array element or a field.
+func newFilterField(key string) (*filterField, bool) {
+	var elems []string
+
+	path, err := types.NewPathFromString(key)
+
+	var pe *types.PathError
+
+	switch {
+	case err == nil:
+		elems = path.Slice()
+	case errors.As(err, &pe) && pe.Code() == types.ErrPathElementEmpty && key == "":
+		// empty key is a valid top-level field
+		elems = []string{""}
+	default:
+		return nil, false
+	}
+
+	for i, e := range elems {
+		if strings.ContainsAny(e, `"\`) {
+			return nil, false
+		}
+
+		if _, err := strconv.ParseUint(e, 10, 64); err == nil && i > 0 {
+			return nil, false
+		}
+	}
+
+	var valuePath, typePath string
+
+	f := new(filterField)
+
+	for i, e := range elems {
+		if i > 0 {
+			f.arrays = append(f.arrays, fmt.Sprintf(
+				`json_extract(%s, %s) = 'array'`,
+				metadata.DefaultColumn, quoteString(`$`+typePath+`.t`),
+			))
+		}
+
+		valuePath += `."` + e + `"`
+		typePath += `."$s".p."` + e + `"`
+	}
+
+	f.path = quoteString(`$` + valuePath)
+	f.value = fmt.Sprintf(`json_extract(%s, %s)`, metadata.DefaultColumn, f.path)
+	f.typ = fmt.Sprintf(`json_extract(%s, %s)`, metadata.DefaultColumn, quoteString(`$`+typePath+`.t`))
+	f.items = quoteString(`$` + typePath + `.i[`)
+
+	return f, true
+}
+
+// condition returns SQL condition with arguments that selects documents where the field value
+// or one of its array items match the given condition of the value and its sjson type.
+//
+// Documents with arrays on the path before the field are also selected, unless strict is true.
+func (f *filterField) condition(cond func(value, typ string) string, args []any, strict bool) (string, []any) {
+	item := cond(`e.value`, fmt.Sprintf(`json_extract(%s, %s || e.key || '].t')`, metadata.DefaultColumn, f.items))
+
+	res := fmt.Sprintf(
+		`(%s) OR (%s = 'array' AND EXISTS (SELECT 1 FROM json_each(%s, %s) AS e WHERE %s))`,
+		cond(f.value, f.typ), f.typ, metadata.DefaultColumn, f.path, item,
+	)
+
+	if !strict {
+		for _, a := range f.arrays {
+			res += ` OR ` + a
+		}
+	}
+
+	return `(` + res + `)`, append(slices.Clone(args), args...)
+}
+
+// filterValue returns the list of sjson types and SQL argument for comparing field values with v.
+//
+// It returns an empty string if v type is not supported.
+func filterValue(v any) (typeNames string, arg any) {
+	switch v := v.(type) {
+	case *types.Document, *types.Array, types.Binary,
+		types.NullType, types.Regex, types.Timestamp:
+		// type not supported for pushdown
+		return "", nil
+
+	case float64:
+		if math.IsNaN(v) {
+			return "", nil
+		}
+
+		return `('int', 'long', 'double')`, v
+
+	case int32, int64:
+		return `('int', 'long', 'double')`, v
+
+	case string:
+		return `('string')`, v
+
+	case types.ObjectID:
+		return `('objectId')`, hex.EncodeToString(v[:])
+
+	case bool:
+		return `('bool')`, v
+
+	case time.Time:
+		return `('date')`, v.UnixMilli()
+
+	default:
+		panic(fmt.Sprintf("Unexpected type of value: %v", v))
+	}
+}
+
+// filterCompare returns SQL filter with arguments that filters documents
+// where the field value (or one of its array items) of the same BSON type order
+// compares with v using the given SQL operator.
+//
+// Integers and doubles are compared exactly by SQLite, and strings are compared bytewise,
+// as for BSON comparison.
+// It returns an empty string if v type is not supported.
+func filterCompare(f *filterField, op string, v any, strict bool) (string, []any) {
+	typeNames, arg := filterValue(v)
+	if typeNames == "" {
+		return "", nil
+	}
+
+	cond := func(value, typ string) string {
+		return fmt.Sprintf(`%s IN %s AND %s %s ?`, typ, typeNames, value, op)
+	}
+
+	return f.condition(cond, []any{arg}, strict)
+}
+
+// filterEqual returns SQL filter with arguments that filters documents
+// where the field value is equal to v.
+//
+// The collection's _id index is used for the string or ObjectID _id value.
+func filterEqual(f *filterField, v any) (string, []any) {
+	if f.path == `'$."_id"'` {
+		switch v.(type) {
+		case string, types.ObjectID:
+			return idEqualCondition, []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
+		}
+	}
+
+	return filterCompare(f, "=", v, false)
+}
+
+// filterNotEqual returns SQL filter with arguments that filters documents
+// where the field value is not equal to v.
+//
+// Documents with arrays on the path before the field are not excluded, so the filter should be applied again.
+func filterNotEqual(f *filterField, v any) (string, []any) {
+	filter, args := filterCompare(f, "=", v, true)
+	if filter == "" {
+		return "", nil
+	}
+
+	return `NOT COALESCE(` + filter + `, false)`, args
+}
+
+// filterRange returns SQL filter with arguments that filters documents
+// where the field value (or one of its array items) matches range operator op with v.
+//
+// Values of other BSON types never match, as for MongoDB.
+func filterRange(f *filterField, op string, v any) (string, []any) {
+	if _, ok := v.(bool); ok {
+		// type not supported for pushdown
+		return "", nil
+	}
+
+	return filterCompare(f, rangeOperators[op], v, false)
+}
+
+// filterIn returns SQL filter with arguments that filters documents
+// where the field value is equal to one of the given values.
+//
+// It returns an empty string if any of values is not supported.
+func filterIn(f *filterField, values *types.Array, strict bool) (string, []any) {
+	var filters []string
+	var args []any
+
+	for i := 0; i < values.Len(); i++ {
+		v := must.NotFail(values.Get(i))
+
+		var cond string
+		var a []any
+
+		if strict {
+			cond, a = filterCompare(f, "=", v, true)
+		} else {
+			cond, a = filterEqual(f, v)
+		}
+
+		if cond == "" {
+			return "", nil
+		}
+
+		filters = append(filters, cond)
+		args = append(args, a...)
+	}
+
+	if len(filters) == 0 {
+		return "", nil
+	}
+
+	return "(" + strings.Join(filters, " OR ") + ")", args
+}
+
+// filterExists returns SQL filter with arguments that filters documents
+// where the field exists or does not exist.
+//
+// The check is exact for top-level fields. For dot notation paths, documents with arrays on the path
+// are not excluded, unless strict is true; in that case, only the check for the existing field is supported.
+// It returns an empty string if v is not a boolean.
+func filterExists(f *filterField, v any, strict bool) (string, []any) {
+	exists, ok := v.(bool)
+	if !ok {
+		return "", nil
+	}
+
+	if !exists {
+		if strict && len(f.arrays) > 0 {
+			return "", nil
+		}
+
+		return fmt.Sprintf(`json_type(%s, %s) IS NULL`, metadata.DefaultColumn, f.path), nil
+	}
+
+	filter := fmt.Sprintf(`json_type(%s, %s) IS NOT NULL`, metadata.DefaultColumn, f.path)
+
+	if !strict && len(f.arrays) > 0 {
+		filter = `(` + filter + ` OR ` + strings.Join(f.arrays, " OR ") + `)`
+	}
+
+	return filter, nil
+}
+
+// quoteString returns a string that is safe to use in SQL queries.
+func quoteString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
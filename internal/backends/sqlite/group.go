@@ -0,0 +1,445 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// minMaxTypes contains sjson types of values for which $min and $max are computed by SQLite.
+var minMaxTypes = []string{"int", "long", "double", "string", "objectId", "bool", "date"}
+
+// dateOperators maps comparison operators that prepareGroupWhereClause supports for dates to SQL ones.
+var dateOperators = map[string]string{
+	"$lt":  "<",
+	"$lte": "<=",
+	"$gt":  ">",
+	"$gte": ">=",
+}
+
+// errGroupUnsupported is returned when grouped values can't be handled by pushdown.
+var errGroupUnsupported = errors.New("unsupported values for $group pushdown")
+
+// groupArgs stores named arguments of the group query.
+type groupArgs struct {
+	args []any
+}
+
+// add adds a named argument and returns its name for the query.
+func (ga *groupArgs) add(v any) string {
+	name := fmt.Sprintf("p%d", len(ga.args)+1)
+	ga.args = append(ga.args, sql.Named(name, v))
+
+	return ":" + name
+}
+
+// fieldExprs returns the JSON path argument of the top-level field, SQL expressions for its value and sjson type,
+// and the argument with the prefix of JSON path to types of array items.
+//
+// It returns false if the field name can't be used in JSON path.
+func fieldExprs(ga *groupArgs, field string) (path, value, typ, items string, ok bool) {
+	if field == "" || strings.ContainsAny(field, `."\`) || strings.HasPrefix(field, "$") {
+		return
+	}
+
+	path = ga.add(`$."` + field + `"`)
+	value = fmt.Sprintf(`json_extract(%s, %s)`, metadata.DefaultColumn, path)
+	typ = fmt.Sprintf(`json_extract(%s, %s)`, metadata.DefaultColumn, ga.add(`$."$s".p."`+field+`".t`))
+	items = ga.add(`$."$s".p."` + field + `".i[`)
+	ok = true
+
+	return
+}
+
+// prepareGroupWhereClause returns WHERE clause that applies the whole given filter.
+//
+// Only equality for top-level fields and comparison of them with dates are supported.
+// The result matches exactly the same documents as the filter does.
+// If that is not possible, it returns false.
+func prepareGroupWhereClause(ga *groupArgs, filter *types.Document) (string, bool) {
+	var filters []string
+
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return "", false
+		}
+
+		if k == "$comment" {
+			continue
+		}
+
+		path, value, typ, items, ok := fieldExprs(ga, k)
+		if !ok {
+			return "", false
+		}
+
+		op := "="
+
+		if d, isDoc := v.(*types.Document); isDoc {
+			if d.Len() != 1 {
+				return "", false
+			}
+
+			operator := d.Command()
+			v = must.NotFail(d.Get(operator))
+
+			switch _, isDate := v.(time.Time); {
+			case operator == "$eq":
+			case isDate && dateOperators[operator] != "":
+				op = dateOperators[operator]
+			default:
+				return "", false
+			}
+		}
+
+		var typeNames, arg string
+
+		switch v := v.(type) {
+		case string:
+			typeNames, arg = `('string')`, ga.add(v)
+		case types.ObjectID:
+			typeNames, arg = `('objectId')`, ga.add(hex.EncodeToString(v[:]))
+		case bool:
+			typeNames, arg = `('bool')`, ga.add(v)
+		case time.Time:
+			typeNames, arg = `('date')`, ga.add(v.UnixMilli())
+		case int32, int64, float64:
+			typeNames, arg = `('int', 'long', 'double')`, ga.add(v)
+		default:
+			return "", false
+		}
+
+		filters = append(filters, fmt.Sprintf(
+			`((%[1]s IN %[2]s AND %[3]s %[8]s %[4]s) OR (%[1]s = 'array' AND EXISTS (`+
+				`SELECT 1 FROM json_each(%[5]s, %[6]s) AS e `+
+				`WHERE json_extract(%[5]s, %[7]s || e.key || '].t') IN %[2]s AND e.value %[8]s %[4]s)))`,
+			typ, typeNames, value, arg, metadata.DefaultColumn, path, items, op,
+		))
+
+		// the _id index is used for string and ObjectID values, see filterEqual
+		switch v.(type) {
+		case string, types.ObjectID:
+			if k == "_id" {
+				filters = append(filters, metadata.IDColumn+` = `+ga.add(string(must.NotFail(sjson.MarshalSingleValue(v)))))
+			}
+		}
+	}
+
+	var where string
+	if len(filters) > 0 {
+		where = ` WHERE ` + strings.Join(filters, " AND ")
+	}
+
+	return where, true
+}
+
+// groupQuery represents the query that groups documents and partially aggregates accumulators.
+type groupQuery struct {
+	q    string
+	args []any
+
+	count    int64
+	keyType  any
+	keyValue any
+
+	// columns for each accumulator
+	columns [][]any
+}
+
+// prepareGroupQuery returns the query that groups documents by the key and partially aggregates accumulators.
+//
+// It returns false if the filter or grouping can't be pushed down completely.
+func prepareGroupQuery(table string, filter *types.Document, group *backends.GroupParams) (*groupQuery, bool) {
+	var ga groupArgs
+
+	gq := &groupQuery{
+		columns: make([][]any, len(group.Accumulators)),
+	}
+
+	selects := []string{`count(*)`}
+
+	if group.Key == "" {
+		selects = append(selects, `NULL`, `NULL`)
+	} else {
+		_, value, typ, _, ok := fieldExprs(&ga, group.Key)
+		if !ok {
+			return nil, false
+		}
+
+		selects = append(selects, typ, value)
+	}
+
+	for i, acc := range group.Accumulators {
+		if acc.Field == "" {
+			if acc.Operator != "$sum" {
+				return nil, false
+			}
+
+			selects = append(selects, `count(*)`)
+			gq.columns[i] = []any{new(int64)}
+
+			continue
+		}
+
+		_, value, typ, _, ok := fieldExprs(&ga, acc.Field)
+		if !ok {
+			return nil, false
+		}
+
+		var exprs []string
+
+		switch acc.Operator {
+		case "$sum", "$avg":
+			// integers are summed by parts to avoid overflow
+			exprs = []string{
+				fmt.Sprintf(`sum(%s >> 32) FILTER (WHERE %s IN ('int', 'long'))`, value, typ),
+				fmt.Sprintf(`sum(%s & 4294967295) FILTER (WHERE %s IN ('int', 'long'))`, value, typ),
+				fmt.Sprintf(`count(*) FILTER (WHERE %s IN ('int', 'long'))`, typ),
+				fmt.Sprintf(`count(*) FILTER (WHERE %s = 'long')`, typ),
+				fmt.Sprintf(`total(%s) FILTER (WHERE %s = 'double')`, value, typ),
+				fmt.Sprintf(`count(*) FILTER (WHERE %s = 'double')`, typ),
+			}
+
+		case "$min", "$max":
+			f := strings.TrimPrefix(acc.Operator, "$")
+
+			for _, t := range minMaxTypes {
+				exprs = append(exprs, fmt.Sprintf(`%s(%s) FILTER (WHERE %s = '%s')`, f, value, typ, t))
+			}
+
+			exprs = append(exprs, fmt.Sprintf(
+				`count(*) FILTER (WHERE %s NOT IN ('null', '%s'))`,
+				typ, strings.Join(minMaxTypes, "', '"),
+			))
+
+		default:
+			return nil, false
+		}
+
+		selects = append(selects, exprs...)
+
+		for range exprs {
+			gq.columns[i] = append(gq.columns[i], new(any))
+		}
+	}
+
+	where, ok := prepareGroupWhereClause(&ga, filter)
+	if !ok {
+		return nil, false
+	}
+
+	gq.q = fmt.Sprintf(`SELECT %s FROM %q%s`, strings.Join(selects, ", "), table, where)
+
+	// groups are ordered by their first documents, so merged groups have keys of the same types
+	// as without pushdown (see GroupMerger)
+	if group.Key != "" {
+		gq.q += ` GROUP BY 2, 3 ORDER BY min(rowid)`
+	}
+
+	gq.args = ga.args
+
+	return gq, true
+}
+
+// dest returns scan destinations for all columns.
+func (gq *groupQuery) dest() []any {
+	res := []any{&gq.count, &gq.keyType, &gq.keyValue}
+
+	for _, cols := range gq.columns {
+		res = append(res, cols...)
+	}
+
+	return res
+}
+
+// queryGroup runs the group query and returns grouped documents.
+//
+// It returns errGroupUnsupported if some values can't be handled by pushdown.
+func queryGroup(ctx context.Context, db *fsql.DB, gq *groupQuery, group *backends.GroupParams) ([]*types.Document, error) {
+	rows, err := db.QueryContext(ctx, gq.q, gq.args...)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer rows.Close()
+
+	merger := backends.NewGroupMerger(group)
+
+	for rows.Next() {
+		if err = rows.Scan(gq.dest()...); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if gq.count == 0 || (group.SkipMissingKey && gq.keyType == nil) {
+			continue
+		}
+
+		var key any
+
+		if key, err = groupValue(gq.keyType, gq.keyValue); err != nil {
+			return nil, err
+		}
+
+		partials := make([]backends.GroupPartial, len(group.Accumulators))
+
+		for i, acc := range group.Accumulators {
+			if partials[i], err = groupPartial(&acc, gq.columns[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		merger.Add(key, partials)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return merger.Documents(), nil
+}
+
+// groupValue converts the value returned by json_extract to the value of the given sjson type.
+func groupValue(typ, v any) (any, error) {
+	if typ == nil || typ == "null" {
+		return types.Null, nil
+	}
+
+	switch typ {
+	case "int":
+		if i, ok := v.(int64); ok {
+			return int32(i), nil
+		}
+
+	case "long":
+		if i, ok := v.(int64); ok {
+			return i, nil
+		}
+
+	case "double":
+		switch f := v.(type) {
+		case float64:
+			return f, nil
+		case int64:
+			// integral doubles are stored without fractional part
+			return float64(f), nil
+		}
+
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+
+	case "objectId":
+		if s, ok := v.(string); ok {
+			var id types.ObjectID
+
+			b, err := hex.DecodeString(s)
+			if err != nil || len(b) != len(id) {
+				return nil, lazyerrors.Errorf("invalid ObjectID %q", s)
+			}
+
+			copy(id[:], b)
+
+			return id, nil
+		}
+
+	case "bool":
+		if i, ok := v.(int64); ok {
+			return i != 0, nil
+		}
+
+	case "date":
+		if i, ok := v.(int64); ok {
+			return time.UnixMilli(i).UTC(), nil
+		}
+
+	default:
+		return nil, errGroupUnsupported
+	}
+
+	return nil, lazyerrors.Errorf("unexpected value %[1]v (%[1]T) for type %[2]v", v, typ)
+}
+
+// groupPartial converts scanned columns of the accumulator to its partial value.
+func groupPartial(acc *backends.GroupAccumulator, cols []any) (backends.GroupPartial, error) {
+	var res backends.GroupPartial
+
+	if acc.Field == "" {
+		res.Count = *cols[0].(*int64)
+		return res, nil
+	}
+
+	switch acc.Operator {
+	case "$sum", "$avg":
+		hi, _ := (*cols[0].(*any)).(int64)
+		lo, _ := (*cols[1].(*any)).(int64)
+		ints, _ := (*cols[2].(*any)).(int64)
+		longs, _ := (*cols[3].(*any)).(int64)
+		doubles, _ := (*cols[5].(*any)).(int64)
+
+		res.IntSum = new(big.Int).Lsh(big.NewInt(hi), 32)
+		res.IntSum.Add(res.IntSum, big.NewInt(lo))
+
+		res.FloatSum, _ = (*cols[4].(*any)).(float64)
+		res.Count = ints + doubles
+		res.HasInt64 = longs > 0
+		res.HasFloat64 = doubles > 0
+
+	default:
+		if unsupported, _ := (*cols[len(minMaxTypes)].(*any)).(int64); unsupported > 0 {
+			return res, errGroupUnsupported
+		}
+
+		for i, t := range minMaxTypes {
+			raw := *cols[i].(*any)
+			if raw == nil {
+				continue
+			}
+
+			v, err := groupValue(t, raw)
+			if err != nil {
+				return res, err
+			}
+
+			res.Values = append(res.Values, v)
+		}
+	}
+
+	return res, nil
+}
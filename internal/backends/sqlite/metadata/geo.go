@@ -0,0 +1,152 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// geoWorldBound is the bound of the box stored in the R-tree for documents without a point location.
+//
+// Such documents are always returned by the query, so the handler could match them itself.
+const geoWorldBound = 3e38
+
+// GeoTableName returns the name of R-tree virtual table for the given geospatial index of the collection table.
+func GeoTableName(tableName, indexName string) string {
+	return tableName + "_" + indexName
+}
+
+// GeoField returns the field of the geospatial index key, or an empty string for other indexes.
+func (index *IndexInfo) GeoField() string {
+	for _, key := range index.Key {
+		switch key.Type {
+		case "2dsphere", "2d":
+			return key.Field
+		}
+	}
+
+	return ""
+}
+
+// geoTriggerNames returns names of triggers that keep R-tree virtual table in sync with the collection table.
+func geoTriggerNames(geoTableName string) []string {
+	return []string{geoTableName + "_insert", geoTableName + "_delete", geoTableName + "_update"}
+}
+
+// geoBox returns SQL expressions of minx, maxx, miny, maxy of the indexed field of the given row.
+//
+// GeoJSON points and legacy coordinate pairs are stored as points;
+// other values (including arrays of points) are stored as the box covering the whole plane.
+func geoBox(row, field string) []string {
+	path := "$"
+	for _, part := range strings.Split(field, ".") {
+		path += `."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(part) + `"`
+	}
+
+	path = strings.ReplaceAll(path, "'", "''")
+	column := row + "." + DefaultColumn
+
+	numbers := func(p string) string {
+		return fmt.Sprintf(
+			`json_type(%[1]s, '%[2]s[0]') IN ('integer', 'real') AND json_type(%[1]s, '%[2]s[1]') IN ('integer', 'real')`,
+			column, p,
+		)
+	}
+
+	legacy, geoJSON := path, path+`."coordinates"`
+
+	value := func(i int, bound float64) string {
+		return fmt.Sprintf(
+			`CASE WHEN %[1]s THEN json_extract(%[5]s, '%[2]s[%[4]d]') `+
+				`WHEN %[3]s THEN json_extract(%[5]s, '%[6]s[%[4]d]') ELSE %[7]g END`,
+			numbers(legacy), legacy, numbers(geoJSON), i, column, geoJSON, bound,
+		)
+	}
+
+	return []string{
+		value(0, -geoWorldBound), value(0, geoWorldBound),
+		value(1, -geoWorldBound), value(1, geoWorldBound),
+	}
+}
+
+// geoIndexCreate creates R-tree virtual table for the geospatial index of the collection table,
+// triggers that keep it in sync, and fills it with existing documents.
+//
+// Virtual table rows have the same ids as collection table rowids.
+func geoIndexCreate(ctx context.Context, db *fsql.DB, tableName string, index *IndexInfo) error {
+	geoTableName := GeoTableName(tableName, index.Name)
+
+	q := fmt.Sprintf("CREATE VIRTUAL TABLE %q USING rtree(id, minx, maxx, miny, maxy)", geoTableName)
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	names := geoTriggerNames(geoTableName)
+	newBox := strings.Join(geoBox("new", index.GeoField()), ", ")
+
+	qs := []string{
+		fmt.Sprintf(
+			"CREATE TRIGGER %q AFTER INSERT ON %q BEGIN "+
+				"INSERT INTO %q (id, minx, maxx, miny, maxy) VALUES (new.rowid, %s); END",
+			names[0], tableName, geoTableName, newBox,
+		),
+		fmt.Sprintf(
+			"CREATE TRIGGER %q AFTER DELETE ON %q BEGIN DELETE FROM %q WHERE id = old.rowid; END",
+			names[1], tableName, geoTableName,
+		),
+		fmt.Sprintf(
+			"CREATE TRIGGER %q AFTER UPDATE ON %q BEGIN "+
+				"INSERT OR REPLACE INTO %q (id, minx, maxx, miny, maxy) VALUES (new.rowid, %s); END",
+			names[2], tableName, geoTableName, newBox,
+		),
+		fmt.Sprintf(
+			"INSERT INTO %q (id, minx, maxx, miny, maxy) SELECT rowid, %s FROM %q AS new",
+			geoTableName, newBox, tableName,
+		),
+	}
+
+	for _, q = range qs {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			_ = geoIndexDrop(ctx, db, tableName, index.Name)
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// geoIndexDrop drops R-tree virtual table of the geospatial index and its triggers.
+func geoIndexDrop(ctx context.Context, db *fsql.DB, tableName, indexName string) error {
+	geoTableName := GeoTableName(tableName, indexName)
+
+	for _, name := range geoTriggerNames(geoTableName) {
+		q := fmt.Sprintf("DROP TRIGGER IF EXISTS %q", name)
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	q := fmt.Sprintf("DROP TABLE IF EXISTS %q", geoTableName)
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
@@ -16,13 +16,16 @@ package metadata
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/AlekSi/pointer"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -30,6 +33,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata/pool"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -236,7 +240,9 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+	Validation      *Validation
+	View            json.RawMessage // SJSON-encoded view definition, set for views only
+	_               struct{}        // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -329,6 +335,8 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 			UUID:            uuid.NewString(),
 			CappedSize:      params.CappedSize,
 			CappedDocuments: params.CappedDocuments,
+			Validation:      params.Validation.deepCopy(),
+			View:            slices.Clone(params.View),
 		},
 	}
 
@@ -415,6 +423,24 @@ func (r *Registry) collectionDrop(ctx context.Context, dbName, collectionName st
 		return false, lazyerrors.Error(err)
 	}
 
+	// triggers are dropped with the table, but virtual tables of text and geospatial indexes are not
+	for _, index := range c.Settings.Indexes {
+		var err error
+
+		switch {
+		case index.Text != nil:
+			err = textIndexDrop(ctx, db, c.TableName, index.Name)
+		case index.Geo != nil:
+			err = geoIndexDrop(ctx, db, c.TableName, index.Name)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return false, lazyerrors.Error(err)
+		}
+	}
+
 	delete(r.colls[dbName], collectionName)
 
 	return true, nil
@@ -454,6 +480,60 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	return true, nil
 }
 
+// CollectionModifyParams contains parameters for CollectionModify.
+type CollectionModifyParams struct {
+	DBName     string
+	Name       string
+	Validation *Validation // nil leaves validation settings unchanged
+
+	CappedSize      *int64 // nil leaves it unchanged
+	CappedDocuments *int64 // nil leaves it unchanged
+
+	_ struct{} // prevent unkeyed literals
+}
+
+// CollectionModify modifies settings of the collection in the database.
+//
+// Returned boolean value indicates whether the collection was modified.
+// If database or collection did not exist, (false, nil) is returned.
+func (r *Registry) CollectionModify(ctx context.Context, params *CollectionModifyParams) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	db := r.DatabaseGetExisting(ctx, params.DBName)
+	if db == nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.Name)
+	if c == nil {
+		return false, nil
+	}
+
+	if params.Validation != nil {
+		c.Settings.Validation = params.Validation.deepCopy()
+	}
+
+	if params.CappedSize != nil {
+		c.Settings.CappedSize = *params.CappedSize
+	}
+
+	if params.CappedDocuments != nil {
+		c.Settings.CappedDocuments = *params.CappedDocuments
+	}
+
+	q := fmt.Sprintf("UPDATE %q SET settings = ? WHERE table_name = ?", metadataTableName)
+	if _, err := db.ExecContext(ctx, q, c.Settings, c.TableName); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.Name] = c
+
+	return true, nil
+}
+
 // IndexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
@@ -496,25 +576,28 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 			continue
 		}
 
-		q := "CREATE "
-
-		if index.Unique {
-			q += "UNIQUE "
-		}
+		if index.Text != nil || index.Geo != nil || index.IsWildcard() {
+			create := textIndexCreate
 
-		// Find a better way to sanitize identifiers.
-		// TODO https://github.com/FerretDB/FerretDB/issues/3418
-		q += "INDEX %q ON %q (%s)"
+			switch {
+			case index.Geo != nil:
+				create = geoIndexCreate
+			case index.IsWildcard():
+				create = wildcardIndexCreate
+			}
 
-		columns := make([]string, len(index.Key))
-		for i, key := range index.Key {
-			columns[i] = fmt.Sprintf("%s->'$.%s'", DefaultColumn, key.Field)
-			if key.Descending {
-				columns[i] += " DESC"
+			if err := create(ctx, db, c.TableName, &index); err != nil {
+				_ = r.indexesDrop(ctx, dbName, collectionName, created)
+				return lazyerrors.Error(err)
 			}
+
+			created = append(created, index.Name)
+			c.Settings.Indexes = append(c.Settings.Indexes, index)
+
+			continue
 		}
 
-		q = fmt.Sprintf(q, c.TableName+"_"+index.Name, c.TableName, strings.Join(columns, ", "))
+		q := indexCreateQuery(c.TableName, &index)
 		if _, err := db.ExecContext(ctx, q); err != nil {
 			_ = r.indexesDrop(ctx, dbName, collectionName, created)
 			return lazyerrors.Error(err)
@@ -535,6 +618,239 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 	return nil
 }
 
+// indexCreateQuery returns a query that creates the regular (not text, geospatial, or wildcard) index.
+func indexCreateQuery(tableName string, index *IndexInfo) string {
+	q := "CREATE "
+
+	if index.Unique {
+		q += "UNIQUE "
+	}
+
+	// Find a better way to sanitize identifiers.
+	// TODO https://github.com/FerretDB/FerretDB/issues/3418
+	q += "INDEX %q ON %q (%s)"
+
+	columns := make([]string, len(index.Key))
+	for i, key := range index.Key {
+		columns[i] = IndexKeyExpression(key.Field)
+		if key.Descending {
+			columns[i] += " DESC"
+		}
+	}
+
+	q = fmt.Sprintf(q, IndexName(tableName, index.Name), tableName, strings.Join(columns, ", "))
+
+	if where := index.WhereClause(); where != "" {
+		q += " WHERE " + where
+	}
+
+	return q
+}
+
+// IndexName returns the name of SQLite index for the given regular index of the collection table.
+func IndexName(tableName, indexName string) string {
+	return tableName + "_" + indexName
+}
+
+// IndexKeyExpression returns SQL expression of the field value that is indexed by regular indexes.
+//
+// Values are returned as JSON text.
+func IndexKeyExpression(field string) string {
+	return fmt.Sprintf("%s->'$.%s'", DefaultColumn, field)
+}
+
+// WhereClause returns the condition of the sparse or partial index, or an empty string for other indexes.
+//
+// Queries should contain that condition to be able to use such index.
+func (index *IndexInfo) WhereClause() string {
+	var conditions []string
+
+	if sparse := index.SparseWhereClause(); sparse != "" {
+		conditions = append(conditions, sparse)
+	}
+
+	if len(index.PartialFilterExpression) > 0 {
+		filter := must.NotFail(sjson.Unmarshal(index.PartialFilterExpression))
+
+		for _, c := range backends.PartialFilterConditions(filter) {
+			conditions = append(conditions, partialFilterCondition(&c))
+		}
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// SparseWhereClause returns the condition of the sparse index, or an empty string for non-sparse indexes.
+//
+// Unlike the condition of the partial index, it selects exactly the documents that MongoDB indexes.
+func (index *IndexInfo) SparseWhereClause() string {
+	if !index.Sparse {
+		return ""
+	}
+
+	exists := make([]string, len(index.Key))
+	for i, key := range index.Key {
+		exists[i] = fmt.Sprintf("json_type(%s, %s) IS NOT NULL", DefaultColumn, valuePath(strings.Split(key.Field, ".")))
+	}
+
+	return "(" + strings.Join(exists, " OR ") + ")"
+}
+
+// partialFilterCondition returns SQL condition for the given partial index filter condition.
+func partialFilterCondition(c *backends.PartialFilterCondition) string {
+	path := c.Path.Slice()
+
+	if c.Operator == "$exists" {
+		return fmt.Sprintf("json_type(%s, %s) IS NOT NULL", DefaultColumn, valuePath(path))
+	}
+
+	value := fmt.Sprintf("json_extract(%s, %s)", DefaultColumn, valuePath(path))
+	typ := fmt.Sprintf("json_extract(%s, %s)", DefaultColumn, typePath(path))
+
+	op := map[string]string{"$eq": "=", "$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[c.Operator]
+	must.BeTrue(op != "")
+
+	switch v := c.Value.(type) {
+	case string:
+		return fmt.Sprintf("%s = 'string' AND %s %s %s", typ, value, op, quoteString(v))
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+
+		return fmt.Sprintf("%s = 'bool' AND %s %s %d", typ, value, op, b)
+	case int32:
+		return fmt.Sprintf("%s IN ('double', 'int', 'long') AND %s %s %d", typ, value, op, v)
+	case int64:
+		return fmt.Sprintf("%s IN ('double', 'int', 'long') AND %s %s %d", typ, value, op, v)
+	case float64:
+		return fmt.Sprintf(
+			"%s IN ('double', 'int', 'long') AND %s %s %s", typ, value, op, strconv.FormatFloat(v, 'g', -1, 64),
+		)
+	default:
+		panic(fmt.Sprintf("unexpected partial filter value type %T", v))
+	}
+}
+
+// valuePath returns quoted JSON path of the SJSON document's field value with the given path.
+func valuePath(path []string) string {
+	elems := make([]string, len(path))
+	for i, e := range path {
+		elems[i] = quoteJSONPathElement(e)
+	}
+
+	return quoteString("$." + strings.Join(elems, "."))
+}
+
+// typePath returns quoted JSON path of the SJSON document's schema type of the field with the given path.
+func typePath(path []string) string {
+	elems := make([]string, len(path))
+	for i, e := range path {
+		elems[i] = `"$s".p.` + quoteJSONPathElement(e)
+	}
+
+	return quoteString("$." + strings.Join(elems, ".") + ".t")
+}
+
+// quoteJSONPathElement returns the object key quoted for use in JSON path.
+func quoteJSONPathElement(key string) string {
+	return `"` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
+
+// quoteString returns a string that is safe to use in SQL queries.
+func quoteString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
+
+// IndexModifyParams contains parameters for IndexModify.
+type IndexModifyParams struct {
+	DBName             string
+	CollectionName     string
+	Name               string
+	ExpireAfterSeconds *int32 // nil leaves it unchanged
+	Hidden             *bool  // nil leaves it unchanged
+	PrepareUnique      *bool  // nil leaves it unchanged
+	Unique             bool   // true converts the index to the unique one
+	_                  struct{}
+}
+
+// IndexModify modifies options of the existing index.
+//
+// Returned boolean value indicates whether the index was modified.
+// If database, collection or index did not exist, (false, nil) is returned.
+func (r *Registry) IndexModify(ctx context.Context, params *IndexModifyParams) (bool, error) {
+	defer observability.FuncCall(ctx)()
+
+	db := r.DatabaseGetExisting(ctx, params.DBName)
+	if db == nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.CollectionName)
+	if c == nil {
+		return false, nil
+	}
+
+	i := slices.IndexFunc(c.Settings.Indexes, func(i IndexInfo) bool { return params.Name == i.Name })
+	if i < 0 {
+		return false, nil
+	}
+
+	index := &c.Settings.Indexes[i]
+
+	if params.ExpireAfterSeconds != nil {
+		index.ExpireAfterSeconds = pointer.To(*params.ExpireAfterSeconds)
+	}
+
+	if params.Hidden != nil {
+		index.Hidden = *params.Hidden
+	}
+
+	if params.PrepareUnique != nil {
+		index.PrepareUnique = *params.PrepareUnique
+	}
+
+	convert := params.Unique && !index.Unique
+	if convert {
+		if index.Text != nil || index.Geo != nil {
+			return false, lazyerrors.Errorf("index %q can't be unique", index.Name)
+		}
+
+		index.Unique = true
+	}
+
+	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
+		if convert {
+			q := fmt.Sprintf("DROP INDEX %q", IndexName(c.TableName, index.Name))
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			if _, err := tx.ExecContext(ctx, indexCreateQuery(c.TableName, index)); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf("UPDATE %q SET settings = ? WHERE table_name = ?", metadataTableName)
+		if _, err := tx.ExecContext(ctx, q, c.Settings, c.TableName); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.CollectionName] = c
+
+	return true, nil
+}
+
 // IndexesDrop removes given connection's indexes.
 //
 // Non-existing indexes are ignored.
@@ -575,9 +891,24 @@ func (r *Registry) indexesDrop(ctx context.Context, dbName, collectionName strin
 			continue
 		}
 
-		q := fmt.Sprintf("DROP INDEX %q", c.TableName+"_"+name)
-		if _, err := db.ExecContext(ctx, q); err != nil {
-			return lazyerrors.Error(err)
+		switch {
+		case c.Settings.Indexes[i].Text != nil:
+			if err := textIndexDrop(ctx, db, c.TableName, name); err != nil {
+				return lazyerrors.Error(err)
+			}
+		case c.Settings.Indexes[i].Geo != nil:
+			if err := geoIndexDrop(ctx, db, c.TableName, name); err != nil {
+				return lazyerrors.Error(err)
+			}
+		case c.Settings.Indexes[i].IsWildcard():
+			if err := wildcardIndexDrop(ctx, db, c.TableName, &c.Settings.Indexes[i]); err != nil {
+				return lazyerrors.Error(err)
+			}
+		default:
+			q := fmt.Sprintf("DROP INDEX %q", IndexName(c.TableName, name))
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
 		}
 
 		c.Settings.Indexes = slices.Delete(c.Settings.Indexes, i, i+1)
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"slices"
 
+	"github.com/AlekSi/pointer"
+
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
@@ -29,19 +31,118 @@ type Settings struct {
 	Indexes         []IndexInfo `json:"indexes"`
 	CappedSize      int64       `json:"cappedSize"`
 	CappedDocuments int64       `json:"cappedDocuments"`
+	Validation      *Validation `json:"validation,omitempty"`
+
+	// SJSON-encoded document with `viewOn` and `pipeline` fields, set for views only
+	View json.RawMessage `json:"view,omitempty"`
+}
+
+// Validation represents document validation settings of the collection.
+type Validation struct {
+	Validator json.RawMessage `json:"validator,omitempty"` // SJSON-encoded document
+	Level     string          `json:"level"`
+	Action    string          `json:"action"`
+}
+
+// deepCopy returns a deep copy.
+func (v *Validation) deepCopy() *Validation {
+	if v == nil {
+		return nil
+	}
+
+	return &Validation{
+		Validator: slices.Clone(v.Validator),
+		Level:     v.Level,
+		Action:    v.Action,
+	}
 }
 
 // IndexInfo represents information about a single index.
 type IndexInfo struct {
-	Name   string         `json:"name"`
-	Key    []IndexKeyPair `json:"key"`
-	Unique bool           `json:"unique"`
+	Name               string            `json:"name"`
+	Key                []IndexKeyPair    `json:"key"`
+	Unique             bool              `json:"unique"`
+	Text               *TextIndexOptions `json:"text,omitempty"`
+	Geo                *GeoIndexOptions  `json:"geo,omitempty"`
+	ExpireAfterSeconds *int32            `json:"expireAfterSeconds,omitempty"`
+	Hidden             bool              `json:"hidden,omitempty"`
+	PrepareUnique      bool              `json:"prepareUnique,omitempty"`
+	Sparse             bool              `json:"sparse,omitempty"`
+
+	// SJSON-encoded document, set for partial indexes only
+	PartialFilterExpression json.RawMessage `json:"partialFilterExpression,omitempty"`
+
+	// SJSON-encoded document, set for wildcard indexes with projection only
+	WildcardProjection json.RawMessage `json:"wildcardProjection,omitempty"`
+
+	// top-level fields of the wildcard index that have expression indexes
+	WildcardFields []string `json:"wildcardFields,omitempty"`
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
+//
+// For special index keys, Type is set and Descending is not used.
 type IndexKeyPair struct {
 	Field      string `json:"field"`
 	Descending bool   `json:"descending"`
+	Type       string `json:"type,omitempty"`
+}
+
+// TextIndexOptions represents options of the text index.
+//
+// Text index is stored in a separate FTS5 virtual table that is kept in sync with the collection table by triggers.
+type TextIndexOptions struct {
+	Weights          []TextIndexWeight `json:"weights"`
+	DefaultLanguage  string            `json:"defaultLanguage"`
+	LanguageOverride string            `json:"languageOverride"`
+}
+
+// TextIndexWeight represents a weight of the field in the text index.
+type TextIndexWeight struct {
+	Field  string `json:"field"`
+	Weight int32  `json:"weight"`
+}
+
+// GeoIndexOptions represents options of the geospatial index.
+//
+// Geospatial index is stored in a separate R-tree virtual table that is kept in sync with the collection table by triggers.
+type GeoIndexOptions struct {
+	Version int32    `json:"version,omitempty"`
+	Bits    int32    `json:"bits,omitempty"`
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+}
+
+// deepCopy returns a deep copy.
+func (g *GeoIndexOptions) deepCopy() *GeoIndexOptions {
+	if g == nil {
+		return nil
+	}
+
+	res := *g
+
+	if g.Min != nil {
+		res.Min = pointer.To(*g.Min)
+	}
+
+	if g.Max != nil {
+		res.Max = pointer.To(*g.Max)
+	}
+
+	return &res
+}
+
+// deepCopy returns a deep copy.
+func (t *TextIndexOptions) deepCopy() *TextIndexOptions {
+	if t == nil {
+		return nil
+	}
+
+	return &TextIndexOptions{
+		Weights:          slices.Clone(t.Weights),
+		DefaultLanguage:  t.DefaultLanguage,
+		LanguageOverride: t.LanguageOverride,
+	}
 }
 
 // deepCopy returns a deep copy.
@@ -50,9 +151,22 @@ func (s Settings) deepCopy() Settings {
 
 	for i, index := range s.Indexes {
 		indexes[i] = IndexInfo{
-			Name:   index.Name,
-			Key:    slices.Clone(index.Key),
-			Unique: index.Unique,
+			Name:          index.Name,
+			Key:           slices.Clone(index.Key),
+			Unique:        index.Unique,
+			Text:          index.Text.deepCopy(),
+			Geo:           index.Geo.deepCopy(),
+			Hidden:        index.Hidden,
+			PrepareUnique: index.PrepareUnique,
+			Sparse:        index.Sparse,
+
+			PartialFilterExpression: slices.Clone(index.PartialFilterExpression),
+			WildcardProjection:      slices.Clone(index.WildcardProjection),
+			WildcardFields:          slices.Clone(index.WildcardFields),
+		}
+
+		if index.ExpireAfterSeconds != nil {
+			indexes[i].ExpireAfterSeconds = pointer.To(*index.ExpireAfterSeconds)
 		}
 	}
 
@@ -61,6 +175,8 @@ func (s Settings) deepCopy() Settings {
 		Indexes:         indexes,
 		CappedSize:      s.CappedSize,
 		CappedDocuments: s.CappedDocuments,
+		Validation:      s.Validation.deepCopy(),
+		View:            slices.Clone(s.View),
 	}
 }
 
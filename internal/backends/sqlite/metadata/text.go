@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// TextTableName returns the name of FTS5 virtual table for the given text index of the collection table.
+func TextTableName(tableName, indexName string) string {
+	return tableName + "_" + indexName
+}
+
+// IndexStatNames returns names of tables and indexes that store the given index of the collection table,
+// as they are reported by dbstat virtual table.
+//
+// FTS5 virtual tables of text indexes and R-tree virtual tables of geospatial indexes
+// store data in several shadow tables.
+func IndexStatNames(tableName string, index *IndexInfo) []string {
+	name := tableName + "_" + index.Name
+
+	switch {
+	case index.Text != nil:
+		return []string{name + "_data", name + "_idx", name + "_content", name + "_docsize", name + "_config"}
+	case index.Geo != nil:
+		return []string{name + "_node", name + "_rowid", name + "_parent"}
+	default:
+		return []string{name}
+	}
+}
+
+// textTriggerNames returns names of triggers that keep FTS5 virtual table in sync with the collection table.
+func textTriggerNames(textTableName string) []string {
+	return []string{textTableName + "_insert", textTableName + "_delete", textTableName + "_update"}
+}
+
+// textTokenizer returns FTS5 tokenizer for the text index language.
+//
+// Only English words are stemmed with the Porter stemmer, the same way as the handler does that.
+func textTokenizer(language string) string {
+	switch language {
+	case "english", "en":
+		return "porter unicode61 remove_diacritics 0"
+	default:
+		return "unicode61 remove_diacritics 0"
+	}
+}
+
+// textValue returns SQL expression that concatenates all strings of indexed fields of the given row.
+//
+// All strings of top-level fields are used, even if the index contains only nested fields.
+// That is safe, because the handler matches documents returned by the query.
+func textValue(row string, opts *TextIndexOptions) string {
+	var roots []string
+
+	for _, w := range opts.Weights {
+		root := "$"
+
+		if w.Field != "$**" {
+			root = `$."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.Split(w.Field, ".")[0]) + `"`
+		}
+
+		if !slices.Contains(roots, root) {
+			roots = append(roots, root)
+		}
+	}
+
+	values := make([]string, len(roots))
+	for i, root := range roots {
+		values[i] = fmt.Sprintf(
+			`coalesce((SELECT group_concat(value, ' ') FROM json_tree(%s.%s, '%s') WHERE type = 'text'), '')`,
+			row, DefaultColumn, strings.ReplaceAll(root, "'", "''"),
+		)
+	}
+
+	return strings.Join(values, ` || ' ' || `)
+}
+
+// textIndexCreate creates FTS5 virtual table for the text index of the collection table,
+// triggers that keep it in sync, and fills it with existing documents.
+//
+// Virtual table rows have the same rowids as collection table rows.
+func textIndexCreate(ctx context.Context, db *fsql.DB, tableName string, index *IndexInfo) error {
+	textTableName := TextTableName(tableName, index.Name)
+
+	q := fmt.Sprintf(
+		"CREATE VIRTUAL TABLE %q USING fts5(text, tokenize = '%s')",
+		textTableName, textTokenizer(index.Text.DefaultLanguage),
+	)
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	names := textTriggerNames(textTableName)
+	newValue := textValue("new", index.Text)
+
+	qs := []string{
+		fmt.Sprintf(
+			"CREATE TRIGGER %q AFTER INSERT ON %q BEGIN INSERT INTO %q (rowid, text) VALUES (new.rowid, %s); END",
+			names[0], tableName, textTableName, newValue,
+		),
+		fmt.Sprintf(
+			"CREATE TRIGGER %q AFTER DELETE ON %q BEGIN DELETE FROM %q WHERE rowid = old.rowid; END",
+			names[1], tableName, textTableName,
+		),
+		fmt.Sprintf(
+			"CREATE TRIGGER %q AFTER UPDATE ON %q BEGIN UPDATE %q SET text = %s WHERE rowid = new.rowid; END",
+			names[2], tableName, textTableName, newValue,
+		),
+		fmt.Sprintf(
+			"INSERT INTO %q (rowid, text) SELECT rowid, %s FROM %q AS new",
+			textTableName, newValue, tableName,
+		),
+	}
+
+	for _, q = range qs {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			_ = textIndexDrop(ctx, db, tableName, index.Name)
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// textIndexDrop drops FTS5 virtual table of the text index and its triggers.
+func textIndexDrop(ctx context.Context, db *fsql.DB, tableName, indexName string) error {
+	textTableName := TextTableName(tableName, indexName)
+
+	for _, name := range textTriggerNames(textTableName) {
+		q := fmt.Sprintf("DROP TRIGGER IF EXISTS %q", name)
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	q := fmt.Sprintf("DROP TABLE IF EXISTS %q", textTableName)
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// IsWildcard returns true for wildcard indexes.
+func (index *IndexInfo) IsWildcard() bool {
+	return len(index.Key) == 1 && index.Key[0].Type == "" && backends.IsWildcardIndexKey(index.Key[0].Field)
+}
+
+// WildcardExpression returns SQL expression of the top-level field value that is indexed by wildcard indexes.
+//
+// Scalar values are returned as SQL values, arrays and objects as JSON text.
+func WildcardExpression(field string) string {
+	return fmt.Sprintf("%s->>%s", DefaultColumn, valuePath([]string{field}))
+}
+
+// wildcardIndexName returns the name of the expression index for the i-th field of the wildcard index.
+func wildcardIndexName(tableName, indexName string, i int) string {
+	return fmt.Sprintf("%s_%s_$%d", tableName, indexName, i)
+}
+
+// wildcardIndexCreate creates expression indexes for fields of the wildcard index listed in WildcardFields.
+//
+// Wildcard indexes without such fields are stored in metadata only and are not used by queries.
+func wildcardIndexCreate(ctx context.Context, db *fsql.DB, tableName string, index *IndexInfo) error {
+	for i, field := range index.WildcardFields {
+		q := fmt.Sprintf(
+			"CREATE INDEX %q ON %q (%s)",
+			wildcardIndexName(tableName, index.Name, i), tableName, WildcardExpression(field),
+		)
+
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			_ = wildcardIndexDrop(ctx, db, tableName, index)
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// wildcardIndexDrop drops expression indexes of the wildcard index.
+func wildcardIndexDrop(ctx context.Context, db *fsql.DB, tableName string, index *IndexInfo) error {
+	for i := range index.WildcardFields {
+		q := fmt.Sprintf("DROP INDEX IF EXISTS %q", wildcardIndexName(tableName, index.Name, i))
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
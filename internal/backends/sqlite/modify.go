@@ -0,0 +1,104 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sqlite3 "modernc.org/sqlite"
+	sqlite3lib "modernc.org/sqlite/lib"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// applyChanges applies changes returned by ModifyAllParams.Modify function in the given transaction,
+// and adds the numbers of changed documents to the result.
+func applyChanges(ctx context.Context, tx *fsql.Tx, meta *metadata.Collection, changes *backends.ModifyChanges, res *backends.ModifyAllResult) error { //nolint:lll // for readability
+	if len(changes.Delete) > 0 {
+		placeholders := make([]string, len(changes.Delete))
+		args := make([]any, len(changes.Delete))
+
+		for i, id := range changes.Delete {
+			placeholders[i] = "?"
+			args[i] = string(must.NotFail(sjson.MarshalSingleValue(id)))
+		}
+
+		q := fmt.Sprintf(`DELETE FROM %q WHERE %s IN (%s)`, meta.TableName, metadata.IDColumn, strings.Join(placeholders, ", "))
+
+		r, err := tx.ExecContext(ctx, q, args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		ra, err := r.RowsAffected()
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		res.Deleted += int32(ra)
+	}
+
+	q := fmt.Sprintf(`UPDATE %q SET %s = ? WHERE %s = ?`, meta.TableName, metadata.DefaultColumn, metadata.IDColumn)
+
+	for _, doc := range changes.Update {
+		b, err := sjson.Marshal(doc)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		id, _ := doc.Get("_id")
+		must.NotBeZero(id)
+
+		r, err := tx.ExecContext(ctx, q, string(b), string(must.NotFail(sjson.MarshalSingleValue(id))))
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		ra, err := r.RowsAffected()
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		res.Updated += int32(ra)
+	}
+
+	if len(changes.Insert) > 0 {
+		q, args, err := prepareInsertStatement(meta.TableName, meta.Capped(), changes.Insert)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if _, err = tx.ExecContext(ctx, q, args...); err != nil {
+			var se *sqlite3.Error
+			if errors.As(err, &se) && se.Code() == sqlite3lib.SQLITE_CONSTRAINT_UNIQUE {
+				return backends.NewError(backends.ErrorCodeInsertDuplicateID, err)
+			}
+
+			return lazyerrors.Error(err)
+		}
+
+		res.Inserted += int32(len(changes.Insert))
+	}
+
+	return nil
+}
@@ -15,19 +15,116 @@
 package sqlite
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
+// selectQuery represents the query that selects documents.
+type selectQuery struct {
+	q            string
+	args         []any
+	index        string // the first used index, if any
+	skipPushdown bool
+}
+
+// prepareQuery returns the query that selects documents for the given parameters, except grouping.
+func prepareQuery(meta *metadata.Collection, params *backends.QueryParams) (*selectQuery, error) {
+	var conditions []string
+	var args []any
+	var indexes []string
+
+	filters, filterArgs, err := prepareFilters(params.Filter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	conditions = append(conditions, filters...)
+	args = append(args, filterArgs...)
+
+	plan := planQuery(meta, params.Filter, params.Sort, params.Hint, slices.Contains(filters, idEqualCondition))
+
+	conditions = append(conditions, plan.conditions...)
+	args = append(args, plan.args...)
+
+	if plan.index != "" {
+		indexes = append(indexes, plan.index)
+	}
+
+	if cond, condArgs, index := prepareTextSearchCondition(meta, params.TextSearch); cond != "" {
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+		indexes = append(indexes, index)
+	}
+
+	if cond, condArgs, index := prepareGeoCondition(meta, params.Geo); cond != "" {
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+		indexes = append(indexes, index)
+	}
+
+	if cond, condArgs, index := prepareWildcardCondition(meta, params.Filter); cond != "" {
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+		indexes = append(indexes, index)
+	}
+
+	var whereClause string
+	if len(conditions) > 0 {
+		whereClause = ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	q := prepareSelectClause(meta.TableName, params.Comment, meta.Capped(), params.OnlyRecordIDs, params.Projection)
+	q += plan.indexedBy + whereClause
+
+	orderByClause := prepareOrderByClause(params.Sort, meta.Capped())
+	if plan.orderBy != "" {
+		orderByClause = plan.orderBy
+	}
+
+	q += orderByClause
+
+	var skipPushdown bool
+
+	if limitClause, limitArgs := prepareLimitClause(params.Skip, params.Limit); limitClause != "" {
+		if skipPushdown, err = isExactFilter(params.Filter); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if skipPushdown {
+			q += limitClause
+			args = append(args, limitArgs...)
+		}
+	}
+
+	res := &selectQuery{
+		q:            q,
+		args:         args,
+		skipPushdown: skipPushdown,
+	}
+
+	if len(indexes) > 0 {
+		res.index = indexes[0]
+	}
+
+	return res, nil
+}
+
 // prepareSelectClause returns SELECT clause for default column of provided table name.
 //
 // For capped collection with onlyRecordIDs, it returns select clause for recordID column.
 //
 // For capped collection, it returns select clause for recordID column and default column.
-func prepareSelectClause(table, comment string, capped, onlyRecordIDs bool) string {
+//
+// If projection is not empty, only given top-level fields of documents are selected (see prepareProjection).
+func prepareSelectClause(table, comment string, capped, onlyRecordIDs bool, projection []string) string {
 	if comment != "" {
 		comment = strings.ReplaceAll(comment, "/*", "/ *")
 		comment = strings.ReplaceAll(comment, "*/", "* /")
@@ -39,20 +136,238 @@ func prepareSelectClause(table, comment string, capped, onlyRecordIDs bool) stri
 	}
 
 	if capped {
-		return fmt.Sprintf(`SELECT %s %s, %s FROM %q`, comment, metadata.RecordIDColumn, metadata.DefaultColumn, table)
+		return fmt.Sprintf(`SELECT %s %s, %s FROM %q`, comment, metadata.RecordIDColumn, prepareProjection(projection), table)
+	}
+
+	return fmt.Sprintf(`SELECT %s %s FROM %q`, comment, prepareProjection(projection), table)
+}
+
+// sortTypeRanks contains the order of sjson types values used for sorting.
+// It is the same as BSON comparison order; all numbers have the same rank.
+// Missing fields and nulls have zero rank.
+var sortTypeRanks = [][]string{
+	{"double", "int", "long"},
+	{"string"},
+	{"object"},
+	{"array"},
+	{"binData"},
+	{"objectId"},
+	{"bool"},
+	{"date"},
+	{"timestamp"},
+	{"regex"},
+}
+
+// prepareProjection returns SQL expression of the document with the given top-level fields only.
+//
+// The document's sjson schema is reduced to those fields too.
+// The expression is named as the default column; the default column itself is returned if fields are empty
+// or if some field name can't be used in JSON path.
+func prepareProjection(fields []string) string {
+	if len(fields) == 0 {
+		return metadata.DefaultColumn
+	}
+
+	in := make([]string, len(fields))
+	for i, f := range fields {
+		if strings.ContainsRune(f, '"') {
+			return metadata.DefaultColumn
+		}
+
+		in[i] = quoteString(f)
+	}
+
+	list := "(" + strings.Join(in, ", ") + ")"
+
+	// json_each returns values as SQL values that lose the precision of doubles and the type of booleans,
+	// so values are extracted again as JSON text;
+	// subqueries results are passed through json() to be embedded as JSON
+	return fmt.Sprintf(
+		`json_set(`+
+			`(SELECT json_group_object(key, json(%[1]s -> ('$."' || key || '"'))) FROM json_each(%[1]s) WHERE key IN %[2]s), `+
+			`'$."$s"', json_object(`+
+			`'$k', json((SELECT json_group_array(value) FROM json_each(%[1]s, '$."$s"."$k"') WHERE value IN %[2]s)), `+
+			`'p', json((SELECT json_group_object(key, value) FROM json_each(%[1]s, '$."$s".p') WHERE key IN %[2]s))`+
+			`)) AS %[1]s`,
+		metadata.DefaultColumn, list,
+	)
+}
+
+// prepareLimitClause returns LIMIT and OFFSET clause with arguments for the given skip and limit.
+//
+// It returns an empty string if both are zero.
+func prepareLimitClause(skip, limit int64) (string, []any) {
+	if skip == 0 && limit == 0 {
+		return "", nil
 	}
 
-	return fmt.Sprintf(`SELECT %s %s FROM %q`, comment, metadata.DefaultColumn, table)
+	if limit == 0 {
+		// OFFSET can't be used without LIMIT
+		limit = -1
+	}
+
+	if skip == 0 {
+		return ` LIMIT ?`, []any{limit}
+	}
+
+	return ` LIMIT ? OFFSET ?`, []any{limit, skip}
 }
 
-// prepareOrderByClause returns ORDER BY clause for given sort document and returns.
+// prepareOrderByClause returns ORDER BY clause for given sort document.
 //
-// For capped collection, it returns ORDER BY recordID only if sort field is nil.
+// For capped collection, it returns ORDER BY recordID only if sort document is empty.
+//
+// For each sort key, documents are sorted by the type rank of the value first (see sortTypeRanks),
+// then by the value as returned by json_extract.
+// That gives the correct order for scalar values; the handler sorts documents again anyway.
+// It returns an empty string if some sort key can't be used in JSON path (see newFilterField).
 func prepareOrderByClause(sort *types.Document, capped bool) string {
-	if sort.Len() == 0 && capped {
-		return fmt.Sprintf(` ORDER BY %s`, metadata.RecordIDColumn)
+	if sort.Len() == 0 {
+		if capped {
+			return fmt.Sprintf(` ORDER BY %s`, metadata.RecordIDColumn)
+		}
+
+		return ""
+	}
+
+	var ranks string
+
+	for i, typs := range sortTypeRanks {
+		for _, t := range typs {
+			ranks += fmt.Sprintf(" WHEN '%s' THEN %d", t, i+1)
+		}
+	}
+
+	terms := make([]string, 0, sort.Len()*2)
+
+	for i, k := range sort.Keys() {
+		f, ok := newFilterField(k)
+		if !ok {
+			return ""
+		}
+
+		var order string
+		if sort.Values()[i].(int64) == -1 {
+			order = " DESC"
+		}
+
+		terms = append(terms, fmt.Sprintf("CASE %s%s ELSE 0 END%s", f.typ, ranks, order), f.value+order)
+	}
+
+	return ` ORDER BY ` + strings.Join(terms, ", ")
+}
+
+// prepareTextSearchCondition returns SQL condition with arguments that selects documents
+// containing any of the given words, using FTS5 virtual table of the collection's text index.
+//
+// It also returns the name of that index.
+// It returns an empty string if the collection has no visible text index or there are no words.
+func prepareTextSearchCondition(meta *metadata.Collection, params *backends.TextSearchParams) (string, []any, string) {
+	if params == nil || len(params.Words) == 0 {
+		return "", nil, ""
+	}
+
+	for _, index := range meta.Settings.Indexes {
+		if index.Text == nil || index.Hidden {
+			continue
+		}
+
+		words := make([]string, len(params.Words))
+		for i, w := range params.Words {
+			words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+		}
+
+		textTable := metadata.TextTableName(meta.TableName, index.Name)
+		cond := fmt.Sprintf(`rowid IN (SELECT rowid FROM %[1]q WHERE %[1]q MATCH ?)`, textTable)
+
+		return cond, []any{strings.Join(words, " OR ")}, index.Name
+	}
+
+	return "", nil, ""
+}
+
+// prepareGeoCondition returns SQL condition with arguments that selects documents
+// with locations intersecting the given box, using R-tree virtual table of the collection's geospatial index.
+//
+// It also returns the name of that index.
+// It returns an empty string if the collection has no visible geospatial index on the given field.
+func prepareGeoCondition(meta *metadata.Collection, params *backends.GeoParams) (string, []any, string) {
+	if params == nil {
+		return "", nil, ""
+	}
+
+	for _, index := range meta.Settings.Indexes {
+		if index.Geo == nil || index.Hidden || index.GeoField() != params.Field {
+			continue
+		}
+
+		geoTable := metadata.GeoTableName(meta.TableName, index.Name)
+		cond := fmt.Sprintf(`rowid IN (SELECT id FROM %q WHERE minx <= ? AND maxx >= ? AND miny <= ? AND maxy >= ?)`, geoTable)
+
+		return cond, []any{params.MaxX, params.MinX, params.MaxY, params.MinY}, index.Name
+	}
+
+	return "", nil, ""
+}
+
+// prepareWildcardCondition returns SQL condition with arguments that selects documents
+// where the value of top-level field (or one of its array elements) may be equal to the filter value,
+// using expression index of the collection's wildcard index.
+//
+// The first filter field covered by a visible wildcard index is used; the name of that index is also returned.
+// It returns an empty string if there is no such field.
+func prepareWildcardCondition(meta *metadata.Collection, filter *types.Document) (string, []any, string) {
+	if filter == nil {
+		return "", nil, ""
+	}
+
+	values := filter.Values()
+
+	for i, field := range filter.Keys() {
+		v := values[i]
+
+		if doc, ok := v.(*types.Document); ok {
+			if doc.Len() != 1 || doc.Keys()[0] != "$eq" {
+				continue
+			}
+
+			v = doc.Values()[0]
+		}
+
+		var arg any
+
+		switch v := v.(type) {
+		case string, int32, int64:
+			arg = v
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+
+			arg = v
+		case bool:
+			// SQLite returns JSON booleans as integers
+			arg = 0
+			if v {
+				arg = 1
+			}
+		case types.ObjectID:
+			arg = hex.EncodeToString(v[:])
+		default:
+			continue
+		}
+
+		for _, index := range meta.Settings.Indexes {
+			if index.Hidden || !index.IsWildcard() || !slices.Contains(index.WildcardFields, field) {
+				continue
+			}
+
+			// arrays are returned as JSON text, so include all of them
+			cond := fmt.Sprintf(`(%[1]s = ? OR (%[1]s >= '[' AND %[1]s < '\'))`, metadata.WildcardExpression(field))
+
+			return cond, []any{arg}, index.Name
+		}
 	}
 
-	// TODO https://github.com/FerretDB/FerretDB/issues/3181
-	return ""
+	return "", nil, ""
 }
@@ -69,7 +69,7 @@ func TestPrepareSelectClause(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			query := prepareSelectClause(table, comment, tc.capped, tc.onlyRecordIDs)
+			query := prepareSelectClause(table, comment, tc.capped, tc.onlyRecordIDs, nil)
 			assert.Equal(t, tc.expectQuery, query)
 		})
 	}
@@ -78,6 +78,10 @@ func TestPrepareSelectClause(t *testing.T) {
 func TestPrepareOrderByClause(t *testing.T) {
 	t.Parallel()
 
+	ranks := "WHEN 'double' THEN 1 WHEN 'int' THEN 1 WHEN 'long' THEN 1 WHEN 'string' THEN 2 WHEN 'object' THEN 3 " +
+		"WHEN 'array' THEN 4 WHEN 'binData' THEN 5 WHEN 'objectId' THEN 6 WHEN 'bool' THEN 7 WHEN 'date' THEN 8 " +
+		"WHEN 'timestamp' THEN 9 WHEN 'regex' THEN 10 ELSE 0 END"
+
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
 		sort   *types.Document
 		capped bool
@@ -85,11 +89,24 @@ func TestPrepareOrderByClause(t *testing.T) {
 		orderBy string
 	}{
 		"Ascending": {
-			sort:    must.NotFail(types.NewDocument("field", int64(1))),
-			orderBy: "",
+			sort: must.NotFail(types.NewDocument("field", int64(1))),
+			orderBy: ` ORDER BY CASE json_extract(_ferretdb_sjson, '$."$s".p."field".t') ` + ranks + `, ` +
+				`json_extract(_ferretdb_sjson, '$."field"')`,
 		},
 		"Descending": {
-			sort:    must.NotFail(types.NewDocument("field", int64(-1))),
+			sort: must.NotFail(types.NewDocument("field", int64(-1))),
+			orderBy: ` ORDER BY CASE json_extract(_ferretdb_sjson, '$."$s".p."field".t') ` + ranks + ` DESC, ` +
+				`json_extract(_ferretdb_sjson, '$."field"') DESC`,
+		},
+		"MultipleKeys": {
+			sort: must.NotFail(types.NewDocument("field.embedded", int64(1), "_id", int64(-1))),
+			orderBy: ` ORDER BY CASE json_extract(_ferretdb_sjson, '$."$s".p."field"."$s".p."embedded".t') ` + ranks + `, ` +
+				`json_extract(_ferretdb_sjson, '$."field"."embedded"'), ` +
+				`CASE json_extract(_ferretdb_sjson, '$."$s".p."_id".t') ` + ranks + ` DESC, ` +
+				`json_extract(_ferretdb_sjson, '$."_id"') DESC`,
+		},
+		"ArrayIndex": {
+			sort:    must.NotFail(types.NewDocument("field", int64(1), "field.0", int64(1))),
 			orderBy: "",
 		},
 		"SortNil": {
@@ -127,8 +127,10 @@ func collectionsStats(ctx context.Context, db *fsql.DB, list []*metadata.Collect
 
 	for _, c := range list {
 		for _, index := range c.Settings.Indexes {
-			placeholders = append(placeholders, "?")
-			args = append(args, c.TableName+"_"+index.Name)
+			for _, name := range metadata.IndexStatNames(c.TableName, &index) {
+				placeholders = append(placeholders, "?")
+				args = append(args, name)
+			}
 		}
 	}
 
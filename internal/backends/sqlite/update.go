@@ -0,0 +1,348 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// maxUpdateArgs is the maximal number of json_set and json_remove arguments;
+// SQLite limits the number of function arguments.
+const maxUpdateArgs = 100
+
+// updateQuery represents queries that apply update operators pushed down to the backend.
+type updateQuery struct {
+	// check returns the number of matching documents (at most one for single document updates),
+	// the number of them to which operators can't be applied exactly, and the largest rowid of them.
+	check string
+
+	// update changes matching documents that are changed by operators.
+	// For single document updates, it uses the rowid returned by check as :id argument.
+	update string
+
+	args []any
+}
+
+// updateParent represents the object containing fields that are added or removed by update operators.
+type updateParent struct {
+	path    []string
+	added   []updateKey
+	removed []string // arguments with removed keys
+}
+
+// updateKey represents the key that is added by update operator if it is not present.
+type updateKey struct {
+	arg string // argument with the key
+	typ string // SQL expression for the current sjson type of the field
+}
+
+// prepareUpdateQuery returns queries that apply update operators to documents matching the filter.
+//
+// Operators are applied only to documents where all parents of updated fields are objects,
+// and incremented fields are missing or contain integers that would not overflow;
+// other documents are updated by the handler the same way as before.
+//
+// It returns false if the filter or operators can't be pushed down completely.
+func prepareUpdateQuery(
+	meta *metadata.Collection, filter *types.Document, ops []backends.UpdateOperator, multi bool,
+) (*updateQuery, bool) {
+	// the handler does not check unique indexes for updated documents
+	for _, index := range meta.Settings.Indexes {
+		if index.Unique && index.Name != backends.DefaultIndexName {
+			return nil, false
+		}
+	}
+
+	var ga groupArgs
+
+	where, ok := prepareGroupWhereClause(&ga, filter)
+	if !ok {
+		return nil, false
+	}
+
+	var sets, removes, suitable, changed []string
+
+	var parents []*updateParent
+	checked := map[string]bool{}
+
+	for _, op := range ops {
+		path := op.Path.Slice()
+		for _, e := range path {
+			if strings.ContainsAny(e, `"\`) {
+				return nil, false
+			}
+		}
+
+		for i := 1; i < len(path); i++ {
+			if k := strings.Join(path[:i], "."); !checked[k] {
+				checked[k] = true
+				suitable = append(suitable, fmt.Sprintf(`%s = 'object'`, updateTypeExpr(&ga, path[:i])))
+			}
+		}
+
+		valuePath := ga.add(`$."` + strings.Join(path, `"."`) + `"`)
+		schemaPath := ga.add(updateSchemaPath(path))
+		value := fmt.Sprintf(`json_extract(%s, %s)`, metadata.DefaultColumn, valuePath)
+		typ := updateTypeExpr(&ga, path)
+
+		parent := updateParentFor(&parents, path[:len(path)-1])
+		key := ga.add(path[len(path)-1])
+
+		switch op.Operator {
+		case "$set":
+			b, err := sjson.MarshalSingleValue(op.Value)
+			if err != nil {
+				return nil, false
+			}
+
+			v := ga.add(string(b))
+			t := ga.add(sjson.GetTypeOfValue(op.Value))
+
+			sets = append(sets, valuePath, `json(`+v+`)`, schemaPath, `json_object('t', `+t+`)`)
+			changed = append(changed, fmt.Sprintf(`%s IS NOT %s OR %s -> %s IS NOT %s`, typ, t, metadata.DefaultColumn, valuePath, v))
+			parent.added = append(parent.added, updateKey{arg: key, typ: typ})
+
+		case "$inc":
+			var n int64
+			var resType, cond string
+
+			switch v := op.Value.(type) {
+			case int32:
+				n = int64(v)
+				resType = fmt.Sprintf(`CASE WHEN %s = 'long' THEN 'long' ELSE 'int' END`, typ)
+				cond = fmt.Sprintf(
+					`%s IS NULL OR (%s = 'int' AND %s) OR (%s = 'long' AND %s)`,
+					typ,
+					typ, updateIncRange(value, n, math.MinInt32, math.MaxInt32),
+					typ, updateIncRange(value, n, math.MinInt64, math.MaxInt64),
+				)
+			case int64:
+				n = v
+				resType = `'long'`
+				cond = fmt.Sprintf(
+					`%s IS NULL OR (%s IN ('int', 'long') AND %s)`,
+					typ, typ, updateIncRange(value, n, math.MinInt64, math.MaxInt64),
+				)
+			default:
+				return nil, false
+			}
+
+			sets = append(
+				sets,
+				valuePath, fmt.Sprintf(`coalesce(%s, 0) + %s`, value, ga.add(n)),
+				schemaPath, `json_object('t', `+resType+`)`,
+			)
+			suitable = append(suitable, `(`+cond+`)`)
+
+			if n == 0 {
+				changed = append(changed, typ+` IS NULL`)
+			} else {
+				changed = append(changed, `TRUE`)
+			}
+
+			parent.added = append(parent.added, updateKey{arg: key, typ: typ})
+
+		case "$unset":
+			removes = append(removes, valuePath, schemaPath)
+			changed = append(changed, typ+` IS NOT NULL`)
+			parent.removed = append(parent.removed, key)
+
+		default:
+			return nil, false
+		}
+	}
+
+	// keys are kept in order, and added keys are appended in order of operators
+	for _, p := range parents {
+		keysPath := ga.add(updateKeysPath(p.path))
+
+		keys := fmt.Sprintf(`SELECT 0 AS g, e.key AS o, e.value AS k FROM json_each(%s, %s) AS e`, metadata.DefaultColumn, keysPath)
+
+		if len(p.removed) > 0 {
+			keys += fmt.Sprintf(` WHERE e.value NOT IN (%s)`, strings.Join(p.removed, ", "))
+		}
+
+		for i, k := range p.added {
+			keys += fmt.Sprintf(` UNION ALL SELECT 1, %d, %s WHERE %s IS NULL`, i, k.arg, k.typ)
+		}
+
+		sets = append(sets, keysPath, fmt.Sprintf(`json((SELECT json_group_array(k) FROM (%s ORDER BY g, o)))`, keys))
+	}
+
+	if len(sets) > maxUpdateArgs || len(removes) > maxUpdateArgs {
+		return nil, false
+	}
+
+	doc := metadata.DefaultColumn
+
+	if len(sets) > 0 {
+		doc = fmt.Sprintf(`json_set(%s, %s)`, doc, strings.Join(sets, ", "))
+	}
+
+	if len(removes) > 0 {
+		doc = fmt.Sprintf(`json_remove(%s, %s)`, doc, strings.Join(removes, ", "))
+	}
+
+	suitableCond := `TRUE`
+	if len(suitable) > 0 {
+		suitableCond = strings.Join(suitable, " AND ")
+	}
+
+	changedCond := `((` + strings.Join(changed, `) OR (`) + `))`
+
+	var limit string
+	if !multi {
+		limit = ` LIMIT 1`
+	}
+
+	uq := &updateQuery{
+		check: fmt.Sprintf(
+			`SELECT count(*), count(*) FILTER (WHERE (%s) IS NOT TRUE), max(id) FROM (SELECT rowid AS id, %s FROM %q%s%s)`,
+			suitableCond, metadata.DefaultColumn, meta.TableName, where, limit,
+		),
+		args: ga.args,
+	}
+
+	uq.update = fmt.Sprintf(`UPDATE %q SET %s = %s`, meta.TableName, metadata.DefaultColumn, doc)
+
+	switch {
+	case !multi:
+		uq.update += ` WHERE rowid = :id AND ` + changedCond
+	case where == "":
+		uq.update += ` WHERE ` + changedCond
+	default:
+		uq.update += where + ` AND ` + changedCond
+	}
+
+	return uq, true
+}
+
+// updateParentFor returns the parent with the given path, adding it if needed.
+func updateParentFor(parents *[]*updateParent, path []string) *updateParent {
+	for _, p := range *parents {
+		if slices.Equal(p.path, path) {
+			return p
+		}
+	}
+
+	p := &updateParent{path: path}
+	*parents = append(*parents, p)
+
+	return p
+}
+
+// updateSchemaPath returns JSON path to the sjson schema of the field with the given path.
+func updateSchemaPath(path []string) string {
+	res := `$."$s"`
+
+	for i, e := range path {
+		if i > 0 {
+			res += `."$s"`
+		}
+
+		res += `.p."` + e + `"`
+	}
+
+	return res
+}
+
+// updateKeysPath returns JSON path to sjson keys of the object with the given path.
+func updateKeysPath(path []string) string {
+	if len(path) == 0 {
+		return `$."$s"."$k"`
+	}
+
+	return updateSchemaPath(path) + `."$s"."$k"`
+}
+
+// updateTypeExpr returns SQL expression for the sjson type of the field with the given path,
+// or NULL if the field is missing.
+func updateTypeExpr(ga *groupArgs, path []string) string {
+	return fmt.Sprintf(`json_extract(%s, %s)`, metadata.DefaultColumn, ga.add(updateSchemaPath(path)+`.t`))
+}
+
+// updateIncRange returns SQL condition that is true if incrementing the value by n
+// does not overflow [minValue, maxValue] range.
+func updateIncRange(value string, n, minValue, maxValue int64) string {
+	if n > 0 {
+		return fmt.Sprintf(`%s <= %d`, value, maxValue-n)
+	}
+
+	return fmt.Sprintf(`%s >= %d`, value, minValue-n)
+}
+
+// execUpdateQuery applies update operators to documents matching the filter in a single transaction.
+//
+// If operators can't be applied exactly to some of them, nothing is updated,
+// and the result's UpdatePushdown field is set to false.
+func execUpdateQuery(ctx context.Context, db *fsql.DB, uq *updateQuery, multi bool) (*backends.UpdateAllResult, error) {
+	var res backends.UpdateAllResult
+
+	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
+		var matched, unsuitable int64
+		var id sql.NullInt64
+
+		if err := tx.QueryRowContext(ctx, uq.check, uq.args...).Scan(&matched, &unsuitable, &id); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if unsuitable > 0 {
+			return nil
+		}
+
+		res.Matched = int32(matched)
+		res.UpdatePushdown = true
+
+		if matched == 0 {
+			return nil
+		}
+
+		args := uq.args
+		if !multi {
+			args = append(slices.Clip(args), sql.Named("id", id.Int64))
+		}
+
+		r, err := tx.ExecContext(ctx, uq.update, args...)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		ra, err := r.RowsAffected()
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		res.Updated = int32(ra)
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
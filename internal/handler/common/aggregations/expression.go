@@ -15,7 +15,6 @@
 package aggregations
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
@@ -46,6 +45,9 @@ const (
 
 	// ErrEmptyVariable indicates that variable name is empty.
 	ErrEmptyVariable
+
+	// ErrPathNotFound indicates that evaluated field path does not exist in the document.
+	ErrPathNotFound
 )
 
 // ExpressionError describes an error that occurs while evaluating expression.
@@ -142,15 +144,15 @@ func NewExpression(expression string, opts *commonpath.FindValuesOpts) (*Express
 // returns found value. If values were found from embedded array, it returns *types.Array
 // containing values.
 //
-// It returns error if field value was not found. With embedded array field being exception,
-// that case it returns empty array instead of error.
+// It returns ExpressionError with ErrPathNotFound code if field value was not found.
+// With embedded array field being exception, that case it returns empty array instead of error.
 func (e *Expression) Evaluate(doc *types.Document) (any, error) {
 	path := e.path
 
 	if path.Len() == 1 {
 		val, err := doc.Get(path.String())
 		if err != nil {
-			return nil, err
+			return nil, newExpressionError(ErrPathNotFound, path.String())
 		}
 
 		return val, nil
@@ -176,7 +178,7 @@ func (e *Expression) Evaluate(doc *types.Document) (any, error) {
 			return must.NotFail(types.NewArray()), nil
 		}
 
-		return nil, fmt.Errorf("no document found under %s path", path)
+		return nil, newExpressionError(ErrPathNotFound, path.String())
 	}
 
 	if len(vals) == 1 && !isArrayField {
@@ -13,11 +13,12 @@ func _() {
 	_ = x[ErrEmptyFieldPath-3]
 	_ = x[ErrUndefinedVariable-4]
 	_ = x[ErrEmptyVariable-5]
+	_ = x[ErrPathNotFound-6]
 }
 
-const _ExpressionErrorCode_name = "ErrNotExpressionErrInvalidExpressionErrEmptyFieldPathErrUndefinedVariableErrEmptyVariable"
+const _ExpressionErrorCode_name = "ErrNotExpressionErrInvalidExpressionErrEmptyFieldPathErrUndefinedVariableErrEmptyVariableErrPathNotFound"
 
-var _ExpressionErrorCode_index = [...]uint8{0, 16, 36, 53, 73, 89}
+var _ExpressionErrorCode_index = [...]uint8{0, 16, 36, 53, 73, 89, 104}
 
 func (i ExpressionErrorCode) String() string {
 	i -= 1
@@ -17,6 +17,10 @@ package aggregations
 import (
 	"math"
 	"math/big"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // SumNumbers accumulate numbers and returns the result of summation.
@@ -27,45 +31,137 @@ import (
 // This should only be used for aggregation, aggregation does not return
 // error on overflow.
 func SumNumbers(vs ...any) any {
+	var s Sum
+
+	for _, v := range vs {
+		s.Add(v)
+	}
+
+	return s.Result()
+}
+
+// Sum accumulates numbers one by one the same way as SumNumbers does.
+//
+// The zero value is an empty sum ready to use.
+type Sum struct {
 	// use big.Int to accumulate values larger than math.MaxInt64.
-	intSum := big.NewInt(0)
+	intSum *big.Int
 
 	// handle accumulation of doubles close to max precision.
 	// TODO https://github.com/FerretDB/FerretDB/issues/2300
-	var floatSum float64
+	floatSum float64
 
-	var hasFloat64, hasInt64 bool
+	hasFloat64 bool
+	hasInt64   bool
+}
 
-	for _, v := range vs {
-		switch v := v.(type) {
-		case float64:
-			hasFloat64 = true
-
-			floatSum = floatSum + v
-		case int32:
-			intSum.Add(intSum, big.NewInt(int64(v)))
-		case int64:
-			hasInt64 = true
-
-			intSum.Add(intSum, big.NewInt(v))
-		default:
-			// ignore non-number
-		}
+// Add adds a number to the sum. It ignores non-number values.
+func (s *Sum) Add(v any) {
+	if s.intSum == nil {
+		s.intSum = new(big.Int)
+	}
+
+	switch v := v.(type) {
+	case float64:
+		s.hasFloat64 = true
+
+		s.floatSum = s.floatSum + v
+	case int32:
+		s.intSum.Add(s.intSum, big.NewInt(int64(v)))
+	case int64:
+		s.hasInt64 = true
+
+		s.intSum.Add(s.intSum, big.NewInt(v))
+	default:
+		// ignore non-number
+	}
+}
+
+// Merge adds another sum to the sum.
+func (s *Sum) Merge(other *Sum) {
+	if s.intSum == nil {
+		s.intSum = new(big.Int)
+	}
+
+	if other.intSum != nil {
+		s.intSum.Add(s.intSum, other.intSum)
+	}
+
+	s.floatSum += other.floatSum
+	s.hasFloat64 = s.hasFloat64 || other.hasFloat64
+	s.hasInt64 = s.hasInt64 || other.hasInt64
+}
+
+// Result returns the result of summation.
+func (s *Sum) Result() any {
+	intSum := s.intSum
+	if intSum == nil {
+		intSum = new(big.Int)
 	}
 
-	if hasFloat64 || !intSum.IsInt64() {
+	if s.hasFloat64 || !intSum.IsInt64() {
 		// ignore accuracy because there is no rounding from int64.
 		intAsFloat, _ := new(big.Float).SetInt(intSum).Float64()
 
-		return intAsFloat + floatSum
+		return intAsFloat + s.floatSum
 	}
 
 	integer := intSum.Int64()
 
-	if !hasInt64 && integer <= math.MaxInt32 && integer >= math.MinInt32 {
+	if !s.hasInt64 && integer <= math.MaxInt32 && integer >= math.MinInt32 {
 		// convert to int32 if input has no int64 and can be represented in int32.
 		return int32(integer)
 	}
 
 	return integer
 }
+
+// Document returns a document that represents the sum.
+//
+// It could be converted back with UnmarshalSum.
+func (s *Sum) Document() *types.Document {
+	intSum := "0"
+	if s.intSum != nil {
+		intSum = s.intSum.String()
+	}
+
+	return must.NotFail(types.NewDocument(
+		"int", intSum,
+		"float", s.floatSum,
+		"hasFloat64", s.hasFloat64,
+		"hasInt64", s.hasInt64,
+	))
+}
+
+// UnmarshalSum returns the sum represented by the document returned by Sum.Document.
+func UnmarshalSum(doc *types.Document) (*Sum, error) {
+	intSum, _ := doc.Get("int")
+	floatSum, _ := doc.Get("float")
+	hasFloat64, _ := doc.Get("hasFloat64")
+	hasInt64, _ := doc.Get("hasInt64")
+
+	s := &Sum{intSum: new(big.Int)}
+
+	str, ok := intSum.(string)
+	if ok {
+		_, ok = s.intSum.SetString(str, 10)
+	}
+
+	if !ok {
+		return nil, lazyerrors.Errorf("invalid sum %v", intSum)
+	}
+
+	if s.floatSum, ok = floatSum.(float64); !ok {
+		return nil, lazyerrors.Errorf("invalid sum %v", floatSum)
+	}
+
+	if s.hasFloat64, ok = hasFloat64.(bool); !ok {
+		return nil, lazyerrors.Errorf("invalid sum %v", hasFloat64)
+	}
+
+	if s.hasInt64, ok = hasInt64.(bool); !ok {
+		return nil, lazyerrors.Errorf("invalid sum %v", hasInt64)
+	}
+
+	return s, nil
+}
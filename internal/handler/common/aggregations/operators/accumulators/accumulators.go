@@ -36,10 +36,37 @@ import (
 type newAccumulatorFunc func(args ...any) (Accumulator, error)
 
 // Accumulator is a common interface for aggregation accumulation operators.
+//
+// Accumulator itself does not change during accumulation;
+// the state of each group is kept separately, so only that state should be stored for each group.
 type Accumulator interface {
-	// Accumulate documents and returns the result of applying operator.
-	// It should always close iterator.
-	Accumulate(iter types.DocumentsIterator) (any, error)
+	// NewState returns a new empty state of the accumulator for a single group.
+	NewState() State
+}
+
+// State is a common interface for states of aggregation accumulation operators.
+//
+// State could be converted to a BSON value and back, so it could be stored outside of memory.
+type State interface {
+	// Add updates the state with a new document of the group.
+	Add(doc *types.Document) error
+
+	// Merge updates the state with another state of the same accumulator
+	// that was created for documents of the same group following already added ones.
+	Merge(other State) error
+
+	// Result returns the result of applying operator to all added documents.
+	Result() (any, error)
+
+	// Marshal returns a BSON value that represents the state.
+	Marshal() any
+
+	// Unmarshal replaces the state with the one represented by the value returned by Marshal.
+	Unmarshal(v any) error
+
+	// Size returns the approximate size of the state in memory, in bytes.
+	// It should be cheap to call.
+	Size() int64
 }
 
 // NewAccumulator returns accumulator for provided value.
@@ -103,7 +130,27 @@ func NewAccumulator(stage, key string, value any) (Accumulator, error) {
 // Accumulators maps all aggregation accumulators.
 var Accumulators = map[string]newAccumulatorFunc{
 	// sorted alphabetically
-	"$count": newCount,
-	"$sum":   newSum,
+	"$addToSet":     newAddToSet,
+	"$avg":          newAvg,
+	"$bottom":       newBottom,
+	"$bottomN":      newBottomN,
+	"$count":        newCount,
+	"$first":        newFirst,
+	"$firstN":       newFirstN,
+	"$last":         newLast,
+	"$lastN":        newLastN,
+	"$max":          newMax,
+	"$maxN":         newMaxN,
+	"$median":       newMedian,
+	"$mergeObjects": newMergeObjects,
+	"$min":          newMin,
+	"$minN":         newMinN,
+	"$percentile":   newPercentile,
+	"$push":         newPush,
+	"$stdDevPop":    newStdDevPop,
+	"$stdDevSamp":   newStdDevSamp,
+	"$sum":          newSum,
+	"$top":          newTop,
+	"$topN":         newTopN,
 	// please keep sorted alphabetically
 }
@@ -0,0 +1,314 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// testDocs returns documents of a single group used by accumulator tests.
+func testDocs() []*types.Document {
+	return []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", int32(3), "s", "b", "o", must.NotFail(types.NewDocument("a", int32(1))))),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", int64(1), "s", "a", "o", must.NotFail(types.NewDocument("b", int32(2))))),
+		must.NotFail(types.NewDocument("_id", int32(3), "v", 2.5, "s", "c")),
+		must.NotFail(types.NewDocument("_id", int32(4), "s", "a")),
+		must.NotFail(types.NewDocument("_id", int32(5), "v", "x")),
+	}
+}
+
+// accumulate applies accumulator to documents.
+//
+// Documents are split into two states at the given position;
+// the first state is marshaled and unmarshaled before the second one is merged into it.
+func accumulate(t *testing.T, accumulator Accumulator, docs []*types.Document, split int) (any, error) {
+	t.Helper()
+
+	first, second := accumulator.NewState(), accumulator.NewState()
+
+	for i, doc := range docs {
+		st := first
+		if i >= split {
+			st = second
+		}
+
+		if err := st.Add(doc); err != nil {
+			return nil, err
+		}
+
+		assert.Positive(t, st.Size())
+	}
+
+	restored := accumulator.NewState()
+	require.NoError(t, restored.Unmarshal(first.Marshal()))
+	require.NoError(t, restored.Merge(second))
+
+	return restored.Result()
+}
+
+func TestAccumulators(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		accumulator *types.Document
+		expected    any
+		unordered   bool // compare array elements ignoring order
+	}{
+		"Sum": {
+			accumulator: must.NotFail(types.NewDocument("$sum", "$v")),
+			expected:    6.5,
+		},
+		"SumConstant": {
+			accumulator: must.NotFail(types.NewDocument("$sum", int32(1))),
+			expected:    int32(5),
+		},
+		"SumMissing": {
+			accumulator: must.NotFail(types.NewDocument("$sum", "$missing")),
+			expected:    int32(0),
+		},
+		"Avg": {
+			accumulator: must.NotFail(types.NewDocument("$avg", "$v")),
+			expected:    6.5 / 3,
+		},
+		"AvgMissing": {
+			accumulator: must.NotFail(types.NewDocument("$avg", "$missing")),
+			expected:    types.Null,
+		},
+		"Count": {
+			accumulator: must.NotFail(types.NewDocument("$count", new(types.Document))),
+			expected:    int32(5),
+		},
+		"First": {
+			accumulator: must.NotFail(types.NewDocument("$first", "$v")),
+			expected:    int32(3),
+		},
+		"Last": {
+			accumulator: must.NotFail(types.NewDocument("$last", "$v")),
+			expected:    "x",
+		},
+		"Min": {
+			accumulator: must.NotFail(types.NewDocument("$min", "$v")),
+			expected:    int64(1),
+		},
+		"Max": {
+			accumulator: must.NotFail(types.NewDocument("$max", "$v")),
+			expected:    "x",
+		},
+		"Push": {
+			accumulator: must.NotFail(types.NewDocument("$push", "$s")),
+			expected:    must.NotFail(types.NewArray("b", "a", "c", "a")),
+		},
+		"PushObject": {
+			accumulator: must.NotFail(types.NewDocument("$push", must.NotFail(types.NewDocument("id", "$_id", "v", "$v")))),
+			expected: must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("id", int32(1), "v", int32(3))),
+				must.NotFail(types.NewDocument("id", int32(2), "v", int64(1))),
+				must.NotFail(types.NewDocument("id", int32(3), "v", 2.5)),
+				must.NotFail(types.NewDocument("id", int32(4))),
+				must.NotFail(types.NewDocument("id", int32(5), "v", "x")),
+			)),
+		},
+		"AddToSet": {
+			accumulator: must.NotFail(types.NewDocument("$addToSet", "$s")),
+			expected:    must.NotFail(types.NewArray("a", "b", "c")),
+			unordered:   true,
+		},
+		"MergeObjects": {
+			accumulator: must.NotFail(types.NewDocument("$mergeObjects", "$o")),
+			expected:    must.NotFail(types.NewDocument("a", int32(1), "b", int32(2))),
+		},
+		"FirstN": {
+			accumulator: must.NotFail(types.NewDocument("$firstN", must.NotFail(types.NewDocument("input", "$v", "n", int32(2))))),
+			expected:    must.NotFail(types.NewArray(int32(3), int64(1))),
+		},
+		"LastN": {
+			accumulator: must.NotFail(types.NewDocument("$lastN", must.NotFail(types.NewDocument("input", "$_id", "n", int32(2))))),
+			expected:    must.NotFail(types.NewArray(int32(4), int32(5))),
+		},
+		"MaxN": {
+			accumulator: must.NotFail(types.NewDocument("$maxN", must.NotFail(types.NewDocument("input", "$v", "n", int32(2))))),
+			expected:    must.NotFail(types.NewArray("x", int32(3))),
+		},
+		"MinN": {
+			accumulator: must.NotFail(types.NewDocument("$minN", must.NotFail(types.NewDocument("input", "$v", "n", int32(2))))),
+			expected:    must.NotFail(types.NewArray(int64(1), 2.5)),
+		},
+		"Top": {
+			accumulator: must.NotFail(types.NewDocument("$top", must.NotFail(types.NewDocument(
+				"sortBy", must.NotFail(types.NewDocument("v", int32(1))),
+				"output", "$_id",
+			)))),
+			expected: int32(4),
+		},
+		"Bottom": {
+			accumulator: must.NotFail(types.NewDocument("$bottom", must.NotFail(types.NewDocument(
+				"sortBy", must.NotFail(types.NewDocument("v", int32(1))),
+				"output", "$_id",
+			)))),
+			expected: int32(5),
+		},
+		"TopN": {
+			accumulator: must.NotFail(types.NewDocument("$topN", must.NotFail(types.NewDocument(
+				"n", int32(2),
+				"sortBy", must.NotFail(types.NewDocument("v", int32(-1))),
+				"output", "$_id",
+			)))),
+			expected: must.NotFail(types.NewArray(int32(5), int32(1))),
+		},
+		"BottomN": {
+			accumulator: must.NotFail(types.NewDocument("$bottomN", must.NotFail(types.NewDocument(
+				"n", int32(2),
+				"sortBy", must.NotFail(types.NewDocument("v", int32(-1))),
+				"output", "$_id",
+			)))),
+			expected: must.NotFail(types.NewArray(int32(2), int32(4))),
+		},
+		"StdDevPop": {
+			accumulator: must.NotFail(types.NewDocument("$stdDevPop", "$v")),
+			expected:    math.Sqrt((math.Pow(3-6.5/3, 2) + math.Pow(1-6.5/3, 2) + math.Pow(2.5-6.5/3, 2)) / 3),
+		},
+		"StdDevSamp": {
+			accumulator: must.NotFail(types.NewDocument("$stdDevSamp", "$v")),
+			expected:    math.Sqrt((math.Pow(3-6.5/3, 2) + math.Pow(1-6.5/3, 2) + math.Pow(2.5-6.5/3, 2)) / 2),
+		},
+		"Median": {
+			accumulator: must.NotFail(types.NewDocument("$median", must.NotFail(types.NewDocument(
+				"input", "$v",
+				"method", "approximate",
+			)))),
+			expected: 2.5,
+		},
+		"Percentile": {
+			accumulator: must.NotFail(types.NewDocument("$percentile", must.NotFail(types.NewDocument(
+				"input", "$v",
+				"p", must.NotFail(types.NewArray(0.5, 1.0)),
+				"method", "approximate",
+			)))),
+			expected: must.NotFail(types.NewArray(2.5, 3.0)),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			accumulator, err := NewAccumulator("$group", "res", tc.accumulator)
+			require.NoError(t, err)
+
+			docs := testDocs()
+
+			for split := 0; split <= len(docs); split++ {
+				actual, err := accumulate(t, accumulator, docs, split)
+				require.NoError(t, err)
+
+				if tc.unordered {
+					expected, ok := tc.expected.(*types.Array)
+					require.True(t, ok)
+
+					actualArr, ok := actual.(*types.Array)
+					require.True(t, ok, "%T", actual)
+
+					assert.ElementsMatch(t, must.NotFail(arrayValues(expected)), must.NotFail(arrayValues(actualArr)), "split = %d", split)
+
+					continue
+				}
+
+				if f, ok := tc.expected.(float64); ok {
+					assert.InDelta(t, f, actual, 1e-9, "split = %d", split)
+					continue
+				}
+
+				testutil.AssertEqual(
+					t,
+					must.NotFail(types.NewDocument("res", tc.expected)),
+					must.NotFail(types.NewDocument("res", actual)),
+				)
+			}
+		})
+	}
+}
+
+func TestNewAccumulatorErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		value any
+		code  handlererrors.ErrorCode
+	}{
+		"NotDocument": {
+			value: "$v",
+			code:  handlererrors.ErrStageGroupInvalidAccumulator,
+		},
+		"Multiple": {
+			value: must.NotFail(types.NewDocument("$sum", "$v", "$avg", "$v")),
+			code:  handlererrors.ErrStageGroupMultipleAccumulator,
+		},
+		"NotImplemented": {
+			value: must.NotFail(types.NewDocument("$accumulator", new(types.Document))),
+			code:  handlererrors.ErrNotImplemented,
+		},
+		"SpecNotDocument": {
+			value: must.NotFail(types.NewDocument("$firstN", "$v")),
+			code:  handlererrors.ErrStageGroupNInvalidSpec,
+		},
+		"MissingN": {
+			value: must.NotFail(types.NewDocument("$firstN", must.NotFail(types.NewDocument("input", "$v")))),
+			code:  handlererrors.ErrStageGroupNMissingN,
+		},
+		"NonPositiveN": {
+			value: must.NotFail(types.NewDocument("$lastN", must.NotFail(types.NewDocument("input", "$v", "n", int32(0))))),
+			code:  handlererrors.ErrStageGroupNNonPositive,
+		},
+		"UnknownArgument": {
+			value: must.NotFail(types.NewDocument("$maxN", must.NotFail(types.NewDocument("input", "$v", "n", int32(1), "x", int32(1))))),
+			code:  handlererrors.ErrStageGroupNUnknownArgument,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewAccumulator("$group", "res", tc.value)
+
+			var ce *handlererrors.CommandError
+			require.True(t, errors.As(err, &ce), "%v", err)
+			assert.Equal(t, tc.code, ce.Code())
+		})
+	}
+}
+
+func TestIsPathNotFound(t *testing.T) {
+	t.Parallel()
+
+	expression, err := aggregations.NewExpression("$a.b", nil)
+	require.NoError(t, err)
+
+	_, err = expression.Evaluate(must.NotFail(types.NewDocument("a", must.NotFail(types.NewDocument("c", int32(1))))))
+	assert.True(t, isPathNotFound(err))
+	assert.True(t, isPathNotFound(lazyerrors.Error(err)))
+
+	assert.False(t, isPathNotFound(errors.New("other error")))
+}
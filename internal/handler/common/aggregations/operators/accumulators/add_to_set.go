@@ -0,0 +1,134 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// addToSet represents $addToSet aggregation operator.
+type addToSet struct {
+	e *evaluator
+}
+
+// newAddToSet creates a new $addToSet aggregation operator.
+func newAddToSet(args ...any) (Accumulator, error) {
+	if err := checkUnary("$addToSet", args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &addToSet{e: e}, nil
+}
+
+// NewState implements Accumulator interface.
+func (a *addToSet) NewState() State {
+	return &addToSetState{a: a}
+}
+
+// addToSetState represents the state of $addToSet aggregation operator.
+type addToSetState struct {
+	a    *addToSet
+	set  []any
+	size int64
+}
+
+// Add implements State interface.
+//
+// It skips missing fields.
+func (st *addToSetState) Add(doc *types.Document) error {
+	v, ok, err := st.a.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		st.add(v)
+	}
+
+	return nil
+}
+
+// add adds the value to the set if it is not there yet.
+// Numbers of different types with the same value are considered equal.
+func (st *addToSetState) add(v any) {
+	if containsForAggregation(st.set, v) {
+		return
+	}
+
+	st.set = append(st.set, v)
+	st.size += aggregations.SizeOf(v)
+}
+
+// Merge implements State interface.
+func (st *addToSetState) Merge(other State) error {
+	for _, v := range other.(*addToSetState).set {
+		st.add(v)
+	}
+
+	return nil
+}
+
+// Result implements State interface.
+//
+// It returns an array of unique values in the group.
+func (st *addToSetState) Result() (any, error) {
+	return makeArray(st.set), nil
+}
+
+// Marshal implements State interface.
+func (st *addToSetState) Marshal() any {
+	return makeArray(st.set)
+}
+
+// Unmarshal implements State interface.
+func (st *addToSetState) Unmarshal(v any) error {
+	values, err := arrayValues(v)
+	if err != nil {
+		return err
+	}
+
+	st.set = values
+	st.size = aggregations.SizeOf(v)
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *addToSetState) Size() int64 {
+	return 32 + st.size
+}
+
+// containsForAggregation returns true if values contain v.
+func containsForAggregation(values []any, v any) bool {
+	for _, value := range values {
+		if types.CompareForAggregation(value, v) == types.Equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*addToSet)(nil)
+	_ State       = (*addToSetState)(nil)
+)
@@ -0,0 +1,136 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// avg represents $avg aggregation operator.
+type avg struct {
+	e *evaluator
+}
+
+// newAvg creates a new $avg aggregation operator.
+func newAvg(args ...any) (Accumulator, error) {
+	if err := checkUnary("$avg", args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &avg{e: e}, nil
+}
+
+// NewState implements Accumulator interface.
+func (a *avg) NewState() State {
+	return &avgState{a: a}
+}
+
+// avgState represents the state of $avg aggregation operator.
+type avgState struct {
+	a     *avg
+	sum   aggregations.Sum
+	count int64
+}
+
+// Add implements State interface.
+//
+// It ignores non-numeric values.
+func (st *avgState) Add(doc *types.Document) error {
+	v, ok, err := st.a.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if ok && isNumber(v) {
+		st.sum.Add(v)
+		st.count++
+	}
+
+	return nil
+}
+
+// Merge implements State interface.
+func (st *avgState) Merge(other State) error {
+	o := other.(*avgState)
+
+	st.sum.Merge(&o.sum)
+	st.count += o.count
+
+	return nil
+}
+
+// Result implements State interface.
+//
+// It returns null if there are no numbers in the group.
+func (st *avgState) Result() (any, error) {
+	if st.count == 0 {
+		return types.Null, nil
+	}
+
+	return toFloat64(st.sum.Result()) / float64(st.count), nil
+}
+
+// Marshal implements State interface.
+func (st *avgState) Marshal() any {
+	return must.NotFail(types.NewDocument("sum", st.sum.Document(), "count", st.count))
+}
+
+// Unmarshal implements State interface.
+func (st *avgState) Unmarshal(v any) error {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return invalidStateError(v)
+	}
+
+	sumDoc, _ := doc.Get("sum")
+	count, _ := doc.Get("count")
+
+	d, ok := sumDoc.(*types.Document)
+	if !ok {
+		return invalidStateError(v)
+	}
+
+	if st.count, ok = count.(int64); !ok {
+		return invalidStateError(v)
+	}
+
+	res, err := aggregations.UnmarshalSum(d)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	st.sum = *res
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *avgState) Size() int64 {
+	return 72
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*avg)(nil)
+	_ State       = (*avgState)(nil)
+)
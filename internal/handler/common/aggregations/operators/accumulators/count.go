@@ -15,11 +15,8 @@
 package accumulators
 
 import (
-	"errors"
-
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
-	"github.com/FerretDB/FerretDB/internal/util/iterator"
 )
 
 // count represents $count operator.
@@ -48,27 +45,55 @@ func newCount(args ...any) (Accumulator, error) {
 	return new(count), nil
 }
 
-// Accumulate implements Accumulator interface.
-func (c *count) Accumulate(iter types.DocumentsIterator) (any, error) {
-	defer iter.Close()
-	var count int32
-
-	for {
-		_, _, err := iter.Next()
-		if err != nil {
-			if errors.Is(err, iterator.ErrIteratorDone) {
-				break
-			}
-
-			return nil, err
-		}
-		count++
+// NewState implements Accumulator interface.
+func (c *count) NewState() State {
+	return new(countState)
+}
+
+// countState represents the state of $count aggregation operator.
+type countState struct {
+	count int32
+}
+
+// Add implements State interface.
+func (st *countState) Add(*types.Document) error {
+	st.count++
+	return nil
+}
+
+// Merge implements State interface.
+func (st *countState) Merge(other State) error {
+	st.count += other.(*countState).count
+	return nil
+}
+
+// Result implements State interface.
+func (st *countState) Result() (any, error) {
+	return st.count, nil
+}
+
+// Marshal implements State interface.
+func (st *countState) Marshal() any {
+	return st.count
+}
+
+// Unmarshal implements State interface.
+func (st *countState) Unmarshal(v any) error {
+	var ok bool
+	if st.count, ok = v.(int32); !ok {
+		return invalidStateError(v)
 	}
 
-	return count, nil
+	return nil
+}
+
+// Size implements State interface.
+func (st *countState) Size() int64 {
+	return 4
 }
 
 // check interfaces
 var (
 	_ Accumulator = (*count)(nil)
+	_ State       = (*countState)(nil)
 )
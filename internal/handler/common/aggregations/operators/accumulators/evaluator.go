@@ -0,0 +1,209 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// evaluator evaluates accumulator's argument against each document of the group.
+//
+// The argument could be a path expression (`"$v"`), an operator (`{$type: "$v"}`),
+// an object literal with nested arguments (`{a: "$v", b: 1}`) or a constant value.
+type evaluator struct {
+	expression *aggregations.Expression
+	operator   operators.Operator
+	fields     map[string]*evaluator // object literal fields
+	keys       []string              // object literal keys in original order
+	value      any                   // constant value
+}
+
+// newEvaluator creates a new evaluator for the given accumulator's argument.
+func newEvaluator(arg any) (*evaluator, error) {
+	switch arg := arg.(type) {
+	case *types.Document:
+		if operators.IsOperator(arg) {
+			op, err := operators.NewOperator(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			return &evaluator{operator: op}, nil
+		}
+
+		e := &evaluator{
+			fields: make(map[string]*evaluator, arg.Len()),
+			keys:   arg.Keys(),
+		}
+
+		iter := arg.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if e.fields[k], err = newEvaluator(v); err != nil {
+				return nil, err
+			}
+		}
+
+		return e, nil
+
+	case string:
+		expression, err := aggregations.NewExpression(arg, nil)
+
+		var exprErr *aggregations.ExpressionError
+		if errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrNotExpression {
+			return &evaluator{value: arg}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &evaluator{expression: expression}, nil
+
+	default:
+		return &evaluator{value: arg}, nil
+	}
+}
+
+// evaluate returns the value of the argument for the given document.
+// It returns false if the argument refers to a field that does not exist.
+func (e *evaluator) evaluate(doc *types.Document) (any, bool, error) {
+	switch {
+	case e.expression != nil:
+		v, err := e.expression.Evaluate(doc)
+		if err != nil {
+			// missing fields are not errors for accumulators
+			if isPathNotFound(err) {
+				return nil, false, nil
+			}
+
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		return v, true, nil
+
+	case e.operator != nil:
+		v, err := e.operator.Process(doc)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return v, true, nil
+
+	case e.fields != nil:
+		res := types.MakeDocument(len(e.keys))
+
+		for _, k := range e.keys {
+			v, ok, err := e.fields[k].evaluate(doc)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if ok {
+				res.Set(k, v)
+			}
+		}
+
+		return res, true, nil
+
+	default:
+		return e.value, true, nil
+	}
+}
+
+// checkUnary returns an error if accumulator is called with other than one argument.
+func checkUnary(accumulator string, args []any) error {
+	if len(args) == 1 {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrStageGroupUnaryOperator,
+		fmt.Sprintf("The %s accumulator is a unary operator", accumulator),
+		accumulator+" (accumulator)",
+	)
+}
+
+// invalidStateError returns an error for the value that does not represent the state of accumulator.
+func invalidStateError(v any) error {
+	return lazyerrors.Errorf("invalid accumulator state %[1]v (%[1]T)", v)
+}
+
+// arrayValues returns values of the array that represents the state of accumulator.
+func arrayValues(v any) ([]any, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, invalidStateError(v)
+	}
+
+	res := make([]any, arr.Len())
+
+	for i := range res {
+		res[i] = must.NotFail(arr.Get(i))
+	}
+
+	return res, nil
+}
+
+// isNumber returns true if v is a number that could be used by arithmetic accumulators.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, int32, int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// toFloat64 converts a number to float64.
+//
+// It panics for non-number values.
+func toFloat64(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// isPathNotFound returns true if the error is returned by expression evaluation for a field that does not exist.
+func isPathNotFound(err error) bool {
+	var exprErr *aggregations.ExpressionError
+	return errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrPathNotFound
+}
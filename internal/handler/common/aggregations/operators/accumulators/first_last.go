@@ -0,0 +1,151 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// firstLast represents $first and $last aggregation operators.
+type firstLast struct {
+	e    *evaluator
+	last bool
+}
+
+// newFirst creates a new $first aggregation operator.
+func newFirst(args ...any) (Accumulator, error) {
+	return newFirstLast("$first", false, args)
+}
+
+// newLast creates a new $last aggregation operator.
+func newLast(args ...any) (Accumulator, error) {
+	return newFirstLast("$last", true, args)
+}
+
+// newFirstLast creates a new $first or $last aggregation operator.
+func newFirstLast(accumulator string, last bool, args []any) (Accumulator, error) {
+	if err := checkUnary(accumulator, args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &firstLast{e: e, last: last}, nil
+}
+
+// NewState implements Accumulator interface.
+func (f *firstLast) NewState() State {
+	return &firstLastState{f: f}
+}
+
+// firstLastState represents the state of $first and $last aggregation operators.
+type firstLastState struct {
+	f     *firstLast
+	value any // nil if there are no documents yet
+	size  int64
+}
+
+// Add implements State interface.
+//
+// Value of the missing field is stored as null.
+func (st *firstLastState) Add(doc *types.Document) error {
+	v, ok, err := st.f.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if st.value != nil && !st.f.last {
+		return nil
+	}
+
+	if !ok {
+		v = types.Null
+	}
+
+	st.set(v)
+
+	return nil
+}
+
+// set sets the current value.
+func (st *firstLastState) set(v any) {
+	st.value = v
+	st.size = aggregations.SizeOf(v)
+}
+
+// Merge implements State interface.
+func (st *firstLastState) Merge(other State) error {
+	o := other.(*firstLastState)
+
+	if o.value != nil && (st.value == nil || st.f.last) {
+		st.set(o.value)
+	}
+
+	return nil
+}
+
+// Result implements State interface.
+func (st *firstLastState) Result() (any, error) {
+	if st.value == nil {
+		return types.Null, nil
+	}
+
+	return st.value, nil
+}
+
+// Marshal implements State interface.
+func (st *firstLastState) Marshal() any {
+	if st.value == nil {
+		return types.MakeArray(0)
+	}
+
+	return must.NotFail(types.NewArray(st.value))
+}
+
+// Unmarshal implements State interface.
+func (st *firstLastState) Unmarshal(v any) error {
+	values, err := arrayValues(v)
+	if err != nil {
+		return err
+	}
+
+	switch len(values) {
+	case 0:
+		st.value = nil
+		st.size = 0
+	case 1:
+		st.set(values[0])
+	default:
+		return invalidStateError(v)
+	}
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *firstLastState) Size() int64 {
+	return 32 + st.size
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*firstLast)(nil)
+	_ State       = (*firstLastState)(nil)
+)
@@ -0,0 +1,163 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// mergeObjects represents $mergeObjects aggregation operator.
+type mergeObjects struct {
+	e *evaluator
+}
+
+// newMergeObjects creates a new $mergeObjects aggregation operator.
+func newMergeObjects(args ...any) (Accumulator, error) {
+	if err := checkUnary("$mergeObjects", args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &mergeObjects{e: e}, nil
+}
+
+// NewState implements Accumulator interface.
+func (m *mergeObjects) NewState() State {
+	return &mergeObjectsState{m: m, res: new(types.Document)}
+}
+
+// mergeObjectsState represents the state of $mergeObjects aggregation operator.
+type mergeObjectsState struct {
+	m    *mergeObjects
+	res  *types.Document
+	size int64
+}
+
+// Add implements State interface.
+//
+// It combines documents of the group into a single document.
+// If documents contain the same field, the value of the last document is used.
+// Null and missing values are ignored.
+func (st *mergeObjectsState) Add(doc *types.Document) error {
+	v, ok, err := st.m.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case types.NullType:
+		return nil
+	case *types.Document:
+		if err = mergeDocument(st.res, v); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		// replaced fields are counted too, but that's good enough
+		st.size += aggregations.SizeOf(v)
+
+		return nil
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupMergeObjectsType,
+			fmt.Sprintf(
+				"$mergeObjects requires object inputs, but input %s is of type %s",
+				types.FormatAnyValue(v), handlerparams.AliasFromType(v),
+			),
+			"$mergeObjects (accumulator)",
+		)
+	}
+}
+
+// Merge implements State interface.
+func (st *mergeObjectsState) Merge(other State) error {
+	o := other.(*mergeObjectsState)
+
+	if err := mergeDocument(st.res, o.res); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	st.size += o.size
+
+	return nil
+}
+
+// Result implements State interface.
+func (st *mergeObjectsState) Result() (any, error) {
+	return st.res, nil
+}
+
+// Marshal implements State interface.
+func (st *mergeObjectsState) Marshal() any {
+	return st.res
+}
+
+// Unmarshal implements State interface.
+func (st *mergeObjectsState) Unmarshal(v any) error {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return invalidStateError(v)
+	}
+
+	st.res = doc
+	st.size = aggregations.SizeOf(doc)
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *mergeObjectsState) Size() int64 {
+	return 32 + st.size
+}
+
+// mergeDocument sets all fields of src to dst.
+func mergeDocument(dst, src *types.Document) error {
+	iter := src.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		dst.Set(k, v)
+	}
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*mergeObjects)(nil)
+	_ State       = (*mergeObjectsState)(nil)
+)
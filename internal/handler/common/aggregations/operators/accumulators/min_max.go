@@ -0,0 +1,141 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// minMax represents $min and $max aggregation operators.
+type minMax struct {
+	e *evaluator
+
+	// the value is replaced when comparison of a new value with the current one returns cmp
+	cmp types.CompareResult
+}
+
+// newMin creates a new $min aggregation operator.
+func newMin(args ...any) (Accumulator, error) {
+	return newMinMax("$min", types.Less, args)
+}
+
+// newMax creates a new $max aggregation operator.
+func newMax(args ...any) (Accumulator, error) {
+	return newMinMax("$max", types.Greater, args)
+}
+
+// newMinMax creates a new $min or $max aggregation operator.
+func newMinMax(accumulator string, cmp types.CompareResult, args []any) (Accumulator, error) {
+	if err := checkUnary(accumulator, args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &minMax{e: e, cmp: cmp}, nil
+}
+
+// NewState implements Accumulator interface.
+func (m *minMax) NewState() State {
+	return &minMaxState{m: m}
+}
+
+// minMaxState represents the state of $min and $max aggregation operators.
+type minMaxState struct {
+	m     *minMax
+	value any // nil if there are no values yet
+	size  int64
+}
+
+// Add implements State interface.
+//
+// Values are compared using BSON comparison order. Null and missing values are ignored.
+func (st *minMaxState) Add(doc *types.Document) error {
+	v, ok, err := st.m.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		st.add(v)
+	}
+
+	return nil
+}
+
+// add replaces the current value with v if needed.
+func (st *minMaxState) add(v any) {
+	if v == types.Null {
+		return
+	}
+
+	if st.value == nil || types.CompareOrder(v, st.value, types.Ascending) == st.m.cmp {
+		st.value = v
+		st.size = aggregations.SizeOf(v)
+	}
+}
+
+// Merge implements State interface.
+func (st *minMaxState) Merge(other State) error {
+	if v := other.(*minMaxState).value; v != nil {
+		st.add(v)
+	}
+
+	return nil
+}
+
+// Result implements State interface.
+//
+// It returns null if there are no values in the group.
+func (st *minMaxState) Result() (any, error) {
+	if st.value == nil {
+		return types.Null, nil
+	}
+
+	return st.value, nil
+}
+
+// Marshal implements State interface.
+func (st *minMaxState) Marshal() any {
+	if st.value == nil {
+		return types.Null
+	}
+
+	return st.value
+}
+
+// Unmarshal implements State interface.
+func (st *minMaxState) Unmarshal(v any) error {
+	st.value = nil
+	st.size = 0
+	st.add(v)
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *minMaxState) Size() int64 {
+	return 32 + st.size
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*minMax)(nil)
+	_ State       = (*minMaxState)(nil)
+)
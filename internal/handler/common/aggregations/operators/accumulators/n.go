@@ -0,0 +1,220 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// nKind represents the kind of values selected by n-accumulator.
+type nKind int
+
+const (
+	nFirst nKind = iota
+	nLast
+	nMax
+	nMin
+)
+
+// nAccumulator represents $firstN, $lastN, $maxN and $minN aggregation operators.
+//
+//	{ $firstN: { input: <expression>, n: <expression> } }
+type nAccumulator struct {
+	input *evaluator
+	n     int64
+	kind  nKind
+}
+
+// newFirstN creates a new $firstN aggregation operator.
+func newFirstN(args ...any) (Accumulator, error) {
+	return newNAccumulator("$firstN", nFirst, args)
+}
+
+// newLastN creates a new $lastN aggregation operator.
+func newLastN(args ...any) (Accumulator, error) {
+	return newNAccumulator("$lastN", nLast, args)
+}
+
+// newMaxN creates a new $maxN aggregation operator.
+func newMaxN(args ...any) (Accumulator, error) {
+	return newNAccumulator("$maxN", nMax, args)
+}
+
+// newMinN creates a new $minN aggregation operator.
+func newMinN(args ...any) (Accumulator, error) {
+	return newNAccumulator("$minN", nMin, args)
+}
+
+// newNAccumulator creates a new n-accumulator of the given kind.
+func newNAccumulator(accumulator string, kind nKind, args []any) (Accumulator, error) {
+	spec, err := getSpec(accumulator, args, "input", "n")
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := getSpecEvaluator(accumulator, spec, "input", handlererrors.ErrStageGroupNMissingInput)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := getN(accumulator, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nAccumulator{
+		input: input,
+		n:     n,
+		kind:  kind,
+	}, nil
+}
+
+// NewState implements Accumulator interface.
+func (a *nAccumulator) NewState() State {
+	return &nState{a: a}
+}
+
+// nState represents the state of n-accumulator.
+type nState struct {
+	a      *nAccumulator
+	values []any
+	size   int64
+}
+
+// Add implements State interface.
+//
+// $firstN and $lastN keep values in the order of documents, with missing values replaced by null.
+// $maxN and $minN ignore null and missing values, and keep values in descending
+// and ascending order respectively.
+func (st *nState) Add(doc *types.Document) error {
+	v, ok, err := st.a.input.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		v = types.Null
+	}
+
+	st.add(v)
+
+	return nil
+}
+
+// add adds the value to the state, keeping up to n values.
+func (st *nState) add(v any) {
+	switch st.a.kind {
+	case nFirst:
+		if int64(len(st.values)) < st.a.n {
+			st.insert(len(st.values), v)
+		}
+
+		return
+
+	case nLast:
+		st.insert(len(st.values), v)
+
+		if int64(len(st.values)) > st.a.n {
+			st.size -= aggregations.SizeOf(st.values[0])
+			st.values = st.values[1:]
+		}
+
+		return
+	}
+
+	if v == types.Null {
+		return
+	}
+
+	// keep values with the same order in the order of documents
+	i := sort.Search(len(st.values), func(i int) bool {
+		return st.before(v, st.values[i])
+	})
+
+	if int64(i) >= st.a.n {
+		return
+	}
+
+	st.insert(i, v)
+
+	if int64(len(st.values)) > st.a.n {
+		last := len(st.values) - 1
+		st.size -= aggregations.SizeOf(st.values[last])
+		st.values = st.values[:last]
+	}
+}
+
+// before returns true if the value a should be before the value b for $maxN and $minN.
+func (st *nState) before(a, b any) bool {
+	if st.a.kind == nMax {
+		return types.CompareOrder(a, b, types.Descending) == types.Greater
+	}
+
+	return types.CompareOrder(a, b, types.Ascending) == types.Less
+}
+
+// insert inserts the value at the given index.
+func (st *nState) insert(i int, v any) {
+	st.values = slices.Insert(st.values, i, v)
+	st.size += aggregations.SizeOf(v)
+}
+
+// Merge implements State interface.
+func (st *nState) Merge(other State) error {
+	for _, v := range other.(*nState).values {
+		st.add(v)
+	}
+
+	return nil
+}
+
+// Result implements State interface.
+func (st *nState) Result() (any, error) {
+	return makeArray(st.values), nil
+}
+
+// Marshal implements State interface.
+func (st *nState) Marshal() any {
+	return makeArray(st.values)
+}
+
+// Unmarshal implements State interface.
+func (st *nState) Unmarshal(v any) error {
+	values, err := arrayValues(v)
+	if err != nil {
+		return err
+	}
+
+	st.values = values
+	st.size = aggregations.SizeOf(v)
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *nState) Size() int64 {
+	return 32 + st.size
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*nAccumulator)(nil)
+	_ State       = (*nState)(nil)
+)
@@ -0,0 +1,253 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// percentile represents $percentile and $median aggregation operators.
+//
+//	{ $percentile: { input: <expression>, p: [ <expression1>, ... ], method: <string> } }
+//	{ $median: { input: <expression>, method: <string> } }
+type percentile struct {
+	input *evaluator
+	p     []float64
+	// median returns a single value instead of an array
+	median bool
+}
+
+// newPercentile creates a new $percentile aggregation operator.
+func newPercentile(args ...any) (Accumulator, error) {
+	spec, err := getSpec("$percentile", args, "input", "p", "method")
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := getPercentileInput("$percentile", spec)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := getPercentileP(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &percentile{
+		input: input,
+		p:     p,
+	}, nil
+}
+
+// newMedian creates a new $median aggregation operator.
+func newMedian(args ...any) (Accumulator, error) {
+	spec, err := getSpec("$median", args, "input", "method")
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := getPercentileInput("$median", spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &percentile{
+		input:  input,
+		p:      []float64{0.5},
+		median: true,
+	}, nil
+}
+
+// getPercentileInput validates method and returns an evaluator for the input of the specification.
+func getPercentileInput(accumulator string, spec *types.Document) (*evaluator, error) {
+	method, err := spec.Get("method")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("%s parsing failed :: caused by :: BSON field 'method' is missing but a required field", accumulator),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	if method != "approximate" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Currently only 'approximate' can be used as percentile 'method', got %s", types.FormatAnyValue(method)),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	return getSpecEvaluator(accumulator, spec, "input", handlererrors.ErrStageGroupNMissingInput)
+}
+
+// getPercentileP returns validated percentiles of the specification.
+func getPercentileP(spec *types.Document) ([]float64, error) {
+	v, err := spec.Get("p")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$percentile parsing failed :: caused by :: BSON field 'p' is missing but a required field",
+			"$percentile (accumulator)",
+		)
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("The 'p' field must be a non-empty array of numbers, got %s", types.FormatAnyValue(v)),
+			"$percentile (accumulator)",
+		)
+	}
+
+	res := make([]float64, 0, arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, p, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !isNumber(p) || toFloat64(p) < 0 || toFloat64(p) > 1 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("The 'p' field must be an array of numbers from [0.0, 1.0], but found %s", types.FormatAnyValue(p)),
+				"$percentile (accumulator)",
+			)
+		}
+
+		res = append(res, toFloat64(p))
+	}
+
+	return res, nil
+}
+
+// NewState implements Accumulator interface.
+func (p *percentile) NewState() State {
+	return &percentileState{p: p}
+}
+
+// percentileState represents the state of $percentile and $median aggregation operators.
+type percentileState struct {
+	p       *percentile
+	numbers []float64
+}
+
+// Add implements State interface.
+//
+// It ignores non-numeric values.
+func (st *percentileState) Add(doc *types.Document) error {
+	v, ok, err := st.p.input.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if ok && isNumber(v) {
+		st.numbers = append(st.numbers, toFloat64(v))
+	}
+
+	return nil
+}
+
+// Merge implements State interface.
+func (st *percentileState) Merge(other State) error {
+	st.numbers = append(st.numbers, other.(*percentileState).numbers...)
+	return nil
+}
+
+// Result implements State interface.
+//
+// Percentiles are computed over the sorted numbers as the value at rank ceil(p * n);
+// nulls are returned if there are no numbers in the group.
+func (st *percentileState) Result() (any, error) {
+	numbers := slices.Clone(st.numbers)
+	sort.Float64s(numbers)
+
+	res := types.MakeArray(len(st.p.p))
+
+	for _, pv := range st.p.p {
+		if len(numbers) == 0 {
+			res.Append(types.Null)
+			continue
+		}
+
+		i := max(int(math.Ceil(pv*float64(len(numbers))))-1, 0)
+		res.Append(numbers[i])
+	}
+
+	if st.p.median {
+		return must.NotFail(res.Get(0)), nil
+	}
+
+	return res, nil
+}
+
+// Marshal implements State interface.
+func (st *percentileState) Marshal() any {
+	res := types.MakeArray(len(st.numbers))
+	for _, n := range st.numbers {
+		res.Append(n)
+	}
+
+	return res
+}
+
+// Unmarshal implements State interface.
+func (st *percentileState) Unmarshal(v any) error {
+	values, err := arrayValues(v)
+	if err != nil {
+		return err
+	}
+
+	st.numbers = make([]float64, len(values))
+
+	for i, n := range values {
+		var ok bool
+		if st.numbers[i], ok = n.(float64); !ok {
+			return invalidStateError(v)
+		}
+	}
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *percentileState) Size() int64 {
+	return 32 + 8*int64(len(st.numbers))
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*percentile)(nil)
+	_ State       = (*percentileState)(nil)
+)
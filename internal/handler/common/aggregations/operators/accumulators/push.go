@@ -0,0 +1,114 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// push represents $push aggregation operator.
+type push struct {
+	e *evaluator
+}
+
+// newPush creates a new $push aggregation operator.
+func newPush(args ...any) (Accumulator, error) {
+	if err := checkUnary("$push", args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &push{e: e}, nil
+}
+
+// NewState implements Accumulator interface.
+func (p *push) NewState() State {
+	return &pushState{p: p}
+}
+
+// pushState represents the state of $push aggregation operator.
+type pushState struct {
+	p      *push
+	values []any
+	size   int64
+}
+
+// Add implements State interface.
+//
+// It skips missing fields.
+func (st *pushState) Add(doc *types.Document) error {
+	v, ok, err := st.p.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		st.values = append(st.values, v)
+		st.size += aggregations.SizeOf(v)
+	}
+
+	return nil
+}
+
+// Merge implements State interface.
+func (st *pushState) Merge(other State) error {
+	o := other.(*pushState)
+
+	st.values = append(st.values, o.values...)
+	st.size += o.size
+
+	return nil
+}
+
+// Result implements State interface.
+//
+// It returns an array of all values in the group.
+func (st *pushState) Result() (any, error) {
+	return makeArray(st.values), nil
+}
+
+// Marshal implements State interface.
+func (st *pushState) Marshal() any {
+	return makeArray(st.values)
+}
+
+// Unmarshal implements State interface.
+func (st *pushState) Unmarshal(v any) error {
+	values, err := arrayValues(v)
+	if err != nil {
+		return err
+	}
+
+	st.values = values
+	st.size = aggregations.SizeOf(v)
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *pushState) Size() int64 {
+	return 32 + st.size
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*push)(nil)
+	_ State       = (*pushState)(nil)
+)
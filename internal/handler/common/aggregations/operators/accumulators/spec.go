@@ -0,0 +1,127 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// getSpec returns the specification document of accumulators such as $topN or $percentile
+// that take a document with named arguments.
+// It returns an error if the document contains fields other than allowed.
+func getSpec(accumulator string, args []any, allowed ...string) (*types.Document, error) {
+	if err := checkUnary(accumulator, args); err != nil {
+		return nil, err
+	}
+
+	spec, ok := args[0].(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupNInvalidSpec,
+			fmt.Sprintf("specification must be an object; found %s", types.FormatAnyValue(args[0])),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	for _, k := range spec.Keys() {
+		if !slices.Contains(allowed, k) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageGroupNUnknownArgument,
+				fmt.Sprintf("Unknown argument for '%s' operator: %s", accumulator, k),
+				accumulator+" (accumulator)",
+			)
+		}
+	}
+
+	return spec, nil
+}
+
+// getN returns a validated positive value of the 'n' field of the specification.
+func getN(accumulator string, spec *types.Document) (int64, error) {
+	v, err := spec.Get("n")
+	if err != nil {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupNMissingN,
+			fmt.Sprintf("Missing value for 'n' in '%s'", accumulator),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	n, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupNNonIntegral,
+			fmt.Sprintf("Value for 'n' must be of integral type, but found %s", types.FormatAnyValue(v)),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	if n <= 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupNNonPositive,
+			fmt.Sprintf("'n' must be greater than 0, found %d", n),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	return n, nil
+}
+
+// getSpecEvaluator returns an evaluator for the required field of the specification.
+func getSpecEvaluator(accumulator string, spec *types.Document, field string, code handlererrors.ErrorCode) (*evaluator, error) { //nolint:lll // for readability
+	if !spec.Has(field) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			code,
+			fmt.Sprintf("Missing value for '%s'", field),
+			accumulator+" (accumulator)",
+		)
+	}
+
+	return newEvaluator(must.NotFail(spec.Get(field)))
+}
+
+// firstValues returns up to n first values.
+func firstValues(values []any, n int64) []any {
+	if int64(len(values)) > n {
+		return values[:n]
+	}
+
+	return values
+}
+
+// lastValues returns up to n last values.
+func lastValues(values []any, n int64) []any {
+	if int64(len(values)) > n {
+		return values[int64(len(values))-n:]
+	}
+
+	return values
+}
+
+// makeArray returns an array containing given values.
+func makeArray(values []any) *types.Array {
+	res := types.MakeArray(len(values))
+	for _, v := range values {
+		res.Append(v)
+	}
+
+	return res
+}
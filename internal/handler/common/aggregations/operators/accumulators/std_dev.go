@@ -0,0 +1,165 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"math"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// stdDev represents $stdDevPop and $stdDevSamp aggregation operators.
+type stdDev struct {
+	e      *evaluator
+	sample bool
+}
+
+// newStdDevPop creates a new $stdDevPop aggregation operator.
+func newStdDevPop(args ...any) (Accumulator, error) {
+	return newStdDev("$stdDevPop", false, args)
+}
+
+// newStdDevSamp creates a new $stdDevSamp aggregation operator.
+func newStdDevSamp(args ...any) (Accumulator, error) {
+	return newStdDev("$stdDevSamp", true, args)
+}
+
+// newStdDev creates a new $stdDevPop or $stdDevSamp aggregation operator.
+func newStdDev(accumulator string, sample bool, args []any) (Accumulator, error) {
+	if err := checkUnary(accumulator, args); err != nil {
+		return nil, err
+	}
+
+	e, err := newEvaluator(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &stdDev{e: e, sample: sample}, nil
+}
+
+// NewState implements Accumulator interface.
+func (s *stdDev) NewState() State {
+	return &stdDevState{s: s}
+}
+
+// stdDevState represents the state of $stdDevPop and $stdDevSamp aggregation operators.
+//
+// It uses Welford's online algorithm.
+type stdDevState struct {
+	s    *stdDev
+	n    int64
+	mean float64
+	m2   float64
+}
+
+// Add implements State interface.
+//
+// It ignores non-numeric values.
+func (st *stdDevState) Add(doc *types.Document) error {
+	v, ok, err := st.s.e.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if !ok || !isNumber(v) {
+		return nil
+	}
+
+	st.n++
+	x := toFloat64(v)
+	delta := x - st.mean
+	st.mean += delta / float64(st.n)
+	st.m2 += delta * (x - st.mean)
+
+	return nil
+}
+
+// Merge implements State interface.
+//
+// It uses Chan's parallel algorithm.
+func (st *stdDevState) Merge(other State) error {
+	o := other.(*stdDevState)
+
+	if o.n == 0 {
+		return nil
+	}
+
+	n := st.n + o.n
+	delta := o.mean - st.mean
+
+	st.mean += delta * float64(o.n) / float64(n)
+	st.m2 += o.m2 + delta*delta*float64(st.n)*float64(o.n)/float64(n)
+	st.n = n
+
+	return nil
+}
+
+// Result implements State interface.
+//
+// Null is returned if there are no numbers in the group,
+// or, for the sample standard deviation, if there is only one number.
+func (st *stdDevState) Result() (any, error) {
+	switch {
+	case st.n == 0:
+		return types.Null, nil
+	case st.s.sample && st.n == 1:
+		return types.Null, nil
+	case st.s.sample:
+		return math.Sqrt(st.m2 / float64(st.n-1)), nil
+	default:
+		return math.Sqrt(st.m2 / float64(st.n)), nil
+	}
+}
+
+// Marshal implements State interface.
+func (st *stdDevState) Marshal() any {
+	return must.NotFail(types.NewDocument("n", st.n, "mean", st.mean, "m2", st.m2))
+}
+
+// Unmarshal implements State interface.
+func (st *stdDevState) Unmarshal(v any) error {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return invalidStateError(v)
+	}
+
+	n, _ := doc.Get("n")
+	mean, _ := doc.Get("mean")
+	m2, _ := doc.Get("m2")
+
+	var nOk, meanOk, m2Ok bool
+	st.n, nOk = n.(int64)
+	st.mean, meanOk = mean.(float64)
+	st.m2, m2Ok = m2.(float64)
+
+	if !nOk || !meanOk || !m2Ok {
+		return invalidStateError(v)
+	}
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *stdDevState) Size() int64 {
+	return 32
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*stdDev)(nil)
+	_ State       = (*stdDevState)(nil)
+)
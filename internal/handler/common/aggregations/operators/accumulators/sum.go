@@ -21,7 +21,6 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
-	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
@@ -82,59 +81,92 @@ func newSum(args ...any) (Accumulator, error) {
 	return accumulator, nil
 }
 
-// Accumulate implements Accumulator interface.
-func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
-	var numbers []any
+// NewState implements Accumulator interface.
+func (s *sum) NewState() State {
+	return &sumState{s: s}
+}
 
-	for {
-		_, doc, err := iter.Next()
+// sumState represents the state of $sum aggregation operator.
+type sumState struct {
+	s   *sum
+	sum aggregations.Sum
+}
 
-		if errors.Is(err, iterator.ErrIteratorDone) {
-			break
+// Add implements State interface.
+func (st *sumState) Add(doc *types.Document) error {
+	switch {
+	case st.s.operator != nil:
+		v, err := st.s.operator.Process(doc)
+		if err != nil {
+			return err
 		}
 
+		st.sum.Add(v)
+
+	case st.s.expression != nil:
+		v, err := st.s.expression.Evaluate(doc)
 		if err != nil {
-			return nil, lazyerrors.Error(err)
+			// sum fields that exist
+			if isPathNotFound(err) {
+				return nil
+			}
+
+			return lazyerrors.Error(err)
 		}
 
-		switch {
-		case s.operator != nil:
-			v, err := s.operator.Process(doc)
-			if err != nil {
-				return nil, err
-			}
+		st.sum.Add(v)
 
-			numbers = append(numbers, v)
+	default:
+		// For number types, the result is equivalent of group size*number,
+		// with conversion handled upon overflow of int32 and int64.
+		// For example, { $sum: 1 } is equivalent of { $count: { } }.
+		// $sum returns 0 on non-existent and non-numeric field.
+		st.sum.Add(st.s.number)
+	}
+
+	return nil
+}
 
-			continue
+// Merge implements State interface.
+func (st *sumState) Merge(other State) error {
+	st.sum.Merge(&other.(*sumState).sum)
+	return nil
+}
 
-		case s.expression != nil:
-			value, err := s.expression.Evaluate(doc)
+// Result implements State interface.
+func (st *sumState) Result() (any, error) {
+	return st.sum.Result(), nil
+}
 
-			// sum fields that exist
-			if err == nil {
-				numbers = append(numbers, value)
-			}
+// Marshal implements State interface.
+func (st *sumState) Marshal() any {
+	return st.sum.Document()
+}
 
-			continue
-		}
+// Unmarshal implements State interface.
+func (st *sumState) Unmarshal(v any) error {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return invalidStateError(v)
+	}
 
-		switch number := s.number.(type) {
-		case float64, int32, int64:
-			// For number types, the result is equivalent of iterator len*number,
-			// with conversion handled upon overflow of int32 and int64.
-			// For example, { $sum: 1 } is equivalent of { $count: { } }.
-			numbers = append(numbers, number)
-		default:
-			// $sum returns 0 on non-existent and non-numeric field.
-			return int32(0), nil
-		}
+	res, err := aggregations.UnmarshalSum(doc)
+	if err != nil {
+		return lazyerrors.Error(err)
 	}
 
-	return aggregations.SumNumbers(numbers...), nil
+	st.sum = *res
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *sumState) Size() int64 {
+	return 64
 }
 
 // check interfaces
 var (
 	_ Accumulator = (*sum)(nil)
+	_ State       = (*sumState)(nil)
 )
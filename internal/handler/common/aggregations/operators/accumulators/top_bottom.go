@@ -0,0 +1,311 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// topBottom represents $top, $bottom, $topN and $bottomN aggregation operators.
+//
+//	{ $topN: { n: <expression>, sortBy: { <field1>: <sort order>, ... }, output: <expression> } }
+type topBottom struct {
+	sortPaths []types.Path
+	sortTypes []types.SortType
+	output    *evaluator
+	n         int64 // 0 for $top and $bottom
+	bottom    bool
+}
+
+// newTop creates a new $top aggregation operator.
+func newTop(args ...any) (Accumulator, error) {
+	return newTopBottom("$top", false, false, args)
+}
+
+// newBottom creates a new $bottom aggregation operator.
+func newBottom(args ...any) (Accumulator, error) {
+	return newTopBottom("$bottom", true, false, args)
+}
+
+// newTopN creates a new $topN aggregation operator.
+func newTopN(args ...any) (Accumulator, error) {
+	return newTopBottom("$topN", false, true, args)
+}
+
+// newBottomN creates a new $bottomN aggregation operator.
+func newBottomN(args ...any) (Accumulator, error) {
+	return newTopBottom("$bottomN", true, true, args)
+}
+
+// newTopBottom creates a new $top, $bottom, $topN or $bottomN aggregation operator.
+func newTopBottom(accumulator string, bottom, withN bool, args []any) (Accumulator, error) {
+	allowed := []string{"sortBy", "output"}
+	if withN {
+		allowed = append(allowed, "n")
+	}
+
+	spec, err := getSpec(accumulator, args, allowed...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := getSpecEvaluator(accumulator, spec, "output", handlererrors.ErrStageGroupTopMissingOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	if !spec.Has("sortBy") {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupTopMissingSortBy,
+			"Missing value for 'sortBy'",
+			accumulator+" (accumulator)",
+		)
+	}
+
+	sortBy, ok := must.NotFail(spec.Get("sortBy")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			"expected 'sortBy' to already be an object in the arguments to "+accumulator,
+			accumulator+" (accumulator)",
+		)
+	}
+
+	if sortBy, err = common.ValidateSortDocument(sortBy); err != nil {
+		return nil, err
+	}
+
+	var n int64
+
+	if withN {
+		if n, err = getN(accumulator, spec); err != nil {
+			return nil, err
+		}
+	}
+
+	t := &topBottom{
+		output: output,
+		n:      n,
+		bottom: bottom,
+	}
+
+	for _, k := range sortBy.Keys() {
+		sortType, err := common.GetSortType(k, must.NotFail(sortBy.Get(k)))
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := types.NewPathFromString(k)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		t.sortPaths = append(t.sortPaths, path)
+		t.sortTypes = append(t.sortTypes, sortType)
+	}
+
+	return t, nil
+}
+
+// NewState implements Accumulator interface.
+func (t *topBottom) NewState() State {
+	return &topBottomState{t: t}
+}
+
+// topBottomEntry represents a document kept by $top, $bottom, $topN and $bottomN aggregation operators.
+type topBottomEntry struct {
+	keys   []any // values of sortBy fields, missing values are replaced by null
+	output any   // missing value is replaced by null
+	size   int64
+}
+
+// topBottomState represents the state of $top, $bottom, $topN and $bottomN aggregation operators.
+type topBottomState struct {
+	t       *topBottom
+	entries []topBottomEntry // sorted by sortBy
+	size    int64
+}
+
+// Add implements State interface.
+//
+// It keeps the output of the top or bottom document(s) of the group sorted by sortBy.
+func (st *topBottomState) Add(doc *types.Document) error {
+	output, ok, err := st.t.output.evaluate(doc)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		output = types.Null
+	}
+
+	keys := make([]any, len(st.t.sortPaths))
+
+	for i, path := range st.t.sortPaths {
+		if keys[i], err = doc.GetByPath(path); err != nil {
+			// sort order treats null and non-existent field equivalent
+			keys[i] = types.Null
+		}
+	}
+
+	st.add(newTopBottomEntry(keys, output))
+
+	return nil
+}
+
+// newTopBottomEntry creates a new entry with the given sort keys and output.
+func newTopBottomEntry(keys []any, output any) topBottomEntry {
+	size := 32 + aggregations.SizeOf(output)
+	for _, k := range keys {
+		size += 16 + aggregations.SizeOf(k)
+	}
+
+	return topBottomEntry{
+		keys:   keys,
+		output: output,
+		size:   size,
+	}
+}
+
+// add adds the entry to the state, keeping up to n entries.
+func (st *topBottomState) add(e topBottomEntry) {
+	n := max(int(st.t.n), 1)
+
+	// keep entries with the same sort keys in the order of documents
+	i := sort.Search(len(st.entries), func(i int) bool {
+		return st.less(e, st.entries[i])
+	})
+
+	if !st.t.bottom && i >= n {
+		return
+	}
+
+	st.entries = slices.Insert(st.entries, i, e)
+	st.size += e.size
+
+	if len(st.entries) <= n {
+		return
+	}
+
+	if st.t.bottom {
+		st.size -= st.entries[0].size
+		st.entries = st.entries[1:]
+
+		return
+	}
+
+	last := len(st.entries) - 1
+	st.size -= st.entries[last].size
+	st.entries = st.entries[:last]
+}
+
+// less returns true if the entry a is sorted before the entry b.
+func (st *topBottomState) less(a, b topBottomEntry) bool {
+	for i, sortType := range st.t.sortTypes {
+		if res := types.CompareOrderForSort(a.keys[i], b.keys[i], sortType); res != types.Equal {
+			return res == types.Less
+		}
+	}
+
+	return false
+}
+
+// Merge implements State interface.
+func (st *topBottomState) Merge(other State) error {
+	for _, e := range other.(*topBottomState).entries {
+		st.add(e)
+	}
+
+	return nil
+}
+
+// Result implements State interface.
+func (st *topBottomState) Result() (any, error) {
+	values := make([]any, len(st.entries))
+	for i, e := range st.entries {
+		values[i] = e.output
+	}
+
+	if st.t.n > 0 {
+		return makeArray(values), nil
+	}
+
+	if len(values) == 0 {
+		return types.Null, nil
+	}
+
+	return values[0], nil
+}
+
+// Marshal implements State interface.
+func (st *topBottomState) Marshal() any {
+	res := types.MakeArray(len(st.entries))
+
+	for _, e := range st.entries {
+		res.Append(must.NotFail(types.NewDocument("keys", makeArray(e.keys), "output", e.output)))
+	}
+
+	return res
+}
+
+// Unmarshal implements State interface.
+func (st *topBottomState) Unmarshal(v any) error {
+	values, err := arrayValues(v)
+	if err != nil {
+		return err
+	}
+
+	st.entries = make([]topBottomEntry, len(values))
+	st.size = 0
+
+	for i, ev := range values {
+		doc, ok := ev.(*types.Document)
+		if !ok {
+			return invalidStateError(v)
+		}
+
+		keysV, _ := doc.Get("keys")
+		output, _ := doc.Get("output")
+
+		keys, err := arrayValues(keysV)
+		if err != nil || len(keys) != len(st.t.sortPaths) || output == nil {
+			return invalidStateError(v)
+		}
+
+		st.entries[i] = newTopBottomEntry(keys, output)
+		st.size += st.entries[i].size
+	}
+
+	return nil
+}
+
+// Size implements State interface.
+func (st *topBottomState) Size() int64 {
+	return 32 + st.size
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*topBottom)(nil)
+	_ State       = (*topBottomState)(nil)
+)
@@ -0,0 +1,67 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// meta represents `$meta` operator.
+//
+// Only `textScore` metadata is supported.
+type meta struct{}
+
+// newMeta returns `$meta` operator.
+func newMeta(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$meta",
+			fmt.Sprintf("Expression $meta takes exactly 1 arguments. %d were passed in.", len(args)),
+		)
+	}
+
+	if args[0] != "textScore" {
+		return nil, newOperatorError(
+			ErrNotImplemented,
+			"$meta",
+			fmt.Sprintf("$meta %s is not implemented yet", types.FormatAnyValue(args[0])),
+		)
+	}
+
+	return new(meta), nil
+}
+
+// Process implements Operator interface.
+func (m *meta) Process(doc *types.Document) (any, error) {
+	score, ok := doc.TextScore()
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTextScoreNotAvailable,
+			"query requires text score metadata, but it is not available",
+			"$meta",
+		)
+	}
+
+	return score, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*meta)(nil)
+)
@@ -139,6 +139,7 @@ func NewOperator(doc *types.Document) (Operator, error) {
 // Operators maps all standard aggregation operators.
 var Operators = map[string]newOperatorFunc{
 	// sorted alphabetically
+	"$meta": newMeta,
 	"$sum":  newSum,
 	"$type": newType,
 	// please keep sorted alphabetically
@@ -223,7 +224,6 @@ var unsupportedOperators = map[string]struct{}{
 	"$ltrim":            {},
 	"$map":              {},
 	"$max":              {},
-	"$meta":             {},
 	"$min":              {},
 	"$minN":             {},
 	"$millisecond":      {},
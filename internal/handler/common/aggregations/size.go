@@ -0,0 +1,70 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregations
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// SizeOf returns the approximate size of the value in memory, in bytes.
+//
+// It is used by stages that have to limit their memory usage,
+// so it is cheap to compute rather than precise.
+func SizeOf(v any) int64 {
+	switch v := v.(type) {
+	case *types.Document:
+		size := int64(48)
+
+		values := v.Values()
+
+		for i, k := range v.Keys() {
+			size += 32 + int64(len(k)) + SizeOf(values[i])
+		}
+
+		return size
+
+	case *types.Array:
+		size := int64(32)
+
+		for i := 0; i < v.Len(); i++ {
+			size += 16 + SizeOf(must.NotFail(v.Get(i)))
+		}
+
+		return size
+
+	case string:
+		return 16 + int64(len(v))
+	case types.Binary:
+		return 32 + int64(len(v.B))
+	case types.Regex:
+		return 32 + int64(len(v.Pattern)+len(v.Options))
+	case types.ObjectID:
+		return int64(len(v))
+	case time.Time:
+		return 24
+	case float64, int64, types.Timestamp:
+		return 8
+	case int32:
+		return 4
+	case bool, types.NullType:
+		return 1
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
@@ -34,7 +34,7 @@ type addFields struct {
 }
 
 // newAddFields validates stage document and creates a new $addFields stage.
-func newAddFields(stage *types.Document) (aggregations.Stage, error) {
+func newAddFields(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := stage.Get("$addFields")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
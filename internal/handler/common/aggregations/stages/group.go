@@ -18,6 +18,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -43,9 +48,15 @@ import (
 // $group uses group expression to group documents that have the same evaluated expression.
 // The evaluated expression becomes the _id for that group of documents.
 // For each group of documents, accumulators are applied.
+//
+// Only the accumulators' states are kept for each group.
+// When they exceed the memory limit, they are stored in temporary files if that is allowed.
 type group struct {
 	groupExpression any
 	groupBy         []groupBy
+	allowDiskUse    bool
+	memoryLimit     int64
+	spillDir        string
 }
 
 // groupBy represents accumulation to apply on the group.
@@ -55,7 +66,7 @@ type groupBy struct {
 }
 
 // newGroup creates a new $group stage.
-func newGroup(stage *types.Document) (aggregations.Stage, error) {
+func newGroup(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$group")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -113,47 +124,67 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 	return &group{
 		groupExpression: groupKey,
 		groupBy:         groups,
+		allowDiskUse:    params.AllowDiskUse,
+		memoryLimit:     params.GroupMemoryLimit,
+		spillDir:        params.SpillDir,
 	}, nil
 }
 
 // Process implements Stage interface.
 func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	groupedDocuments, err := g.groupDocuments(iter)
-	if err != nil {
+	m := newGroupMap(g)
+
+	if err := g.groupDocuments(iter, m); err != nil {
+		m.close()
 		return nil, err
 	}
 
-	var res []*types.Document
+	closer.Add(iterator.CloserFunc(m.close))
 
-	for _, groupedDocument := range groupedDocuments {
-		doc := must.NotFail(types.NewDocument("_id", groupedDocument.groupID))
+	if m.spills == nil {
+		res, err := m.results()
+		if err != nil {
+			return nil, err
+		}
 
-		groupIter := iterator.Values(iterator.ForSlice(groupedDocument.documents))
-		defer groupIter.Close()
+		iter = iterator.Values(iterator.ForSlice(res))
+		closer.Add(iter)
 
-		for _, accumulation := range g.groupBy {
-			out, err := accumulation.accumulator.Accumulate(groupIter)
-			if err != nil {
-				// existing accumulators do not return error
-				return nil, processGroupStageError(err)
+		return iter, nil
+	}
+
+	// store the rest of groups too, so all states of each group could be merged
+	if err := m.spill(); err != nil {
+		return nil, err
+	}
+
+	var partition int
+	var res []*types.Document
+
+	// results are produced one partition at a time
+	next := func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		for len(res) == 0 {
+			if partition == len(m.spills) {
+				return unused, nil, iterator.ErrIteratorDone
 			}
 
-			if doc.Has(accumulation.outputField) {
-				// document has duplicate key
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrDuplicateField,
-					fmt.Sprintf("duplicate field: %s", accumulation.outputField),
-					"$group (stage)",
-				)
+			var err error
+			if res, err = m.readPartition(partition); err != nil {
+				return unused, nil, err
 			}
 
-			doc.Set(accumulation.outputField, out)
+			partition++
 		}
 
-		res = append(res, doc)
+		doc := res[0]
+		res = res[1:]
+
+		return unused, doc, nil
 	}
 
-	iter = iterator.Values(iterator.ForSlice(res))
+	iter = iterator.ForFunc(next)
 	closer.Add(iter)
 
 	return iter, nil
@@ -224,62 +255,71 @@ func validateGroupKey(groupKey any) error {
 	return nil
 }
 
-// groupDocuments groups documents into groups using group key. If group key contains expressions
+// groupDocuments adds documents to groups using group key. If group key contains expressions
 // or operators, they are evaluated before using it as the group key of documents.
-func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments, error) {
-	var m groupMap
+func (g *group) groupDocuments(iter types.DocumentsIterator, m *groupMap) error {
+	defer iter.Close()
 
 	for {
 		_, doc, err := iter.Next()
 		if errors.Is(err, iterator.ErrIteratorDone) {
-			break
+			return nil
 		}
 
 		if err != nil {
-			return nil, lazyerrors.Error(err)
+			return lazyerrors.Error(err)
 		}
 
-		switch groupKey := g.groupExpression.(type) {
-		case *types.Document:
-			val, err := evaluateDocument(groupKey, doc, false)
-			if err != nil {
-				// operator and expression errors are validated in newGroup
-				return nil, lazyerrors.Error(err)
-			}
+		groupID, err := g.evaluateGroupKey(doc)
+		if err != nil {
+			return err
+		}
 
-			m.addOrAppend(val, doc)
-		case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time, types.NullType,
-			types.Regex, int32, types.Timestamp, int64:
-			m.addOrAppend(groupKey, doc)
-		case string:
-			expression, err := aggregations.NewExpression(groupKey, nil)
-			if err != nil {
-				var exprErr *aggregations.ExpressionError
-				if errors.As(err, &exprErr) {
-					if exprErr.Code() == aggregations.ErrNotExpression {
-						m.addOrAppend(groupKey, doc)
-						continue
-					}
-
-					return nil, processGroupStageError(err)
+		if err = m.add(groupID, doc); err != nil {
+			return err
+		}
+	}
+}
+
+// evaluateGroupKey returns the group key of the document.
+func (g *group) evaluateGroupKey(doc *types.Document) (any, error) {
+	switch groupKey := g.groupExpression.(type) {
+	case *types.Document:
+		val, err := evaluateDocument(groupKey, doc, false)
+		if err != nil {
+			// operator and expression errors are validated in newGroup
+			return nil, lazyerrors.Error(err)
+		}
+
+		return val, nil
+	case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time, types.NullType,
+		types.Regex, int32, types.Timestamp, int64:
+		return groupKey, nil
+	case string:
+		expression, err := aggregations.NewExpression(groupKey, nil)
+		if err != nil {
+			var exprErr *aggregations.ExpressionError
+			if errors.As(err, &exprErr) {
+				if exprErr.Code() == aggregations.ErrNotExpression {
+					return groupKey, nil
 				}
 
-				return nil, lazyerrors.Error(err)
+				return nil, processGroupStageError(err)
 			}
 
-			val, err := expression.Evaluate(doc)
-			if err != nil {
-				// $group treats non-existent fields as nulls
-				val = types.Null
-			}
+			return nil, lazyerrors.Error(err)
+		}
 
-			m.addOrAppend(val, doc)
-		default:
-			panic(fmt.Sprintf("unexpected type %[1]T (%#[1]v)", groupKey))
+		val, err := expression.Evaluate(doc)
+		if err != nil {
+			// $group treats non-existent fields as nulls
+			val = types.Null
 		}
-	}
 
-	return m.docs, nil
+		return val, nil
+	default:
+		panic(fmt.Sprintf("unexpected type %[1]T (%#[1]v)", groupKey))
+	}
 }
 
 // evaluateDocument recursively evaluates document's field expressions and operators.
@@ -356,35 +396,323 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 	return evaluatedDocument, nil
 }
 
-// groupedDocuments contains group key and the documents for that group.
-type groupedDocuments struct {
-	groupID   any
-	documents []*types.Document
+// groupSpillPartitions is the number of temporary files used to store groups' states.
+//
+// Each group is always stored in the same file, so after all documents are processed,
+// states could be merged file by file without loading all groups into memory.
+const groupSpillPartitions = 16
+
+// groupState contains group key and the accumulators' states for that group.
+type groupState struct {
+	groupID any
+	states  []accumulators.State
+	size    int64
 }
 
-// groupMap holds groups of documents.
+// groupMap holds groups' states in memory, and stores them in temporary files
+// when they exceed the memory limit.
 type groupMap struct {
-	docs []groupedDocuments
+	g      *group
+	groups map[string]*groupState
+	keys   []string // to return groups in the order of their first documents
+	size   int64    // approximate size of all groups in memory
+	spills []*common.SpillFile
+}
+
+// newGroupMap creates a new groupMap for the given stage.
+func newGroupMap(g *group) *groupMap {
+	return &groupMap{
+		g:      g,
+		groups: map[string]*groupState{},
+	}
+}
+
+// get returns the state of the group with the given group key, creating it if needed.
+func (m *groupMap) get(groupID any) *groupState {
+	// groupID is a distinct key and can be any BSON type including array and Binary;
+	// numbers are grouped for the same value regardless of their number type.
+	key := groupMapKey(groupID)
+
+	gs, ok := m.groups[key]
+	if ok {
+		return gs
+	}
+
+	gs = &groupState{
+		groupID: groupID,
+		states:  make([]accumulators.State, len(m.g.groupBy)),
+		size:    64 + int64(len(key)) + aggregations.SizeOf(groupID),
+	}
+
+	for i, accumulation := range m.g.groupBy {
+		gs.states[i] = accumulation.accumulator.NewState()
+		gs.size += gs.states[i].Size()
+	}
+
+	m.groups[key] = gs
+	m.keys = append(m.keys, key)
+	m.size += gs.size
+
+	return gs
+}
+
+// add adds the document to the group with the given group key.
+//
+// If the memory limit is exceeded, groups are stored in temporary files if that is allowed,
+// and an error is returned otherwise.
+func (m *groupMap) add(groupID any, doc *types.Document) error {
+	gs := m.get(groupID)
+
+	for _, s := range gs.states {
+		before := s.Size()
+
+		if err := s.Add(doc); err != nil {
+			return processGroupStageError(err)
+		}
+
+		diff := s.Size() - before
+		gs.size += diff
+		m.size += diff
+	}
+
+	if m.g.memoryLimit == 0 || m.size <= m.g.memoryLimit {
+		return nil
+	}
+
+	if !m.g.allowDiskUse {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrQueryExceededMemoryLimitNoDiskUseAllowed,
+			"Exceeded memory limit for $group, but didn't allow external sort. Pass allowDiskUse:true to opt in.",
+			"$group (stage)",
+		)
+	}
+
+	return m.spill()
+}
+
+// merge merges states of the group with the given group key.
+func (m *groupMap) merge(groupID any, states []accumulators.State) error {
+	gs := m.get(groupID)
+
+	for i, s := range gs.states {
+		before := s.Size()
+
+		if err := s.Merge(states[i]); err != nil {
+			return processGroupStageError(err)
+		}
+
+		diff := s.Size() - before
+		gs.size += diff
+		m.size += diff
+	}
+
+	return nil
 }
 
-// addOrAppend adds a groupID documents pair if the groupID does not exist,
-// if the groupID exists it appends the documents to the slice.
-func (m *groupMap) addOrAppend(groupKey any, docs ...*types.Document) {
-	for i, g := range m.docs {
-		// groupID is a distinct key and can be any BSON type including array and Binary,
-		// so we cannot use structure like map.
-		// Compare is used to check if groupID exists in groupMap, because
-		// numbers are grouped for the same value regardless of their number type.
-		if types.CompareForAggregation(groupKey, g.groupID) == types.Equal {
-			m.docs[i].documents = append(m.docs[i].documents, docs...)
-			return
+// spill writes all groups to temporary files and removes them from memory.
+func (m *groupMap) spill() error {
+	if m.spills == nil {
+		m.spills = make([]*common.SpillFile, groupSpillPartitions)
+
+		for i := range m.spills {
+			f, err := common.NewSpillFile(m.g.spillDir)
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			m.spills[i] = f
+		}
+	}
+
+	for _, key := range m.keys {
+		gs := m.groups[key]
+
+		states := types.MakeArray(len(gs.states))
+		for _, s := range gs.states {
+			states.Append(s.Marshal())
+		}
+
+		doc := must.NotFail(types.NewDocument("_id", gs.groupID, "states", states))
+
+		h := fnv.New32a()
+		must.NotFail(h.Write([]byte(key)))
+
+		if err := m.spills[h.Sum32()%groupSpillPartitions].Write(doc); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	m.groups = map[string]*groupState{}
+	m.keys = nil
+	m.size = 0
+
+	return nil
+}
+
+// readPartition reads groups' states from the given temporary file,
+// merges states of the same groups and returns resulting documents.
+func (m *groupMap) readPartition(partition int) ([]*types.Document, error) {
+	iter, err := m.spills[partition].Iterator()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer iter.Close()
+
+	pm := newGroupMap(m.g)
+
+	for {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		groupID, _ := doc.Get("_id")
+
+		v, _ := doc.Get("states")
+		arr, ok := v.(*types.Array)
+
+		if groupID == nil || !ok || arr.Len() != len(m.g.groupBy) {
+			return nil, lazyerrors.Errorf("invalid group %v", doc)
+		}
+
+		states := make([]accumulators.State, arr.Len())
+
+		for i, accumulation := range m.g.groupBy {
+			states[i] = accumulation.accumulator.NewState()
+
+			if err = states[i].Unmarshal(must.NotFail(arr.Get(i))); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if err = pm.merge(groupID, states); err != nil {
+			return nil, err
 		}
 	}
 
-	m.docs = append(m.docs, groupedDocuments{
-		groupID:   groupKey,
-		documents: docs,
-	})
+	return pm.results()
+}
+
+// results returns resulting documents for all groups in memory.
+func (m *groupMap) results() ([]*types.Document, error) {
+	res := make([]*types.Document, 0, len(m.keys))
+
+	for _, key := range m.keys {
+		gs := m.groups[key]
+
+		doc := must.NotFail(types.NewDocument("_id", gs.groupID))
+
+		for i, accumulation := range m.g.groupBy {
+			out, err := gs.states[i].Result()
+			if err != nil {
+				return nil, processGroupStageError(err)
+			}
+
+			if doc.Has(accumulation.outputField) {
+				// document has duplicate key
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrDuplicateField,
+					fmt.Sprintf("duplicate field: %s", accumulation.outputField),
+					"$group (stage)",
+				)
+			}
+
+			doc.Set(accumulation.outputField, out)
+		}
+
+		res = append(res, doc)
+	}
+
+	return res, nil
+}
+
+// close removes temporary files.
+func (m *groupMap) close() {
+	for _, f := range m.spills {
+		if f != nil {
+			f.Close()
+		}
+	}
+
+	m.spills = nil
+}
+
+// groupMapKey returns a string that is equal for equal group keys.
+//
+// Numbers of different types are equal if they represent the same value.
+func groupMapKey(groupID any) string {
+	var b strings.Builder
+	writeGroupMapKey(&b, groupID)
+
+	return b.String()
+}
+
+// writeGroupMapKey writes a string representation of the group key used by groupMapKey.
+func writeGroupMapKey(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case *types.Document:
+		b.WriteString("{")
+
+		values := v.Values()
+
+		for i, k := range v.Keys() {
+			b.WriteString(strconv.Quote(k))
+			b.WriteString(":")
+			writeGroupMapKey(b, values[i])
+			b.WriteString(",")
+		}
+
+		b.WriteString("}")
+
+	case *types.Array:
+		b.WriteString("[")
+
+		for i := 0; i < v.Len(); i++ {
+			writeGroupMapKey(b, must.NotFail(v.Get(i)))
+			b.WriteString(",")
+		}
+
+		b.WriteString("]")
+
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			b.WriteString("n:NaN")
+		case math.IsInf(v, 0) || v != math.Trunc(v):
+			b.WriteString("n:" + strconv.FormatFloat(v, 'g', -1, 64))
+		default:
+			i, _ := big.NewFloat(v).Int(nil)
+			b.WriteString("n:" + i.String())
+		}
+
+	case int32:
+		b.WriteString("n:" + strconv.FormatInt(int64(v), 10))
+	case int64:
+		b.WriteString("n:" + strconv.FormatInt(v, 10))
+	case string:
+		b.WriteString("s:" + strconv.Quote(v))
+	case types.Binary:
+		fmt.Fprintf(b, "b:%d:%x", v.Subtype, v.B)
+	case types.ObjectID:
+		fmt.Fprintf(b, "o:%x", v[:])
+	case bool:
+		b.WriteString("t:" + strconv.FormatBool(v))
+	case time.Time:
+		b.WriteString("d:" + strconv.FormatInt(v.UnixMilli(), 10))
+	case types.NullType:
+		b.WriteString("null")
+	case types.Regex:
+		b.WriteString("r:" + strconv.Quote(v.Pattern) + ":" + strconv.Quote(v.Options))
+	case types.Timestamp:
+		b.WriteString("ts:" + strconv.FormatUint(uint64(v), 10))
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
 }
 
 // processGroupError takes internal error related to operator evaluation and
@@ -0,0 +1,134 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// processGroup runs $group stage with given parameters over documents and returns results sorted by _id.
+func processGroup(t *testing.T, stage *types.Document, params *NewStageParams, docs []*types.Document) ([]*types.Document, error) { //nolint:lll // for readability
+	t.Helper()
+
+	s, err := newGroup(stage, params)
+	require.NoError(t, err)
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	iter, err := s.Process(testutil.Ctx(t), iterator.Values(iterator.ForSlice(docs)), closer)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := iterator.ConsumeValues(iter)
+	require.NoError(t, err)
+
+	slices.SortFunc(res, func(a, b *types.Document) int {
+		return int(types.CompareOrder(must.NotFail(a.Get("_id")), must.NotFail(b.Get("_id")), types.Ascending))
+	})
+
+	return res, nil
+}
+
+func TestGroupSpill(t *testing.T) {
+	t.Parallel()
+
+	docs := make([]*types.Document, 1000)
+
+	for i := range docs {
+		var k any = int32(i % 37)
+
+		switch i % 3 {
+		case 1:
+			k = int64(i % 37)
+		case 2:
+			k = float64(i % 37)
+		}
+
+		docs[i] = must.NotFail(types.NewDocument(
+			"_id", int32(i),
+			"k", k,
+			"v", int32(i),
+			"s", fmt.Sprintf("s%03d", i%100),
+			"d", must.NotFail(types.NewDocument(fmt.Sprintf("f%d", i%5), int32(i))),
+		))
+	}
+
+	stage := must.NotFail(types.NewDocument("$group", must.NotFail(types.NewDocument(
+		"_id", "$k",
+		"count", must.NotFail(types.NewDocument("$count", must.NotFail(types.NewDocument()))),
+		"sum", must.NotFail(types.NewDocument("$sum", "$v")),
+		"avg", must.NotFail(types.NewDocument("$avg", "$v")),
+		"min", must.NotFail(types.NewDocument("$min", "$s")),
+		"max", must.NotFail(types.NewDocument("$max", "$s")),
+		"first", must.NotFail(types.NewDocument("$first", "$v")),
+		"last", must.NotFail(types.NewDocument("$last", "$v")),
+		"push", must.NotFail(types.NewDocument("$push", "$v")),
+		"addToSet", must.NotFail(types.NewDocument("$addToSet", "$s")),
+		"mergeObjects", must.NotFail(types.NewDocument("$mergeObjects", "$d")),
+		"lastN", must.NotFail(types.NewDocument("$lastN", must.NotFail(types.NewDocument("input", "$v", "n", int32(3))))),
+		"maxN", must.NotFail(types.NewDocument("$maxN", must.NotFail(types.NewDocument("input", "$s", "n", int32(3))))),
+		"median", must.NotFail(types.NewDocument("$median", must.NotFail(types.NewDocument(
+			"input", "$v", "method", "approximate",
+		)))),
+		"stdDev", must.NotFail(types.NewDocument("$stdDevPop", "$v")),
+		"bottomN", must.NotFail(types.NewDocument("$bottomN", must.NotFail(types.NewDocument(
+			"n", int32(2), "sortBy", must.NotFail(types.NewDocument("s", int32(1))), "output", "$v",
+		)))),
+	))))
+
+	expected, err := processGroup(t, stage, new(NewStageParams), docs)
+	require.NoError(t, err)
+	require.Len(t, expected, 37)
+
+	t.Run("Spill", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := processGroup(t, stage, &NewStageParams{AllowDiskUse: true, GroupMemoryLimit: 10_000}, docs)
+		require.NoError(t, err)
+		require.Len(t, actual, len(expected))
+
+		for i := range expected {
+			// standard deviations of merged states may differ in the last digits
+			testutil.CompareAndSetByPathNum(t, expected[i], actual[i], 1e-9, types.NewStaticPath("stdDev"))
+			testutil.AssertEqual(t, expected[i], actual[i])
+		}
+	})
+
+	t.Run("NoDiskUse", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := processGroup(t, stage, &NewStageParams{GroupMemoryLimit: 10_000}, docs)
+
+		expectedErr := handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrQueryExceededMemoryLimitNoDiskUseAllowed,
+			"Exceeded memory limit for $group, but didn't allow external sort. Pass allowDiskUse:true to opt in.",
+			"$group (stage)",
+		)
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -30,7 +30,7 @@ type limit struct {
 }
 
 // newLimit creates a new $limit stage.
-func newLimit(stage *types.Document) (aggregations.Stage, error) {
+func newLimit(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	doc, err := stage.Get("$limit")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -31,7 +31,7 @@ type match struct {
 }
 
 // newMatch creates a new $match stage.
-func newMatch(stage *types.Document) (aggregations.Stage, error) {
+func newMatch(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	filter, err := common.GetRequiredParam[*types.Document](stage, "$match")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
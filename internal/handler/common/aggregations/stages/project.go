@@ -39,7 +39,7 @@ type project struct {
 }
 
 // newProject validates projection document and creates a new $project stage.
-func newProject(stage *types.Document) (aggregations.Stage, error) {
+func newProject(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$project")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -165,9 +165,12 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				return nil, false, err
 			}
 
-			_, err = op.Process(must.NotFail(types.NewDocument("key", "value")))
-			if err = processOperatorError(err); err != nil {
-				return nil, false, err
+			// metadata is not available for the document used for validation
+			if value.Command() != "$meta" {
+				_, err = op.Process(must.NotFail(types.NewDocument("key", "value")))
+				if err = processOperatorError(err); err != nil {
+					return nil, false, err
+				}
 			}
 
 			// validate operators later
@@ -245,6 +248,10 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 		return nil, err
 	}
 
+	if score, ok := doc.TextScore(); ok {
+		projected.SetTextScore(score)
+	}
+
 	if projection.Has("_id") {
 		idValue := must.NotFail(projection.Get("_id"))
 
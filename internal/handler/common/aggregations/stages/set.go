@@ -36,7 +36,7 @@ type set struct {
 }
 
 // newSet validates stage document and creates a new $set stage.
-func newSet(stage *types.Document) (aggregations.Stage, error) {
+func newSet(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := stage.Get("$set")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
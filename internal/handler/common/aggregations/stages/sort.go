@@ -29,10 +29,11 @@ import (
 // sort represents $sort stage.
 type sort struct {
 	fields *types.Document
+	params common.SortParams
 }
 
 // newSort creates a new $sort stage.
-func newSort(stage *types.Document) (aggregations.Stage, error) {
+func newSort(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$sort")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -54,6 +55,11 @@ func newSort(stage *types.Document) (aggregations.Stage, error) {
 
 	return &sort{
 		fields: fields,
+		params: common.SortParams{
+			MemoryLimit:  params.SortMemoryLimit,
+			AllowDiskUse: params.AllowDiskUse,
+			SpillDir:     params.SpillDir,
+		},
 	}, nil
 }
 
@@ -61,7 +67,7 @@ func newSort(stage *types.Document) (aggregations.Stage, error) {
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func (s *sort) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	iter, err := common.SortIterator(iter, closer, s.fields)
+	iter, err := common.SortIterator(iter, closer, s.fields, &s.params)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/3125
 		var pathErr *types.PathError
@@ -79,6 +85,21 @@ func (s *sort) Process(ctx context.Context, iter types.DocumentsIterator, closer
 	return iter, nil
 }
 
+// CoalesceSortLimit passes the limit of each `$limit` stage to the immediately preceding `$sort` stage,
+// so only that many documents are kept in memory while sorting.
+func CoalesceSortLimit(stages []aggregations.Stage) {
+	for i := 1; i < len(stages); i++ {
+		s, ok := stages[i-1].(*sort)
+		if !ok {
+			continue
+		}
+
+		if l, ok := stages[i].(*limit); ok {
+			s.params.Limit = l.limit
+		}
+	}
+}
+
 // check interfaces
 var (
 	_ aggregations.Stage = (*sort)(nil)
@@ -24,7 +24,26 @@ import (
 )
 
 // newStageFunc is a type for a function that creates a new aggregation stage.
-type newStageFunc func(stage *types.Document) (aggregations.Stage, error)
+type newStageFunc func(stage *types.Document, params *NewStageParams) (aggregations.Stage, error)
+
+// NewStageParams contains parameters common for all stages of the pipeline.
+type NewStageParams struct {
+	// AllowDiskUse allows stages to store data in temporary files when they exceed memory limits.
+	// Commands set it unless the client explicitly disallows it with `allowDiskUse: false`.
+	AllowDiskUse bool
+
+	// GroupMemoryLimit is the approximate memory limit of `$group` stage, in bytes.
+	// Zero means no limit.
+	GroupMemoryLimit int64
+
+	// SortMemoryLimit is the approximate memory limit of `$sort` stage, in bytes.
+	// Zero means no limit.
+	SortMemoryLimit int64
+
+	// SpillDir is the directory for temporary files.
+	// The default directory for temporary files is used if it is empty.
+	SpillDir string
+}
 
 // Stages maps all supported aggregation Stages.
 var Stages = map[string]newStageFunc{
@@ -55,9 +74,7 @@ var unsupportedStages = map[string]struct{}{
 	"$documents":              {},
 	"$facet":                  {},
 	"$fill":                   {},
-	"$geoNear":                {},
 	"$graphLookup":            {},
-	"$indexStats":             {},
 	"$listLocalSessions":      {},
 	"$listSessions":           {},
 	"$lookup":                 {},
@@ -78,7 +95,7 @@ var unsupportedStages = map[string]struct{}{
 }
 
 // NewStage creates a new aggregation stage.
-func NewStage(stage *types.Document) (aggregations.Stage, error) {
+func NewStage(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
 	if stage.Len() != 1 {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrStageInvalid,
@@ -97,7 +114,7 @@ func NewStage(stage *types.Document) (aggregations.Stage, error) {
 		panic(fmt.Sprintf("stage %q is in both `stages` and `unsupportedStages`", name))
 
 	case supported && !unsupported:
-		return f(stage)
+		return f(stage, params)
 
 	case !supported && unsupported:
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
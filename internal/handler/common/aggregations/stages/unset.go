@@ -39,7 +39,7 @@ type unset struct {
 }
 
 // newUnset validates unset document and creates a new $unset stage.
-func newUnset(stage *types.Document) (aggregations.Stage, error) {
+func newUnset(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields := must.NotFail(stage.Get("$unset"))
 
 	// exclusion contains keys with `false` values to specify projection exclusion later.
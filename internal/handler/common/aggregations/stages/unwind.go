@@ -35,7 +35,7 @@ type unwind struct {
 }
 
 // newUnwind creates a new $unwind stage.
-func newUnwind(stage *types.Document) (aggregations.Stage, error) {
+func newUnwind(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	field, err := stage.Get("$unwind")
 	if err != nil {
 		return nil, err
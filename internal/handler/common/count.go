@@ -34,7 +34,7 @@ type CountParams struct {
 
 	Fields any `ferretdb:"fields,ignored"` // legacy MongoDB shell adds it, but it is never actually used
 
-	Hint        any             `ferretdb:"hint,ignored"`
+	Hint        any             `ferretdb:"hint,opt"`
 	ReadConcern *types.Document `ferretdb:"readConcern,ignored"`
 	Comment     string          `ferretdb:"comment,ignored"`
 	LSID        any             `ferretdb:"lsid,ignored"`
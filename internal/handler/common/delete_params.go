@@ -47,7 +47,7 @@ type Delete struct {
 
 	Collation *types.Document `ferretdb:"collation,unimplemented"`
 
-	Hint string `ferretdb:"hint,ignored"`
+	Hint any `ferretdb:"hint,opt"`
 }
 
 // GetDeleteParams returns parameters for delete operation.
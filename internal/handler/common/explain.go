@@ -36,9 +36,11 @@ type ExplainParams struct {
 	Sort   *types.Document `ferretdb:"sort,opt"`
 	Skip   int64           `ferretdb:"skip,opt"`
 	Limit  int64           `ferretdb:"limit,opt"`
+	Hint   any             `ferretdb:"hint,opt"`
 
 	StagesDocs []any           `ferretdb:"-"`
 	Aggregate  bool            `ferretdb:"-"`
+	Update     *types.Document `ferretdb:"-"`
 	Command    *types.Document `ferretdb:"-"`
 
 	Verbosity string `ferretdb:"verbosity,ignored"`
@@ -107,6 +109,8 @@ func GetExplainParams(document *types.Document, l *zap.Logger) (*ExplainParams,
 		return nil, err
 	}
 
+	hint, _ := explain.Get("hint")
+
 	var stagesDocs []any
 
 	if cmd.Command() == "aggregate" {
@@ -133,6 +137,27 @@ func GetExplainParams(document *types.Document, l *zap.Logger) (*ExplainParams,
 		}
 	}
 
+	var update *types.Document
+
+	// only the first update statement is explained
+	if cmd.Command() == "update" {
+		var updates *types.Array
+
+		if updates, err = GetOptionalParam(explain, "updates", updates); err != nil {
+			return nil, err
+		}
+
+		if updates.Len() > 0 {
+			if u, ok := must.NotFail(updates.Get(0)).(*types.Document); ok {
+				q, _ := u.Get("q")
+				filter, _ = q.(*types.Document)
+
+				upd, _ := u.Get("u")
+				update, _ = upd.(*types.Document)
+			}
+		}
+	}
+
 	return &ExplainParams{
 		DB:         db,
 		Collection: collection,
@@ -140,8 +165,10 @@ func GetExplainParams(document *types.Document, l *zap.Logger) (*ExplainParams,
 		Sort:       sort,
 		Skip:       skip,
 		Limit:      limit,
+		Hint:       hint,
 		StagesDocs: stagesDocs,
 		Aggregate:  cmd.Command() == "aggregate",
+		Update:     update,
 		Command:    cmd,
 	}, nil
 }
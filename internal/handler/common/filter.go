@@ -26,6 +26,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/handler/jsonschema"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -324,6 +325,23 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 
 	case "$expr":
 		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)))
+
+	case "$jsonSchema":
+		schema, err := jsonschema.Parse(filterValue)
+		if err != nil {
+			return false, err
+		}
+
+		return schema.Matches(doc), nil
+
+	case "$text":
+		// top-level $text of find and aggregate commands is handled by TextSearchIterator
+		return false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"$text is supported only at the top level of find and $match stage at the beginning of the pipeline",
+			operator,
+		)
+
 	default:
 		msg := fmt.Sprintf(
 			`unknown top level operator: %s. `+
@@ -761,6 +779,27 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 				return false, err
 			}
 
+		case "$geoWithin":
+			// {field: {$geoWithin: shape}}
+			res, err := filterFieldExprGeoWithin(fieldValue, exprValue)
+			if !res || err != nil {
+				return false, err
+			}
+
+		case "$geoIntersects":
+			// {field: {$geoIntersects: {$geometry: geometry}}}
+			res, err := filterFieldExprGeoIntersects(fieldValue, exprValue)
+			if !res || err != nil {
+				return false, err
+			}
+
+		case "$near", "$nearSphere", "$minDistance", "$maxDistance":
+			return false, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"$geoNear, $near, and $nearSphere are not allowed in this context",
+				exprKey,
+			)
+
 		default:
 			return false, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrBadValue,
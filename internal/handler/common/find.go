@@ -41,15 +41,16 @@ type FindParams struct {
 	MaxTimeMS   int64           `ferretdb:"maxTimeMS,opt,wholePositiveNumber"`
 	Tailable    bool            `ferretdb:"tailable,opt"`
 
+	AllowDiskUse bool `ferretdb:"allowDiskUse,opt"`
+
 	Collation *types.Document `ferretdb:"collation,unimplemented"`
 	Let       *types.Document `ferretdb:"let,unimplemented"`
 
-	AllowDiskUse bool            `ferretdb:"allowDiskUse,ignored"`
-	ReadConcern  *types.Document `ferretdb:"readConcern,ignored"`
-	Max          *types.Document `ferretdb:"max,ignored"`
-	Min          *types.Document `ferretdb:"min,ignored"`
-	Hint         any             `ferretdb:"hint,ignored"`
-	LSID         any             `ferretdb:"lsid,ignored"`
+	ReadConcern *types.Document `ferretdb:"readConcern,ignored"`
+	Max         *types.Document `ferretdb:"max,ignored"`
+	Min         *types.Document `ferretdb:"min,ignored"`
+	Hint        any             `ferretdb:"hint,opt"`
+	LSID        any             `ferretdb:"lsid,ignored"`
 
 	ReturnKey           bool `ferretdb:"returnKey,unimplemented-non-default"`
 	ShowRecordId        bool `ferretdb:"showRecordId,opt"`
@@ -63,6 +64,9 @@ type FindParams struct {
 func GetFindParams(doc *types.Document, l *zap.Logger) (*FindParams, error) {
 	params := FindParams{
 		BatchSize: 101,
+
+		// like MongoDB with default allowDiskUseByDefault, only explicit false disallows it
+		AllowDiskUse: true,
 	}
 
 	err := handlerparams.ExtractParams(doc, "find", &params, l)
@@ -64,9 +64,9 @@ type FindAndModifyParams struct {
 	Fields       *types.Document `ferretdb:"fields,unimplemented"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
 
-	Hint                     string          `ferretdb:"hint,ignored"`
+	Hint                     any             `ferretdb:"hint,opt"`
 	WriteConcern             *types.Document `ferretdb:"writeConcern,ignored"`
-	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
+	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,opt"`
 	LSID                     any             `ferretdb:"lsid,ignored"`
 }
 
@@ -0,0 +1,773 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/geo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// GeoNear represents `$near` or `$nearSphere` query operator of the filter, or `$geoNear` aggregation stage.
+type GeoNear struct {
+	// Filter is the filter without `$near` or `$nearSphere` query operator,
+	// or the query of `$geoNear` stage.
+	Filter *types.Document
+
+	field       string // empty if not set for `$geoNear` stage
+	center      geo.Point
+	geoJSON     bool // center is GeoJSON point; distances are in meters
+	spherical   bool
+	minDistance float64
+	maxDistance float64
+
+	// `$geoNear` stage options
+	stage              bool
+	distanceField      string
+	distanceMultiplier float64
+	includeLocs        string
+}
+
+// GetGeoNear returns the top-level `$near` or `$nearSphere` query operator of the given filter,
+// or nil if there is none.
+//
+// Command error codes:
+//   - ErrBadValue when the operator is used more than once or its values are invalid.
+func GetGeoNear(filter *types.Document) (*GeoNear, error) {
+	var res *GeoNear
+
+	values := filter.Values()
+
+	// filter may contain duplicate keys, so Get can't be used
+	for i, field := range filter.Keys() {
+		expr, ok := values[i].(*types.Document)
+		if !ok || strings.HasPrefix(field, "$") {
+			continue
+		}
+
+		for _, op := range []string{"$near", "$nearSphere"} {
+			v, _ := expr.Get(op)
+			if v == nil {
+				continue
+			}
+
+			if res != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"Too many geoNear expressions",
+					op,
+				)
+			}
+
+			gn := &GeoNear{
+				field:       field,
+				spherical:   op == "$nearSphere",
+				maxDistance: math.Inf(1),
+			}
+
+			// GeoJSON point with distances inside, or legacy coordinate pair with distances as siblings
+			distances := expr
+
+			if d, ok := v.(*types.Document); ok && d.Has("$geometry") {
+				g, _ := d.Get("$geometry")
+				if err := gn.setCenter(g, op); err != nil {
+					return nil, err
+				}
+
+				distances = d
+			} else if err := gn.setCenter(v, op); err != nil {
+				return nil, err
+			}
+
+			for _, k := range []string{"$minDistance", "$maxDistance"} {
+				v, _ := distances.Get(k)
+				if v == nil {
+					continue
+				}
+
+				if err := gn.setDistance(k, v, op); err != nil {
+					return nil, err
+				}
+			}
+
+			res = gn
+			res.Filter = filter.DeepCopy()
+
+			expr = expr.DeepCopy()
+			expr.Remove(op)
+			expr.Remove("$minDistance")
+			expr.Remove("$maxDistance")
+
+			if expr.Len() == 0 {
+				res.Filter.Remove(field)
+			} else {
+				res.Filter.Set(field, expr)
+			}
+
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// GetGeoNearStage returns `$geoNear` aggregation stage for the given stage value.
+//
+// Command error codes:
+//   - ErrBadValue when options are missing, unknown, or invalid;
+//   - ErrTypeMismatch when the stage is not an object or options have wrong types.
+func GetGeoNearStage(v any) (*GeoNear, error) {
+	const op = "$geoNear"
+
+	stage, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			"$geoNear argument must be an object",
+			op,
+		)
+	}
+
+	res := &GeoNear{
+		Filter:             new(types.Document),
+		maxDistance:        math.Inf(1),
+		stage:              true,
+		distanceMultiplier: 1,
+	}
+
+	iter := stage.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "near":
+			if err = res.setCenter(v, op); err != nil {
+				return nil, err
+			}
+
+		case "minDistance", "maxDistance":
+			if err = res.setDistance(k, v, op); err != nil {
+				return nil, err
+			}
+
+		case "spherical":
+			if res.spherical, ok = v.(bool); !ok {
+				return nil, geoNearTypeError(k, "a boolean")
+			}
+
+		case "query":
+			if res.Filter, ok = v.(*types.Document); !ok {
+				return nil, geoNearTypeError(k, "an object")
+			}
+
+		case "distanceMultiplier":
+			m, ok := geo.Number(v)
+			if !ok {
+				return nil, geoNearTypeError(k, "a number")
+			}
+
+			if m < 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"distanceMultiplier must be non-negative",
+					op,
+				)
+			}
+
+			res.distanceMultiplier = m
+
+		case "distanceField", "includeLocs", "key":
+			s, ok := v.(string)
+			if !ok || s == "" {
+				return nil, geoNearTypeError(k, "a non-empty string")
+			}
+
+			switch k {
+			case "distanceField":
+				res.distanceField = s
+			case "includeLocs":
+				res.includeLocs = s
+			default:
+				res.field = s
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("unknown argument to $geoNear: %s", k),
+				op,
+			)
+		}
+	}
+
+	for _, k := range []string{"near", "distanceField"} {
+		if !stage.Has(k) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("$geoNear requires a '%s' option", k),
+				op,
+			)
+		}
+	}
+
+	return res, nil
+}
+
+// geoNearTypeError returns ErrTypeMismatch command error for `$geoNear` stage option.
+func geoNearTypeError(option, expected string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf("$geoNear option '%s' must be %s", option, expected),
+		"$geoNear",
+	)
+}
+
+// setCenter sets the center from GeoJSON point or legacy coordinate pair.
+func (gn *GeoNear) setCenter(v any, op string) error {
+	if d, ok := v.(*types.Document); ok && d.Has("type") {
+		g, err := geo.ParseGeoJSON(d)
+		if err != nil || !g.IsPoint() {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("invalid point in %s: %s", op, types.FormatAnyValue(v)),
+				op,
+			)
+		}
+
+		gn.center, gn.geoJSON = g.Points[0], true
+
+		return nil
+	}
+
+	p, ok := geo.LegacyPoint(v)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("invalid point in %s: %s", op, types.FormatAnyValue(v)),
+			op,
+		)
+	}
+
+	gn.center = p
+
+	return nil
+}
+
+// setDistance sets the minimal or maximal distance.
+func (gn *GeoNear) setDistance(key string, v any, op string) error {
+	d, ok := geo.Number(v)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%s must be a number", key),
+			op,
+		)
+	}
+
+	if d < 0 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%s must be non-negative", key),
+			op,
+		)
+	}
+
+	switch key {
+	case "$minDistance", "minDistance":
+		gn.minDistance = d
+	default:
+		gn.maxDistance = d
+	}
+
+	return nil
+}
+
+// GeoNearSearch represents a search of documents near the point that uses the geospatial index.
+type GeoNearSearch struct {
+	field *types.Path
+	near  *geo.Near
+
+	distanceField      *types.Path
+	distanceMultiplier float64
+	includeLocs        *types.Path
+}
+
+// NewSearch returns a search that uses the geospatial index from the given list,
+// and parameters for the backend to pre-filter documents with that index.
+//
+// GeoJSON center requires 2dsphere index; for legacy coordinate pair, 2d index is preferred.
+// Spherical geometry is used for 2dsphere indexes, `$nearSphere`, and `spherical` option of `$geoNear`.
+//
+// Hidden indexes are not used.
+// If there is no suitable index, it returns ErrNoQueryExecutionPlans command error for query operators,
+// and ErrIndexNotFound command error for `$geoNear` stage.
+func (gn *GeoNear) NewSearch(indexes []backends.IndexInfo) (*GeoNearSearch, *backends.GeoParams, error) {
+	var field string
+	var typ backends.IndexKeyType
+
+	for _, index := range indexes {
+		if index.Geo == nil || index.Hidden {
+			continue
+		}
+
+		f, t := geoIndexKey(index.Key)
+
+		if gn.field != "" && f != gn.field {
+			continue
+		}
+
+		if gn.geoJSON && t != backends.IndexKeyType2DSphere {
+			continue
+		}
+
+		if field != "" && f != field {
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexNotFound,
+				"There is more than one 2d or 2dsphere index; unsure which to use for $geoNear",
+				"$geoNear",
+			)
+		}
+
+		if field == "" || t == backends.IndexKeyType2D {
+			field, typ = f, t
+		}
+	}
+
+	if field == "" {
+		if gn.stage {
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexNotFound,
+				"$geoNear requires a 2d or 2dsphere index, but none were found",
+				"$geoNear",
+			)
+		}
+
+		return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNoQueryExecutionPlans,
+			"error processing query: planner returned error :: caused by :: unable to find index for $geoNear query",
+			"$near",
+		)
+	}
+
+	path, err := types.NewPathFromString(field)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	res := &GeoNearSearch{
+		field: &path,
+		near: &geo.Near{
+			Center:      gn.center,
+			Spherical:   gn.spherical || typ == backends.IndexKeyType2DSphere,
+			Meters:      gn.geoJSON,
+			MinDistance: gn.minDistance,
+			MaxDistance: gn.maxDistance,
+		},
+		distanceMultiplier: gn.distanceMultiplier,
+	}
+
+	for _, p := range []struct {
+		s   string
+		res **types.Path
+	}{
+		{gn.distanceField, &res.distanceField},
+		{gn.includeLocs, &res.includeLocs},
+	} {
+		if p.s == "" {
+			continue
+		}
+
+		path, err := types.NewPathFromString(p.s)
+		if err != nil {
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("invalid $geoNear field path: %q", p.s),
+				"$geoNear",
+			)
+		}
+
+		*p.res = &path
+	}
+
+	var params *backends.GeoParams
+
+	if lo, hi, ok := res.near.Bounds(); ok {
+		params = &backends.GeoParams{Field: field, MinX: lo.X, MinY: lo.Y, MaxX: hi.X, MaxY: hi.Y}
+	}
+
+	return res, params, nil
+}
+
+// closest returns the distance to the closest location of the given field value, and that location.
+func (s *GeoNearSearch) closest(v any) (float64, any, bool) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		d, ok := s.near.Distance(geo.Locations(v))
+		return d, v, ok
+	}
+
+	if _, ok = geo.LegacyPoint(arr); ok {
+		d, ok := s.near.Distance(geo.Locations(v))
+		return d, v, ok
+	}
+
+	var found bool
+	var res float64
+	var loc any
+
+	for _, e := range must.NotFail(iterator.ConsumeValues(arr.Iterator())) {
+		d, l, ok := s.closest(e)
+		if ok && (!found || d < res) {
+			found, res, loc = true, d, l
+		}
+	}
+
+	return res, loc, found
+}
+
+// GeoNearIterator returns an iterator that returns documents matched by the given search,
+// sorted by the distance, with `$geoNear` stage fields set.
+//
+// All documents are fetched from the given iterator.
+// The returned iterator will be added to the given closer.
+func GeoNearIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, search *GeoNearSearch) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	defer iter.Close()
+
+	type near struct {
+		doc      *types.Document
+		distance float64
+		loc      any
+	}
+
+	var docs []near
+
+	for {
+		_, doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		v, err := doc.GetByPath(*search.field)
+		if err != nil {
+			continue
+		}
+
+		d, loc, ok := search.closest(v)
+		if !ok || !search.near.Matches(d) {
+			continue
+		}
+
+		docs = append(docs, near{doc: doc, distance: d, loc: loc})
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].distance < docs[j].distance })
+
+	res := make([]*types.Document, len(docs))
+
+	for i, n := range docs {
+		if search.distanceField != nil {
+			if err := n.doc.SetByPath(*search.distanceField, n.distance*search.distanceMultiplier); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if search.includeLocs != nil {
+			loc := n.loc
+
+			switch l := loc.(type) {
+			case *types.Document:
+				loc = l.DeepCopy()
+			case *types.Array:
+				loc = l.DeepCopy()
+			}
+
+			if err := n.doc.SetByPath(*search.includeLocs, loc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		res[i] = n.doc
+	}
+
+	resIter := iterator.Values(iterator.ForSlice(res))
+	closer.Add(resIter)
+
+	return resIter, nil
+}
+
+// geoIndexKey returns the field and the type of the geospatial index key.
+func geoIndexKey(key []backends.IndexKeyPair) (string, backends.IndexKeyType) {
+	for _, pair := range key {
+		switch pair.Type {
+		case backends.IndexKeyType2DSphere, backends.IndexKeyType2D:
+			return pair.Field, pair.Type
+		}
+	}
+
+	return "", ""
+}
+
+// GetGeoParams returns parameters for the backend to pre-filter documents with the geospatial index
+// for the first top-level `$geoWithin` query operator of the given filter, or nil if there is none.
+//
+// Invalid operators are ignored; they are reported when documents are filtered.
+func GetGeoParams(filter *types.Document) *backends.GeoParams {
+	values := filter.Values()
+
+	// filter may contain duplicate keys, so Get can't be used
+	for i, field := range filter.Keys() {
+		expr, ok := values[i].(*types.Document)
+		if !ok || strings.HasPrefix(field, "$") {
+			continue
+		}
+
+		v, _ := expr.Get("$geoWithin")
+		if v == nil {
+			continue
+		}
+
+		shape, err := getGeoWithinShape(v)
+		if err != nil {
+			return nil
+		}
+
+		lo, hi, ok := shape.Bounds()
+		if !ok {
+			return nil
+		}
+
+		return &backends.GeoParams{Field: field, MinX: lo.X, MinY: lo.Y, MaxX: hi.X, MaxY: hi.Y}
+	}
+
+	return nil
+}
+
+// getGeoWithinShape returns the shape of `$geoWithin` query operator.
+func getGeoWithinShape(v any) (geo.Shape, error) {
+	const op = "$geoWithin"
+
+	doc, ok := v.(*types.Document)
+	if !ok || doc.Len() != 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%s expects an object with a single shape: %s", op, types.FormatAnyValue(v)),
+			op,
+		)
+	}
+
+	specifier := doc.Keys()[0]
+	value := must.NotFail(doc.Get(specifier))
+
+	invalid := func() error {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("malformed %s: %s", specifier, types.FormatAnyValue(value)),
+			op,
+		)
+	}
+
+	switch specifier {
+	case "$geometry":
+		g, err := getGeometry(value, op)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(g.Points) > 0 || len(g.Lines) > 0 || len(g.Polygons) == 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("%s not supported with provided geometry: %s", op, types.FormatAnyValue(value)),
+				op,
+			)
+		}
+
+		return geo.SpherePolygons(g.Polygons), nil
+
+	case "$box", "$polygon":
+		points, ok := legacyPoints(value)
+		if !ok {
+			return nil, invalid()
+		}
+
+		if specifier == "$polygon" {
+			if len(points) < 3 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"Polygon must have at least 3 points",
+					op,
+				)
+			}
+
+			return geo.FlatPolygon(points), nil
+		}
+
+		if len(points) != 2 {
+			return nil, invalid()
+		}
+
+		return &geo.Box{
+			Lo: geo.Point{X: math.Min(points[0].X, points[1].X), Y: math.Min(points[0].Y, points[1].Y)},
+			Hi: geo.Point{X: math.Max(points[0].X, points[1].X), Y: math.Max(points[0].Y, points[1].Y)},
+		}, nil
+
+	case "$center", "$centerSphere":
+		arr, ok := value.(*types.Array)
+		if !ok || arr.Len() != 2 {
+			return nil, invalid()
+		}
+
+		center, ok := geo.LegacyPoint(must.NotFail(arr.Get(0)))
+		if !ok {
+			return nil, invalid()
+		}
+
+		radius, ok := geo.Number(must.NotFail(arr.Get(1)))
+		if !ok || radius < 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"radius must be a non-negative number",
+				op,
+			)
+		}
+
+		if specifier == "$center" {
+			return &geo.Circle{Center: center, Radius: radius}, nil
+		}
+
+		return &geo.SphereCircle{Center: center, Radius: radius}, nil
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("unknown geo specifier: %s: %s", specifier, types.FormatAnyValue(value)),
+			op,
+		)
+	}
+}
+
+// getGeometry returns GeoJSON geometry of `$geometry` specifier.
+func getGeometry(v any, op string) (*geo.Geometry, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("$geometry must be an object: %s", types.FormatAnyValue(v)),
+			op,
+		)
+	}
+
+	if doc.Has("crs") {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"$geometry with crs is not implemented yet",
+			op,
+		)
+	}
+
+	g, err := geo.ParseGeoJSON(doc)
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("invalid $geometry %s: %s", types.FormatAnyValue(doc), err),
+			op,
+		)
+	}
+
+	return g, nil
+}
+
+// legacyPoints returns points of the given array of legacy coordinate pairs.
+func legacyPoints(v any) ([]geo.Point, bool) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, false
+	}
+
+	res := make([]geo.Point, arr.Len())
+
+	for i, e := range must.NotFail(iterator.ConsumeValues(arr.Iterator())) {
+		if res[i], ok = geo.LegacyPoint(e); !ok {
+			return nil, false
+		}
+	}
+
+	return res, true
+}
+
+// filterFieldExprGeoWithin handles {field: {$geoWithin: shape}} filter.
+func filterFieldExprGeoWithin(fieldValue, exprValue any) (bool, error) {
+	shape, err := getGeoWithinShape(exprValue)
+	if err != nil {
+		return false, err
+	}
+
+	for _, g := range geo.Locations(fieldValue) {
+		if shape.Contains(g) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// filterFieldExprGeoIntersects handles {field: {$geoIntersects: {$geometry: geometry}}} filter.
+func filterFieldExprGeoIntersects(fieldValue, exprValue any) (bool, error) {
+	const op = "$geoIntersects"
+
+	doc, ok := exprValue.(*types.Document)
+	if !ok || doc.Len() != 1 || !doc.Has("$geometry") {
+		return false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%s expects a $geometry object: %s", op, types.FormatAnyValue(exprValue)),
+			op,
+		)
+	}
+
+	g, err := getGeometry(must.NotFail(doc.Get("$geometry")), op)
+	if err != nil {
+		return false, err
+	}
+
+	for _, l := range geo.Locations(fieldValue) {
+		if geo.Intersects(l, g) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -33,7 +33,7 @@ type InsertParams struct {
 	Ordered    bool         `ferretdb:"ordered,opt"`
 
 	WriteConcern             any    `ferretdb:"writeConcern,ignored"`
-	BypassDocumentValidation bool   `ferretdb:"bypassDocumentValidation,ignored"`
+	BypassDocumentValidation bool   `ferretdb:"bypassDocumentValidation,opt"`
 	Comment                  string `ferretdb:"comment,ignored"`
 	LSID                     any    `ferretdb:"lsid,ignored"`
 }
@@ -147,6 +147,12 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 
 		switch value := value.(type) {
 		case *types.Document:
+			if IsTextScoreMeta(value) && path.Len() == 1 && key != "_id" {
+				// metadata fields are neither included nor excluded
+				validated.Set(key, value)
+				continue
+			}
+
 			return nil, false, handlererrors.NewCommandErrorMsg(
 				handlererrors.ErrNotImplemented,
 				fmt.Sprintf("projection expression %s is not supported", types.FormatAnyValue(value)),
@@ -218,6 +224,11 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 		}
 	}
 
+	if inclusion == nil {
+		// only metadata fields are projected
+		return validated, false, nil
+	}
+
 	return validated, *inclusion, nil
 }
 
@@ -231,13 +242,19 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 // - ErrOperatorWrongLenOfArgs when the operator has an invalid number of arguments.
 // - ErrInvalidPipelineOperator when an the operator does not exist.
 func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*types.Document, error) {
-	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
-	if err != nil {
-		return nil, err
+	projected := types.MakeDocument(0)
+
+	// documents produced by aggregation stages and views might not have _id
+	if id, _ := doc.Get("_id"); id != nil {
+		projected.Set("_id", id)
 	}
 
 	projected.SetRecordID(doc.RecordID())
 
+	if score, ok := doc.TextScore(); ok {
+		projected.SetTextScore(score)
+	}
+
 	if projection.Has("_id") {
 		idValue := must.NotFail(projection.Get("_id"))
 
@@ -296,6 +313,9 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 		projected = docWithoutID.DeepCopy()
 	}
 
+	// metadata fields are set after all other fields
+	var metaKeys []string
+
 	iter := projectionWithoutID.Iterator()
 	defer iter.Close()
 
@@ -316,6 +336,11 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 
 		switch value := value.(type) { // found in the projection
 		case *types.Document: // field: { $elemMatch: { field2: value }}
+			if IsTextScoreMeta(value) {
+				metaKeys = append(metaKeys, key)
+				continue
+			}
+
 			return nil, handlererrors.NewCommandErrorMsg(
 				handlererrors.ErrCommandNotFound,
 				fmt.Sprintf("projection %s is not supported",
@@ -344,6 +369,15 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 		}
 	}
 
+	for _, key := range metaKeys {
+		score, err := textScore(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		projected.Set(key, score)
+	}
+
 	return projected, nil
 }
 
@@ -31,12 +31,27 @@ import (
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
+	sortFuncs, err := getSortFuncs(sortDoc)
+	if err != nil {
+		return err
+	}
+
+	sortDocuments(docs, sortFuncs)
+
+	return nil
+}
+
+// getSortFuncs returns comparison functions for the given sorting conditions.
+//
+// If there are no sorting conditions, it returns nil.
+// If sort path is invalid, it returns a possibly wrapped types.PathError.
+func getSortFuncs(sortDoc *types.Document) ([]sortFunc, error) {
 	if sortDoc.Len() == 0 {
-		return nil
+		return nil, nil
 	}
 
 	if sortDoc.Len() > 32 {
-		return lazyerrors.Errorf("maximum sort keys exceeded: %v", sortDoc.Len())
+		return nil, lazyerrors.Errorf("maximum sort keys exceeded: %v", sortDoc.Len())
 	}
 
 	sortFuncs := make([]sortFunc, sortDoc.Len())
@@ -45,7 +60,7 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 		fields := strings.Split(sortKey, ".")
 		for _, field := range fields {
 			if strings.HasPrefix(field, "$") {
-				return handlererrors.NewCommandErrorMsgWithArgument(
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
 					handlererrors.ErrFieldPathInvalidName,
 					"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
 					"sort",
@@ -55,32 +70,43 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 
 		sortField := must.NotFail(sortDoc.Get(sortKey))
 
+		if IsTextScoreMeta(sortField) {
+			sortFuncs[i] = textScoreLessFunc
+			continue
+		}
+
 		sortType, err := GetSortType(sortKey, sortField)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		sortPath, err := types.NewPathFromString(sortKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		sortFuncs[i] = lessFunc(sortPath, sortType)
 	}
 
+	return sortFuncs, nil
+}
+
+// sortDocuments sorts given documents in place using given comparison functions.
+//
+// Sorting is stable: documents that are equal keep their relative order.
+func sortDocuments(docs []*types.Document, sortFuncs []sortFunc) {
 	if len(sortFuncs) == 0 {
 		// no keys to sort by
-		return nil
+		return
 	}
 
-	sorter := &docsSorter{docs: docs, sorts: sortFuncs}
-	sort.Sort(sorter)
-
-	return nil
+	sort.Stable(&docsSorter{docs: docs, sorts: sortFuncs})
 }
 
 // ValidateSortDocument validates sort documents, and return
 // proper error if it's invalid.
+//
+// `{$meta: "textScore"}` values are kept as-is.
 func ValidateSortDocument(sortDoc *types.Document) (*types.Document, error) {
 	if sortDoc.Len() == 0 {
 		return nil, nil
@@ -106,6 +132,11 @@ func ValidateSortDocument(sortDoc *types.Document) (*types.Document, error) {
 
 		sortField := must.NotFail(sortDoc.Get(sortKey))
 
+		if IsTextScoreMeta(sortField) {
+			res.Set(sortKey, sortField)
+			continue
+		}
+
 		sortValue, err := getSortValue(sortKey, sortField)
 		if err != nil {
 			return nil, err
@@ -145,6 +176,16 @@ func lessFunc(sortPath types.Path, sortType types.SortType) func(a, b *types.Doc
 	}
 }
 
+// textScoreLessFunc compares text scores of 2 documents; higher scores are sorted first.
+//
+// Documents without text score are sorted as if they have zero score.
+func textScoreLessFunc(a, b *types.Document) bool {
+	aScore, _ := a.TextScore()
+	bScore, _ := b.TextScore()
+
+	return aScore > bScore
+}
+
 type sortFunc func(a, b *types.Document) bool
 
 type docsSorter struct {
@@ -161,11 +202,16 @@ func (ds *docsSorter) Swap(i, j int) {
 }
 
 func (ds *docsSorter) Less(i, j int) bool {
-	p, q := ds.docs[i], ds.docs[j]
+	return lessDocuments(ds.sorts, ds.docs[i], ds.docs[j])
+}
+
+// lessDocuments reports whether document p should be sorted before document q
+// according to given comparison functions.
+func lessDocuments(sorts []sortFunc, p, q *types.Document) bool {
 	// Try all but the last comparison.
 	var k int
-	for k = 0; k < len(ds.sorts)-1; k++ {
-		sortFunc := ds.sorts[k]
+	for k = 0; k < len(sorts)-1; k++ {
+		sortFunc := sorts[k]
 
 		switch {
 		case sortFunc(p, q):
@@ -179,7 +225,7 @@ func (ds *docsSorter) Less(i, j int) bool {
 	}
 	// All comparisons to here said "equal", so just return whatever
 	// the final comparison reports.
-	return ds.sorts[k](p, q)
+	return sorts[k](p, q)
 }
 
 // GetSortType determines SortType from input sort value.
@@ -15,33 +15,522 @@
 package common
 
 import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
+// SortParams represents parameters of SortIterator.
+type SortParams struct {
+	// Limit is the number of leading sorted documents that the caller needs.
+	// If it is not zero, only that many documents could be returned.
+	Limit int64
+
+	// MemoryLimit is the approximate size of documents kept in memory, in bytes.
+	// Zero means no limit.
+	MemoryLimit int64
+
+	// AllowDiskUse allows to store sorted documents in temporary files when MemoryLimit is exceeded.
+	// Commands set it unless the client explicitly disallows it with `allowDiskUse: false`.
+	AllowDiskUse bool
+
+	// SpillDir is the directory for temporary files.
+	// The default directory for temporary files is used if it is empty.
+	SpillDir string
+}
+
 // SortIterator returns an iterator of sorted documents.
 // It will be added to the given closer.
 //
-// Since sorting iterator is impossible, this function fully consumes and closes the underlying iterator,
-// sorts documents in memory and returns a new iterator over the sorted slice.
-func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
+// Since sorting iterator is impossible, this function fully consumes and closes the underlying iterator.
+// If params.Limit is set, only that many documents are kept in memory.
+// Otherwise, documents are sorted in memory until params.MemoryLimit is exceeded;
+// after that, sorted runs of documents are stored in temporary files and merged,
+// or an error is returned if disk use is not allowed.
+func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document, params *SortParams) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// don't consume all documents if there is no sort
 	if sort.Len() == 0 {
 		return iter, nil
 	}
 
-	docs, err := iterator.ConsumeValues(iter)
+	defer iter.Close()
+
+	sortFuncs, err := getSortFuncs(sort)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	if err = SortDocuments(docs, sort); err != nil {
+	s := &sorter{
+		sortFuncs: sortFuncs,
+		params:    params,
+	}
+
+	var res types.DocumentsIterator
+
+	if params.Limit > 0 {
+		res, err = s.sortTopK(iter)
+	} else {
+		res, err = s.sortExternal(iter, nil)
+	}
+
+	if err != nil {
+		s.close()
 		return nil, lazyerrors.Error(err)
 	}
 
-	res := iterator.Values(iterator.ForSlice(docs))
 	closer.Add(res)
 
 	return res, nil
 }
+
+// sortMergeFanIn is the number of sorted runs of the same level that are merged into a single run
+// of the next level while spilling.
+//
+// That keeps the number of temporary files (and open file descriptors) logarithmic
+// in the number of sorted documents.
+const sortMergeFanIn = 16
+
+// sorter sorts documents using a bounded amount of memory.
+type sorter struct {
+	sortFuncs []sortFunc
+	params    *SortParams
+	runs      []sortRun // in the order of input documents
+}
+
+// sortRun represents a sorted run of documents stored in a temporary file.
+type sortRun struct {
+	f     *SpillFile
+	level int // number of merge passes
+}
+
+// sortTopK returns an iterator over params.Limit first sorted documents.
+//
+// Only that many documents are kept in memory using a heap.
+// If they exceed the memory limit, it falls back to the external sort.
+func (s *sorter) sortTopK(iter types.DocumentsIterator) (types.DocumentsIterator, error) {
+	h := &topKHeap{sortFuncs: s.sortFuncs}
+
+	var size int64
+
+	for seq := 0; ; seq++ {
+		_, doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		e := topKEntry{doc: doc, seq: seq, size: aggregations.SizeOf(doc)}
+
+		switch {
+		case int64(h.Len()) < s.params.Limit:
+			heap.Push(h, e)
+			size += e.size
+
+		case lessDocuments(s.sortFuncs, doc, h.entries[0].doc):
+			size += e.size - h.entries[0].size
+			h.entries[0] = e
+			heap.Fix(h, 0)
+
+		default:
+			continue
+		}
+
+		if s.params.MemoryLimit == 0 || size <= s.params.MemoryLimit {
+			continue
+		}
+
+		if !s.params.AllowDiskUse {
+			return nil, sortMemoryLimitError(s.params.MemoryLimit)
+		}
+
+		// Kept documents are passed in their input order, followed by the rest of the input,
+		// so equal documents are still returned in that order.
+		// Documents that were dropped from the heap can't be among params.Limit first ones.
+		return s.sortExternal(iter, h.documents())
+	}
+
+	docs := h.documents()
+	sortDocuments(docs, s.sortFuncs)
+
+	return iterator.Values(iterator.ForSlice(docs)), nil
+}
+
+// sortExternal returns an iterator over all sorted documents:
+// given documents that were already read, followed by the rest of documents from the iterator.
+//
+// When documents kept in memory exceed the memory limit, they are sorted and stored in a temporary file.
+// All those sorted runs are merged by the returned iterator.
+func (s *sorter) sortExternal(iter types.DocumentsIterator, docs []*types.Document) (types.DocumentsIterator, error) {
+	var size int64
+	for _, doc := range docs {
+		size += aggregations.SizeOf(doc)
+	}
+
+	for {
+		if s.params.MemoryLimit != 0 && size > s.params.MemoryLimit {
+			if !s.params.AllowDiskUse {
+				return nil, sortMemoryLimitError(s.params.MemoryLimit)
+			}
+
+			if err := s.spill(docs); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			docs, size = nil, 0
+		}
+
+		_, doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		docs = append(docs, doc)
+		size += aggregations.SizeOf(doc)
+	}
+
+	sortDocuments(docs, s.sortFuncs)
+
+	if len(s.runs) == 0 {
+		return iterator.Values(iterator.ForSlice(docs)), nil
+	}
+
+	// documents kept in memory are the last run
+	sources := make([]types.DocumentsIterator, 0, len(s.runs)+1)
+
+	for _, run := range s.runs {
+		runIter, err := run.f.Iterator()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		sources = append(sources, runIter)
+	}
+
+	sources = append(sources, iterator.Values(iterator.ForSlice(docs)))
+
+	res, err := newMergeIterator(sources, s.sortFuncs, s.close)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// spill sorts given documents and stores them in a new temporary file.
+//
+// Then the last sortMergeFanIn runs are merged while they have the same level.
+func (s *sorter) spill(docs []*types.Document) error {
+	sortDocuments(docs, s.sortFuncs)
+
+	f, err := NewSpillFile(s.params.SpillDir)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	s.runs = append(s.runs, sortRun{f: f})
+
+	for _, doc := range docs {
+		if err = f.Write(doc); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	// levels of runs never increase, so the first and the last runs of the same level are enough to check
+	for n := len(s.runs); n >= sortMergeFanIn && s.runs[n-sortMergeFanIn].level == s.runs[n-1].level; n = len(s.runs) {
+		if err = s.merge(n - sortMergeFanIn); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// merge merges runs starting from the given index into a single run of the next level.
+//
+// Merged runs are consecutive, so equal documents keep their input order.
+func (s *sorter) merge(from int) error {
+	runs := s.runs[from:]
+
+	sources := make([]types.DocumentsIterator, 0, len(runs))
+
+	for _, run := range runs {
+		runIter, err := run.f.Iterator()
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		sources = append(sources, runIter)
+	}
+
+	iter, err := newMergeIterator(sources, s.sortFuncs, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer iter.Close()
+
+	f, err := NewSpillFile(s.params.SpillDir)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for {
+		var doc *types.Document
+
+		if _, doc, err = iter.Next(); err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			f.Close()
+
+			return lazyerrors.Error(err)
+		}
+
+		if err = f.Write(doc); err != nil {
+			f.Close()
+			return lazyerrors.Error(err)
+		}
+	}
+
+	level := runs[len(runs)-1].level + 1
+
+	for _, run := range runs {
+		run.f.Close()
+	}
+
+	s.runs = append(s.runs[:from], sortRun{f: f, level: level})
+
+	return nil
+}
+
+// close removes all temporary files.
+func (s *sorter) close() {
+	for _, run := range s.runs {
+		run.f.Close()
+	}
+
+	s.runs = nil
+}
+
+// sortMemoryLimitError returns an error for the sort that exceeded the memory limit
+// when disk use is not allowed.
+func sortMemoryLimitError(limit int64) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrQueryExceededMemoryLimitNoDiskUseAllowed,
+		fmt.Sprintf("Sort exceeded memory limit of %d bytes, but did not opt in to external sorting.", limit),
+		"sort",
+	)
+}
+
+// topKEntry represents a document kept by topKHeap.
+type topKEntry struct {
+	doc  *types.Document
+	seq  int // position in the input, used to keep the sort stable
+	size int64
+}
+
+// topKHeap is a heap of documents with the last sorted document at the root.
+//
+// It implements heap.Interface.
+type topKHeap struct {
+	sortFuncs []sortFunc
+	entries   []topKEntry
+}
+
+// Len implements heap.Interface.
+func (h *topKHeap) Len() int {
+	return len(h.entries)
+}
+
+// Less implements heap.Interface.
+func (h *topKHeap) Less(i, j int) bool {
+	a, b := h.entries[i], h.entries[j]
+
+	switch {
+	case lessDocuments(h.sortFuncs, b.doc, a.doc):
+		return true
+	case lessDocuments(h.sortFuncs, a.doc, b.doc):
+		return false
+	default:
+		return a.seq > b.seq
+	}
+}
+
+// Swap implements heap.Interface.
+func (h *topKHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+}
+
+// Push implements heap.Interface.
+func (h *topKHeap) Push(x any) {
+	h.entries = append(h.entries, x.(topKEntry))
+}
+
+// Pop implements heap.Interface.
+func (h *topKHeap) Pop() any {
+	e := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+
+	return e
+}
+
+// documents returns kept documents in their original order.
+func (h *topKHeap) documents() []*types.Document {
+	slices.SortFunc(h.entries, func(a, b topKEntry) int { return a.seq - b.seq })
+
+	res := make([]*types.Document, len(h.entries))
+	for i, e := range h.entries {
+		res[i] = e.doc
+	}
+
+	return res
+}
+
+// mergeIterator merges sorted runs of documents.
+type mergeIterator struct {
+	sortFuncs []sortFunc
+	sources   []types.DocumentsIterator
+	heads     []mergeHead
+	onClose   func()
+}
+
+// mergeHead represents the next document of the run.
+type mergeHead struct {
+	doc    *types.Document
+	source int
+}
+
+// newMergeIterator returns an iterator that merges documents from sorted sources.
+//
+// Equal documents are returned in the order of sources.
+// Close method closes all sources and calls the given function.
+func newMergeIterator(sources []types.DocumentsIterator, sortFuncs []sortFunc, onClose func()) (*mergeIterator, error) {
+	iter := &mergeIterator{
+		sortFuncs: sortFuncs,
+		sources:   sources,
+		heads:     make([]mergeHead, 0, len(sources)),
+		onClose:   onClose,
+	}
+
+	for i := range sources {
+		if err := iter.advance(i); err != nil {
+			iter.Close()
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	heap.Init(iter)
+
+	return iter, nil
+}
+
+// advance reads the next document from the given source and pushes it to the end of heads.
+func (iter *mergeIterator) advance(source int) error {
+	_, doc, err := iter.sources[source].Next()
+	if err != nil {
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	iter.heads = append(iter.heads, mergeHead{doc: doc, source: source})
+
+	return nil
+}
+
+// Next implements iterator.Interface.
+func (iter *mergeIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	if len(iter.heads) == 0 {
+		return unused, nil, iterator.ErrIteratorDone
+	}
+
+	head := heap.Pop(iter).(mergeHead)
+
+	n := len(iter.heads)
+	if err := iter.advance(head.source); err != nil {
+		return unused, nil, lazyerrors.Error(err)
+	}
+
+	if len(iter.heads) > n {
+		heap.Fix(iter, n)
+	}
+
+	return unused, head.doc, nil
+}
+
+// Close implements iterator.Interface.
+func (iter *mergeIterator) Close() {
+	for _, s := range iter.sources {
+		s.Close()
+	}
+
+	iter.heads = nil
+
+	if iter.onClose != nil {
+		iter.onClose()
+		iter.onClose = nil
+	}
+}
+
+// Len implements heap.Interface.
+func (iter *mergeIterator) Len() int {
+	return len(iter.heads)
+}
+
+// Less implements heap.Interface.
+func (iter *mergeIterator) Less(i, j int) bool {
+	a, b := iter.heads[i], iter.heads[j]
+
+	switch {
+	case lessDocuments(iter.sortFuncs, a.doc, b.doc):
+		return true
+	case lessDocuments(iter.sortFuncs, b.doc, a.doc):
+		return false
+	default:
+		return a.source < b.source
+	}
+}
+
+// Swap implements heap.Interface.
+func (iter *mergeIterator) Swap(i, j int) {
+	iter.heads[i], iter.heads[j] = iter.heads[j], iter.heads[i]
+}
+
+// Push implements heap.Interface.
+func (iter *mergeIterator) Push(x any) {
+	iter.heads = append(iter.heads, x.(mergeHead))
+}
+
+// Pop implements heap.Interface.
+func (iter *mergeIterator) Pop() any {
+	h := iter.heads[len(iter.heads)-1]
+	iter.heads = iter.heads[:len(iter.heads)-1]
+
+	return h
+}
+
+// check interfaces
+var (
+	_ heap.Interface          = (*topKHeap)(nil)
+	_ types.DocumentsIterator = (*mergeIterator)(nil)
+	_ heap.Interface          = (*mergeIterator)(nil)
+)
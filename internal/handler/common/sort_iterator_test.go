@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestSortIterator(t *testing.T) {
+	t.Parallel()
+
+	docs := make([]*types.Document, 1000)
+
+	for i := range docs {
+		var v any = int32(i % 17)
+
+		switch i % 4 {
+		case 1:
+			v = float64(i%17) + 0.5
+		case 2:
+			v = fmt.Sprintf("s%02d", i%13)
+		case 3:
+			v = types.Null
+		}
+
+		docs[i] = must.NotFail(types.NewDocument("_id", int32(i), "v", v, "w", int32(i%3)))
+	}
+
+	sort := must.NotFail(types.NewDocument("v", int32(1), "w", int32(-1)))
+
+	expected := slices.Clone(docs)
+	require.NoError(t, SortDocuments(expected, sort))
+
+	for name, tc := range map[string]struct {
+		params   *SortParams
+		expected []*types.Document
+		err      error
+		spill    bool // check that temporary files are used and removed
+		maxFiles int  // maximal number of temporary files, if not zero
+	}{
+		"NoLimit": {
+			params:   new(SortParams),
+			expected: expected,
+		},
+		"MemoryLimit": {
+			params:   &SortParams{MemoryLimit: 10_000, AllowDiskUse: true},
+			expected: expected,
+			spill:    true,
+		},
+		"MemoryLimitNoDiskUse": {
+			params: &SortParams{MemoryLimit: 10_000},
+			err: handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrQueryExceededMemoryLimitNoDiskUseAllowed,
+				"Sort exceeded memory limit of 10000 bytes, but did not opt in to external sorting.",
+				"sort",
+			),
+		},
+		"MemoryLimitMerge": {
+			params:   &SortParams{MemoryLimit: 500, AllowDiskUse: true},
+			expected: expected,
+			spill:    true,
+			maxFiles: 2 * sortMergeFanIn, // runs of two levels
+		},
+		"TopK": {
+			params:   &SortParams{Limit: 42, MemoryLimit: 10_000},
+			expected: expected[:42],
+		},
+		"TopKMemoryLimit": {
+			params:   &SortParams{Limit: 500, MemoryLimit: 10_000, AllowDiskUse: true},
+			expected: expected,
+			spill:    true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.spill {
+				tc.params.SpillDir = t.TempDir()
+			}
+
+			closer := iterator.NewMultiCloser()
+			defer closer.Close()
+
+			iter, err := SortIterator(iterator.Values(iterator.ForSlice(docs)), closer, sort, tc.params)
+			if tc.err != nil {
+				var ce *handlererrors.CommandError
+				require.ErrorAs(t, err, &ce)
+				assert.Equal(t, tc.err, ce)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.spill {
+				files, err := os.ReadDir(tc.params.SpillDir)
+				require.NoError(t, err)
+				assert.NotEmpty(t, files)
+
+				if tc.maxFiles != 0 {
+					assert.Less(t, len(files), tc.maxFiles)
+				}
+			}
+
+			actual, err := iterator.ConsumeValues(iter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.spill {
+				closer.Close()
+
+				files, err := os.ReadDir(tc.params.SpillDir)
+				require.NoError(t, err)
+				assert.Empty(t, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// SpillFilePattern is the pattern of spill file names, as used by os.CreateTemp.
+const SpillFilePattern = "ferretdb-spill-*"
+
+// SpillFile is a temporary file that stores documents that do not fit into memory.
+//
+// Documents are written one by one and then read back in the same order.
+// SpillFile is not safe for concurrent use.
+type SpillFile struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// NewSpillFile creates a new spill file in the given directory.
+// If dir is empty, the default directory for temporary files is used.
+//
+// The caller is responsible for calling Close.
+func NewSpillFile(dir string) (*SpillFile, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	f, err := os.CreateTemp(dir, SpillFilePattern)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &SpillFile{
+		f: f,
+		w: bufio.NewWriter(f),
+	}, nil
+}
+
+// Write writes the document to the end of the file.
+//
+// Document's RecordID and TextScore properties are preserved.
+func (sf *SpillFile) Write(doc *types.Document) error {
+	// properties are not fields, so the document is wrapped
+	w := must.NotFail(types.NewDocument("d", doc, "r", doc.RecordID()))
+
+	if score, ok := doc.TextScore(); ok {
+		w.Set("s", score)
+	}
+
+	d, err := bson.ConvertDocument(w)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if err = d.WriteTo(sf.w); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// Iterator returns an iterator over all written documents.
+//
+// The file should not be written after that.
+func (sf *SpillFile) Iterator() (types.DocumentsIterator, error) {
+	if err := sf.w.Flush(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(sf.f, 0, 1<<63-1))
+
+	next := func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		if _, err := r.Peek(1); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = iterator.ErrIteratorDone
+			}
+
+			return unused, nil, err
+		}
+
+		var d bson.Document
+		if err := d.ReadFrom(r); err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		w, err := types.ConvertDocument(&d)
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		doc := must.NotFail(w.Get("d")).(*types.Document)
+		doc.SetRecordID(must.NotFail(w.Get("r")).(int64))
+
+		if score, _ := w.Get("s"); score != nil {
+			doc.SetTextScore(score.(float64))
+		}
+
+		return unused, doc, nil
+	}
+
+	return iterator.ForFunc(next), nil
+}
+
+// Close closes and removes the file.
+func (sf *SpillFile) Close() {
+	_ = sf.f.Close()
+	_ = os.Remove(sf.f.Name())
+}
@@ -0,0 +1,266 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/textsearch"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// TextSearch represents `$text` query operator of the filter.
+type TextSearch struct {
+	// Filter is the filter without `$text` query operator.
+	Filter *types.Document
+
+	params *textsearch.QueryParams
+}
+
+// GetTextSearch returns the top-level `$text` query operator of the given filter, or nil if there is none.
+//
+// Command error codes:
+//   - ErrBadValue when `$text` is not an object, has unknown fields, or the language is not supported;
+//   - ErrTypeMismatch when `$text` fields have wrong types.
+func GetTextSearch(filter *types.Document) (*TextSearch, error) {
+	v, _ := filter.Get("$text")
+	if v == nil {
+		return nil, nil
+	}
+
+	text, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$text expects an object",
+			"$text",
+		)
+	}
+
+	var params textsearch.QueryParams
+
+	iter := text.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "$search":
+			if params.Search, ok = v.(string); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					"$search requires a string value",
+					"$text",
+				)
+			}
+
+		case "$language":
+			if params.Language, ok = v.(string); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					"$language requires a string value",
+					"$text",
+				)
+			}
+
+			if err = textsearch.ValidateLanguage(params.Language); err != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, err.Error(), "$text")
+			}
+
+		case "$caseSensitive":
+			if params.CaseSensitive, ok = v.(bool); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					"$caseSensitive requires a boolean value",
+					"$text",
+				)
+			}
+
+		case "$diacriticSensitive":
+			// diacritics are always significant
+			if _, ok = v.(bool); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					"$diacriticSensitive requires a boolean value",
+					"$text",
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("extra fields in $text: %s", k),
+				"$text",
+			)
+		}
+	}
+
+	if !text.Has("$search") {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$search required",
+			"$text",
+		)
+	}
+
+	res := &TextSearch{
+		Filter: filter.DeepCopy(),
+		params: &params,
+	}
+
+	res.Filter.Remove("$text")
+
+	return res, nil
+}
+
+// NewSearch returns a search that uses the text index from the given list.
+//
+// If there is no visible text index, it returns ErrIndexNotFound command error.
+func (ts *TextSearch) NewSearch(indexes []backends.IndexInfo) (*textsearch.Search, *backends.TextSearchParams, error) {
+	for _, index := range indexes {
+		if index.Text == nil || index.Hidden {
+			continue
+		}
+
+		weights := make(map[string]int32, len(index.Text.Weights))
+		for _, w := range index.Text.Weights {
+			weights[w.Field] = w.Weight
+		}
+
+		search := textsearch.NewSearch(&textsearch.Index{
+			Weights:          weights,
+			DefaultLanguage:  index.Text.DefaultLanguage,
+			LanguageOverride: index.Text.LanguageOverride,
+		}, ts.params)
+
+		// the backend's text index could be used only if words are processed in the same way
+		var params *backends.TextSearchParams
+		if ts.params.Language == "" || textsearch.SameLanguage(ts.params.Language, index.Text.DefaultLanguage) {
+			params = &backends.TextSearchParams{
+				Words: search.Words(index.Text.DefaultLanguage),
+			}
+		}
+
+		return search, params, nil
+	}
+
+	return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrIndexNotFound,
+		"text index required for $text query",
+		"$text",
+	)
+}
+
+// TextSearchIterator returns an iterator that filters documents matched by the given search
+// and sets their TextScore.
+// It will be added to the given closer.
+func TextSearchIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, search *textsearch.Search) types.DocumentsIterator { //nolint:lll // for readability
+	res := &textSearchIterator{
+		iter:   iter,
+		search: search,
+	}
+	closer.Add(res)
+
+	return res
+}
+
+// textSearchIterator is returned by TextSearchIterator.
+type textSearchIterator struct {
+	iter   types.DocumentsIterator
+	search *textsearch.Search
+}
+
+// Next implements iterator.Interface. See TextSearchIterator for details.
+func (iter *textSearchIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	for {
+		_, doc, err := iter.iter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		score, ok := iter.search.Score(doc)
+		if !ok {
+			continue
+		}
+
+		doc.SetTextScore(score)
+
+		return unused, doc, nil
+	}
+}
+
+// Close implements iterator.Interface. See TextSearchIterator for details.
+func (iter *textSearchIterator) Close() {
+	iter.iter.Close()
+}
+
+// IsTextScoreMeta returns true if the given value is `{$meta: "textScore"}`.
+func IsTextScoreMeta(v any) bool {
+	doc, ok := v.(*types.Document)
+	if !ok || doc.Len() != 1 {
+		return false
+	}
+
+	meta, _ := doc.Get("$meta")
+
+	return meta == "textScore"
+}
+
+// HasTextScoreMeta returns true if any value of the given document is `{$meta: "textScore"}`.
+func HasTextScoreMeta(doc *types.Document) bool {
+	for _, v := range doc.Values() {
+		if IsTextScoreMeta(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// textScore returns the document's text score.
+//
+// If it is not available, it returns ErrTextScoreNotAvailable command error.
+func textScore(doc *types.Document) (float64, error) {
+	score, ok := doc.TextScore()
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTextScoreNotAvailable,
+			"query requires text score metadata, but it is not available",
+			"$meta",
+		)
+	}
+
+	return score, nil
+}
+
+// check interfaces
+var (
+	_ types.DocumentsIterator = (*textSearchIterator)(nil)
+)
@@ -35,7 +35,7 @@ type UpdateParams struct {
 	Let *types.Document `ferretdb:"let,unimplemented"`
 
 	Ordered                  bool            `ferretdb:"ordered,ignored"`
-	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
+	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,opt"`
 	WriteConcern             *types.Document `ferretdb:"writeConcern,ignored"`
 	LSID                     any             `ferretdb:"lsid,ignored"`
 }
@@ -53,7 +53,7 @@ type Update struct {
 	Collation    *types.Document `ferretdb:"collation,unimplemented"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
 
-	Hint string `ferretdb:"hint,ignored"`
+	Hint any `ferretdb:"hint,opt"`
 }
 
 // GetUpdateParams returns parameters for update command.
@@ -0,0 +1,389 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/jsonschema"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Collection validation levels.
+const (
+	ValidationLevelOff      = "off"
+	ValidationLevelStrict   = "strict"
+	ValidationLevelModerate = "moderate"
+)
+
+// Collection validation actions.
+const (
+	ValidationActionError = "error"
+	ValidationActionWarn  = "warn"
+)
+
+// validatorNotAllowed contains query operators that can't be used in collection validators.
+var validatorNotAllowed = []string{"$near", "$nearSphere", "$geoNear", "$text", "$where"}
+
+// GetValidationParams returns `validator`, `validationLevel` and `validationAction` parameters
+// of `create` and `collMod` commands.
+//
+// It returns nil if none of them are set.
+// Parameters that are not set are returned as zero values.
+func GetValidationParams(doc *types.Document, command string) (*backends.CollectionValidation, error) {
+	if !doc.Has("validator") && !doc.Has("validationLevel") && !doc.Has("validationAction") {
+		return nil, nil
+	}
+
+	var res backends.CollectionValidation
+	var err error
+
+	if res.Validator, err = GetOptionalParam[*types.Document](doc, "validator", nil); err != nil {
+		return nil, err
+	}
+
+	if res.Validator != nil {
+		if err = ValidateValidator(res.Validator); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.Level, err = getEnumParam(doc, command, "validationLevel", []string{
+		ValidationLevelOff, ValidationLevelStrict, ValidationLevelModerate,
+	}); err != nil {
+		return nil, err
+	}
+
+	if res.Action, err = getEnumParam(doc, command, "validationAction", []string{
+		ValidationActionError, ValidationActionWarn,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// getEnumParam returns the string parameter that should be one of the given values, or an empty string if not set.
+func getEnumParam(doc *types.Document, command, key string, values []string) (string, error) {
+	v, err := GetOptionalParam(doc, key, "")
+	if err != nil {
+		return "", err
+	}
+
+	if v != "" && !slices.Contains(values, v) {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Enumeration value '%s' for field '%s.%s' is not a valid value.", v, command, key),
+			key,
+		)
+	}
+
+	return v, nil
+}
+
+// ValidateValidator returns an error if the given collection validator is not valid.
+func ValidateValidator(validator *types.Document) error {
+	if err := validateValidatorOperators(validator); err != nil {
+		return err
+	}
+
+	// check all other query operators by applying the validator to an empty document
+	if _, err := FilterDocument(must.NotFail(types.NewDocument()), validator); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateValidatorOperators checks that the validator does not contain operators
+// that are not allowed in validators, and that all `$jsonSchema` values are valid.
+func validateValidatorOperators(v any) error {
+	switch v := v.(type) {
+	case *types.Document:
+		for _, key := range v.Keys() {
+			value := must.NotFail(v.Get(key))
+
+			if slices.Contains(validatorNotAllowed, key) {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrQueryFeatureNotAllowed,
+					fmt.Sprintf("%s is not allowed in collection validators", key),
+					key,
+				)
+			}
+
+			if key == "$jsonSchema" {
+				if _, err := jsonschema.Parse(value); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := validateValidatorOperators(value); err != nil {
+				return err
+			}
+		}
+
+	case *types.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateValidatorOperators(must.NotFail(v.Get(i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateDocument checks that the document satisfies the collection validator.
+//
+// It returns nil if it does, or the document validation error information
+// (`errInfo` in MongoDB terms) if it does not.
+func ValidateDocument(doc, validator *types.Document) (*types.Document, error) {
+	matches, err := FilterDocument(doc, validator)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if matches {
+		return nil, nil
+	}
+
+	details, err := validatorDetails(doc, validator)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return must.NotFail(types.NewDocument(
+		"failingDocumentId", must.NotFail(doc.Get("_id")),
+		"details", details,
+	)), nil
+}
+
+// validatorDetails returns details of the not satisfied validator expression.
+//
+// Top-level expressions are ANDed together like in MongoDB.
+func validatorDetails(doc, expr *types.Document) (*types.Document, error) {
+	if expr.Len() == 1 {
+		key := expr.Keys()[0]
+		return clauseDetails(doc, key, must.NotFail(expr.Get(key)))
+	}
+
+	var failed []any
+
+	for i, key := range expr.Keys() {
+		value := must.NotFail(expr.Get(key))
+
+		matches, err := FilterDocument(doc, must.NotFail(types.NewDocument(key, value)))
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if matches {
+			continue
+		}
+
+		details, err := clauseDetails(doc, key, value)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		failed = append(failed, must.NotFail(types.NewDocument("index", int32(i), "details", details)))
+	}
+
+	return must.NotFail(types.NewDocument(
+		"operatorName", "$and",
+		"clausesNotSatisfied", must.NotFail(types.NewArray(failed...)),
+	)), nil
+}
+
+// clauseDetails returns details of the single not satisfied validator clause.
+func clauseDetails(doc *types.Document, key string, value any) (*types.Document, error) {
+	switch key {
+	case "$jsonSchema":
+		schema, err := jsonschema.Parse(value)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return must.NotFail(types.NewDocument(
+			"operatorName", key,
+			"schemaRulesNotSatisfied", schema.Validate(doc),
+		)), nil
+
+	case "$and", "$or", "$nor":
+		return logicalDetails(doc, key, value.(*types.Array))
+
+	case "$expr":
+		return must.NotFail(types.NewDocument(
+			"operatorName", key,
+			"specifiedAs", must.NotFail(types.NewDocument(key, value)),
+			"reason", "expression did not match",
+			"expressionResult", false,
+		)), nil
+	}
+
+	if strings.HasPrefix(key, "$") {
+		return must.NotFail(types.NewDocument(
+			"operatorName", key,
+			"specifiedAs", must.NotFail(types.NewDocument(key, value)),
+			"reason", "expression did not match",
+		)), nil
+	}
+
+	expr, ok := value.(*types.Document)
+	if !ok || expr.Len() == 0 || !strings.HasPrefix(expr.Keys()[0], "$") {
+		return fieldDetails(doc, key, "$eq", must.NotFail(types.NewDocument(key, value))), nil
+	}
+
+	// each field operator is a separate clause
+	var failed []*types.Document
+
+	for _, op := range expr.Keys() {
+		if op == "$options" {
+			continue
+		}
+
+		opExpr := must.NotFail(types.NewDocument(op, must.NotFail(expr.Get(op))))
+		if op == "$regex" && expr.Has("$options") {
+			opExpr.Set("$options", must.NotFail(expr.Get("$options")))
+		}
+
+		specified := must.NotFail(types.NewDocument(key, opExpr))
+
+		matches, err := FilterDocument(doc, specified)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !matches {
+			failed = append(failed, fieldDetails(doc, key, op, specified))
+		}
+	}
+
+	if len(failed) == 1 {
+		return failed[0], nil
+	}
+
+	clauses := types.MakeArray(len(failed))
+	for i, d := range failed {
+		clauses.Append(must.NotFail(types.NewDocument("index", int32(i), "details", d)))
+	}
+
+	return must.NotFail(types.NewDocument(
+		"operatorName", "$and",
+		"clausesNotSatisfied", clauses,
+	)), nil
+}
+
+// logicalDetails returns details of the not satisfied `$and`, `$or` or `$nor` validator clause.
+func logicalDetails(doc *types.Document, op string, exprs *types.Array) (*types.Document, error) {
+	var clauses []any
+
+	for i := 0; i < exprs.Len(); i++ {
+		expr := must.NotFail(exprs.Get(i)).(*types.Document)
+
+		matches, err := FilterDocument(doc, expr)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// $nor fails because of satisfied clauses, $and and $or because of not satisfied ones
+		if matches != (op == "$nor") {
+			continue
+		}
+
+		clause := must.NotFail(types.NewDocument("index", int32(i)))
+
+		if !matches {
+			var details *types.Document
+			if details, err = validatorDetails(doc, expr); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			clause.Set("details", details)
+		}
+
+		clauses = append(clauses, clause)
+	}
+
+	name := "clausesNotSatisfied"
+	if op == "$nor" {
+		name = "clausesSatisfied"
+	}
+
+	return must.NotFail(types.NewDocument(
+		"operatorName", op,
+		name, must.NotFail(types.NewArray(clauses...)),
+	)), nil
+}
+
+// fieldDetails returns details of the not satisfied field operator.
+func fieldDetails(doc *types.Document, key, op string, specified *types.Document) *types.Document {
+	res := must.NotFail(types.NewDocument(
+		"operatorName", op,
+		"specifiedAs", specified,
+	))
+
+	var fieldValue any
+
+	if path, err := types.NewPathFromString(key); err == nil {
+		fieldValue, _ = doc.GetByPath(path)
+	}
+
+	if fieldValue == nil && op != "$exists" {
+		res.Set("reason", "field was missing")
+		return res
+	}
+
+	var reason string
+
+	switch op {
+	case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte", "$in", "$nin":
+		reason = "comparison failed"
+	case "$type":
+		reason = "type did not match"
+	case "$regex":
+		reason = "regular expression did not match"
+	case "$exists":
+		if fieldValue == nil {
+			reason = "field was missing"
+		} else {
+			reason = "field was present"
+		}
+	case "$size":
+		reason = "array length was not equal to given size"
+	case "$all":
+		reason = "array did not contain all specified values"
+	case "$elemMatch":
+		reason = "array did not satisfy the child predicate"
+	default:
+		reason = "expression did not match"
+	}
+
+	res.Set("reason", reason)
+
+	if fieldValue != nil {
+		res.Set("consideredValue", fieldValue)
+	}
+
+	return res
+}
@@ -0,0 +1,367 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geo implements geometry used by geospatial indexes and query operators.
+//
+// GeoJSON objects use spherical geometry: points are longitude/latitude pairs,
+// and edges of lines and polygons are great circle arcs.
+// Legacy coordinate pairs could be used with both spherical geometry
+// and flat geometry of legacy shapes ($box, $polygon, $center).
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// EarthRadius is the radius of the Earth in meters used for spherical distances, as MongoDB does.
+const EarthRadius = 6378100.0
+
+// Point represents a point.
+//
+// For spherical geometry, X is longitude and Y is latitude in degrees.
+type Point struct {
+	X, Y float64
+}
+
+// Loop represents a closed ring of points; the last point is the same as the first one.
+type Loop []Point
+
+// Polygon represents a polygon: the exterior loop followed by optional holes.
+type Polygon []Loop
+
+// Geometry represents a GeoJSON object or a legacy coordinate pair.
+//
+// GeoJSON multi-geometries and geometry collections are flattened.
+type Geometry struct {
+	Points   []Point
+	Lines    [][]Point
+	Polygons []Polygon
+}
+
+// IsPoint returns true if the geometry is a single point.
+func (g *Geometry) IsPoint() bool {
+	return len(g.Points) == 1 && len(g.Lines) == 0 && len(g.Polygons) == 0
+}
+
+// vertices returns all points of the geometry.
+func (g *Geometry) vertices() []Point {
+	res := slices.Clone(g.Points)
+
+	for _, l := range g.Lines {
+		res = append(res, l...)
+	}
+
+	for _, p := range g.Polygons {
+		for _, l := range p {
+			res = append(res, l...)
+		}
+	}
+
+	return res
+}
+
+// BoundingBox returns the minimal box containing all points of the geometry.
+//
+// For spherical geometry, great circle arcs between points are not taken into account.
+func (g *Geometry) BoundingBox() (lo, hi Point) {
+	return boundingBox(g.vertices())
+}
+
+// boundingBox returns the minimal box containing all given points.
+func boundingBox(points []Point) (lo, hi Point) {
+	lo = Point{X: math.Inf(1), Y: math.Inf(1)}
+	hi = Point{X: math.Inf(-1), Y: math.Inf(-1)}
+
+	for _, p := range points {
+		lo.X, lo.Y = math.Min(lo.X, p.X), math.Min(lo.Y, p.Y)
+		hi.X, hi.Y = math.Max(hi.X, p.X), math.Max(hi.Y, p.Y)
+	}
+
+	return lo, hi
+}
+
+// Number returns float64 value of the given number, or false if it is not a number.
+func Number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, !math.IsNaN(v)
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// LegacyPoint returns a point for the given legacy coordinate pair:
+// an array or a document with at least two numbers, the first two of which are used.
+func LegacyPoint(v any) (Point, bool) {
+	var values []any
+
+	switch v := v.(type) {
+	case *types.Array:
+		values = must.NotFail(iterator.ConsumeValues(v.Iterator()))
+	case *types.Document:
+		values = v.Values()
+	default:
+		return Point{}, false
+	}
+
+	if len(values) < 2 {
+		return Point{}, false
+	}
+
+	x, ok := Number(values[0])
+	if !ok {
+		return Point{}, false
+	}
+
+	y, ok := Number(values[1])
+	if !ok {
+		return Point{}, false
+	}
+
+	return Point{X: x, Y: y}, true
+}
+
+// Locations returns all geometries of the given document field value.
+//
+// The value could be a GeoJSON object, a legacy coordinate pair,
+// or an array of them. Invalid values are ignored.
+func Locations(v any) []*Geometry {
+	switch v := v.(type) {
+	case *types.Document:
+		if v.Has("type") {
+			g, err := ParseGeoJSON(v)
+			if err != nil {
+				return nil
+			}
+
+			return []*Geometry{g}
+		}
+
+		if p, ok := LegacyPoint(v); ok {
+			return []*Geometry{{Points: []Point{p}}}
+		}
+
+	case *types.Array:
+		if p, ok := LegacyPoint(v); ok {
+			return []*Geometry{{Points: []Point{p}}}
+		}
+
+		var res []*Geometry
+
+		for _, e := range must.NotFail(iterator.ConsumeValues(v.Iterator())) {
+			switch e.(type) {
+			case *types.Document, *types.Array:
+				res = append(res, Locations(e)...)
+			}
+		}
+
+		return res
+	}
+
+	return nil
+}
+
+// ParseGeoJSON parses the given GeoJSON object.
+//
+// Supported types are Point, LineString, Polygon, MultiPoint, MultiLineString, MultiPolygon,
+// and GeometryCollection.
+func ParseGeoJSON(doc *types.Document) (*Geometry, error) {
+	v, _ := doc.Get("type")
+
+	typ, ok := v.(string)
+	if !ok {
+		return nil, errors.New("unknown GeoJSON type")
+	}
+
+	var res Geometry
+
+	if typ == "GeometryCollection" {
+		v, _ = doc.Get("geometries")
+
+		geometries, ok := v.(*types.Array)
+		if !ok {
+			return nil, errors.New("geometries of GeometryCollection must be an array")
+		}
+
+		for _, gv := range must.NotFail(iterator.ConsumeValues(geometries.Iterator())) {
+			gd, ok := gv.(*types.Document)
+			if !ok {
+				return nil, errors.New("element of geometries of GeometryCollection must be an object")
+			}
+
+			g, err := ParseGeoJSON(gd)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Points = append(res.Points, g.Points...)
+			res.Lines = append(res.Lines, g.Lines...)
+			res.Polygons = append(res.Polygons, g.Polygons...)
+		}
+
+		return &res, nil
+	}
+
+	coordinates, _ := doc.Get("coordinates")
+
+	var err error
+
+	switch typ {
+	case "Point":
+		var p Point
+		if p, err = parsePoint(coordinates); err == nil {
+			res.Points = []Point{p}
+		}
+
+	case "MultiPoint":
+		res.Points, err = parsePoints(coordinates, 0)
+
+	case "LineString":
+		var l []Point
+		if l, err = parsePoints(coordinates, 2); err == nil {
+			res.Lines = [][]Point{l}
+		}
+
+	case "MultiLineString":
+		err = forEachArray(coordinates, func(v any) error {
+			l, err := parsePoints(v, 2)
+			res.Lines = append(res.Lines, l)
+
+			return err
+		})
+
+	case "Polygon":
+		var p Polygon
+		if p, err = parsePolygon(coordinates); err == nil {
+			res.Polygons = []Polygon{p}
+		}
+
+	case "MultiPolygon":
+		err = forEachArray(coordinates, func(v any) error {
+			p, err := parsePolygon(v)
+			res.Polygons = append(res.Polygons, p)
+
+			return err
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown GeoJSON type: %s", types.FormatAnyValue(doc))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// forEachArray calls f for each element of the given array.
+func forEachArray(v any, f func(any) error) error {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return errors.New("coordinates must be an array")
+	}
+
+	for _, e := range must.NotFail(iterator.ConsumeValues(arr.Iterator())) {
+		if err := f(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parsePoint parses GeoJSON position.
+func parsePoint(v any) (Point, error) {
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() < 2 {
+		return Point{}, errors.New("point must be an array of at least two numbers")
+	}
+
+	p, ok := LegacyPoint(arr)
+	if !ok {
+		return Point{}, errors.New("point coordinates must be numbers")
+	}
+
+	if p.X < -180 || p.X > 180 || p.Y < -90 || p.Y > 90 {
+		return Point{}, fmt.Errorf("longitude/latitude is out of bounds, lng: %g lat: %g", p.X, p.Y)
+	}
+
+	return p, nil
+}
+
+// parsePoints parses an array of GeoJSON positions of at least minLen length.
+func parsePoints(v any, minLen int) ([]Point, error) {
+	var res []Point
+
+	err := forEachArray(v, func(e any) error {
+		p, err := parsePoint(e)
+		res = append(res, p)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) < minLen {
+		return nil, fmt.Errorf("line must have at least %d vertices", minLen)
+	}
+
+	return res, nil
+}
+
+// parsePolygon parses an array of GeoJSON linear rings.
+func parsePolygon(v any) (Polygon, error) {
+	var res Polygon
+
+	err := forEachArray(v, func(e any) error {
+		l, err := parsePoints(e, 0)
+		if err != nil {
+			return err
+		}
+
+		if len(l) < 4 {
+			return errors.New("loop must have at least 4 vertices")
+		}
+
+		if l[0] != l[len(l)-1] {
+			return errors.New("loop is not closed, first vertex does not equal last vertex")
+		}
+
+		res = append(res, l)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("polygon has no loops")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// point returns GeoJSON Point document.
+func point(x, y float64) *types.Document {
+	return must.NotFail(types.NewDocument("type", "Point", "coordinates", must.NotFail(types.NewArray(x, y))))
+}
+
+// square returns GeoJSON Polygon document of the square with the given corners.
+func square(lo, hi float64) *types.Document {
+	ring := must.NotFail(types.NewArray(
+		must.NotFail(types.NewArray(lo, lo)),
+		must.NotFail(types.NewArray(hi, lo)),
+		must.NotFail(types.NewArray(hi, hi)),
+		must.NotFail(types.NewArray(lo, hi)),
+		must.NotFail(types.NewArray(lo, lo)),
+	))
+
+	return must.NotFail(types.NewDocument("type", "Polygon", "coordinates", must.NotFail(types.NewArray(ring))))
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	g, err := ParseGeoJSON(point(1, 2))
+	require.NoError(t, err)
+	assert.True(t, g.IsPoint())
+	assert.Equal(t, Point{X: 1, Y: 2}, g.Points[0])
+
+	g, err = ParseGeoJSON(square(0, 10))
+	require.NoError(t, err)
+	require.Len(t, g.Polygons, 1)
+	lo, hi := g.BoundingBox()
+	assert.Equal(t, Point{X: 0, Y: 0}, lo)
+	assert.Equal(t, Point{X: 10, Y: 10}, hi)
+
+	_, err = ParseGeoJSON(point(200, 0))
+	assert.EqualError(t, err, "longitude/latitude is out of bounds, lng: 200 lat: 0")
+
+	open := must.NotFail(types.NewArray(
+		must.NotFail(types.NewArray(0.0, 0.0)),
+		must.NotFail(types.NewArray(1.0, 0.0)),
+		must.NotFail(types.NewArray(1.0, 1.0)),
+		must.NotFail(types.NewArray(0.0, 1.0)),
+	))
+	_, err = ParseGeoJSON(must.NotFail(types.NewDocument(
+		"type", "Polygon", "coordinates", must.NotFail(types.NewArray(open)),
+	)))
+	assert.EqualError(t, err, "loop is not closed, first vertex does not equal last vertex")
+
+	_, err = ParseGeoJSON(must.NotFail(types.NewDocument("type", "Circle")))
+	assert.Error(t, err)
+}
+
+func TestLocations(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, Locations(must.NotFail(types.NewArray(1.0, 2.0))), 1)
+	assert.Len(t, Locations(must.NotFail(types.NewDocument("lng", int32(1), "lat", int32(2)))), 1)
+	assert.Len(t, Locations(must.NotFail(types.NewArray(point(1, 2), point(3, 4)))), 2)
+	assert.Empty(t, Locations("foo"))
+	assert.Empty(t, Locations(point(200, 0)))
+}
+
+func TestSphereDistance(t *testing.T) {
+	t.Parallel()
+
+	// Paris to London is about 344 km
+	d := SphereDistance(Point{X: 2.3522, Y: 48.8566}, Point{X: -0.1276, Y: 51.5072}) * EarthRadius
+	assert.InDelta(t, 344_000, d, 2_000)
+
+	assert.InDelta(t, math.Pi, SphereDistance(Point{X: 0, Y: 0}, Point{X: 180, Y: 0}), 1e-12)
+}
+
+func TestWithin(t *testing.T) {
+	t.Parallel()
+
+	sq := SpherePolygons(must.NotFail(ParseGeoJSON(square(0, 10))).Polygons)
+
+	for name, tc := range map[string]struct {
+		shape    Shape
+		g        *Geometry
+		expected bool
+	}{
+		"SphereInside":     {sq, &Geometry{Points: []Point{{X: 5, Y: 5}}}, true},
+		"SphereBoundary":   {sq, &Geometry{Points: []Point{{X: 0, Y: 5}}}, true},
+		"SphereOutside":    {sq, &Geometry{Points: []Point{{X: 11, Y: 5}}}, false},
+		"SphereLineInside": {sq, &Geometry{Lines: [][]Point{{{X: 1, Y: 1}, {X: 9, Y: 9}}}}, true},
+		"SphereLineCross":  {sq, &Geometry{Lines: [][]Point{{{X: 5, Y: 5}, {X: 15, Y: 5}}}}, false},
+		"Box":              {&Box{Lo: Point{X: 0, Y: 0}, Hi: Point{X: 2, Y: 2}}, &Geometry{Points: []Point{{X: 2, Y: 1}}}, true},
+		"BoxOutside":       {&Box{Lo: Point{X: 0, Y: 0}, Hi: Point{X: 2, Y: 2}}, &Geometry{Points: []Point{{X: 3, Y: 1}}}, false},
+		"Polygon": {
+			FlatPolygon{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}},
+			&Geometry{Points: []Point{{X: 1, Y: 1}}},
+			true,
+		},
+		"PolygonOutside": {
+			FlatPolygon{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}},
+			&Geometry{Points: []Point{{X: 3, Y: 3}}},
+			false,
+		},
+		"Circle":        {&Circle{Center: Point{X: 0, Y: 0}, Radius: 1}, &Geometry{Points: []Point{{X: 0, Y: 1}}}, true},
+		"CircleOutside": {&Circle{Center: Point{X: 0, Y: 0}, Radius: 1}, &Geometry{Points: []Point{{X: 1, Y: 1}}}, false},
+		"SphereCircle": {
+			&SphereCircle{Center: Point{X: 0, Y: 0}, Radius: 0.1},
+			&Geometry{Points: []Point{{X: 5, Y: 0}}},
+			true,
+		},
+		"SphereCircleOutside": {
+			&SphereCircle{Center: Point{X: 0, Y: 0}, Radius: 0.1},
+			&Geometry{Points: []Point{{X: 6, Y: 0}}},
+			false,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.shape.Contains(tc.g))
+		})
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	t.Parallel()
+
+	sq := must.NotFail(ParseGeoJSON(square(0, 10)))
+
+	assert.True(t, Intersects(sq, must.NotFail(ParseGeoJSON(square(5, 15)))))
+	assert.False(t, Intersects(sq, must.NotFail(ParseGeoJSON(square(20, 30)))))
+	assert.True(t, Intersects(sq, &Geometry{Lines: [][]Point{{{X: -5, Y: 5}, {X: 15, Y: 5}}}}))
+	assert.True(t, Intersects(&Geometry{Points: []Point{{X: 1, Y: 1}}}, sq))
+}
+
+func TestNear(t *testing.T) {
+	t.Parallel()
+
+	n := &Near{Center: Point{X: 0, Y: 0}, Spherical: true, Meters: true, MaxDistance: 5_000}
+
+	d, ok := n.Distance([]*Geometry{{Points: []Point{{X: 0.03, Y: 0}}}})
+	require.True(t, ok)
+	assert.InDelta(t, 3_339, d, 1)
+	assert.True(t, n.Matches(d))
+
+	d, ok = n.Distance([]*Geometry{{Points: []Point{{X: 0.05, Y: 0}}}})
+	require.True(t, ok)
+	assert.False(t, n.Matches(d))
+
+	lo, hi, ok := n.Bounds()
+	require.True(t, ok)
+	assert.InDelta(t, -0.045, lo.X, 0.001)
+	assert.InDelta(t, 0.045, hi.Y, 0.001)
+
+	_, ok = n.Distance(nil)
+	assert.False(t, ok)
+
+	flat := &Near{Center: Point{X: 0, Y: 0}, MaxDistance: math.Inf(1)}
+	d, ok = flat.Distance([]*Geometry{{Points: []Point{{X: 3, Y: 4}}}})
+	require.True(t, ok)
+	assert.Equal(t, 5.0, d)
+
+	_, _, ok = flat.Bounds()
+	assert.False(t, ok)
+}
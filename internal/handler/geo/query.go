@@ -0,0 +1,339 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"math"
+)
+
+// Shape represents a shape of `$geoWithin` query operator.
+type Shape interface {
+	// Contains returns true if the given geometry lies within the shape.
+	Contains(g *Geometry) bool
+
+	// Bounds returns the bounding box of the shape, or false if there is no such box
+	// (for example, if the shape crosses the antimeridian).
+	Bounds() (lo, hi Point, ok bool)
+}
+
+// Box represents `$box` legacy shape.
+type Box struct {
+	Lo, Hi Point
+}
+
+// Contains implements Shape interface.
+func (b *Box) Contains(g *Geometry) bool {
+	for _, p := range g.vertices() {
+		if p.X < b.Lo.X || p.X > b.Hi.X || p.Y < b.Lo.Y || p.Y > b.Hi.Y {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bounds implements Shape interface.
+func (b *Box) Bounds() (lo, hi Point, ok bool) {
+	return b.Lo, b.Hi, true
+}
+
+// FlatPolygon represents `$polygon` legacy shape.
+type FlatPolygon []Point
+
+// Contains implements Shape interface.
+func (fp FlatPolygon) Contains(g *Geometry) bool {
+	for _, p := range g.vertices() {
+		if !flatPolygonContains(fp, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bounds implements Shape interface.
+func (fp FlatPolygon) Bounds() (lo, hi Point, ok bool) {
+	lo, hi = boundingBox(fp)
+	return lo, hi, true
+}
+
+// Circle represents `$center` legacy shape.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// Contains implements Shape interface.
+func (c *Circle) Contains(g *Geometry) bool {
+	for _, p := range g.vertices() {
+		if FlatDistance(c.Center, p) > c.Radius {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bounds implements Shape interface.
+func (c *Circle) Bounds() (lo, hi Point, ok bool) {
+	lo = Point{X: c.Center.X - c.Radius, Y: c.Center.Y - c.Radius}
+	hi = Point{X: c.Center.X + c.Radius, Y: c.Center.Y + c.Radius}
+
+	return lo, hi, true
+}
+
+// SphereCircle represents `$centerSphere` shape; Radius is in radians.
+type SphereCircle struct {
+	Center Point
+	Radius float64
+}
+
+// Contains implements Shape interface.
+func (c *SphereCircle) Contains(g *Geometry) bool {
+	for _, p := range g.vertices() {
+		if SphereDistance(c.Center, p) > c.Radius {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bounds implements Shape interface.
+func (c *SphereCircle) Bounds() (lo, hi Point, ok bool) {
+	return capBounds(c.Center, c.Radius)
+}
+
+// SpherePolygons represents GeoJSON Polygon or MultiPolygon of `$geometry` shape.
+type SpherePolygons []Polygon
+
+// Contains implements Shape interface.
+//
+// All points of the geometry should lie within polygons, and its edges should not cross polygon boundaries.
+func (sp SpherePolygons) Contains(g *Geometry) bool {
+	polygons := toSphere(&Geometry{Polygons: sp})
+	s := toSphere(g)
+
+	for _, v := range s.vertices() {
+		if !polygons.covers(v) {
+			return false
+		}
+	}
+
+	for _, x := range s.arcs {
+		for _, y := range polygons.arcs {
+			if arcsCross(x[0], x[1], y[0], y[1]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Bounds implements Shape interface.
+func (sp SpherePolygons) Bounds() (lo, hi Point, ok bool) {
+	s := toSphere(&Geometry{Polygons: sp})
+
+	// polygons that contain a pole are not bounded by longitude
+	for _, pole := range []vec{{0, 0, 1}, {0, 0, -1}} {
+		if s.covers(pole) {
+			return Point{}, Point{}, false
+		}
+	}
+
+	var points []Point
+
+	for _, p := range sp {
+		for _, l := range p {
+			for i := 0; i < len(l)-1; i++ {
+				// edge crosses the antimeridian
+				if math.Abs(l[i+1].X-l[i].X) > 180 {
+					return Point{}, Point{}, false
+				}
+			}
+
+			points = append(points, l...)
+		}
+	}
+
+	lo, hi = boundingBox(points)
+
+	// great circle arcs could reach latitudes beyond their ends
+	for _, a := range s.arcs {
+		n := a[0].cross(a[1])
+		if n.norm() < epsilon {
+			continue
+		}
+
+		n = n.normalize()
+
+		top := vec{0, 0, 1}.add(n.scale(-n[2]))
+		if top.norm() < epsilon {
+			continue
+		}
+
+		top = top.normalize()
+
+		for _, m := range []vec{top, top.scale(-1)} {
+			if a[0].cross(m).dot(n) >= 0 && m.cross(a[1]).dot(n) >= 0 {
+				lat := math.Asin(math.Max(-1, math.Min(1, m[2]))) * 180 / math.Pi
+				lo.Y, hi.Y = math.Min(lo.Y, lat), math.Max(hi.Y, lat)
+			}
+		}
+	}
+
+	return lo, hi, true
+}
+
+// capBounds returns the bounding box of the spherical cap with the given center and radius in radians.
+func capBounds(c Point, r float64) (lo, hi Point, ok bool) {
+	rd := r * 180 / math.Pi
+
+	lo.Y, hi.Y = c.Y-rd, c.Y+rd
+
+	if lo.Y <= -90 || hi.Y >= 90 {
+		return Point{X: -180, Y: math.Max(lo.Y, -90)}, Point{X: 180, Y: math.Min(hi.Y, 90)}, true
+	}
+
+	dLon := math.Asin(math.Sin(r)/math.Cos(c.Y*math.Pi/180)) * 180 / math.Pi
+
+	lo.X, hi.X = c.X-dLon, c.X+dLon
+
+	if lo.X < -180 || hi.X > 180 {
+		return Point{}, Point{}, false
+	}
+
+	return lo, hi, true
+}
+
+// FlatDistance returns the Euclidean distance between the given points.
+func FlatDistance(a, b Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// flatPolygonContains returns true if p lies inside the flat polygon or on its boundary.
+func flatPolygonContains(polygon []Point, p Point) bool {
+	var inside bool
+
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[j], polygon[i]
+
+		if onSegment(p, a, b) {
+			return true
+		}
+
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// onSegment returns true if p lies on flat segment ab.
+func onSegment(p, a, b Point) bool {
+	cross := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+	if math.Abs(cross) > 1e-12*math.Max(1, FlatDistance(a, b)) {
+		return false
+	}
+
+	return p.X >= math.Min(a.X, b.X) && p.X <= math.Max(a.X, b.X) &&
+		p.Y >= math.Min(a.Y, b.Y) && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// Near represents a search of geometries near the point,
+// used by `$near` and `$nearSphere` query operators and `$geoNear` aggregation stage.
+type Near struct {
+	Center Point
+
+	// Spherical is true for spherical geometry; flat geometry is used otherwise.
+	Spherical bool
+
+	// Meters is true if spherical distances are in meters; they are in radians otherwise.
+	Meters bool
+
+	MinDistance float64
+	MaxDistance float64 // +Inf if not limited
+}
+
+// Distance returns the distance from the center to the closest of the given geometries,
+// or false if there are no geometries.
+func (n *Near) Distance(locations []*Geometry) (float64, bool) {
+	if len(locations) == 0 {
+		return 0, false
+	}
+
+	res := math.Inf(1)
+
+	if !n.Spherical {
+		for _, g := range locations {
+			for _, p := range g.vertices() {
+				res = math.Min(res, FlatDistance(n.Center, p))
+			}
+		}
+
+		return res, true
+	}
+
+	c := toVec(n.Center)
+
+	for _, g := range locations {
+		res = math.Min(res, toSphere(g).distance(c))
+	}
+
+	if n.Meters {
+		res *= EarthRadius
+	}
+
+	return res, true
+}
+
+// Matches returns true if the given distance is within limits.
+func (n *Near) Matches(distance float64) bool {
+	return distance >= n.MinDistance && distance <= n.MaxDistance
+}
+
+// Bounds returns the bounding box of all points within the maximal distance,
+// or false if there is no such box.
+func (n *Near) Bounds() (lo, hi Point, ok bool) {
+	if math.IsInf(n.MaxDistance, 1) {
+		return Point{}, Point{}, false
+	}
+
+	if !n.Spherical {
+		return (&Circle{Center: n.Center, Radius: n.MaxDistance}).Bounds()
+	}
+
+	r := n.MaxDistance
+	if n.Meters {
+		r /= EarthRadius
+	}
+
+	if r >= math.Pi {
+		return Point{}, Point{}, false
+	}
+
+	return capBounds(n.Center, r)
+}
+
+// check interfaces
+var (
+	_ Shape = (*Box)(nil)
+	_ Shape = FlatPolygon(nil)
+	_ Shape = (*Circle)(nil)
+	_ Shape = (*SphereCircle)(nil)
+	_ Shape = SpherePolygons(nil)
+)
@@ -0,0 +1,344 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"math"
+)
+
+// epsilon is the tolerance of spherical computations on unit vectors.
+const epsilon = 1e-12
+
+// vec represents a point on the unit sphere as a 3D vector.
+type vec [3]float64
+
+// toVec returns the unit vector for the given longitude/latitude point.
+func toVec(p Point) vec {
+	lon, lat := p.X*math.Pi/180, p.Y*math.Pi/180
+
+	return vec{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+func (a vec) add(b vec) vec {
+	return vec{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func (a vec) scale(k float64) vec {
+	return vec{a[0] * k, a[1] * k, a[2] * k}
+}
+
+func (a vec) dot(b vec) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func (a vec) cross(b vec) vec {
+	return vec{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func (a vec) norm() float64 {
+	return math.Sqrt(a.dot(a))
+}
+
+func (a vec) normalize() vec {
+	return a.scale(1 / a.norm())
+}
+
+// angle returns the angle between the given unit vectors in radians.
+func (a vec) angle(b vec) float64 {
+	return math.Atan2(a.cross(b).norm(), a.dot(b))
+}
+
+// SphereDistance returns the great circle distance between the given points in radians.
+func SphereDistance(a, b Point) float64 {
+	lat1, lat2 := a.Y*math.Pi/180, b.Y*math.Pi/180
+	dLat, dLon := lat2-lat1, (b.X-a.X)*math.Pi/180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// arcsCross returns true if great circle arcs ab and cd cross at a point interior to both.
+//
+// Both arcs should be shorter than a half of the great circle.
+func arcsCross(a, b, c, d vec) bool {
+	ab := a.cross(b)
+	acb := -ab.dot(c)
+	bda := ab.dot(d)
+
+	if acb*bda <= 0 {
+		return false
+	}
+
+	cd := c.cross(d)
+	cbd := -cd.dot(b)
+	dac := cd.dot(a)
+
+	return acb*cbd > 0 && acb*dac > 0
+}
+
+// onArc returns true if p lies on great circle arc ab.
+func onArc(p, a, b vec) bool {
+	n := a.cross(b)
+	if n.norm() < epsilon {
+		return p.angle(a) < 1e-9
+	}
+
+	n = n.normalize()
+
+	if math.Abs(p.dot(n)) > 1e-9 {
+		return false
+	}
+
+	return a.cross(p).dot(n) >= -epsilon && p.cross(b).dot(n) >= -epsilon
+}
+
+// arcDistance returns the distance from p to great circle arc ab in radians.
+func arcDistance(p, a, b vec) float64 {
+	n := a.cross(b)
+	if n.norm() >= epsilon {
+		n = n.normalize()
+
+		// the closest point of the great circle lies within the arc
+		if a.cross(p).dot(n) >= 0 && p.cross(b).dot(n) >= 0 {
+			return math.Asin(math.Min(1, math.Abs(p.dot(n))))
+		}
+	}
+
+	return math.Min(p.angle(a), p.angle(b))
+}
+
+// loopVecs returns unit vectors of the loop's points.
+func loopVecs(l Loop) []vec {
+	res := make([]vec, len(l))
+	for i, p := range l {
+		res[i] = toVec(p)
+	}
+
+	return res
+}
+
+// onLoop returns true if p lies on the boundary of the loop.
+func onLoop(vs []vec, p vec) bool {
+	for i := 0; i < len(vs)-1; i++ {
+		if onArc(p, vs[i], vs[i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loopContains returns true if p lies inside the loop or on its boundary.
+//
+// The loop should be smaller than a hemisphere, as MongoDB requires for polygons.
+// Crossings of the arc from p to a point outside the loop are counted;
+// the antipode of the loop's centroid is used as such point.
+func loopContains(vs []vec, p vec) bool {
+	if onLoop(vs, p) {
+		return true
+	}
+
+	var centroid vec
+	for _, v := range vs[:len(vs)-1] {
+		centroid = centroid.add(v)
+	}
+
+	if centroid.norm() < epsilon {
+		return false
+	}
+
+	outside := centroid.normalize().scale(-1)
+
+	// move the outside point a bit, so the arc is not a half of the great circle
+	// and does not pass through loop vertices
+	ortho := outside.cross(vec{0, 0, 1})
+	if ortho.norm() < epsilon {
+		ortho = outside.cross(vec{1, 0, 0})
+	}
+
+	ortho = ortho.normalize()
+
+	for attempt := 0; ; attempt++ {
+		ref := outside
+		if attempt > 0 || p.dot(ref) < -1+1e-9 {
+			ref = outside.add(ortho.scale(1e-3 * float64(attempt+1))).normalize()
+		}
+
+		var crossings int
+		var degenerate bool
+
+		for i := 0; i < len(vs)-1; i++ {
+			if onArc(vs[i], p, ref) {
+				degenerate = true
+				break
+			}
+
+			if arcsCross(p, ref, vs[i], vs[i+1]) {
+				crossings++
+			}
+		}
+
+		if !degenerate || attempt > 10 {
+			return crossings%2 == 1
+		}
+	}
+}
+
+// polygonVecs represents a polygon as unit vectors.
+type polygonVecs [][]vec
+
+// toPolygonVecs returns unit vectors of the polygon's loops.
+func toPolygonVecs(p Polygon) polygonVecs {
+	res := make(polygonVecs, len(p))
+	for i, l := range p {
+		res[i] = loopVecs(l)
+	}
+
+	return res
+}
+
+// contains returns true if p lies inside the polygon or on its boundary.
+func (pv polygonVecs) contains(p vec) bool {
+	if !loopContains(pv[0], p) {
+		return false
+	}
+
+	for _, hole := range pv[1:] {
+		if loopContains(hole, p) && !onLoop(hole, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sphereGeometry represents a geometry as unit vectors.
+type sphereGeometry struct {
+	points   []vec
+	arcs     [][2]vec // arcs of lines and polygon loops
+	polygons []polygonVecs
+}
+
+// toSphere returns unit vectors of the geometry.
+func toSphere(g *Geometry) *sphereGeometry {
+	var res sphereGeometry
+
+	for _, p := range g.Points {
+		res.points = append(res.points, toVec(p))
+	}
+
+	addArcs := func(vs []vec) {
+		for i := 0; i < len(vs)-1; i++ {
+			res.arcs = append(res.arcs, [2]vec{vs[i], vs[i+1]})
+		}
+	}
+
+	for _, l := range g.Lines {
+		addArcs(loopVecs(l))
+	}
+
+	for _, p := range g.Polygons {
+		pv := toPolygonVecs(p)
+		res.polygons = append(res.polygons, pv)
+
+		for _, l := range pv {
+			addArcs(l)
+		}
+	}
+
+	return &res
+}
+
+// vertices returns all points and ends of arcs.
+func (s *sphereGeometry) vertices() []vec {
+	res := append([]vec{}, s.points...)
+	for _, a := range s.arcs {
+		res = append(res, a[0], a[1])
+	}
+
+	return res
+}
+
+// covers returns true if the given point lies on the geometry.
+func (s *sphereGeometry) covers(p vec) bool {
+	for _, q := range s.points {
+		if p.angle(q) < 1e-9 {
+			return true
+		}
+	}
+
+	for _, a := range s.arcs {
+		if onArc(p, a[0], a[1]) {
+			return true
+		}
+	}
+
+	for _, pv := range s.polygons {
+		if pv.contains(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// distance returns the distance from the given point to the geometry in radians.
+func (s *sphereGeometry) distance(p vec) float64 {
+	res := math.Inf(1)
+
+	for _, pv := range s.polygons {
+		if pv.contains(p) {
+			return 0
+		}
+	}
+
+	for _, q := range s.points {
+		res = math.Min(res, p.angle(q))
+	}
+
+	for _, a := range s.arcs {
+		res = math.Min(res, arcDistance(p, a[0], a[1]))
+	}
+
+	return res
+}
+
+// Intersects returns true if the given geometries have a common point, using spherical geometry.
+func Intersects(a, b *Geometry) bool {
+	sa, sb := toSphere(a), toSphere(b)
+
+	for _, v := range sa.vertices() {
+		if sb.covers(v) {
+			return true
+		}
+	}
+
+	for _, v := range sb.vertices() {
+		if sa.covers(v) {
+			return true
+		}
+	}
+
+	for _, x := range sa.arcs {
+		for _, y := range sb.arcs {
+			if arcsCross(x[0], x[1], y[0], y[1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -16,6 +16,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
@@ -39,6 +41,10 @@ type Handler struct {
 
 	cursors  *cursor.Registry
 	commands map[string]command
+
+	ttl *ttlMonitor // nil if disabled
+
+	indexUsage *indexUsage
 }
 
 // NewOpts represents handler configuration.
@@ -51,6 +57,23 @@ type NewOpts struct {
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
 
+	// GroupMemoryLimit is the approximate memory limit of `$group` aggregation stage, in bytes.
+	// Zero means no limit.
+	GroupMemoryLimit int64
+
+	// SortMemoryLimit is the approximate memory limit of sorting in `find`, `findAndModify`
+	// and `$sort` aggregation stage, in bytes.
+	// Zero means no limit.
+	SortMemoryLimit int64
+
+	// SpillDir is the directory for temporary files of stages that exceed memory limits.
+	// The default directory for temporary files is used if it is empty.
+	SpillDir string
+
+	// TTLMonitorInterval is the interval between TTL monitor passes that delete expired documents.
+	// Zero disables the monitor.
+	TTLMonitorInterval time.Duration
+
 	// test options
 	DisableFilterPushdown bool
 	EnableOplog           bool
@@ -69,6 +92,12 @@ func New(opts *NewOpts) (*Handler, error) {
 		b:       b,
 		NewOpts: opts,
 		cursors: cursor.NewRegistry(opts.L.Named("cursors")),
+
+		indexUsage: newIndexUsage(),
+	}
+
+	if opts.TTLMonitorInterval > 0 {
+		h.ttl = newTTLMonitor(b, opts.L.Named("ttl"), opts.TTLMonitorInterval)
 	}
 
 	h.initCommands()
@@ -79,6 +108,10 @@ func New(opts *NewOpts) (*Handler, error) {
 // Close gracefully shutdowns handler.
 // It should be called after listener closes all client connections and stops listening.
 func (h *Handler) Close() {
+	if h.ttl != nil {
+		h.ttl.close()
+	}
+
 	h.cursors.Close()
 }
 
@@ -86,10 +119,18 @@ func (h *Handler) Close() {
 func (h *Handler) Describe(ch chan<- *prometheus.Desc) {
 	h.b.Describe(ch)
 	h.cursors.Describe(ch)
+
+	if h.ttl != nil {
+		h.ttl.Describe(ch)
+	}
 }
 
 // Collect implements prometheus.Collector interface.
 func (h *Handler) Collect(ch chan<- prometheus.Metric) {
 	h.b.Collect(ch)
 	h.cursors.Collect(ch)
+
+	if h.ttl != nil {
+		h.ttl.Collect(ch)
+	}
 }
@@ -26,9 +26,10 @@ import (
 type CommandError struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
-	err  error
-	info *ErrInfo
-	code ErrorCode
+	err     error
+	info    *ErrInfo
+	errInfo *types.Document
+	code    ErrorCode
 }
 
 // There should not be NewCommandError function variant that accepts printf-like format specifiers.
@@ -66,6 +67,16 @@ func NewCommandErrorMsgWithArgument(code ErrorCode, msg string, argument string)
 	}
 }
 
+// NewCommandErrorMsgWithErrInfo creates a new wire protocol error
+// with additional error details returned to the client as `errInfo`.
+func NewCommandErrorMsgWithErrInfo(code ErrorCode, msg string, errInfo *types.Document) error {
+	return &CommandError{
+		code:    code,
+		err:     errors.New(msg),
+		errInfo: errInfo,
+	}
+}
+
 // Err returns original error.
 //
 // It is not called Unwrap to prevent unwrapping by errors.Is and errors.As.
@@ -96,6 +107,10 @@ func (e *CommandError) Document() *types.Document {
 		d.Set("codeName", e.code.String())
 	}
 
+	if e.errInfo != nil {
+		d.Set("errInfo", e.errInfo)
+	}
+
 	return d
 }
 
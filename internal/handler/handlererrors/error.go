@@ -108,6 +108,18 @@ const (
 	// ErrDocumentValidationFailure indicates that document validation failed.
 	ErrDocumentValidationFailure = ErrorCode(121) // DocumentValidationFailure
 
+	// ErrViewDepthLimitExceeded indicates that the view is defined on too many nested views.
+	ErrViewDepthLimitExceeded = ErrorCode(149) // ViewDepthLimitExceeded
+
+	// ErrGraphContainsCycle indicates that views are defined on each other.
+	ErrGraphContainsCycle = ErrorCode(160) // GraphContainsCycle
+
+	// ErrCommandNotSupportedOnView indicates that the command is not supported on views.
+	ErrCommandNotSupportedOnView = ErrorCode(166) // CommandNotSupportedOnView
+
+	// ErrOptionNotSupportedOnView indicates that the option is not supported on views.
+	ErrOptionNotSupportedOnView = ErrorCode(167) // OptionNotSupportedOnView
+
 	// ErrInvalidIndexSpecificationOption indicates that the index option is invalid.
 	ErrInvalidIndexSpecificationOption = ErrorCode(197) // InvalidIndexSpecificationOption
 
@@ -117,9 +129,24 @@ const (
 	// ErrClientMetadataCannotBeMutated indicates that client metadata cannot be mutated.
 	ErrClientMetadataCannotBeMutated = ErrorCode(186) // ClientMetadataCannotBeMutated
 
+	// ErrQueryFeatureNotAllowed indicates that the query feature is not allowed in this context.
+	ErrQueryFeatureNotAllowed = ErrorCode(224) // QueryFeatureNotAllowed
+
 	// ErrNotImplemented indicates that a flag or command is not implemented.
 	ErrNotImplemented = ErrorCode(238) // NotImplemented
 
+	// ErrNoQueryExecutionPlans indicates that the query could not be planned,
+	// for example, because the required index is missing.
+	ErrNoQueryExecutionPlans = ErrorCode(291) // NoQueryExecutionPlans
+
+	// ErrQueryExceededMemoryLimitNoDiskUseAllowed indicates that the memory limit was exceeded
+	// while using disk was not allowed.
+	ErrQueryExceededMemoryLimitNoDiskUseAllowed = ErrorCode(292) // QueryExceededMemoryLimitNoDiskUseAllowed
+
+	// ErrCannotConvertIndexToUnique indicates that the index can't be converted to unique one
+	// because of duplicate keys.
+	ErrCannotConvertIndexToUnique = ErrorCode(359) // CannotConvertIndexToUnique
+
 	// ErrIndexesWrongType indicates that indexes parameter has wrong type.
 	ErrIndexesWrongType = ErrorCode(10065) // Location10065
 
@@ -190,6 +217,9 @@ const (
 	// ErrSliceFirstArg for $slice indicates that the first argument is not an array.
 	ErrSliceFirstArg = ErrorCode(28724) // Location28724
 
+	// ErrStageIndexStatsInvalidSpec indicates that $indexStats stage specification is not an empty object.
+	ErrStageIndexStatsInvalidSpec = ErrorCode(28803) // Location28803
+
 	// ErrStageUnsetNoPath indicates that $unwind aggregation stage is empty.
 	ErrStageUnsetNoPath = ErrorCode(31119) // Location31119
 
@@ -248,6 +278,9 @@ const (
 	// amount of arguments.
 	ErrAddFieldsExpressionWrongAmountOfArgs = ErrorCode(40181) // Location40181
 
+	// ErrTextScoreNotAvailable indicates that text score metadata is used without `$text` query operator.
+	ErrTextScoreNotAvailable = ErrorCode(40218) // Location40218
+
 	// ErrStageGroupUnaryOperator indicates that $sum is a unary operator.
 	ErrStageGroupUnaryOperator = ErrorCode(40237) // Location40237
 
@@ -266,6 +299,9 @@ const (
 	// ErrInvalidFieldPath indicates that the field path is not valid.
 	ErrInvalidFieldPath = ErrorCode(40353) // Location40353
 
+	// ErrStageGroupMergeObjectsType indicates that $mergeObjects got non-document input.
+	ErrStageGroupMergeObjectsType = ErrorCode(40400) // Location40400
+
 	// ErrMissingField indicates that the required field in document is missing.
 	ErrMissingField = ErrorCode(40414) // Location40414
 
@@ -275,6 +311,9 @@ const (
 	// ErrCollStatsIsNotFirstStage indicates that $collStats must be the first stage in the pipeline.
 	ErrCollStatsIsNotFirstStage = ErrorCode(40602) // Location40602
 
+	// ErrGeoNearNotFirstStage indicates that $geoNear must be the first stage in the pipeline.
+	ErrGeoNearNotFirstStage = ErrorCode(40603) // Location40603
+
 	// ErrFreeMonitoringDisabled indicates that free monitoring is disabled
 	// by command-line or config file.
 	ErrFreeMonitoringDisabled = ErrorCode(50840) // Location50840
@@ -314,6 +353,30 @@ const (
 
 	// ErrStageCollStatsInvalidArg indicates invalid argument for the aggregation $collStats stage.
 	ErrStageCollStatsInvalidArg = ErrorCode(5447000) // Location5447000
+
+	// ErrStageGroupNInvalidSpec indicates that n-accumulator specification is not a document.
+	ErrStageGroupNInvalidSpec = ErrorCode(5787801) // Location5787801
+
+	// ErrStageGroupNUnknownArgument indicates unknown field in n-accumulator specification.
+	ErrStageGroupNUnknownArgument = ErrorCode(5787901) // Location5787901
+
+	// ErrStageGroupNNonIntegral indicates that 'n' of n-accumulator is not an integral number.
+	ErrStageGroupNNonIntegral = ErrorCode(5787902) // Location5787902
+
+	// ErrStageGroupNMissingN indicates that 'n' of n-accumulator is missing.
+	ErrStageGroupNMissingN = ErrorCode(5787906) // Location5787906
+
+	// ErrStageGroupNMissingInput indicates that 'input' of n-accumulator is missing.
+	ErrStageGroupNMissingInput = ErrorCode(5787907) // Location5787907
+
+	// ErrStageGroupNNonPositive indicates that 'n' of n-accumulator is not positive.
+	ErrStageGroupNNonPositive = ErrorCode(5787908) // Location5787908
+
+	// ErrStageGroupTopMissingOutput indicates that 'output' of $top/$bottom accumulator is missing.
+	ErrStageGroupTopMissingOutput = ErrorCode(5788004) // Location5788004
+
+	// ErrStageGroupTopMissingSortBy indicates that 'sortBy' of $top/$bottom accumulator is missing.
+	ErrStageGroupTopMissingSortBy = ErrorCode(5788005) // Location5788005
 )
 
 // ErrInfo represents additional optional error information.
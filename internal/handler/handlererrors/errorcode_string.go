@@ -35,10 +35,18 @@ func _() {
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
 	_ = x[ErrDocumentValidationFailure-121]
+	_ = x[ErrViewDepthLimitExceeded-149]
+	_ = x[ErrGraphContainsCycle-160]
+	_ = x[ErrCommandNotSupportedOnView-166]
+	_ = x[ErrOptionNotSupportedOnView-167]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidPipelineOperator-168]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
+	_ = x[ErrQueryFeatureNotAllowed-224]
 	_ = x[ErrNotImplemented-238]
+	_ = x[ErrNoQueryExecutionPlans-291]
+	_ = x[ErrQueryExceededMemoryLimitNoDiskUseAllowed-292]
+	_ = x[ErrCannotConvertIndexToUnique-359]
 	_ = x[ErrIndexesWrongType-10065]
 	_ = x[ErrDuplicateKeyInsert-11000]
 	_ = x[ErrSetBadExpression-40272]
@@ -62,6 +70,7 @@ func _() {
 	_ = x[ErrGroupUndefinedVariable-17276]
 	_ = x[ErrInvalidArg-28667]
 	_ = x[ErrSliceFirstArg-28724]
+	_ = x[ErrStageIndexStatsInvalidSpec-28803]
 	_ = x[ErrStageUnsetNoPath-31119]
 	_ = x[ErrStageUnsetArrElementInvalidType-31120]
 	_ = x[ErrStageUnsetInvalidType-31002]
@@ -80,15 +89,18 @@ func _() {
 	_ = x[ErrStageCountBadPrefix-40158]
 	_ = x[ErrStageCountBadValue-40160]
 	_ = x[ErrAddFieldsExpressionWrongAmountOfArgs-40181]
+	_ = x[ErrTextScoreNotAvailable-40218]
 	_ = x[ErrStageGroupUnaryOperator-40237]
 	_ = x[ErrStageGroupMultipleAccumulator-40238]
 	_ = x[ErrStageGroupInvalidAccumulator-40234]
 	_ = x[ErrStageInvalid-40323]
 	_ = x[ErrEmptyFieldPath-40352]
 	_ = x[ErrInvalidFieldPath-40353]
+	_ = x[ErrStageGroupMergeObjectsType-40400]
 	_ = x[ErrMissingField-40414]
 	_ = x[ErrFailedToParseInput-40415]
 	_ = x[ErrCollStatsIsNotFirstStage-40602]
+	_ = x[ErrGeoNearNotFirstStage-40603]
 	_ = x[ErrFreeMonitoringDisabled-50840]
 	_ = x[ErrValueNegative-51024]
 	_ = x[ErrRegexOptions-51075]
@@ -102,9 +114,17 @@ func _() {
 	_ = x[ErrStageSkipBadValue-5107200]
 	_ = x[ErrStageLimitInvalidArg-5107201]
 	_ = x[ErrStageCollStatsInvalidArg-5447000]
+	_ = x[ErrStageGroupNInvalidSpec-5787801]
+	_ = x[ErrStageGroupNUnknownArgument-5787901]
+	_ = x[ErrStageGroupNNonIntegral-5787902]
+	_ = x[ErrStageGroupNMissingN-5787906]
+	_ = x[ErrStageGroupNMissingInput-5787907]
+	_ = x[ErrStageGroupNNonPositive-5787908]
+	_ = x[ErrStageGroupTopMissingOutput-5788004]
+	_ = x[ErrStageGroupTopMissingSortBy-5788005]
 }
 
-const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsDollarPrefixedFieldNameInvalidIDEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedLocation10065Location11000Location15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location50840Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000"
+const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsDollarPrefixedFieldNameInvalidIDEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureViewDepthLimitExceededGraphContainsCycleCommandNotSupportedOnViewOptionNotSupportedOnViewInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionQueryFeatureNotAllowedNotImplementedNoQueryExecutionPlansQueryExceededMemoryLimitNoDiskUseAllowedCannotConvertIndexToUniqueLocation10065Location11000Location15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location17276Location28667Location28724Location28803Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40218Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40400Location40414Location40415Location40602Location40603Location50840Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000Location5787801Location5787901Location5787902Location5787906Location5787907Location5787908Location5788004Location5788005"
 
 var _ErrorCode_map = map[ErrorCode]string{
 	0:       _ErrorCode_name[0:5],
@@ -134,73 +154,93 @@ var _ErrorCode_map = map[ErrorCode]string{
 	86:      _ErrorCode_name[357:378],
 	96:      _ErrorCode_name[378:393],
 	121:     _ErrorCode_name[393:418],
-	168:     _ErrorCode_name[418:441],
-	186:     _ErrorCode_name[441:470],
-	197:     _ErrorCode_name[470:501],
-	238:     _ErrorCode_name[501:515],
-	10065:   _ErrorCode_name[515:528],
-	11000:   _ErrorCode_name[528:541],
-	15947:   _ErrorCode_name[541:554],
-	15948:   _ErrorCode_name[554:567],
-	15955:   _ErrorCode_name[567:580],
-	15958:   _ErrorCode_name[580:593],
-	15959:   _ErrorCode_name[593:606],
-	15969:   _ErrorCode_name[606:619],
-	15973:   _ErrorCode_name[619:632],
-	15974:   _ErrorCode_name[632:645],
-	15975:   _ErrorCode_name[645:658],
-	15976:   _ErrorCode_name[658:671],
-	15981:   _ErrorCode_name[671:684],
-	15983:   _ErrorCode_name[684:697],
-	15998:   _ErrorCode_name[697:710],
-	16020:   _ErrorCode_name[710:723],
-	16406:   _ErrorCode_name[723:736],
-	16410:   _ErrorCode_name[736:749],
-	16872:   _ErrorCode_name[749:762],
-	17276:   _ErrorCode_name[762:775],
-	28667:   _ErrorCode_name[775:788],
-	28724:   _ErrorCode_name[788:801],
-	28812:   _ErrorCode_name[801:814],
-	28818:   _ErrorCode_name[814:827],
-	31002:   _ErrorCode_name[827:840],
-	31119:   _ErrorCode_name[840:853],
-	31120:   _ErrorCode_name[853:866],
-	31249:   _ErrorCode_name[866:879],
-	31250:   _ErrorCode_name[879:892],
-	31253:   _ErrorCode_name[892:905],
-	31254:   _ErrorCode_name[905:918],
-	31324:   _ErrorCode_name[918:931],
-	31325:   _ErrorCode_name[931:944],
-	31394:   _ErrorCode_name[944:957],
-	31395:   _ErrorCode_name[957:970],
-	40156:   _ErrorCode_name[970:983],
-	40157:   _ErrorCode_name[983:996],
-	40158:   _ErrorCode_name[996:1009],
-	40160:   _ErrorCode_name[1009:1022],
-	40181:   _ErrorCode_name[1022:1035],
-	40234:   _ErrorCode_name[1035:1048],
-	40237:   _ErrorCode_name[1048:1061],
-	40238:   _ErrorCode_name[1061:1074],
-	40272:   _ErrorCode_name[1074:1087],
-	40323:   _ErrorCode_name[1087:1100],
-	40352:   _ErrorCode_name[1100:1113],
-	40353:   _ErrorCode_name[1113:1126],
-	40414:   _ErrorCode_name[1126:1139],
-	40415:   _ErrorCode_name[1139:1152],
-	40602:   _ErrorCode_name[1152:1165],
-	50840:   _ErrorCode_name[1165:1178],
-	51024:   _ErrorCode_name[1178:1191],
-	51075:   _ErrorCode_name[1191:1204],
-	51091:   _ErrorCode_name[1204:1217],
-	51108:   _ErrorCode_name[1217:1230],
-	51246:   _ErrorCode_name[1230:1243],
-	51247:   _ErrorCode_name[1243:1256],
-	51270:   _ErrorCode_name[1256:1269],
-	51272:   _ErrorCode_name[1269:1282],
-	4822819: _ErrorCode_name[1282:1297],
-	5107200: _ErrorCode_name[1297:1312],
-	5107201: _ErrorCode_name[1312:1327],
-	5447000: _ErrorCode_name[1327:1342],
+	149:     _ErrorCode_name[418:440],
+	160:     _ErrorCode_name[440:458],
+	166:     _ErrorCode_name[458:483],
+	167:     _ErrorCode_name[483:507],
+	168:     _ErrorCode_name[507:530],
+	186:     _ErrorCode_name[530:559],
+	197:     _ErrorCode_name[559:590],
+	224:     _ErrorCode_name[590:612],
+	238:     _ErrorCode_name[612:626],
+	291:     _ErrorCode_name[626:647],
+	292:     _ErrorCode_name[647:687],
+	359:     _ErrorCode_name[687:713],
+	10065:   _ErrorCode_name[713:726],
+	11000:   _ErrorCode_name[726:739],
+	15947:   _ErrorCode_name[739:752],
+	15948:   _ErrorCode_name[752:765],
+	15955:   _ErrorCode_name[765:778],
+	15958:   _ErrorCode_name[778:791],
+	15959:   _ErrorCode_name[791:804],
+	15969:   _ErrorCode_name[804:817],
+	15973:   _ErrorCode_name[817:830],
+	15974:   _ErrorCode_name[830:843],
+	15975:   _ErrorCode_name[843:856],
+	15976:   _ErrorCode_name[856:869],
+	15981:   _ErrorCode_name[869:882],
+	15983:   _ErrorCode_name[882:895],
+	15998:   _ErrorCode_name[895:908],
+	16020:   _ErrorCode_name[908:921],
+	16406:   _ErrorCode_name[921:934],
+	16410:   _ErrorCode_name[934:947],
+	16872:   _ErrorCode_name[947:960],
+	17276:   _ErrorCode_name[960:973],
+	28667:   _ErrorCode_name[973:986],
+	28724:   _ErrorCode_name[986:999],
+	28803:   _ErrorCode_name[999:1012],
+	28812:   _ErrorCode_name[1012:1025],
+	28818:   _ErrorCode_name[1025:1038],
+	31002:   _ErrorCode_name[1038:1051],
+	31119:   _ErrorCode_name[1051:1064],
+	31120:   _ErrorCode_name[1064:1077],
+	31249:   _ErrorCode_name[1077:1090],
+	31250:   _ErrorCode_name[1090:1103],
+	31253:   _ErrorCode_name[1103:1116],
+	31254:   _ErrorCode_name[1116:1129],
+	31324:   _ErrorCode_name[1129:1142],
+	31325:   _ErrorCode_name[1142:1155],
+	31394:   _ErrorCode_name[1155:1168],
+	31395:   _ErrorCode_name[1168:1181],
+	40156:   _ErrorCode_name[1181:1194],
+	40157:   _ErrorCode_name[1194:1207],
+	40158:   _ErrorCode_name[1207:1220],
+	40160:   _ErrorCode_name[1220:1233],
+	40181:   _ErrorCode_name[1233:1246],
+	40218:   _ErrorCode_name[1246:1259],
+	40234:   _ErrorCode_name[1259:1272],
+	40237:   _ErrorCode_name[1272:1285],
+	40238:   _ErrorCode_name[1285:1298],
+	40272:   _ErrorCode_name[1298:1311],
+	40323:   _ErrorCode_name[1311:1324],
+	40352:   _ErrorCode_name[1324:1337],
+	40353:   _ErrorCode_name[1337:1350],
+	40400:   _ErrorCode_name[1350:1363],
+	40414:   _ErrorCode_name[1363:1376],
+	40415:   _ErrorCode_name[1376:1389],
+	40602:   _ErrorCode_name[1389:1402],
+	40603:   _ErrorCode_name[1402:1415],
+	50840:   _ErrorCode_name[1415:1428],
+	51024:   _ErrorCode_name[1428:1441],
+	51075:   _ErrorCode_name[1441:1454],
+	51091:   _ErrorCode_name[1454:1467],
+	51108:   _ErrorCode_name[1467:1480],
+	51246:   _ErrorCode_name[1480:1493],
+	51247:   _ErrorCode_name[1493:1506],
+	51270:   _ErrorCode_name[1506:1519],
+	51272:   _ErrorCode_name[1519:1532],
+	4822819: _ErrorCode_name[1532:1547],
+	5107200: _ErrorCode_name[1547:1562],
+	5107201: _ErrorCode_name[1562:1577],
+	5447000: _ErrorCode_name[1577:1592],
+	5787801: _ErrorCode_name[1592:1607],
+	5787901: _ErrorCode_name[1607:1622],
+	5787902: _ErrorCode_name[1622:1637],
+	5787906: _ErrorCode_name[1637:1652],
+	5787907: _ErrorCode_name[1652:1667],
+	5787908: _ErrorCode_name[1667:1682],
+	5788004: _ErrorCode_name[1682:1697],
+	5788005: _ErrorCode_name[1697:1712],
 }
 
 func (i ErrorCode) String() string {
@@ -0,0 +1,95 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// getHintIndex returns the name of the collection's index specified by the `hint` parameter value,
+// or an empty string if no index is specified.
+//
+// The index could be specified by its name or key pattern; hidden indexes can't be used.
+// The `$natural` hint is accepted, but no index is used for it.
+// Hints for non-existent collections are accepted too, as such queries return nothing anyway.
+func getHintIndex(ctx context.Context, c backends.Collection, command string, hint any) (string, error) {
+	var name string
+	var key []backends.IndexKeyPair
+
+	switch hint := hint.(type) {
+	case nil:
+		return "", nil
+
+	case string:
+		if hint == "" {
+			return "", nil
+		}
+
+		name = hint
+
+	case *types.Document:
+		if hint.Len() == 0 || hint.Has("$natural") {
+			return "", nil
+		}
+
+		var err error
+		if key, err = processIndexKey(command, hint); err != nil {
+			return "", err
+		}
+
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"hint must be either a string or nested object",
+			command,
+		)
+	}
+
+	res, err := c.ListIndexes(ctx, nil)
+
+	switch {
+	case err == nil:
+		// search below
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		return "", nil
+	default:
+		return "", lazyerrors.Error(err)
+	}
+
+	for _, index := range res.Indexes {
+		if index.Hidden {
+			continue
+		}
+
+		if key == nil && index.Name == name {
+			return index.Name, nil
+		}
+
+		if key != nil && formatIndexKey(index.Key) == formatIndexKey(key) {
+			return index.Name, nil
+		}
+	}
+
+	return "", handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrBadValue,
+		"hint provided does not correspond to an existing index",
+		command,
+	)
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// indexUsageKey identifies the index of the collection.
+type indexUsageKey struct {
+	db         string
+	collection string
+	index      string
+}
+
+// indexAccesses contains usage counter of a single index.
+type indexAccesses struct {
+	ops   int64
+	since time.Time
+}
+
+// indexUsage tracks how many times each index was used by queries pushed down to the backend.
+//
+// Counters are kept in memory and start from zero when the handler starts or the index is created.
+type indexUsage struct {
+	start time.Time
+
+	rw sync.RWMutex
+	m  map[indexUsageKey]*indexAccesses
+}
+
+// newIndexUsage creates a new index usage tracker.
+func newIndexUsage() *indexUsage {
+	return &indexUsage{
+		start: time.Now(),
+		m:     make(map[indexUsageKey]*indexAccesses),
+	}
+}
+
+// record increments the counter of the given index.
+// It does nothing if the index name is empty, i.e. no index was used.
+func (u *indexUsage) record(db, collection, index string) {
+	if index == "" {
+		return
+	}
+
+	key := indexUsageKey{db: db, collection: collection, index: index}
+
+	u.rw.Lock()
+	defer u.rw.Unlock()
+
+	a := u.m[key]
+	if a == nil {
+		a = &indexAccesses{since: u.start}
+		u.m[key] = a
+	}
+
+	a.ops++
+}
+
+// get returns the counter of the given index and the time since it is counted.
+func (u *indexUsage) get(db, collection, index string) (int64, time.Time) {
+	u.rw.RLock()
+	defer u.rw.RUnlock()
+
+	a := u.m[indexUsageKey{db: db, collection: collection, index: index}]
+	if a == nil {
+		return 0, u.start
+	}
+
+	return a.ops, a.since
+}
+
+// reset starts counters of the given new indexes from zero.
+func (u *indexUsage) reset(db, collection string, indexes []string) {
+	now := time.Now()
+
+	u.rw.Lock()
+	defer u.rw.Unlock()
+
+	for _, index := range indexes {
+		u.m[indexUsageKey{db: db, collection: collection, index: index}] = &indexAccesses{since: now}
+	}
+}
+
+// forget removes counters of the given indexes of the collection,
+// of all its indexes if none are given,
+// or of all collections of the database if collection is empty.
+func (u *indexUsage) forget(db, collection string, indexes ...string) {
+	u.rw.Lock()
+	defer u.rw.Unlock()
+
+	for key := range u.m {
+		if key.db != db || (collection != "" && key.collection != collection) {
+			continue
+		}
+
+		if len(indexes) == 0 || slices.Contains(indexes, key.index) {
+			delete(u.m, key)
+		}
+	}
+}
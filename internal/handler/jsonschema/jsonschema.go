@@ -0,0 +1,615 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema implements `$jsonSchema` query operator used by queries and collection validators.
+//
+// Like MongoDB, it supports a subset of JSON Schema draft 4 with the `bsonType` extension.
+// Keywords other than type keywords only apply to values of the matching type,
+// for example, `minLength` is ignored for non-string values.
+//
+// Validation results are reported the same way as MongoDB does in `errInfo` of document validation errors.
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Schema represents parsed `$jsonSchema`.
+type Schema struct {
+	spec *types.Document
+
+	typeKeyword string          // `type` or `bsonType`, empty if not set
+	types       map[string]bool // BSON type aliases; `number` matches all numeric types
+
+	required []string
+
+	properties           []property
+	patternProperties    []patternProperty
+	additionalProperties *boolOrSchema
+	minProperties        *int64
+	maxProperties        *int64
+	dependencies         []dependency
+
+	enum *types.Array
+
+	minimum          any
+	maximum          any
+	exclusiveMinimum bool
+	exclusiveMaximum bool
+	multipleOf       any
+
+	minLength *int64
+	maxLength *int64
+	pattern   *regexp.Regexp
+
+	items           []*Schema // a single schema for all items if itemsList is false
+	itemsList       bool
+	additionalItems *boolOrSchema
+	minItems        *int64
+	maxItems        *int64
+	uniqueItems     bool
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+// property represents a schema of the named property.
+type property struct {
+	schema *Schema
+	name   string
+}
+
+// patternProperty represents a schema of properties with names matching the regular expression.
+type patternProperty struct {
+	schema *Schema
+	re     *regexp.Regexp
+	source string
+}
+
+// boolOrSchema represents a value of keywords that accept either a boolean or a schema.
+type boolOrSchema struct {
+	schema  *Schema // nil if boolean is used
+	allowed bool
+}
+
+// dependency represents a property dependency: either a list of required properties, or a schema.
+type dependency struct {
+	schema   *Schema
+	property string
+	required []string
+}
+
+// jsonTypes maps JSON Schema type names to BSON type aliases.
+var jsonTypes = map[string]string{
+	"object":  "object",
+	"array":   "array",
+	"number":  "number",
+	"boolean": "bool",
+	"string":  "string",
+	"null":    "null",
+}
+
+// unsupportedKeywords contains JSON Schema keywords that MongoDB does not support.
+var unsupportedKeywords = map[string]struct{}{
+	"$ref":        {},
+	"$schema":     {},
+	"default":     {},
+	"definitions": {},
+	"format":      {},
+	"id":          {},
+}
+
+// Parse parses the given `$jsonSchema` operator value.
+func Parse(v any) (*Schema, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			"$jsonSchema must be an object",
+			"$jsonSchema",
+		)
+	}
+
+	return parse(doc)
+}
+
+// parse parses the given schema document.
+func parse(doc *types.Document) (*Schema, error) {
+	s := &Schema{spec: doc}
+
+	if doc.Has("type") && doc.Has("bsonType") {
+		return nil, failedToParse("Cannot specify both $jsonSchema keywords 'type' and 'bsonType'")
+	}
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		keyword, v, err := iter.Next()
+		if err != nil {
+			break
+		}
+
+		if _, ok := unsupportedKeywords[keyword]; ok {
+			return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' is not currently supported", keyword))
+		}
+
+		if err = s.parseKeyword(keyword, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.exclusiveMinimum && s.minimum == nil {
+		return nil, failedToParse("$jsonSchema keyword 'minimum' must be a present if exclusiveMinimum is present")
+	}
+
+	if s.exclusiveMaximum && s.maximum == nil {
+		return nil, failedToParse("$jsonSchema keyword 'maximum' must be a present if exclusiveMaximum is present")
+	}
+
+	return s, nil
+}
+
+// parseKeyword parses a single keyword of the schema.
+func (s *Schema) parseKeyword(keyword string, v any) error {
+	var err error
+
+	switch keyword {
+	case "type", "bsonType":
+		s.typeKeyword = keyword
+		s.types, err = parseTypes(keyword, v)
+
+	case "required":
+		s.required, err = parseStrings(keyword, v)
+
+	case "properties":
+		var props *types.Document
+		if props, err = parseObject(keyword, v); err != nil {
+			return err
+		}
+
+		for _, name := range props.Keys() {
+			var ps *Schema
+			if ps, err = parseNested(fmt.Sprintf("Nested schema for $jsonSchema property '%s'", name), must.NotFail(props.Get(name))); err != nil {
+				return err
+			}
+
+			s.properties = append(s.properties, property{name: name, schema: ps})
+		}
+
+	case "patternProperties":
+		var props *types.Document
+		if props, err = parseObject(keyword, v); err != nil {
+			return err
+		}
+
+		for _, source := range props.Keys() {
+			var re *regexp.Regexp
+			if re, err = regexp.Compile(source); err != nil {
+				return failedToParse(fmt.Sprintf("Invalid regular expression in $jsonSchema keyword '%s': %s", keyword, source))
+			}
+
+			var ps *Schema
+			if ps, err = parseNested(fmt.Sprintf("$jsonSchema keyword '%s' property '%s'", keyword, source), must.NotFail(props.Get(source))); err != nil {
+				return err
+			}
+
+			s.patternProperties = append(s.patternProperties, patternProperty{schema: ps, re: re, source: source})
+		}
+
+	case "additionalProperties":
+		s.additionalProperties, err = parseBoolOrSchema(keyword, v)
+
+	case "minProperties":
+		s.minProperties, err = parseNonNegative(keyword, v)
+
+	case "maxProperties":
+		s.maxProperties, err = parseNonNegative(keyword, v)
+
+	case "dependencies":
+		err = s.parseDependencies(v)
+
+	case "enum":
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return typeMismatch(keyword, "an array")
+		}
+
+		if arr.Len() == 0 {
+			return failedToParse("$jsonSchema keyword 'enum' cannot be an empty array")
+		}
+
+		s.enum = arr
+
+	case "minimum", "maximum", "multipleOf":
+		if !isNumber(v) {
+			return typeMismatch(keyword, "a number")
+		}
+
+		switch keyword {
+		case "minimum":
+			s.minimum = v
+		case "maximum":
+			s.maximum = v
+		default:
+			if f, _ := toFloat(v); !(f > 0) {
+				return failedToParse("$jsonSchema keyword 'multipleOf' must have a positive value")
+			}
+
+			s.multipleOf = v
+		}
+
+	case "exclusiveMinimum", "exclusiveMaximum":
+		b, ok := v.(bool)
+		if !ok {
+			return typeMismatch(keyword, "a boolean")
+		}
+
+		if keyword == "exclusiveMinimum" {
+			s.exclusiveMinimum = b
+		} else {
+			s.exclusiveMaximum = b
+		}
+
+	case "minLength":
+		s.minLength, err = parseNonNegative(keyword, v)
+
+	case "maxLength":
+		s.maxLength, err = parseNonNegative(keyword, v)
+
+	case "pattern":
+		source, ok := v.(string)
+		if !ok {
+			return typeMismatch(keyword, "a string")
+		}
+
+		if s.pattern, err = regexp.Compile(source); err != nil {
+			return failedToParse(fmt.Sprintf("$jsonSchema keyword 'pattern' is not a valid regular expression: %s", source))
+		}
+
+	case "items":
+		err = s.parseItems(v)
+
+	case "additionalItems":
+		s.additionalItems, err = parseBoolOrSchema(keyword, v)
+
+	case "minItems":
+		s.minItems, err = parseNonNegative(keyword, v)
+
+	case "maxItems":
+		s.maxItems, err = parseNonNegative(keyword, v)
+
+	case "uniqueItems":
+		b, ok := v.(bool)
+		if !ok {
+			return typeMismatch(keyword, "a boolean")
+		}
+
+		s.uniqueItems = b
+
+	case "allOf", "anyOf", "oneOf":
+		var list []*Schema
+		if list, err = parseSchemas(keyword, v); err != nil {
+			return err
+		}
+
+		switch keyword {
+		case "allOf":
+			s.allOf = list
+		case "anyOf":
+			s.anyOf = list
+		default:
+			s.oneOf = list
+		}
+
+	case "not":
+		s.not, err = parseNested("$jsonSchema keyword 'not'", v)
+
+	case "title", "description":
+		if _, ok := v.(string); !ok {
+			return typeMismatch(keyword, "a string")
+		}
+
+	default:
+		return failedToParse(fmt.Sprintf("Unknown $jsonSchema keyword: %s", keyword))
+	}
+
+	return err
+}
+
+// parseDependencies parses `dependencies` keyword.
+func (s *Schema) parseDependencies(v any) error {
+	deps, err := parseObject("dependencies", v)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range deps.Keys() {
+		dep := dependency{property: name}
+
+		switch dv := must.NotFail(deps.Get(name)).(type) {
+		case *types.Document:
+			if dep.schema, err = parse(dv); err != nil {
+				return err
+			}
+
+		case *types.Array:
+			if dep.required, err = parseStrings(fmt.Sprintf("dependencies.%s", name), dv); err != nil {
+				return err
+			}
+
+		default:
+			return typeMismatch(fmt.Sprintf("dependencies.%s", name), "an object or an array of strings")
+		}
+
+		s.dependencies = append(s.dependencies, dep)
+	}
+
+	return nil
+}
+
+// parseItems parses `items` keyword that is either a schema for all items or an array of schemas.
+func (s *Schema) parseItems(v any) error {
+	var err error
+
+	switch v := v.(type) {
+	case *types.Document:
+		var is *Schema
+		if is, err = parse(v); err != nil {
+			return err
+		}
+
+		s.items = []*Schema{is}
+
+	case *types.Array:
+		if s.items, err = parseSchemas("items", v); err != nil {
+			return err
+		}
+
+		s.itemsList = true
+
+	default:
+		return typeMismatch("items", "an object or an array of objects")
+	}
+
+	return nil
+}
+
+// parseTypes parses `type` and `bsonType` keywords.
+func parseTypes(keyword string, v any) (map[string]bool, error) {
+	var names []string
+
+	switch v := v.(type) {
+	case string:
+		names = []string{v}
+
+	case *types.Array:
+		if v.Len() == 0 {
+			return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' must describe at least one type", keyword))
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			name, ok := must.NotFail(v.Get(i)).(string)
+			if !ok {
+				return nil, typeMismatch(keyword, "a string or an array of strings")
+			}
+
+			names = append(names, name)
+		}
+
+	default:
+		return nil, typeMismatch(keyword, "a string or an array of strings")
+	}
+
+	res := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if keyword == "type" {
+			alias, ok := jsonTypes[name]
+			if !ok {
+				if name == "integer" {
+					return nil, badValue("$jsonSchema type 'integer' is not currently supported.")
+				}
+
+				return nil, badValue(fmt.Sprintf("Unknown type name alias: %s", name))
+			}
+
+			name = alias
+		} else if _, err := handlerparams.ParseTypeCode(name); err != nil {
+			return nil, badValue(fmt.Sprintf("Unknown type name alias: %s", name))
+		}
+
+		if res[name] {
+			return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' has duplicate value: %s", keyword, name))
+		}
+
+		res[name] = true
+	}
+
+	return res, nil
+}
+
+// parseStrings parses a non-empty array of unique strings.
+func parseStrings(keyword string, v any) ([]string, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, typeMismatch(keyword, "an array")
+	}
+
+	if arr.Len() == 0 {
+		return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' cannot be an empty array", keyword))
+	}
+
+	res := make([]string, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		s, ok := must.NotFail(arr.Get(i)).(string)
+		if !ok {
+			return nil, typeMismatch(keyword, "an array of strings")
+		}
+
+		for _, prev := range res[:i] {
+			if prev == s {
+				return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' array cannot contain duplicate values", keyword))
+			}
+		}
+
+		res[i] = s
+	}
+
+	return res, nil
+}
+
+// parseObject checks that the keyword's value is an object.
+func parseObject(keyword string, v any) (*types.Document, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, typeMismatch(keyword, "an object")
+	}
+
+	return doc, nil
+}
+
+// parseNested parses a nested schema; what describes it for the error message.
+func parseNested(what string, v any) (*Schema, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			what+" must be an object",
+			"$jsonSchema",
+		)
+	}
+
+	return parse(doc)
+}
+
+// parseSchemas parses a non-empty array of schemas.
+func parseSchemas(keyword string, v any) ([]*Schema, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, typeMismatch(keyword, "an array")
+	}
+
+	if arr.Len() == 0 && keyword != "items" {
+		return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' must be a non-empty array", keyword))
+	}
+
+	res := make([]*Schema, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		doc, ok := must.NotFail(arr.Get(i)).(*types.Document)
+		if !ok {
+			return nil, typeMismatch(keyword, "an array of objects")
+		}
+
+		var err error
+		if res[i], err = parse(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// parseBoolOrSchema parses a keyword that accepts either a boolean or a schema.
+func parseBoolOrSchema(keyword string, v any) (*boolOrSchema, error) {
+	switch v := v.(type) {
+	case bool:
+		return &boolOrSchema{allowed: v}, nil
+
+	case *types.Document:
+		s, err := parse(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return &boolOrSchema{schema: s, allowed: true}, nil
+
+	default:
+		return nil, typeMismatch(keyword, "either an object or a boolean")
+	}
+}
+
+// parseNonNegative parses a non-negative integer keyword value.
+func parseNonNegative(keyword string, v any) (*int64, error) {
+	if !isNumber(v) {
+		return nil, typeMismatch(keyword, "a number")
+	}
+
+	f, _ := toFloat(v)
+	if f != math.Trunc(f) || math.IsInf(f, 0) {
+		return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' must be a representable as a long integer", keyword))
+	}
+
+	if f < 0 {
+		return nil, failedToParse(fmt.Sprintf("$jsonSchema keyword '%s' must be a non-negative integer", keyword))
+	}
+
+	n := int64(f)
+
+	return &n, nil
+}
+
+// isNumber returns true if the given value is a number.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, int32, int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// toFloat converts the given number to float64.
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// typeMismatch returns TypeMismatch error for the keyword with the wrong value type.
+func typeMismatch(keyword, expected string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf("$jsonSchema keyword '%s' must be %s", keyword, expected),
+		"$jsonSchema",
+	)
+}
+
+// failedToParse returns FailedToParse error with the given message.
+func failedToParse(msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrFailedToParse, msg, "$jsonSchema")
+}
+
+// badValue returns BadValue error with the given message.
+func badValue(msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, "$jsonSchema")
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		schema any
+		code   handlererrors.ErrorCode
+		msg    string
+	}{
+		"NotObject": {
+			schema: "string",
+			code:   handlererrors.ErrTypeMismatch,
+			msg:    "$jsonSchema must be an object",
+		},
+		"TypeAndBSONType": {
+			schema: must.NotFail(types.NewDocument("type", "string", "bsonType", "string")),
+			code:   handlererrors.ErrFailedToParse,
+			msg:    "Cannot specify both $jsonSchema keywords 'type' and 'bsonType'",
+		},
+		"Unsupported": {
+			schema: must.NotFail(types.NewDocument("format", "email")),
+			code:   handlererrors.ErrFailedToParse,
+			msg:    "$jsonSchema keyword 'format' is not currently supported",
+		},
+		"Unknown": {
+			schema: must.NotFail(types.NewDocument("foo", "bar")),
+			code:   handlererrors.ErrFailedToParse,
+			msg:    "Unknown $jsonSchema keyword: foo",
+		},
+		"Integer": {
+			schema: must.NotFail(types.NewDocument("type", "integer")),
+			code:   handlererrors.ErrBadValue,
+			msg:    "$jsonSchema type 'integer' is not currently supported.",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tc.schema)
+			require.Error(t, err)
+
+			var ce *handlererrors.CommandError
+			require.True(t, errors.As(err, &ce), "%T", err)
+			assert.Equal(t, tc.code, ce.Code())
+			assert.Equal(t, tc.msg, ce.Err().Error())
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	schema := must.NotFail(types.NewDocument(
+		"bsonType", "object",
+		"required", must.NotFail(types.NewArray("name")),
+		"properties", must.NotFail(types.NewDocument(
+			"name", must.NotFail(types.NewDocument("bsonType", "string", "minLength", int32(2))),
+			"age", must.NotFail(types.NewDocument("bsonType", must.NotFail(types.NewArray("int", "long")), "minimum", int32(0))),
+			"tags", must.NotFail(types.NewDocument("bsonType", "array", "uniqueItems", true)),
+		)),
+		"additionalProperties", false,
+	))
+
+	s, err := Parse(schema)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		doc      *types.Document
+		expected bool
+	}{
+		"Valid": {
+			doc:      must.NotFail(types.NewDocument("name", "foo", "age", int32(42))),
+			expected: true,
+		},
+		"Missing": {
+			doc:      must.NotFail(types.NewDocument("age", int32(42))),
+			expected: false,
+		},
+		"WrongType": {
+			doc:      must.NotFail(types.NewDocument("name", int32(42))),
+			expected: false,
+		},
+		"TooShort": {
+			doc:      must.NotFail(types.NewDocument("name", "f")),
+			expected: false,
+		},
+		"Negative": {
+			doc:      must.NotFail(types.NewDocument("name", "foo", "age", int64(-1))),
+			expected: false,
+		},
+		"Double": {
+			doc:      must.NotFail(types.NewDocument("name", "foo", "age", 42.0)),
+			expected: false,
+		},
+		"Duplicate": {
+			doc:      must.NotFail(types.NewDocument("name", "foo", "tags", must.NotFail(types.NewArray("a", "a")))),
+			expected: false,
+		},
+		"Additional": {
+			doc:      must.NotFail(types.NewDocument("name", "foo", "foo", "bar")),
+			expected: false,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, s.Matches(tc.doc))
+
+			if tc.expected {
+				assert.Nil(t, s.Validate(tc.doc))
+			} else {
+				assert.NotNil(t, s.Validate(tc.doc))
+			}
+		})
+	}
+}
+
+func TestValidateDetails(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse(must.NotFail(types.NewDocument(
+		"required", must.NotFail(types.NewArray("a", "b")),
+	)))
+	require.NoError(t, err)
+
+	actual := s.Validate(must.NotFail(types.NewDocument("a", int32(1))))
+	expected := must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+		"operatorName", "required",
+		"specifiedAs", must.NotFail(types.NewDocument("required", must.NotFail(types.NewArray("a", "b")))),
+		"missingProperties", must.NotFail(types.NewArray("b")),
+	))))
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,549 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Matches returns true if the given value satisfies the schema.
+func (s *Schema) Matches(v any) bool {
+	return len(s.validate(v)) == 0
+}
+
+// Validate returns nil if the given value satisfies the schema,
+// or an array of not satisfied rules (`schemaRulesNotSatisfied` in MongoDB terms) otherwise.
+func (s *Schema) Validate(v any) *types.Array {
+	return detailsArray(s.validate(v))
+}
+
+// validate returns details of not satisfied rules in the order of schema keywords.
+func (s *Schema) validate(v any) []any {
+	var res []any
+
+	for _, keyword := range s.spec.Keys() {
+		if d := s.validateKeyword(keyword, v); d != nil {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
+
+// validateKeyword returns details of the keyword rule if the given value does not satisfy it, or nil.
+func (s *Schema) validateKeyword(keyword string, v any) *types.Document {
+	doc, isDoc := v.(*types.Document)
+	arr, isArr := v.(*types.Array)
+	str, isStr := v.(string)
+
+	switch keyword {
+	case "type", "bsonType":
+		if s.matchesType(v) {
+			return nil
+		}
+
+		return s.failed(keyword, "type did not match", "consideredValue", v, "consideredType", handlerparams.AliasFromType(v))
+
+	case "required":
+		if !isDoc {
+			return nil
+		}
+
+		var missing []any
+
+		for _, name := range s.required {
+			if !doc.Has(name) {
+				missing = append(missing, name)
+			}
+		}
+
+		if missing == nil {
+			return nil
+		}
+
+		return s.failed(keyword, "", "missingProperties", must.NotFail(types.NewArray(missing...)))
+
+	case "properties":
+		if !isDoc {
+			return nil
+		}
+
+		var failed []any
+
+		for _, p := range s.properties {
+			pv, err := doc.Get(p.name)
+			if err != nil {
+				continue
+			}
+
+			if details := p.schema.validate(pv); details != nil {
+				failed = append(failed, must.NotFail(types.NewDocument(
+					"propertyName", p.name,
+					"details", detailsArray(details),
+				)))
+			}
+		}
+
+		if failed == nil {
+			return nil
+		}
+
+		return operator(keyword, "propertiesNotSatisfied", must.NotFail(types.NewArray(failed...)))
+
+	case "patternProperties":
+		if !isDoc {
+			return nil
+		}
+
+		var failed []any
+
+		for _, name := range doc.Keys() {
+			for _, pp := range s.patternProperties {
+				if !pp.re.MatchString(name) {
+					continue
+				}
+
+				if details := pp.schema.validate(must.NotFail(doc.Get(name))); details != nil {
+					failed = append(failed, must.NotFail(types.NewDocument(
+						"propertyName", name,
+						"regexMatched", pp.source,
+						"details", detailsArray(details),
+					)))
+				}
+			}
+		}
+
+		if failed == nil {
+			return nil
+		}
+
+		return operator(keyword, "details", must.NotFail(types.NewArray(failed...)))
+
+	case "additionalProperties":
+		if !isDoc {
+			return nil
+		}
+
+		var additional []any
+
+		for _, name := range doc.Keys() {
+			if !s.isAdditionalProperty(name) {
+				continue
+			}
+
+			if s.additionalProperties.schema == nil {
+				if !s.additionalProperties.allowed {
+					additional = append(additional, name)
+				}
+
+				continue
+			}
+
+			if details := s.additionalProperties.schema.validate(must.NotFail(doc.Get(name))); details != nil {
+				return operator(
+					keyword,
+					"reason", "at least one additional property did not match the subschema",
+					"failingProperty", name,
+					"details", detailsArray(details),
+				)
+			}
+		}
+
+		if additional == nil {
+			return nil
+		}
+
+		return s.failed(keyword, "", "additionalProperties", must.NotFail(types.NewArray(additional...)))
+
+	case "minProperties", "maxProperties":
+		if !isDoc {
+			return nil
+		}
+
+		n := int64(doc.Len())
+		if (keyword == "minProperties" && n >= *s.minProperties) || (keyword == "maxProperties" && n <= *s.maxProperties) {
+			return nil
+		}
+
+		return s.failed(keyword, "specified number of properties was not satisfied", "numberOfProperties", int32(n))
+
+	case "dependencies":
+		if !isDoc {
+			return nil
+		}
+
+		return s.validateDependencies(doc)
+
+	case "enum":
+		for i := 0; i < s.enum.Len(); i++ {
+			if types.CompareForAggregation(v, must.NotFail(s.enum.Get(i))) == types.Equal {
+				return nil
+			}
+		}
+
+		return s.failed(keyword, "value was not found in enum", "consideredValue", v)
+
+	case "minimum", "maximum":
+		if !isNumber(v) {
+			return nil
+		}
+
+		if s.compareBound(keyword, v) {
+			return nil
+		}
+
+		return s.failed(keyword, "comparison failed", "consideredValue", v)
+
+	case "multipleOf":
+		if !isNumber(v) || isMultipleOf(v, s.multipleOf) {
+			return nil
+		}
+
+		return s.failed(keyword, "considered value is not a multiple of the specified value", "consideredValue", v)
+
+	case "minLength", "maxLength":
+		if !isStr {
+			return nil
+		}
+
+		n := int64(utf8.RuneCountInString(str))
+		if (keyword == "minLength" && n >= *s.minLength) || (keyword == "maxLength" && n <= *s.maxLength) {
+			return nil
+		}
+
+		return s.failed(keyword, "specified string length was not satisfied", "consideredValue", v)
+
+	case "pattern":
+		if !isStr || s.pattern.MatchString(str) {
+			return nil
+		}
+
+		return s.failed(keyword, "regular expression did not match", "consideredValue", v)
+
+	case "items":
+		if !isArr {
+			return nil
+		}
+
+		for i := 0; i < arr.Len(); i++ {
+			is := s.items[0]
+
+			if s.itemsList {
+				if i >= len(s.items) {
+					break
+				}
+
+				is = s.items[i]
+			}
+
+			if details := is.validate(must.NotFail(arr.Get(i))); details != nil {
+				return operator(
+					keyword,
+					"reason", "At least one item did not match the sub-schema",
+					"itemIndex", int32(i),
+					"details", detailsArray(details),
+				)
+			}
+		}
+
+		return nil
+
+	case "additionalItems":
+		// additionalItems only applies when items is an array of schemas
+		if !isArr || !s.itemsList || arr.Len() <= len(s.items) {
+			return nil
+		}
+
+		if s.additionalItems.schema == nil {
+			if s.additionalItems.allowed {
+				return nil
+			}
+
+			additional := types.MakeArray(arr.Len() - len(s.items))
+			for i := len(s.items); i < arr.Len(); i++ {
+				additional.Append(must.NotFail(arr.Get(i)))
+			}
+
+			return s.failed(keyword, "found additional items", "additionalItems", additional)
+		}
+
+		for i := len(s.items); i < arr.Len(); i++ {
+			if details := s.additionalItems.schema.validate(must.NotFail(arr.Get(i))); details != nil {
+				return operator(
+					keyword,
+					"reason", "At least one additional item did not match the sub-schema",
+					"itemIndex", int32(i),
+					"details", detailsArray(details),
+				)
+			}
+		}
+
+		return nil
+
+	case "minItems", "maxItems":
+		if !isArr {
+			return nil
+		}
+
+		n := int64(arr.Len())
+		if (keyword == "minItems" && n >= *s.minItems) || (keyword == "maxItems" && n <= *s.maxItems) {
+			return nil
+		}
+
+		return s.failed(keyword, "array did not match specified length", "consideredValue", v)
+
+	case "uniqueItems":
+		if !isArr || !s.uniqueItems {
+			return nil
+		}
+
+		for i := 0; i < arr.Len(); i++ {
+			for j := 0; j < i; j++ {
+				item := must.NotFail(arr.Get(i))
+
+				if types.CompareForAggregation(item, must.NotFail(arr.Get(j))) == types.Equal {
+					return s.failed(keyword, "found a duplicate item", "consideredValue", v, "duplicatedValue", item)
+				}
+			}
+		}
+
+		return nil
+
+	case "allOf", "anyOf", "oneOf":
+		return s.validateCombination(keyword, v)
+
+	case "not":
+		if !s.not.Matches(v) {
+			return nil
+		}
+
+		return operator(keyword, "reason", "child expression matched", "childSchema", s.not.spec)
+
+	default:
+		// title, description
+		return nil
+	}
+}
+
+// validateDependencies validates `dependencies` keyword for the given document.
+func (s *Schema) validateDependencies(doc *types.Document) *types.Document {
+	var failed []any
+
+	for _, dep := range s.dependencies {
+		if !doc.Has(dep.property) {
+			continue
+		}
+
+		if dep.schema != nil {
+			if details := dep.schema.validate(doc); details != nil {
+				failed = append(failed, must.NotFail(types.NewDocument(
+					"conditionalProperty", dep.property,
+					"details", detailsArray(details),
+				)))
+			}
+
+			continue
+		}
+
+		var missing []any
+
+		for _, name := range dep.required {
+			if !doc.Has(name) {
+				missing = append(missing, name)
+			}
+		}
+
+		if missing != nil {
+			failed = append(failed, must.NotFail(types.NewDocument(
+				"conditionalProperty", dep.property,
+				"missingProperties", must.NotFail(types.NewArray(missing...)),
+			)))
+		}
+	}
+
+	if failed == nil {
+		return nil
+	}
+
+	return operator("dependencies", "failingDependencies", must.NotFail(types.NewArray(failed...)))
+}
+
+// validateCombination validates `allOf`, `anyOf` and `oneOf` keywords.
+func (s *Schema) validateCombination(keyword string, v any) *types.Document {
+	list := s.allOf
+
+	switch keyword {
+	case "anyOf":
+		list = s.anyOf
+	case "oneOf":
+		list = s.oneOf
+	}
+
+	var failed, matched []any
+
+	for i, sub := range list {
+		details := sub.validate(v)
+		if details == nil {
+			matched = append(matched, int32(i))
+			continue
+		}
+
+		failed = append(failed, must.NotFail(types.NewDocument(
+			"index", int32(i),
+			"details", detailsArray(details),
+		)))
+	}
+
+	switch keyword {
+	case "allOf":
+		if failed == nil {
+			return nil
+		}
+
+	case "anyOf":
+		if matched != nil {
+			return nil
+		}
+
+	default:
+		if len(matched) == 1 {
+			return nil
+		}
+
+		if len(matched) > 1 {
+			return operator(
+				keyword,
+				"reason", "more than one subschema matched",
+				"matchingSchemaIndexes", must.NotFail(types.NewArray(matched...)),
+			)
+		}
+	}
+
+	return operator(keyword, "schemasNotSatisfied", must.NotFail(types.NewArray(failed...)))
+}
+
+// matchesType returns true if the given value has one of the schema types.
+func (s *Schema) matchesType(v any) bool {
+	alias := handlerparams.AliasFromType(v)
+
+	return s.types[alias] || (s.types["number"] && isNumber(v))
+}
+
+// isAdditionalProperty returns true if the property is not described by `properties` or `patternProperties`.
+func (s *Schema) isAdditionalProperty(name string) bool {
+	for _, p := range s.properties {
+		if p.name == name {
+			return false
+		}
+	}
+
+	for _, pp := range s.patternProperties {
+		if pp.re.MatchString(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareBound returns true if the given number satisfies `minimum` or `maximum` keyword.
+func (s *Schema) compareBound(keyword string, v any) bool {
+	if keyword == "minimum" {
+		res := types.CompareForAggregation(v, s.minimum)
+		return res == types.Greater || (res == types.Equal && !s.exclusiveMinimum)
+	}
+
+	res := types.CompareForAggregation(v, s.maximum)
+
+	return res == types.Less || (res == types.Equal && !s.exclusiveMaximum)
+}
+
+// failed returns details of the not satisfied keyword rule with the given reason and additional fields.
+//
+// The reason is omitted if it is empty.
+func (s *Schema) failed(keyword, reason string, pairs ...any) *types.Document {
+	specified := must.NotFail(types.NewDocument(keyword, must.NotFail(s.spec.Get(keyword))))
+
+	switch keyword {
+	case "minimum":
+		if s.exclusiveMinimum {
+			specified.Set("exclusiveMinimum", true)
+		}
+	case "maximum":
+		if s.exclusiveMaximum {
+			specified.Set("exclusiveMaximum", true)
+		}
+	}
+
+	res := must.NotFail(types.NewDocument(
+		"operatorName", keyword,
+		"specifiedAs", specified,
+	))
+
+	if reason != "" {
+		res.Set("reason", reason)
+	}
+
+	for i := 0; i < len(pairs); i += 2 {
+		res.Set(pairs[i].(string), pairs[i+1])
+	}
+
+	return res
+}
+
+// operator returns details of the not satisfied keyword rule with the given fields.
+func operator(keyword string, pairs ...any) *types.Document {
+	return must.NotFail(types.NewDocument(append([]any{"operatorName", keyword}, pairs...)...))
+}
+
+// detailsArray converts the list of details to an array, or nil if the list is empty.
+func detailsArray(details []any) *types.Array {
+	if len(details) == 0 {
+		return nil
+	}
+
+	return must.NotFail(types.NewArray(details...))
+}
+
+// isMultipleOf returns true if the given number is a multiple of m.
+func isMultipleOf(v, m any) bool {
+	vi, vInt := v.(int64)
+	if v32, ok := v.(int32); ok {
+		vi, vInt = int64(v32), true
+	}
+
+	mi, mInt := m.(int64)
+	if m32, ok := m.(int32); ok {
+		mi, mInt = int64(m32), true
+	}
+
+	if vInt && mInt {
+		return vi%mi == 0
+	}
+
+	vf, _ := toFloat(v)
+	mf, _ := toFloat(m)
+
+	if math.IsInf(vf, 0) || math.IsNaN(vf) {
+		return false
+	}
+
+	return math.Mod(vf, mf) == 0
+}
@@ -22,6 +22,7 @@ import (
 	"math"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -34,6 +35,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/handler/textsearch"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -56,7 +58,7 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 	common.Ignored(
 		document, h.L,
-		"allowDiskUse", "bypassDocumentValidation", "readConcern", "hint", "comment", "writeConcern",
+		"bypassDocumentValidation", "readConcern", "comment", "writeConcern",
 	)
 
 	var dbName string
@@ -103,6 +105,19 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		return nil, lazyerrors.Error(err)
 	}
 
+	view, err := h.getView(ctx, db, dbName, cName)
+	if err != nil {
+		return nil, err
+	}
+
+	// index usage is tracked for the collection the view is defined on
+	source := cName
+
+	if view != nil {
+		c = view.c
+		source = view.source
+	}
+
 	username, _ := conninfo.Get(ctx).Auth()
 
 	v, _ := document.Get("maxTimeMS")
@@ -179,7 +194,86 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		)
 	}
 
+	// like MongoDB with default allowDiskUseByDefault, only explicit false disallows it
+	stageParams := &stages.NewStageParams{
+		AllowDiskUse:     true,
+		GroupMemoryLimit: h.GroupMemoryLimit,
+		SortMemoryLimit:  h.SortMemoryLimit,
+		SpillDir:         h.SpillDir,
+	}
+
+	if v, _ = document.Get("allowDiskUse"); v != nil {
+		if stageParams.AllowDiskUse, err = handlerparams.GetBoolOptionalParam("allowDiskUse", v); err != nil {
+			return nil, err
+		}
+	}
+
 	aggregationStages := must.NotFail(iterator.ConsumeValues(pipeline.Iterator()))
+
+	// the view pipeline is applied to documents of the collection before the given pipeline
+	if view != nil {
+		aggregationStages = append(slices.Clone(view.pipeline), aggregationStages...)
+	}
+
+	// `$text` is allowed only in the first $match stage;
+	// it is handled separately, and the stage is replaced with the one without it
+	var textSearch *common.TextSearch
+
+	if len(aggregationStages) > 0 {
+		if d, isDoc := aggregationStages[0].(*types.Document); isDoc && d.Command() == "$match" {
+			if match, isDoc := must.NotFail(d.Get("$match")).(*types.Document); isDoc {
+				if textSearch, err = common.GetTextSearch(match); err != nil {
+					return nil, err
+				}
+
+				if textSearch != nil {
+					aggregationStages[0] = must.NotFail(types.NewDocument("$match", textSearch.Filter))
+				}
+			}
+		}
+	}
+
+	// `$geoNear` is allowed only as the first stage;
+	// it is handled separately, and the stage is removed
+	var geoNear *common.GeoNear
+
+	if len(aggregationStages) > 0 {
+		if d, isDoc := aggregationStages[0].(*types.Document); isDoc && d.Command() == "$geoNear" {
+			if geoNear, err = common.GetGeoNearStage(must.NotFail(d.Get("$geoNear"))); err != nil {
+				return nil, err
+			}
+
+			aggregationStages = aggregationStages[1:]
+		}
+	}
+
+	// `$indexStats` is allowed only as the first stage;
+	// it is handled separately, and the stage is removed
+	var indexStats bool
+
+	if len(aggregationStages) > 0 {
+		if d, isDoc := aggregationStages[0].(*types.Document); isDoc && d.Command() == "$indexStats" {
+			if spec, _ := must.NotFail(d.Get("$indexStats")).(*types.Document); spec == nil || spec.Len() != 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrStageIndexStatsInvalidSpec,
+					"The $indexStats stage specification must be an empty object",
+					document.Command(),
+				)
+			}
+
+			if view != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCommandNotSupportedOnView,
+					fmt.Sprintf("Namespace %s.%s is a view, not a collection", dbName, cName),
+					document.Command(),
+				)
+			}
+
+			indexStats = true
+			aggregationStages = aggregationStages[1:]
+		}
+	}
+
 	stagesDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 	collStatsDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 
@@ -194,9 +288,25 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 			)
 		}
 
+		if d.Command() == "$geoNear" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrGeoNearNotFirstStage,
+				"$geoNear is only valid as the first stage in a pipeline",
+				document.Command(),
+			)
+		}
+
+		if d.Command() == "$indexStats" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCollStatsIsNotFirstStage,
+				"$indexStats is only valid as the first stage in a pipeline",
+				document.Command(),
+			)
+		}
+
 		var s aggregations.Stage
 
-		if s, err = stages.NewStage(d); err != nil {
+		if s, err = stages.NewStage(d, stageParams); err != nil {
 			return nil, err
 		}
 
@@ -217,6 +327,8 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		}
 	}
 
+	stages.CoalesceSortLimit(stagesDocuments)
+
 	// validate cursor after validating pipeline stages to keep compatibility
 	v, _ = document.Get("cursor")
 	if v == nil {
@@ -260,9 +372,23 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 	var iter iterator.Interface[struct{}, *types.Document]
 
-	if len(collStatsDocuments) == len(stagesDocuments) {
+	switch {
+	case indexStats:
+		iter, err = processStagesIndexStats(ctx, closer, &stagesIndexStatsParams{
+			c:          c,
+			indexUsage: h.indexUsage,
+			dbName:     dbName,
+			cName:      cName,
+			stages:     stagesDocuments,
+		})
+	case len(collStatsDocuments) == len(stagesDocuments):
 		filter, sort := aggregations.GetPushdownQuery(aggregationStages)
 
+		// stages after $geoNear could use fields it sets, so only its query is pushed down
+		if geoNear != nil {
+			filter, sort = geoNear.Filter, nil
+		}
+
 		// only documents stages or no stages - fetch documents from the DB and apply stages to them
 		qp := new(backends.QueryParams)
 
@@ -285,13 +411,68 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 			return nil, err
 		}
 
-		// Skip sorting if there are more than one sort parameters
-		if sort.Len() == 1 {
+		// Skip sorting if text score is used
+		if sort.Len() > 0 && !common.HasTextScoreMeta(sort) {
 			qp.Sort = sort
 		}
 
-		iter, err = processStagesDocuments(ctx, closer, &stagesDocumentsParams{c, qp, stagesDocuments})
-	} else {
+		var search *textsearch.Search
+
+		if textSearch != nil {
+			if search, qp.TextSearch, err = newTextSearch(ctx, c, textSearch); err != nil {
+				closer.Close()
+				return nil, err
+			}
+		}
+
+		var nearSearch *common.GeoNearSearch
+
+		if geoNear != nil {
+			if nearSearch, qp.Geo, err = newGeoNearSearch(ctx, c, geoNear); err != nil {
+				closer.Close()
+				return nil, err
+			}
+		} else {
+			qp.Geo = common.GetGeoParams(filter)
+		}
+
+		hint, _ := document.Get("hint")
+
+		if qp.Hint, err = getHintIndex(ctx, c, document.Command(), hint); err != nil {
+			closer.Close()
+			return nil, err
+		}
+
+		// $group pushdown requires the whole $match stage to be pushed down too,
+		// and it can't use the hinted index
+		var groupStages int
+		var groupFixup aggregations.Stage
+
+		if !h.DisableFilterPushdown && qp.Sort == nil && search == nil && geoNear == nil && qp.Hint == "" {
+			var fixup *types.Document
+
+			if qp.Group, groupStages, fixup = getGroupPushdown(aggregationStages); fixup != nil {
+				if groupFixup, err = stages.NewStage(fixup, stageParams); err != nil {
+					closer.Close()
+					return nil, lazyerrors.Error(err)
+				}
+			}
+		}
+
+		iter, err = processStagesDocuments(ctx, closer, &stagesDocumentsParams{
+			c:           c,
+			dbName:      dbName,
+			cName:       source,
+			indexUsage:  h.indexUsage,
+			qp:          qp,
+			stages:      stagesDocuments,
+			groupStages: groupStages,
+			groupFixup:  groupFixup,
+			search:      search,
+			geoNear:     geoNear,
+			nearSearch:  nearSearch,
+		})
+	default:
 		// TODO https://github.com/FerretDB/FerretDB/issues/2423
 		statistics := stages.GetStatistics(collStatsDocuments)
 
@@ -355,9 +536,17 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 // stagesDocumentsParams contains the parameters for processStagesDocuments.
 type stagesDocumentsParams struct {
-	c      backends.Collection
-	qp     *backends.QueryParams
-	stages []aggregations.Stage
+	c           backends.Collection
+	dbName      string
+	cName       string
+	indexUsage  *indexUsage // updated with the index used by the query, if any
+	qp          *backends.QueryParams
+	stages      []aggregations.Stage
+	groupStages int                   // number of leading stages replaced by $group pushdown
+	groupFixup  aggregations.Stage    // stage applied to grouped documents instead of them, if any
+	search      *textsearch.Search    // `$text` search of the first $match stage, if any
+	geoNear     *common.GeoNear       // the first $geoNear stage, if any
+	nearSearch  *common.GeoNearSearch // search of geoNear
 }
 
 // processStagesDocuments retrieves the documents from the database and then processes them through the stages.
@@ -368,11 +557,36 @@ func processStagesDocuments(ctx context.Context, closer *iterator.MultiCloser, p
 		return nil, lazyerrors.Error(err)
 	}
 
+	p.indexUsage.record(p.dbName, p.cName, queryRes.Index)
+
 	closer.Add(queryRes.Iter)
 
-	iter := queryRes.Iter
+	iter := types.DocumentsIterator(queryRes.Iter)
+	stages := p.stages
 
-	for _, s := range p.stages {
+	if p.search != nil {
+		iter = common.TextSearchIterator(iter, closer, p.search)
+	}
+
+	if p.geoNear != nil {
+		iter = common.FilterIterator(iter, closer, p.geoNear.Filter)
+
+		if iter, err = common.GeoNearIterator(iter, closer, p.nearSearch); err != nil {
+			closer.Close()
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if queryRes.GroupPushdown {
+		// $match and $group (or $count) stages were applied by the backend
+		stages = stages[p.groupStages:]
+
+		if p.groupFixup != nil {
+			stages = append([]aggregations.Stage{p.groupFixup}, stages...)
+		}
+	}
+
+	for _, s := range stages {
 		if iter, err = s.Process(ctx, iter, closer); err != nil {
 			return nil, err
 		}
@@ -381,6 +595,174 @@ func processStagesDocuments(ctx context.Context, closer *iterator.MultiCloser, p
 	return iter, nil
 }
 
+// getGroupPushdown returns parameters of $group stage that could be pushed down to the backend,
+// the number of leading stages replaced by that pushdown,
+// and the stage document that should be applied to grouped documents instead of them, if any.
+//
+// Only pipelines that start with $group or $count, or with $match followed by one of them, are handled.
+// $group key should be null, a top-level field, or a number, boolean or string constant,
+// and accumulators should be $sum, $avg, $min or $max of a top-level field,
+// $sum of an integer constant, or $count.
+// If the pipeline can't be pushed down, it returns nil.
+func getGroupPushdown(stagesDocs []any) (*backends.GroupParams, int, *types.Document) {
+	var n int
+
+	if len(stagesDocs) > 0 {
+		if stage, ok := stagesDocs[0].(*types.Document); ok && stage.Command() == "$match" {
+			n = 1
+		}
+	}
+
+	if len(stagesDocs) <= n {
+		return nil, 0, nil
+	}
+
+	stage, ok := stagesDocs[n].(*types.Document)
+	if !ok {
+		return nil, 0, nil
+	}
+
+	// $count stage is the same as $group with null key and $sum of 1 followed by unsetting _id
+	if stage.Command() == "$count" {
+		field, isString := must.NotFail(stage.Get("$count")).(string)
+		if !isString {
+			return nil, 0, nil
+		}
+
+		group := &backends.GroupParams{
+			Accumulators: []backends.GroupAccumulator{{Output: field, Operator: "$sum", Value: int32(1)}},
+		}
+
+		return group, n + 1, must.NotFail(types.NewDocument("$unset", "_id"))
+	}
+
+	if stage.Command() != "$group" {
+		return nil, 0, nil
+	}
+
+	groupDoc, ok := must.NotFail(stage.Get("$group")).(*types.Document)
+	if !ok {
+		return nil, 0, nil
+	}
+
+	group := new(backends.GroupParams)
+	seen := make(map[string]struct{}, groupDoc.Len())
+
+	var fixup *types.Document
+
+	iter := groupDoc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, 0, nil
+		}
+
+		if _, ok = seen[k]; ok {
+			return nil, 0, nil
+		}
+
+		seen[k] = struct{}{}
+
+		if k == "_id" {
+			if v == types.Null {
+				continue
+			}
+
+			if group.Key, ok = groupPushdownField(v); ok {
+				continue
+			}
+
+			// all documents belong to a single group with constant key,
+			// so they are grouped with null key that is replaced then
+			if !groupPushdownConstant(v) {
+				return nil, 0, nil
+			}
+
+			fixup = must.NotFail(types.NewDocument("$set", must.NotFail(types.NewDocument("_id", v))))
+
+			continue
+		}
+
+		accDoc, ok := v.(*types.Document)
+		if !ok || accDoc.Len() != 1 {
+			return nil, 0, nil
+		}
+
+		acc := backends.GroupAccumulator{
+			Output:   k,
+			Operator: accDoc.Command(),
+		}
+
+		arg := must.NotFail(accDoc.Get(acc.Operator))
+
+		switch acc.Operator {
+		case "$count":
+			if d, isDoc := arg.(*types.Document); !isDoc || d.Len() != 0 {
+				return nil, 0, nil
+			}
+
+			acc.Operator = "$sum"
+			acc.Value = int32(1)
+
+		case "$sum":
+			switch arg := arg.(type) {
+			case int32, int64:
+				acc.Value = arg
+			default:
+				if acc.Field, ok = groupPushdownField(arg); !ok {
+					return nil, 0, nil
+				}
+			}
+
+		case "$avg", "$min", "$max":
+			if acc.Field, ok = groupPushdownField(arg); !ok {
+				return nil, 0, nil
+			}
+
+		default:
+			return nil, 0, nil
+		}
+
+		group.Accumulators = append(group.Accumulators, acc)
+	}
+
+	return group, n + 1, fixup
+}
+
+// groupPushdownField returns the top-level field name of the given path expression.
+// It returns false if the value is not such an expression.
+func groupPushdownField(v any) (string, bool) {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "$") || strings.HasPrefix(s, "$$") {
+		return "", false
+	}
+
+	field := strings.TrimPrefix(s, "$")
+	if field == "" || strings.Contains(field, ".") {
+		return "", false
+	}
+
+	return field, true
+}
+
+// groupPushdownConstant returns true if the given value is a constant group key that could be pushed down.
+func groupPushdownConstant(v any) bool {
+	switch v := v.(type) {
+	case int32, int64, float64, bool:
+		return true
+	case string:
+		return !strings.HasPrefix(v, "$")
+	default:
+		return false
+	}
+}
+
 // stagesStatsParams contains the parameters for processStagesStats.
 type stagesStatsParams struct {
 	c          backends.Collection
@@ -509,3 +891,60 @@ func processStagesStats(ctx context.Context, closer *iterator.MultiCloser, p *st
 
 	return iter, nil
 }
+
+// stagesIndexStatsParams contains the parameters for processStagesIndexStats.
+type stagesIndexStatsParams struct {
+	c          backends.Collection
+	indexUsage *indexUsage
+	dbName     string
+	cName      string
+	stages     []aggregations.Stage
+}
+
+// processStagesIndexStats retrieves usage statistics of collection indexes
+// and then processes them through the stages.
+func processStagesIndexStats(ctx context.Context, closer *iterator.MultiCloser, p *stagesIndexStatsParams) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := p.c.ListIndexes(ctx, new(backends.ListIndexesParams))
+	if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+		res = new(backends.ListIndexesResult)
+		err = nil
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	docs := make([]*types.Document, len(res.Indexes))
+
+	for i := range res.Indexes {
+		spec := indexSpec(&res.Indexes[i])
+		ops, since := p.indexUsage.get(p.dbName, p.cName, res.Indexes[i].Name)
+
+		docs[i] = must.NotFail(types.NewDocument(
+			"name", res.Indexes[i].Name,
+			"key", must.NotFail(spec.Get("key")),
+			"host", host,
+			"accesses", must.NotFail(types.NewDocument(
+				"ops", ops,
+				"since", since,
+			)),
+			"spec", spec,
+		))
+	}
+
+	iter := iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	for _, s := range p.stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
@@ -15,16 +15,521 @@
 package handler
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
 
+	"github.com/AlekSi/pointer"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
 // MsgCollMod implements `collMod` command.
 func (h *Handler) MsgCollMod(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	return nil, handlererrors.NewCommandErrorMsg(
-		handlererrors.ErrNotImplemented,
-		"`collMod` command is not implemented yet",
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	unimplementedFields := []string{
+		"expireAfterSeconds",
+		"viewOn",
+		"pipeline",
+		"timeseries",
+		"changeStreamPreAndPostImages",
+		"dryRun",
+	}
+	if err = common.Unimplemented(document, unimplementedFields...); err != nil {
+		return nil, err
+	}
+
+	ignoredFields := []string{
+		"writeConcern",
+		"comment",
+	}
+	common.Ignored(document, h.L, ignoredFields...)
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collectionName, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	validation, err := common.GetValidationParams(document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	indexSpec, err := common.GetOptionalParam[*types.Document](document, "index", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var cappedSize, cappedDocuments *int64
+
+	if v, _ := document.Get("cappedSize"); v != nil {
+		var size int64
+		if size, err = handlerparams.GetValidatedNumberParamWithMinValue(command, "cappedSize", v, 1); err != nil {
+			return nil, err
+		}
+
+		if size%256 != 0 {
+			size = (size/256 + 1) * 256
+		}
+
+		cappedSize = &size
+	}
+
+	if v, _ := document.Get("cappedMax"); v != nil {
+		var max int64
+		if max, err = handlerparams.GetValidatedNumberParamWithMinValue(command, "cappedMax", v, 0); err != nil {
+			return nil, err
+		}
+
+		cappedDocuments = &max
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collectionName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// TODO https://github.com/FerretDB/FerretDB/issues/3601
+	i, found := slices.BinarySearchFunc(list.Collections, collectionName, func(e backends.CollectionInfo, t string) int {
+		return cmp.Compare(e.Name, t)
+	})
+	if !found {
+		msg := fmt.Sprintf("ns does not exist: %s.%s", dbName, collectionName)
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceNotFound, msg, command)
+	}
+
+	info := list.Collections[i]
+
+	if info.View != nil && (indexSpec != nil || validation != nil || cappedSize != nil || cappedDocuments != nil) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"option not supported on a view: index, validator, validationLevel, validationAction, cappedSize, cappedMax",
+			command,
+		)
+	}
+
+	if (cappedSize != nil || cappedDocuments != nil) && !info.Capped() {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"Cannot change the size or maximum number of documents of a non-capped collection",
+			command,
+		)
+	}
+
+	res := must.NotFail(types.NewDocument())
+
+	if indexSpec != nil {
+		var c backends.Collection
+		if c, err = db.Collection(collectionName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		ns := dbName + "." + collectionName
+
+		if res, err = h.collModIndex(ctx, c, command, ns, indexSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	if validation != nil || cappedSize != nil || cappedDocuments != nil {
+		if validation != nil {
+			validation = mergeValidation(info.Validation, validation)
+		}
+
+		err = db.ModifyCollection(ctx, &backends.ModifyCollectionParams{
+			Name:            collectionName,
+			Validation:      validation,
+			CappedSize:      cappedSize,
+			CappedDocuments: cappedDocuments,
+		})
+
+		switch {
+		case err == nil:
+			// nothing
+		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
+			msg := fmt.Sprintf("Invalid collection name: %s", collectionName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+			msg := fmt.Sprintf("ns does not exist: %s.%s", dbName, collectionName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceNotFound, msg, command)
+		default:
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	res.Set("ok", float64(1))
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.OpMsgSection{
+		Documents: []*types.Document{res},
+	}))
+
+	return &reply, nil
+}
+
+// collModIndex modifies the index according to `collMod`'s `index` parameter.
+//
+// It returns a document with old and new values of modified options.
+func (h *Handler) collModIndex(ctx context.Context, c backends.Collection, command, ns string, spec *types.Document) (*types.Document, error) { //nolint:lll // for readability
+	field := func(name string) string {
+		return command + ".index." + name
+	}
+
+	name, err := common.GetOptionalParam(spec, "name", "")
+	if err != nil {
+		return nil, err
+	}
+
+	keyPattern, err := common.GetOptionalParam[*types.Document](spec, "keyPattern", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var params backends.ModifyIndexParams
+
+	for _, key := range spec.Keys() {
+		v := must.NotFail(spec.Get(key))
+
+		switch key {
+		case "name", "keyPattern":
+			// already processed
+
+		case "expireAfterSeconds":
+			var expireAfterSeconds int64
+			if expireAfterSeconds, err = handlerparams.GetValidatedNumberParamWithMinValue(
+				command, "index.expireAfterSeconds", v, 0,
+			); err != nil {
+				return nil, err
+			}
+
+			if expireAfterSeconds > math.MaxInt32 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidOptions,
+					"TTL index 'expireAfterSeconds' option must be within an acceptable range, try a lower number",
+					command,
+				)
+			}
+
+			params.ExpireAfterSeconds = pointer.ToInt32(int32(expireAfterSeconds))
+
+		case "hidden", "prepareUnique", "unique":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s' is the wrong type '%s', expected type 'bool'",
+						field(key), handlerparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+
+			switch key {
+			case "hidden":
+				params.Hidden = &b
+			case "prepareUnique":
+				params.PrepareUnique = &b
+			case "unique":
+				if !b {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrBadValue,
+						"collMod does not support converting an index to 'unique: false'",
+						command,
+					)
+				}
+
+				params.Unique = true
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s' is an unknown field.", field(key)),
+				command,
+			)
+		}
+	}
+
+	switch {
+	case name != "" && keyPattern != nil:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions, "Cannot specify both key pattern and name.", command,
+		)
+	case name == "" && keyPattern == nil:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions, "Must specify either index name or key pattern.", command,
+		)
+	}
+
+	if params.ExpireAfterSeconds == nil && params.Hidden == nil && params.PrepareUnique == nil && !params.Unique {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"no expireAfterSeconds, hidden, prepareUnique or unique field",
+			command,
+		)
+	}
+
+	list, err := c.ListIndexes(ctx, new(backends.ListIndexesParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var index *backends.IndexInfo
+
+	if keyPattern != nil {
+		var key []backends.IndexKeyPair
+		if key, err = processIndexKey(command, keyPattern); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Indexes {
+			if formatIndexKey(list.Indexes[i].Key) == formatIndexKey(key) {
+				index = &list.Indexes[i]
+				break
+			}
+		}
+
+		if index == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexNotFound,
+				fmt.Sprintf("cannot find index %s for ns %s", types.FormatAnyValue(keyPattern), ns),
+				command,
+			)
+		}
+	} else {
+		for i := range list.Indexes {
+			if list.Indexes[i].Name == name {
+				index = &list.Indexes[i]
+				break
+			}
+		}
+
+		if index == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexNotFound,
+				fmt.Sprintf("cannot find index %s for ns %s", name, ns),
+				command,
+			)
+		}
+	}
+
+	if err = validateCollModIndex(command, index, &params); err != nil {
+		return nil, err
+	}
+
+	if params.Unique && !index.Unique {
+		if err = checkIndexConvertibleToUnique(ctx, c, command, index); err != nil {
+			return nil, err
+		}
+	}
+
+	params.Name = index.Name
+
+	if _, err = c.ModifyIndex(ctx, &params); err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeIndexDoesNotExist) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexNotFound,
+				fmt.Sprintf("cannot find index %s for ns %s", index.Name, ns),
+				command,
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := must.NotFail(types.NewDocument())
+
+	if params.ExpireAfterSeconds != nil {
+		if index.ExpireAfterSeconds == nil || *index.ExpireAfterSeconds != *params.ExpireAfterSeconds {
+			if index.ExpireAfterSeconds != nil {
+				res.Set("expireAfterSeconds_old", int64(*index.ExpireAfterSeconds))
+			}
+
+			res.Set("expireAfterSeconds_new", int64(*params.ExpireAfterSeconds))
+		}
+	}
+
+	if params.Hidden != nil && index.Hidden != *params.Hidden {
+		res.Set("hidden_old", index.Hidden)
+		res.Set("hidden_new", *params.Hidden)
+	}
+
+	if params.PrepareUnique != nil && index.PrepareUnique != *params.PrepareUnique {
+		res.Set("prepareUnique_old", index.PrepareUnique)
+		res.Set("prepareUnique_new", *params.PrepareUnique)
+	}
+
+	if params.Unique && !index.Unique {
+		res.Set("unique_new", true)
+	}
+
+	return res, nil
+}
+
+// validateCollModIndex checks that the index modification is allowed.
+func validateCollModIndex(command string, index *backends.IndexInfo, params *backends.ModifyIndexParams) error {
+	isID := index.Name == backends.DefaultIndexName
+	special := isTextIndexKey(index.Key) || geoIndexKeyType(index.Key) != ""
+
+	if params.ExpireAfterSeconds != nil && index.ExpireAfterSeconds == nil {
+		switch {
+		case isID:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				"The field 'expireAfterSeconds' is not valid for an _id index specification.",
+				command,
+			)
+		case len(index.Key) > 1:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				"TTL indexes are single-field indexes, compound indexes do not support TTL.",
+				command,
+			)
+		}
+	}
+
+	if params.Hidden != nil && isID {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue, "can't hide _id index", command,
+		)
+	}
+
+	if (params.PrepareUnique != nil || params.Unique) && (isID || special) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			fmt.Sprintf("index %s can't be converted to unique", index.Name),
+			command,
+		)
+	}
+
+	if params.Unique && !index.Unique && !index.PrepareUnique && (params.PrepareUnique == nil || !*params.PrepareUnique) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"Cannot make index unique with 'prepareUnique=false'. Run collMod to set it first.",
+			command,
+		)
+	}
+
+	return nil
+}
+
+// checkIndexConvertibleToUnique returns an error if the collection contains documents with duplicate index keys.
+func checkIndexConvertibleToUnique(ctx context.Context, c backends.Collection, command string, index *backends.IndexInfo) error {
+	res, err := c.Query(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	// keys are BSON-encoded index keys, values are _id values of documents with that key
+	keys := map[string][]any{}
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		key := indexKey(index, doc)
+		if key == nil {
+			continue
+		}
+
+		var b []byte
+		if b, err = must.NotFail(bson.ConvertDocument(key)).MarshalBinary(); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		keys[string(b)] = append(keys[string(b)], must.NotFail(doc.Get("_id")))
+	}
+
+	var violations []any
+
+	for _, ids := range keys {
+		if len(ids) > 1 {
+			violations = append(violations, must.NotFail(types.NewDocument("ids", must.NotFail(types.NewArray(ids...)))))
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrCannotConvertIndexToUnique,
+		fmt.Sprintf(
+			"Cannot convert the index to unique. Please resolve conflicting documents before running collMod again. "+
+				"Violations: %s",
+			types.FormatAnyValue(must.NotFail(types.NewArray(violations...))),
+		),
+		command,
 	)
 }
+
+// mergeValidation returns collection validation settings with set parameters replaced by the new ones.
+// Parameters that were never set get default values.
+func mergeValidation(current, update *backends.CollectionValidation) *backends.CollectionValidation {
+	res := &backends.CollectionValidation{
+		Level:  common.ValidationLevelStrict,
+		Action: common.ValidationActionError,
+	}
+
+	if current != nil {
+		res = current.DeepCopy()
+	}
+
+	if update.Validator != nil {
+		res.Validator = update.Validator
+	}
+
+	if update.Level != "" {
+		res.Level = update.Level
+	}
+
+	if update.Action != "" {
+		res.Action = update.Action
+	}
+
+	return res
+}
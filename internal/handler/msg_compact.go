@@ -75,6 +75,10 @@ func (h *Handler) MsgCompact(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, dbName, collection, command); err != nil {
+		return nil, err
+	}
+
 	var force bool
 
 	if v, _ := document.Get("force"); v != nil {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -61,21 +62,82 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 		return nil, lazyerrors.Error(err)
 	}
 
-	var qp backends.QueryParams
-	if !h.DisableFilterPushdown {
-		qp.Filter = params.Filter
-	}
-
-	queryRes, err := c.Query(ctx, &qp)
+	view, err := h.getView(ctx, db, params.DB, params.Collection)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, err
 	}
 
-	iter := queryRes.Iter
-
-	closer := iterator.NewMultiCloser(iter)
+	closer := iterator.NewMultiCloser()
 	defer closer.Close()
 
+	var iter types.DocumentsIterator
+
+	if view != nil {
+		if iter, err = h.queryView(ctx, view, closer); err != nil {
+			return nil, err
+		}
+	} else {
+		var qp backends.QueryParams
+		if !h.DisableFilterPushdown {
+			qp.Filter = params.Filter
+		}
+
+		if qp.Hint, err = getHintIndex(ctx, c, "count", params.Hint); err != nil {
+			return nil, err
+		}
+
+		// `estimatedDocumentCount` sends `count` command without query;
+		// like MongoDB, answer it from the collection's statistics
+		if params.Filter.Len() == 0 && params.Skip == 0 && params.Limit == 0 && qp.Hint == "" {
+			var n int64
+			var ok bool
+
+			if n, ok, err = estimatedCount(ctx, c); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if ok {
+				return countReply(n), nil
+			}
+		}
+
+		// Counting is pushed down if there is no filter, or if the whole filter is pushed down too.
+		// In both cases, it can't use the hinted index.
+		if (!h.DisableFilterPushdown || params.Filter.Len() == 0) && qp.Hint == "" {
+			qp.Group = &backends.GroupParams{
+				Accumulators: []backends.GroupAccumulator{{Output: "n", Operator: "$sum", Value: int32(1)}},
+			}
+		}
+
+		var queryRes *backends.QueryResult
+
+		if queryRes, err = c.Query(ctx, &qp); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.indexUsage.record(params.DB, params.Collection, queryRes.Index)
+
+		closer.Add(queryRes.Iter)
+
+		if queryRes.GroupPushdown {
+			var n int64
+
+			if n, err = groupCount(queryRes.Iter, "n"); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			n = max(n-params.Skip, 0)
+
+			if params.Limit > 0 {
+				n = min(n, params.Limit)
+			}
+
+			return countReply(n), nil
+		}
+
+		iter = queryRes.Iter
+	}
+
 	iter = common.FilterIterator(iter, closer, params.Filter)
 
 	iter = common.SkipIterator(iter, closer, params.Skip)
@@ -96,13 +158,70 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 	count, _ := res.Get("count")
 	n, _ := count.(int32)
 
+	return countReply(int64(n)), nil
+}
+
+// estimatedCountMin is the minimal number of documents in the collection
+// for which `count` without query returns the collection's statistics.
+//
+// Statistics are estimations, so smaller collections are counted exactly.
+const estimatedCountMin = 100_000
+
+// estimatedCount returns the number of documents in the collection from its statistics,
+// or false if the collection is too small (see estimatedCountMin), has no statistics yet
+// (for example, PostgreSQL table that was never analyzed), or does not exist.
+func estimatedCount(ctx context.Context, c backends.Collection) (int64, bool, error) {
+	stats, err := c.Stats(ctx, new(backends.CollectionStatsParams))
+
+	switch {
+	case err == nil:
+		return stats.CountDocuments, stats.CountDocuments >= estimatedCountMin, nil
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		return 0, false, nil
+	default:
+		return 0, false, lazyerrors.Error(err)
+	}
+}
+
+// groupCount returns the value of the given field of the only document
+// returned by the backend for the pushed down counting group.
+// No documents are returned if nothing was counted.
+func groupCount(iter types.DocumentsIterator, field string) (int64, error) {
+	defer iter.Close()
+
+	_, doc, err := iter.Next()
+	if errors.Is(err, iterator.ErrIteratorDone) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	switch n := must.NotFail(doc.Get(field)).(type) {
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	default:
+		return 0, lazyerrors.Errorf("unexpected count %[1]v (%[1]T)", n)
+	}
+}
+
+// countReply returns `count` command reply with the given number of documents.
+func countReply(n int64) *wire.OpMsg {
+	var count any = n
+	if n <= math.MaxInt32 {
+		count = int32(n)
+	}
+
 	var reply wire.OpMsg
 	must.NoError(reply.SetSections(wire.OpMsgSection{
 		Documents: []*types.Document{must.NotFail(types.NewDocument(
-			"n", n,
+			"n", count,
 			"ok", float64(1),
 		))},
 	}))
 
-	return &reply, nil
+	return &reply
 }
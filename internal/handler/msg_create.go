@@ -38,11 +38,6 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	unimplementedFields := []string{
 		"timeseries",
 		"expireAfterSeconds",
-		"validator",
-		"validationLevel",
-		"validationAction",
-		"viewOn",
-		"pipeline",
 		"collation",
 	}
 	if err = common.Unimplemented(document, unimplementedFields...); err != nil {
@@ -110,6 +105,50 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		}
 	}
 
+	validation, err := common.GetValidationParams(document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	if validation != nil {
+		params.Validation = mergeValidation(nil, validation)
+	}
+
+	viewOn, err := common.GetOptionalParam(document, "viewOn", "")
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := common.GetOptionalParam[*types.Array](document, "pipeline", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if pipeline != nil && viewOn == "" {
+		msg := "'pipeline' requires 'viewOn' to also be specified"
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, "create")
+	}
+
+	if viewOn != "" {
+		if params.Capped() || validation != nil {
+			msg := "option not supported on a view: capped, validator, validationLevel, validationAction"
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, "create")
+		}
+
+		if pipeline == nil {
+			pipeline = types.MakeArray(0)
+		}
+
+		if err = validateViewPipeline(pipeline, "create"); err != nil {
+			return nil, err
+		}
+
+		params.View = &backends.CollectionView{
+			ViewOn:   viewOn,
+			Pipeline: pipeline,
+		}
+	}
+
 	db, err := h.b.Database(dbName)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -120,6 +159,17 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if params.View != nil {
+		var list *backends.ListCollectionsResult
+		if list, err = db.ListCollections(ctx, new(backends.ListCollectionsParams)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err = checkViewCycle(list.Collections, dbName, collectionName, viewOn, "create"); err != nil {
+			return nil, err
+		}
+	}
+
 	err = db.CreateCollection(ctx, &params)
 
 	switch {
@@ -18,13 +18,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"strings"
 
+	"github.com/AlekSi/pointer"
+
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/geo"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/handler/textsearch"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -32,6 +37,12 @@ import (
 	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
+// textIndexVersion is the only supported version of text indexes.
+const textIndexVersion = 3
+
+// geoIndexVersion is the default and the latest supported 2dsphereIndexVersion.
+const geoIndexVersion = 3
+
 // MsgCreateIndexes implements `createIndexes` command.
 func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
 	document, err := msg.Document()
@@ -77,6 +88,10 @@ func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, dbName, collection, command); err != nil {
+		return nil, err
+	}
+
 	v, _ := document.Get("indexes")
 	if v == nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -152,6 +167,17 @@ func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
+	created := make([]string, 0, len(toCreate)+1)
+	if createCollection {
+		created = append(created, backends.DefaultIndexName)
+	}
+
+	for _, index := range toCreate {
+		created = append(created, index.Name)
+	}
+
+	h.indexUsage.reset(dbName, collection, created)
+
 	resp := new(types.Document)
 
 	resp.Set("numIndexesBefore", int32(numIndexesBefore))
@@ -242,6 +268,36 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 				)
 			}
 
+			if isTextIndexKey(index.Key) {
+				if err = processTextIndexOptions(command, indexDoc, &index); err != nil {
+					return nil, err
+				}
+			}
+
+			if geoIndexKeyType(index.Key) != "" {
+				if err = processGeoIndexOptions(command, indexDoc, &index); err != nil {
+					return nil, err
+				}
+			}
+
+			if isWildcardIndexKey(index.Key) {
+				if err = processWildcardIndexOptions(command, indexDoc, &index); err != nil {
+					return nil, err
+				}
+			}
+
+			if index.Sparse || indexDoc.Has("partialFilterExpression") {
+				if err = processPartialIndexOptions(command, indexDoc, &index); err != nil {
+					return nil, err
+				}
+			}
+
+			if indexDoc.Has("expireAfterSeconds") {
+				if err = processTTLIndexOptions(command, indexDoc, &index); err != nil {
+					return nil, err
+				}
+			}
+
 			return &index, nil
 		default:
 			return nil, lazyerrors.Error(err)
@@ -354,9 +410,96 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 		case "background":
 			// ignore deprecated options
 
-		case "sparse", "partialFilterExpression", "expireAfterSeconds", "hidden", "storageEngine",
-			"weights", "default_language", "language_override", "textIndexVersion", "2dsphereIndexVersion",
-			"bits", "min", "max", "bucketSize", "collation", "wildcardProjection":
+		case "weights", "default_language", "language_override", "textIndexVersion":
+			if !isTextIndexKey(index.Key) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidIndexSpecificationOption,
+					fmt.Sprintf("The field '%s' is valid only for text indexes", opt),
+					command,
+				)
+			}
+
+			// processed by processTextIndexOptions
+
+		case "2dsphereIndexVersion":
+			if geoIndexKeyType(index.Key) != backends.IndexKeyType2DSphere {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidIndexSpecificationOption,
+					fmt.Sprintf("The field '%s' is valid only for 2dsphere indexes", opt),
+					command,
+				)
+			}
+
+			// processed by processGeoIndexOptions
+
+		case "bits", "min", "max":
+			if geoIndexKeyType(index.Key) != backends.IndexKeyType2D {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidIndexSpecificationOption,
+					fmt.Sprintf("The field '%s' is valid only for 2d indexes", opt),
+					command,
+				)
+			}
+
+			// processed by processGeoIndexOptions
+
+		case "expireAfterSeconds":
+			// processed by processTTLIndexOptions
+
+		case "sparse":
+			v := must.NotFail(indexDoc.Get("sparse"))
+
+			switch v := v.(type) {
+			case bool:
+				index.Sparse = v
+			case int32, int64, float64:
+				index.Sparse = types.Compare(v, int32(0)) != types.Equal
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"The field 'sparse' must be a boolean or a number, but got %s",
+						handlerparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+
+		case "partialFilterExpression":
+			// processed by processPartialIndexOptions
+
+		case "wildcardProjection":
+			if !isWildcardIndexKey(index.Key) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"The field 'wildcardProjection' is only allowed in an '$**' index",
+					command,
+				)
+			}
+
+			// processed by processWildcardIndexOptions
+
+		case "hidden":
+			v := must.NotFail(indexDoc.Get("hidden"))
+
+			hidden, ok := v.(bool)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("The field 'hidden' must be a boolean, but got %s", handlerparams.AliasFromType(v)),
+					command,
+				)
+			}
+
+			if hidden && len(index.Key) == 1 && index.Key[0].Field == "_id" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue, "can't hide _id index", command,
+				)
+			}
+
+			index.Hidden = hidden
+
+		case "storageEngine", "bucketSize", "collation":
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrNotImplemented,
 				fmt.Sprintf("Index option %q is not implemented yet", opt),
@@ -373,6 +516,660 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 	}
 }
 
+// isTextIndexKey returns true if the given index key contains text fields.
+func isTextIndexKey(key []backends.IndexKeyPair) bool {
+	return slices.ContainsFunc(key, func(pair backends.IndexKeyPair) bool {
+		return pair.Type == backends.IndexKeyTypeText
+	})
+}
+
+// processTextIndexOptions validates the text index key and sets text index options from the given index document.
+func processTextIndexOptions(command string, indexDoc *types.Document, index *backends.IndexInfo) error {
+	if index.Unique {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"Text indexes cannot be unique",
+			command,
+		)
+	}
+
+	weights := make(map[string]int32, len(index.Key))
+
+	for _, pair := range index.Key {
+		if pair.Type != backends.IndexKeyTypeText {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				"Compound text indexes are not implemented yet",
+				command,
+			)
+		}
+
+		weights[pair.Field] = 1
+	}
+
+	if v, _ := indexDoc.Get("weights"); v != nil {
+		weightsDoc, ok := v.(*types.Document)
+		if !ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"text index option 'weights' must be an object",
+				command,
+			)
+		}
+
+		iter := weightsDoc.Iterator()
+		defer iter.Close()
+
+		for {
+			field, w, err := iter.Next()
+			if err != nil {
+				if errors.Is(err, iterator.ErrIteratorDone) {
+					break
+				}
+
+				return lazyerrors.Error(err)
+			}
+
+			weight, err := handlerparams.GetWholeNumberParam(w)
+			if err != nil || weight <= 0 || weight >= 100_000 {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCannotCreateIndex,
+					fmt.Sprintf(
+						"text index weights must be in the exclusive interval (0,99999) but found: %s",
+						types.FormatAnyValue(w),
+					),
+					command,
+				)
+			}
+
+			weights[field] = int32(weight)
+		}
+	}
+
+	text := &backends.TextIndexOptions{
+		DefaultLanguage:  textsearch.DefaultLanguage,
+		LanguageOverride: textsearch.DefaultLanguageOverride,
+	}
+
+	if v, _ := indexDoc.Get("default_language"); v != nil {
+		language, ok := v.(string)
+		if !ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"text index option 'default_language' must be a string",
+				command,
+			)
+		}
+
+		if err := textsearch.ValidateLanguage(language); err != nil {
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrCannotCreateIndex, err.Error(), command)
+		}
+
+		text.DefaultLanguage = language
+	}
+
+	if v, _ := indexDoc.Get("language_override"); v != nil {
+		field, ok := v.(string)
+		if !ok || field == "" {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"text index option 'language_override' must be a non-empty string",
+				command,
+			)
+		}
+
+		text.LanguageOverride = field
+	}
+
+	if v, _ := indexDoc.Get("textIndexVersion"); v != nil {
+		version, err := handlerparams.GetWholeNumberParam(v)
+		if err != nil || version != textIndexVersion {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				fmt.Sprintf("Currently only textIndexVersion %d is supported, not %s", textIndexVersion, types.FormatAnyValue(v)),
+				command,
+			)
+		}
+	}
+
+	for field, weight := range weights {
+		text.Weights = append(text.Weights, backends.TextIndexWeight{Field: field, Weight: weight})
+	}
+
+	slices.SortFunc(text.Weights, func(a, b backends.TextIndexWeight) int { return strings.Compare(a.Field, b.Field) })
+
+	index.Text = text
+
+	return nil
+}
+
+// geoIndexKeyType returns the type of the geospatial field of the given index key,
+// or an empty string if there is no such field.
+func geoIndexKeyType(key []backends.IndexKeyPair) backends.IndexKeyType {
+	for _, pair := range key {
+		switch pair.Type {
+		case backends.IndexKeyType2DSphere, backends.IndexKeyType2D:
+			return pair.Type
+		}
+	}
+
+	return ""
+}
+
+// processGeoIndexOptions validates the geospatial index key and sets geospatial index options
+// from the given index document.
+func processGeoIndexOptions(command string, indexDoc *types.Document, index *backends.IndexInfo) error {
+	if index.Unique {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Unique geospatial indexes are not implemented yet",
+			command,
+		)
+	}
+
+	var geoFields int
+
+	for _, pair := range index.Key {
+		if pair.Type != "" {
+			geoFields++
+		}
+	}
+
+	if geoFields > 1 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Indexes with several geospatial fields are not implemented yet",
+			command,
+		)
+	}
+
+	typ := geoIndexKeyType(index.Key)
+
+	if typ == backends.IndexKeyType2D && index.Key[0].Type != typ {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"2d has to be first in index",
+			command,
+		)
+	}
+
+	var opts backends.GeoIndexOptions
+
+	if typ == backends.IndexKeyType2DSphere {
+		opts.Version = geoIndexVersion
+
+		if v, _ := indexDoc.Get("2dsphereIndexVersion"); v != nil {
+			version, err := handlerparams.GetWholeNumberParam(v)
+			if err != nil || version < 1 || version > geoIndexVersion {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCannotCreateIndex,
+					fmt.Sprintf(
+						"unsupported geo index version { 2dsphereIndexVersion : %s }, only support versions: [1,2,3]",
+						types.FormatAnyValue(v),
+					),
+					command,
+				)
+			}
+
+			opts.Version = int32(version)
+		}
+
+		index.Geo = &opts
+
+		return nil
+	}
+
+	if v, _ := indexDoc.Get("bits"); v != nil {
+		bits, err := handlerparams.GetWholeNumberParam(v)
+		if err != nil || bits < 1 || bits > 32 {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				"bits in geo index must be between 1 and 32",
+				command,
+			)
+		}
+
+		opts.Bits = int32(bits)
+	}
+
+	for _, opt := range []string{"min", "max"} {
+		v, _ := indexDoc.Get(opt)
+		if v == nil {
+			continue
+		}
+
+		f, ok := geo.Number(v)
+		if !ok || math.IsInf(f, 0) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("geo index option '%s' must be a finite number", opt),
+				command,
+			)
+		}
+
+		if opt == "min" {
+			opts.Min = &f
+		} else {
+			opts.Max = &f
+		}
+	}
+
+	lo, hi := -180.0, 180.0
+	if opts.Min != nil {
+		lo = *opts.Min
+	}
+
+	if opts.Max != nil {
+		hi = *opts.Max
+	}
+
+	if lo >= hi {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"max must be greater than min",
+			command,
+		)
+	}
+
+	index.Geo = &opts
+
+	return nil
+}
+
+// isWildcardIndexKey returns true if the given index key contains wildcard fields.
+func isWildcardIndexKey(key []backends.IndexKeyPair) bool {
+	return slices.ContainsFunc(key, func(pair backends.IndexKeyPair) bool {
+		return pair.Type == "" && backends.IsWildcardIndexKey(pair.Field)
+	})
+}
+
+// processWildcardIndexOptions validates the wildcard index and sets wildcardProjection from the given index document.
+func processWildcardIndexOptions(command string, indexDoc *types.Document, index *backends.IndexInfo) error {
+	if len(index.Key) > 1 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"wildcard indexes do not allow compounding",
+			command,
+		)
+	}
+
+	if index.Key[0].Descending {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"A numeric value in a $** index key pattern must be positive.",
+			command,
+		)
+	}
+
+	for _, opt := range []struct {
+		name string
+		set  bool
+	}{
+		{"unique", index.Unique},
+		{"sparse", index.Sparse},
+		{"expireAfterSeconds", indexDoc.Has("expireAfterSeconds")},
+	} {
+		if !opt.set {
+			continue
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			fmt.Sprintf("Index type 'wildcard' does not support the %s option", opt.name),
+			command,
+		)
+	}
+
+	if indexDoc.Has("partialFilterExpression") {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			`Index option "partialFilterExpression" is not implemented yet for wildcard indexes`,
+			command,
+		)
+	}
+
+	v, _ := indexDoc.Get("wildcardProjection")
+	if v == nil {
+		return nil
+	}
+
+	projection, ok := v.(*types.Document)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"The field 'wildcardProjection' must be a non-empty object, but got %s",
+				handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if index.Key[0].Field != "$**" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			`The field 'wildcardProjection' is only allowed when 'key' is {"$**": ±1}`,
+			command,
+		)
+	}
+
+	if projection.Len() == 0 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"The 'wildcardProjection' field can't be an empty object",
+			command,
+		)
+	}
+
+	var inclusion *bool
+
+	values := projection.Values()
+
+	for i, field := range projection.Keys() {
+		var included bool
+
+		switch v := values[i].(type) {
+		case bool:
+			included = v
+		case int32, int64, float64:
+			included = types.Compare(v, int32(0)) != types.Equal
+		case *types.Document:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				"Nested fields of 'wildcardProjection' are not implemented yet, use dot notation instead",
+				command,
+			)
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf(
+					"The field 'wildcardProjection' must contain only boolean or numeric values, but got %s for %q",
+					handlerparams.AliasFromType(v), field,
+				),
+				command,
+			)
+		}
+
+		// _id could be included or excluded independently of other fields
+		if field == "_id" {
+			continue
+		}
+
+		if inclusion == nil {
+			inclusion = &included
+			continue
+		}
+
+		if *inclusion == included {
+			continue
+		}
+
+		if *inclusion {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrProjectionExIn,
+				fmt.Sprintf("Cannot do exclusion on field %s in inclusion projection", field),
+				command,
+			)
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrProjectionInEx,
+			fmt.Sprintf("Cannot do inclusion on field %s in exclusion projection", field),
+			command,
+		)
+	}
+
+	index.WildcardProjection = projection
+
+	return nil
+}
+
+// processTTLIndexOptions validates the TTL index key and sets expireAfterSeconds from the given index document.
+func processTTLIndexOptions(command string, indexDoc *types.Document, index *backends.IndexInfo) error {
+	v := must.NotFail(indexDoc.Get("expireAfterSeconds"))
+
+	var expireAfterSeconds float64
+
+	switch v := v.(type) {
+	case float64:
+		expireAfterSeconds = v
+	case int32:
+		expireAfterSeconds = float64(v)
+	case int64:
+		expireAfterSeconds = float64(v)
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			fmt.Sprintf(
+				"TTL index 'expireAfterSeconds' option must be numeric, but received a type of '%s'. Index spec: %s",
+				handlerparams.AliasFromType(v), types.FormatAnyValue(indexDoc),
+			),
+			command,
+		)
+	}
+
+	if math.IsNaN(expireAfterSeconds) || expireAfterSeconds < 0 || expireAfterSeconds > math.MaxInt32 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"TTL index 'expireAfterSeconds' option must be within an acceptable range, try a lower number",
+			command,
+		)
+	}
+
+	if len(index.Key) == 1 && index.Key[0].Field == "_id" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidIndexSpecificationOption,
+			fmt.Sprintf(
+				"The field 'expireAfterSeconds' is not valid for an _id index specification. Specification: %s",
+				types.FormatAnyValue(indexDoc),
+			),
+			command,
+		)
+	}
+
+	if len(index.Key) > 1 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			fmt.Sprintf(
+				"TTL indexes are single-field indexes, compound indexes do not support TTL. Index spec: %s",
+				types.FormatAnyValue(indexDoc),
+			),
+			command,
+		)
+	}
+
+	index.ExpireAfterSeconds = pointer.ToInt32(int32(expireAfterSeconds))
+
+	return nil
+}
+
+// processPartialIndexOptions validates the sparse or partial index
+// and sets partialFilterExpression from the given index document.
+func processPartialIndexOptions(command string, indexDoc *types.Document, index *backends.IndexInfo) error {
+	v, _ := indexDoc.Get("partialFilterExpression")
+
+	if v != nil && index.Sparse {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			`cannot mix "partialFilterExpression" and "sparse" options`,
+			command,
+		)
+	}
+
+	opt := "sparse"
+	if v != nil {
+		opt = "partialFilterExpression"
+	}
+
+	if len(index.Key) == 1 && index.Key[0].Field == "_id" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidIndexSpecificationOption,
+			fmt.Sprintf(
+				"The field '%s' is not valid for an _id index specification. Specification: %s",
+				opt, types.FormatAnyValue(indexDoc),
+			),
+			command,
+		)
+	}
+
+	if index.Text != nil || index.Geo != nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			fmt.Sprintf("Index option %q is not implemented yet for text and geospatial indexes", opt),
+			command,
+		)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	filter, ok := v.(*types.Document)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"The field 'partialFilterExpression' must be an object, but got %s",
+				handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if err := validatePartialFilterExpression(command, filter, true); err != nil {
+		return err
+	}
+
+	index.PartialFilterExpression = filter
+
+	return nil
+}
+
+// validatePartialFilterExpression returns an error if the given partial index filter expression
+// (or its $and element if topLevel is false) contains operators or values
+// not supported by backends; see [backends.PartialFilterConditions].
+func validatePartialFilterExpression(command string, filter *types.Document, topLevel bool) error {
+	values := filter.Values()
+
+	for i, field := range filter.Keys() {
+		v := values[i]
+
+		if field == "$and" && topLevel {
+			arr, ok := v.(*types.Array)
+			if !ok || arr.Len() == 0 {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue, "$and must be a nonempty array", command,
+				)
+			}
+
+			iter := arr.Iterator()
+			defer iter.Close()
+
+			for _, e := range must.NotFail(iterator.ConsumeValues(iter)) {
+				d, ok := e.(*types.Document)
+				if !ok {
+					return handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrBadValue, "$and's elements must be objects", command,
+					)
+				}
+
+				if err := validatePartialFilterExpression(command, d, false); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(field, "$") {
+			return newPartialFilterExpressionError(field, command)
+		}
+
+		if _, err := types.NewPathFromString(field); err != nil {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("Invalid field name in partialFilterExpression: %q", field),
+				command,
+			)
+		}
+
+		expr, ok := v.(*types.Document)
+		if !ok || expr.Len() == 0 || !strings.HasPrefix(expr.Keys()[0], "$") {
+			if err := validatePartialFilterValue(command, "$eq", v); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		exprValues := expr.Values()
+
+		for j, op := range expr.Keys() {
+			switch op {
+			case "$exists":
+				if b, ok := exprValues[j].(bool); !ok || !b {
+					return newPartialFilterExpressionError(op, command)
+				}
+
+			case "$eq", "$gt", "$gte", "$lt", "$lte":
+				if err := validatePartialFilterValue(command, op, exprValues[j]); err != nil {
+					return err
+				}
+
+			case "$type", "$in", "$or":
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("Operator %s in partialFilterExpression is not implemented yet", op),
+					command,
+				)
+
+			default:
+				return newPartialFilterExpressionError(op, command)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatePartialFilterValue returns an error if the value of the given partial index filter operator
+// is not supported by backends.
+func validatePartialFilterValue(command, op string, v any) error {
+	switch v := v.(type) {
+	case string, bool:
+		if op == "$eq" {
+			return nil
+		}
+
+	case int32, int64:
+		return nil
+
+	case float64:
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return nil
+		}
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrNotImplemented,
+		fmt.Sprintf(
+			"Operator %s with %s value in partialFilterExpression is not implemented yet",
+			op, handlerparams.AliasFromType(v),
+		),
+		command,
+	)
+}
+
+// newPartialFilterExpressionError returns an error for the query operator
+// that can't be used in the partial index filter expression.
+func newPartialFilterExpressionError(operator, command string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrCannotCreateIndex,
+		fmt.Sprintf("Expression not supported in partial index: %s", operator),
+		command,
+	)
+}
+
 // processIndexKey processes the document containing the index key (set of "field-order" pairs).
 func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKeyPair, error) {
 	res := make([]backends.IndexKeyPair, 0, keyDoc.Len())
@@ -407,6 +1204,18 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 
 		duplicateChecker[field] = struct{}{}
 
+		if typ, ok := order.(string); ok {
+			switch backends.IndexKeyType(typ) {
+			case backends.IndexKeyTypeText, backends.IndexKeyType2DSphere, backends.IndexKeyType2D:
+				res = append(res, backends.IndexKeyPair{
+					Field: field,
+					Type:  backends.IndexKeyType(typ),
+				})
+
+				continue
+			}
+		}
+
 		var orderParam int64
 
 		if orderParam, err = handlerparams.GetWholeNumberParam(order); err != nil {
@@ -440,10 +1249,21 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 }
 
 // formatIndexKey formats the given index key to a string.
+//
+// All text indexes have the same key, as MongoDB reports it.
 func formatIndexKey(key []backends.IndexKeyPair) string {
+	if isTextIndexKey(key) {
+		return `_fts: "text", _ftsx: 1`
+	}
+
 	res := make([]string, len(key))
 
 	for i, pair := range key {
+		if pair.Type != "" {
+			res[i] = fmt.Sprintf("%s: %q", pair.Field, pair.Type)
+			continue
+		}
+
 		order := "1"
 		if pair.Descending {
 			order = "-1"
@@ -63,11 +63,15 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, params.DB, params.Collection, document.Command()); err != nil {
+		return nil, err
+	}
+
 	var deleted int32
 	writeErrors := types.MakeArray(0)
 
 	for i, p := range params.Deletes {
-		d, err := h.execDelete(ctx, c, &p)
+		d, err := h.execDelete(ctx, c, params.DB, params.Collection, &p)
 
 		deleted += d
 
@@ -115,17 +119,35 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 //
 // It returns a number of deleted documents or error.
 // The error is either a (wrapped) *handlererrors.CommandError or something fatal.
-func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *common.Delete) (int32, error) {
+func (h *Handler) execDelete(ctx context.Context, c backends.Collection, dbName, cName string, p *common.Delete) (int32, error) { //nolint:lll // for readability
 	var qp backends.QueryParams
 	if !h.DisableFilterPushdown {
 		qp.Filter = p.Filter
 	}
 
+	var err error
+	if qp.Hint, err = getHintIndex(ctx, c, "delete", p.Hint); err != nil {
+		return 0, err
+	}
+
+	if qp.Filter != nil && qp.Hint == "" {
+		var d *backends.DeleteAllResult
+		if d, err = c.DeleteAll(ctx, &backends.DeleteAllParams{Filter: qp.Filter, Limited: p.Limited}); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		if d.FilterPushdown {
+			return d.Deleted, nil
+		}
+	}
+
 	q, err := c.Query(ctx, &qp)
 	if err != nil {
 		return 0, lazyerrors.Error(err)
 	}
 
+	h.indexUsage.record(dbName, cName, q.Index)
+
 	var ids []any
 	for {
 		var doc *types.Document
@@ -17,6 +17,7 @@ package handler
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -60,25 +61,61 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		return nil, lazyerrors.Error(err)
 	}
 
+	view, err := h.getView(ctx, db, params.DB, params.Collection)
+	if err != nil {
+		return nil, err
+	}
+
 	closer := iterator.NewMultiCloser()
 	defer closer.Close()
 
-	var qp backends.QueryParams
-	if !h.DisableFilterPushdown {
-		qp.Filter = params.Filter
-	}
+	var iter types.DocumentsIterator
+	var groupPushdown bool
 
-	// TODO https://github.com/FerretDB/FerretDB/issues/3235
-	queryRes, err := c.Query(ctx, &qp)
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+	if view != nil {
+		if iter, err = h.queryView(ctx, view, closer); err != nil {
+			return nil, err
+		}
+	} else {
+		var qp backends.QueryParams
+		if !h.DisableFilterPushdown {
+			qp.Filter = params.Filter
+
+			// grouping pushdown requires the whole filter to be pushed down too;
+			// if some values (for example, arrays) can't be grouped, backends return all documents
+			if params.Key != "" && !strings.ContainsRune(params.Key, '.') && !strings.HasPrefix(params.Key, "$") {
+				qp.Group = &backends.GroupParams{
+					Key:            params.Key,
+					SkipMissingKey: true,
+				}
+			}
+		}
+
+		var queryRes *backends.QueryResult
+
+		// TODO https://github.com/FerretDB/FerretDB/issues/3235
+		if queryRes, err = c.Query(ctx, &qp); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.indexUsage.record(params.DB, params.Collection, queryRes.Index)
+
+		closer.Add(queryRes.Iter)
+
+		iter = queryRes.Iter
+		groupPushdown = queryRes.GroupPushdown
 	}
 
-	closer.Add(queryRes.Iter)
+	key := params.Key
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter)
+	if groupPushdown {
+		// documents are already filtered and grouped by the key
+		key = "_id"
+	} else {
+		iter = common.FilterIterator(iter, closer, params.Filter)
+	}
 
-	distinct, err := common.FilterDistinctValues(iter, params.Key)
+	distinct, err := common.FilterDistinctValues(iter, key)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
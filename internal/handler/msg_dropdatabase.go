@@ -58,6 +58,7 @@ func (h *Handler) MsgDropDatabase(ctx context.Context, msg *wire.OpMsg) (*wire.O
 
 	switch {
 	case err == nil:
+		h.indexUsage.forget(dbName, "")
 		res.Set("dropped", dbName)
 	case backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid):
 		// nothing?
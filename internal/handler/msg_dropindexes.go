@@ -69,6 +69,10 @@ func (h *Handler) MsgDropIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, dbName, collection, command); err != nil {
+		return nil, err
+	}
+
 	indexValue, err := document.Get("index")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -101,6 +105,10 @@ func (h *Handler) MsgDropIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, lazyerrors.Error(err)
 	}
 
+	if len(toDrop) > 0 {
+		h.indexUsage.forget(dbName, collection, toDrop...)
+	}
+
 	replyDoc := must.NotFail(types.NewDocument(
 		"nIndexesWas", int32(len(beforeDrop.Indexes)),
 	))
@@ -147,16 +155,7 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 		}
 
 		for _, index := range existing {
-			matches := true
-
-			for i, key := range index.Key {
-				if key.Field != spec[i].Field || key.Descending != spec[i].Descending {
-					matches = false
-					break
-				}
-			}
-
-			if matches {
+			if formatIndexKey(index.Key) == formatIndexKey(spec) {
 				return []string{index.Name}, false, nil
 			}
 		}
@@ -101,21 +101,32 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, err
 	}
 
-	// Skip sorting if there are more than one sort parameters
-	if params.Sort.Len() == 1 {
+	// Skip sorting if text score is used
+	if params.Sort.Len() > 0 && !common.HasTextScoreMeta(params.Sort) {
 		qp.Sort = params.Sort
 	}
 
-	// Limit pushdown is not applied if:
-	//  - `filter` is set, it must fetch all documents to filter them in memory;
-	//  - `sort` is set, it must fetch all documents and sort them in memory;
-	//  - `skip` is non-zero value, skip pushdown is not supported yet.
-	if params.Filter.Len() == 0 && params.Sort.Len() == 0 && params.Skip == 0 {
+	if h.DisableFilterPushdown {
+		qp.Filter = nil
+	}
+
+	// Skip and limit pushdown is not applied if `sort` is set,
+	// or if `filter` is set, but filter pushdown is disabled (see MsgFind).
+	if params.Sort.Len() == 0 && (params.Filter.Len() == 0 || qp.Filter != nil) {
+		qp.Skip = params.Skip
 		qp.Limit = params.Limit
 	}
 
-	if h.DisableFilterPushdown {
-		qp.Filter = nil
+	if params.Aggregate && !h.DisableFilterPushdown && qp.Sort == nil {
+		qp.Group, _, _ = getGroupPushdown(params.StagesDocs)
+	}
+
+	if params.Update != nil && !h.DisableFilterPushdown {
+		qp.Update = getUpdatePushdown(params.Update)
+	}
+
+	if qp.Hint, err = getHintIndex(ctx, coll, document.Command(), params.Hint); err != nil {
+		return nil, err
 	}
 
 	res, err := coll.Explain(ctx, &qp)
@@ -123,23 +134,73 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	winningPlan, err := explainWinningPlan(ctx, coll, res.Index)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res.QueryPlanner.Set("winningPlan", winningPlan)
+
+	resDoc := must.NotFail(types.NewDocument(
+		"queryPlanner", res.QueryPlanner,
+		"explainVersion", "1",
+		"command", cmd,
+		"serverInfo", serverInfo,
+
+		// our extensions
+		// TODO https://github.com/FerretDB/FerretDB/issues/3235
+		"filterPushdown", res.FilterPushdown,
+		"sortPushdown", res.SortPushdown,
+		"skipPushdown", res.SkipPushdown,
+		"limitPushdown", res.LimitPushdown,
+		"groupPushdown", res.GroupPushdown,
+		"groupPushdownFallback", res.GroupFallback,
+		"updatePushdown", res.UpdatePushdown,
+	))
+
+	if res.Index != "" {
+		resDoc.Set("indexName", res.Index)
+	}
+
+	resDoc.Set("ok", float64(1))
+
 	var reply wire.OpMsg
 	must.NoError(reply.SetSections(wire.OpMsgSection{
-		Documents: []*types.Document{must.NotFail(types.NewDocument(
-			"queryPlanner", res.QueryPlanner,
-			"explainVersion", "1",
-			"command", cmd,
-			"serverInfo", serverInfo,
-
-			// our extensions
-			// TODO https://github.com/FerretDB/FerretDB/issues/3235
-			"filterPushdown", res.FilterPushdown,
-			"sortPushdown", res.SortPushdown,
-			"limitPushdown", res.LimitPushdown,
-
-			"ok", float64(1),
-		))},
+		Documents: []*types.Document{resDoc},
 	}))
 
 	return &reply, nil
 }
+
+// explainWinningPlan returns the `queryPlanner.winningPlan` document for the given index used by the backend.
+//
+// If no index is used, the collection scan plan is returned.
+func explainWinningPlan(ctx context.Context, c backends.Collection, index string) (*types.Document, error) {
+	if index != "" {
+		res, err := c.ListIndexes(ctx, nil)
+
+		switch {
+		case err == nil:
+			for i := range res.Indexes {
+				if res.Indexes[i].Name != index {
+					continue
+				}
+
+				return types.NewDocument(
+					"stage", "FETCH",
+					"inputStage", must.NotFail(types.NewDocument(
+						"stage", "IXSCAN",
+						"keyPattern", must.NotFail(indexSpec(&res.Indexes[i]).Get("key")),
+						"indexName", index,
+					)),
+				)
+			}
+		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+			// no indexes
+		default:
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	return types.NewDocument("stage", "COLLSCAN")
+}
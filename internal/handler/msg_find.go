@@ -19,7 +19,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -29,6 +31,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/textsearch"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -70,6 +73,11 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, lazyerrors.Error(err)
 	}
 
+	view, err := h.getView(ctx, db, params.DB, params.Collection)
+	if err != nil {
+		return nil, err
+	}
+
 	if params.Tailable {
 		var cList *backends.ListCollectionsResult
 
@@ -108,8 +116,58 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		}
 	}
 
+	filter := params.Filter
+
+	textSearch, err := common.GetTextSearch(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var search *textsearch.Search
+
+	if textSearch != nil {
+		if view != nil {
+			return nil, newQueryOnViewError("$text", document.Command())
+		}
+
+		if search, qp.TextSearch, err = newTextSearch(ctx, c, textSearch); err != nil {
+			return nil, err
+		}
+
+		filter = textSearch.Filter
+	}
+
+	geoNear, err := common.GetGeoNear(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var nearSearch *common.GeoNearSearch
+
+	switch {
+	case geoNear != nil && textSearch != nil:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"text and geoNear not allowed in same query",
+			document.Command(),
+		)
+
+	case geoNear != nil && view != nil:
+		return nil, newQueryOnViewError("$near", document.Command())
+
+	case geoNear != nil:
+		if nearSearch, qp.Geo, err = newGeoNearSearch(ctx, c, geoNear); err != nil {
+			return nil, err
+		}
+
+		filter = geoNear.Filter
+
+	default:
+		qp.Geo = common.GetGeoParams(filter)
+	}
+
 	if !h.DisableFilterPushdown {
-		qp.Filter = params.Filter
+		qp.Filter = filter
 	}
 
 	if params.Sort, err = common.ValidateSortDocument(params.Sort); err != nil {
@@ -125,19 +183,37 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, err
 	}
 
-	// Skip sorting if there are more than one sort parameters
-	if params.Sort.Len() == 1 {
+	if textSearch == nil && (common.HasTextScoreMeta(params.Sort) || common.HasTextScoreMeta(params.Projection)) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTextScoreNotAvailable,
+			"query requires text score metadata, but it is not available",
+			document.Command(),
+		)
+	}
+
+	// Skip sorting if text score is used
+	if params.Sort.Len() > 0 && !common.HasTextScoreMeta(params.Sort) {
 		qp.Sort = params.Sort
 	}
 
-	// Limit pushdown is not applied if:
-	//  - `filter` is set, it must fetch all documents to filter them in memory;
+	if qp.Hint, err = getHintIndex(ctx, c, document.Command(), params.Hint); err != nil {
+		return nil, err
+	}
+
+	// Skip and limit pushdown is not applied if:
 	//  - `sort` is set, it must fetch all documents and sort them in memory;
-	//  - `skip` is non-zero value, skip pushdown is not supported yet.
-	if params.Filter.Len() == 0 && params.Sort.Len() == 0 && params.Skip == 0 {
+	//  - `filter` is set, but filter pushdown is disabled, it must fetch all documents to filter them in memory;
+	//  - text or geospatial search is used, it must fetch all documents to match them in memory.
+	// The backend applies them only if it could apply the whole filter exactly.
+	if params.Sort.Len() == 0 && search == nil && nearSearch == nil && (filter.Len() == 0 || qp.Filter != nil) {
+		qp.Skip = params.Skip
 		qp.Limit = params.Limit
 	}
 
+	if search == nil && nearSearch == nil {
+		qp.Projection = getProjectionPushdown(params.Projection, filter, params.Sort)
+	}
+
 	cancel := func() {}
 	if params.MaxTimeMS != 0 {
 		// It is not clear if maxTimeMS affects only find, or both find and getMore (as the current code does).
@@ -148,17 +224,59 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 	// closer accumulates all things that should be closed / canceled.
 	closer := iterator.NewMultiCloser(iterator.CloserFunc(cancel))
 
-	queryRes, err := c.Query(ctx, qp)
-	if err != nil {
-		closer.Close()
-		return nil, lazyerrors.Error(err)
+	var iter types.DocumentsIterator
+
+	skip := params.Skip
+
+	if view != nil {
+		if iter, err = h.queryView(ctx, view, closer); err != nil {
+			closer.Close()
+			return nil, err
+		}
+	} else {
+		var queryRes *backends.QueryResult
+
+		if queryRes, err = c.Query(ctx, qp); err != nil {
+			closer.Close()
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.indexUsage.record(params.DB, params.Collection, queryRes.Index)
+
+		closer.Add(queryRes.Iter)
+
+		iter = queryRes.Iter
+
+		if queryRes.SkipPushdown {
+			skip = 0
+		}
+	}
+
+	if search != nil {
+		iter = common.TextSearchIterator(iter, closer, search)
 	}
 
-	closer.Add(queryRes.Iter)
+	iter = common.FilterIterator(iter, closer, filter)
+
+	if nearSearch != nil {
+		if iter, err = common.GeoNearIterator(iter, closer, nearSearch); err != nil {
+			closer.Close()
+			return nil, lazyerrors.Error(err)
+		}
+	}
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter)
+	sortParams := &common.SortParams{
+		MemoryLimit:  h.SortMemoryLimit,
+		AllowDiskUse: params.AllowDiskUse,
+		SpillDir:     h.SpillDir,
+	}
 
-	iter, err = common.SortIterator(iter, closer, params.Sort)
+	// only skipped and limited documents are needed
+	if params.Limit > 0 && params.Limit <= math.MaxInt64-params.Skip {
+		sortParams.Limit = params.Skip + params.Limit
+	}
+
+	iter, err = common.SortIterator(iter, closer, params.Sort, sortParams)
 	if err != nil {
 		closer.Close()
 
@@ -174,11 +292,11 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, lazyerrors.Error(err)
 	}
 
-	iter = common.SkipIterator(iter, closer, params.Skip)
+	iter = common.SkipIterator(iter, closer, skip)
 
 	iter = common.LimitIterator(iter, closer, params.Limit)
 
-	iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter)
+	iter, err = common.ProjectionIterator(iter, closer, params.Projection, filter)
 	if err != nil {
 		closer.Close()
 		return nil, lazyerrors.Error(err)
@@ -236,3 +354,127 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	return &reply, nil
 }
+
+// newTextSearch returns a search for the given `$text` query operator
+// that uses the text index of the given collection,
+// and parameters for the backend to pre-filter documents with that index.
+func newTextSearch(ctx context.Context, c backends.Collection, ts *common.TextSearch) (*textsearch.Search, *backends.TextSearchParams, error) { //nolint:lll // for readability
+	var indexes []backends.IndexInfo
+
+	res, err := c.ListIndexes(ctx, nil)
+
+	switch {
+	case err == nil:
+		indexes = res.Indexes
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		// no indexes
+	default:
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	return ts.NewSearch(indexes)
+}
+
+// newGeoNearSearch returns a search for the given `$near` or `$nearSphere` query operator or `$geoNear` stage
+// that uses the geospatial index of the given collection,
+// and parameters for the backend to pre-filter documents with that index.
+func newGeoNearSearch(ctx context.Context, c backends.Collection, gn *common.GeoNear) (*common.GeoNearSearch, *backends.GeoParams, error) { //nolint:lll // for readability
+	var indexes []backends.IndexInfo
+
+	res, err := c.ListIndexes(ctx, nil)
+
+	switch {
+	case err == nil:
+		indexes = res.Indexes
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		// no indexes
+	default:
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	return gn.NewSearch(indexes)
+}
+
+// getProjectionPushdown returns top-level fields that the backend should return for the given inclusion projection,
+// including fields used by the given filter and sort.
+//
+// It returns nil if all fields should be returned, for example,
+// if the projection is not an inclusion one or the filter contains `$expr`.
+func getProjectionPushdown(projection, filter, sort *types.Document) []string {
+	if _, inclusion, err := common.ValidateProjection(projection); err != nil || !inclusion {
+		return nil
+	}
+
+	fields := []string{"_id"}
+
+	for _, key := range projection.Keys() {
+		switch must.NotFail(projection.Get(key)).(type) {
+		case bool, int32, int64, float64:
+			fields = append(fields, key)
+		default:
+			// projection operators and expressions
+			return nil
+		}
+	}
+
+	fields = append(fields, sort.Keys()...)
+
+	filterFields, ok := getFilterFields(filter)
+	if !ok {
+		return nil
+	}
+
+	fields = append(fields, filterFields...)
+
+	for i, f := range fields {
+		fields[i], _, _ = strings.Cut(f, ".")
+	}
+
+	slices.Sort(fields)
+
+	return slices.Compact(fields)
+}
+
+// getFilterFields returns fields used by the given filter, including ones in logical operators.
+//
+// It returns false if the filter contains other top-level operators (except `$comment`).
+func getFilterFields(filter *types.Document) ([]string, bool) {
+	var fields []string
+
+	for _, key := range filter.Keys() {
+		switch key {
+		case "$comment":
+			continue
+
+		case "$and", "$or", "$nor":
+			exprs, ok := must.NotFail(filter.Get(key)).(*types.Array)
+			if !ok {
+				return nil, false
+			}
+
+			for i := 0; i < exprs.Len(); i++ {
+				expr, ok := must.NotFail(exprs.Get(i)).(*types.Document)
+				if !ok {
+					return nil, false
+				}
+
+				exprFields, ok := getFilterFields(expr)
+				if !ok {
+					return nil, false
+				}
+
+				fields = append(fields, exprFields...)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+
+		fields = append(fields, key)
+	}
+
+	return fields, true
+}
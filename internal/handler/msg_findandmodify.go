@@ -122,42 +122,99 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		return nil, lazyerrors.Error(err)
 	}
 
-	cancel := func() {}
+	if err = checkNotView(ctx, db, params.DB, params.Collection, "findAndModify"); err != nil {
+		return nil, err
+	}
+
+	validator, err := h.newDocumentValidator(ctx, db, params.Collection, params.BypassDocumentValidation)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	uniqueChecker, err := h.newPrepareUniqueChecker(ctx, c)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	if params.MaxTimeMS != 0 {
+		var cancel context.CancelFunc
+
 		// TODO https://github.com/FerretDB/FerretDB/issues/2168
 		ctx, cancel = context.WithTimeout(ctx, time.Duration(params.MaxTimeMS)*time.Millisecond)
+		defer cancel()
 	}
 
-	// closer accumulates all things that should be closed / canceled.
-	closer := iterator.NewMultiCloser(iterator.CloserFunc(cancel))
-	defer closer.Close()
-
 	var qp backends.QueryParams
 	if !h.DisableFilterPushdown {
 		qp.Filter = params.Query
 	}
 
-	queryRes, err := c.Query(ctx, &qp)
+	if qp.Hint, err = getHintIndex(ctx, c, "findAndModify", params.Hint); err != nil {
+		return nil, err
+	}
+
+	var res *findAndModifyResult
+
+	// the document is found, checked and changed atomically,
+	// so concurrent upserts and updates do not insert duplicates
+	modifyRes, err := c.ModifyAll(ctx, &backends.ModifyAllParams{
+		Filter: qp.Filter,
+		Hint:   qp.Hint,
+		Modify: func(iter types.DocumentsIterator, query backends.ModifyQueryFunc) (*backends.ModifyChanges, error) {
+			var changes *backends.ModifyChanges
+			var err error
+
+			res, changes, err = h.findAndModifyChanges(ctx, iter, params, validator, uniqueChecker.inTransaction(query))
+
+			return changes, err
+		},
+	})
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+			// TODO https://github.com/FerretDB/FerretDB/issues/2168
+			return nil, newFindAndModifyDuplicateError(params)
+		}
+
+		return nil, err
 	}
 
-	closer.Add(queryRes.Iter)
+	h.indexUsage.record(params.DB, params.Collection, modifyRes.Index)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Query)
+	// at most one document is removed, updated, or inserted
+	res.modified = modifyRes.Deleted + modifyRes.Updated + modifyRes.Inserted
 
-	iter, err = common.SortIterator(iter, closer, params.Sort)
+	return res, nil
+}
+
+// findAndModifyChanges returns the result of findAndModify operation for documents returned by the iterator,
+// and changes that should be made.
+//
+// It is called by ModifyAll and could be called more than once, so it does not modify parameters.
+// The modified field of the result is set by the caller.
+func (h *Handler) findAndModifyChanges(ctx context.Context, iter types.DocumentsIterator, params *common.FindAndModifyParams, validator *documentValidator, uniqueChecker *prepareUniqueChecker) (*findAndModifyResult, *backends.ModifyChanges, error) { //nolint:lll // for readability
+	// closer accumulates all things that should be closed.
+	closer := iterator.NewMultiCloser(iter)
+	defer closer.Close()
+
+	iter = common.FilterIterator(iter, closer, params.Query)
+
+	iter, err := common.SortIterator(iter, closer, params.Sort, &common.SortParams{
+		Limit:        1,
+		MemoryLimit:  h.SortMemoryLimit,
+		AllowDiskUse: true,
+		SpillDir:     h.SpillDir,
+	})
 	if err != nil {
 		var pathErr *types.PathError
 		if errors.As(err, &pathErr) && pathErr.Code() == types.ErrPathElementEmpty {
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrPathContainsEmptyElement,
 				"FieldPath field names may not be empty strings.",
 				"findAndModify",
 			)
 		}
 
-		return nil, lazyerrors.Error(err)
+		return nil, nil, lazyerrors.Error(err)
 	}
 
 	// findAndModify modifies a single document
@@ -170,7 +227,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 			return &findAndModifyResult{
 				modified: int32(0),
 				value:    types.Null,
-			}, nil
+			}, nil, nil
 		}
 
 		if !params.Upsert {
@@ -178,16 +235,18 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 				modified:       int32(0),
 				updateExisting: false,
 				value:          types.Null,
-			}, nil
+			}, nil, nil
 		}
 
-		doc := params.Update
+		var doc *types.Document
 		if params.HasUpdateOperators {
 			doc = must.NotFail(types.NewDocument())
 			if _, err = common.UpdateDocument("findAndModify", doc, params.Update); err != nil {
 				// TODO https://github.com/FerretDB/FerretDB/issues/2168
-				return nil, err
+				return nil, nil, err
 			}
+		} else {
+			doc = params.Update.DeepCopy()
 		}
 
 		upserted, _ := doc.Get("_id")
@@ -203,7 +262,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 
 				if hasOp, err = common.HasQueryOperator(idDoc); err != nil {
 					// TODO https://github.com/FerretDB/FerretDB/issues/2168
-					return nil, err
+					return nil, nil, err
 				}
 
 				if hasOp {
@@ -223,26 +282,18 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 			var we *mongo.WriteError
 
 			if we, err = handleValidationError(err); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			writeErrors.Append(WriteErrorDocument(we))
 		}
 
-		if _, err = c.InsertAll(ctx, &backends.InsertAllParams{
-			Docs: []*types.Document{doc},
-		}); err != nil {
-			if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
-				// TODO https://github.com/FerretDB/FerretDB/issues/2168
-				we := &mongo.WriteError{
-					Index:   0,
-					Code:    int(handlererrors.ErrDuplicateKeyInsert),
-					Message: fmt.Sprintf(`E11000 duplicate key error collection: %s.%s`, params.DB, params.Collection),
-				}
-				writeErrors.Append(WriteErrorDocument(we))
-			}
+		if err = validateFindAndModifyDocument(validator, doc, nil); err != nil {
+			return nil, nil, err
+		}
 
-			return nil, lazyerrors.Error(err)
+		if err = checkFindAndModifyDuplicate(ctx, uniqueChecker, doc, nil, params); err != nil {
+			return nil, nil, err
 		}
 
 		var value any = types.Null
@@ -250,39 +301,37 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 			value = doc
 		}
 
-		return &findAndModifyResult{
-			modified:       int32(1),
+		res := &findAndModifyResult{
 			updateExisting: false,
 			upserted:       upserted,
 			value:          value,
 			writeErrors:    writeErrors,
-		}, nil
+		}
+
+		return res, &backends.ModifyChanges{Insert: []*types.Document{doc}}, nil
 	}
 
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, nil, lazyerrors.Error(err)
 	}
 
 	if params.Remove {
-		var delRes *backends.DeleteAllResult
-
-		if delRes, err = c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{must.NotFail(v.Get("_id"))}}); err != nil {
-			return nil, lazyerrors.Error(err)
+		res := &findAndModifyResult{
+			value: v,
 		}
 
-		return &findAndModifyResult{
-			modified: delRes.Deleted,
-			value:    v,
-		}, nil
+		return res, &backends.ModifyChanges{Delete: []any{must.NotFail(v.Get("_id"))}}, nil
 	}
 
 	// TODO https://github.com/FerretDB/FerretDB/issues/3040
-	doc := params.Update
+	var doc *types.Document
 	if params.HasUpdateOperators {
 		doc = v.DeepCopy()
 		if _, err = common.UpdateDocument("findAndModify", doc, params.Update); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+	} else {
+		doc = params.Update.DeepCopy()
 	}
 
 	id := must.NotFail(v.Get("_id"))
@@ -293,7 +342,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 	}
 
 	if updateID != nil && updateID != id {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrImmutableField,
 			fmt.Sprintf(
 				`Plan executor error during findAndModify :: caused `+
@@ -314,15 +363,18 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		var we *mongo.WriteError
 
 		if we, err = handleValidationError(err); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		writeErrors.Append(WriteErrorDocument(we))
 	}
 
-	updateRes, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+	if err = validateFindAndModifyDocument(validator, doc, v); err != nil {
+		return nil, nil, err
+	}
+
+	if err = checkFindAndModifyDuplicate(ctx, uniqueChecker, doc, v, params); err != nil {
+		return nil, nil, err
 	}
 
 	value := v
@@ -330,12 +382,56 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		value = doc
 	}
 
-	return &findAndModifyResult{
-		modified:       updateRes.Updated,
+	res := &findAndModifyResult{
 		updateExisting: true,
 		value:          value,
 		writeErrors:    writeErrors,
-	}, nil
+	}
+
+	return res, &backends.ModifyChanges{Update: []*types.Document{doc}}, nil
+}
+
+// validateFindAndModifyDocument returns a command error if the inserted or updated document
+// does not satisfy the collection validator.
+func validateFindAndModifyDocument(validator *documentValidator, doc, old *types.Document) error {
+	errInfo, err := validator.validate(doc, old)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if errInfo == nil {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithErrInfo(
+		handlererrors.ErrDocumentValidationFailure,
+		documentValidationFailed,
+		errInfo,
+	)
+}
+
+// checkFindAndModifyDuplicate returns a command error if the inserted or updated document
+// has a duplicate key of the index with `prepareUnique` option.
+func checkFindAndModifyDuplicate(ctx context.Context, uc *prepareUniqueChecker, doc, old *types.Document, params *common.FindAndModifyParams) error { //nolint:lll // for readability
+	duplicate, err := uc.duplicate(ctx, doc, old, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !duplicate {
+		return nil
+	}
+
+	return newFindAndModifyDuplicateError(params)
+}
+
+// newFindAndModifyDuplicateError returns a command error for the inserted or updated document with a duplicate key.
+func newFindAndModifyDuplicateError(params *common.FindAndModifyParams) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrDuplicateKeyInsert,
+		fmt.Sprintf(`E11000 duplicate key error collection: %s.%s`, params.DB, params.Collection),
+		"findAndModify",
+	)
 }
 
 // handleValidationError checks validation error code and returns *writeError.
@@ -38,11 +38,17 @@ import (
 // Find a better place for this function.
 // TODO https://github.com/FerretDB/FerretDB/issues/3263
 func WriteErrorDocument(we *mongo.WriteError) *types.Document {
-	return must.NotFail(types.NewDocument(
+	doc := must.NotFail(types.NewDocument(
 		"index", int32(we.Index),
 		"code", int32(we.Code),
 		"errmsg", we.Message,
 	))
+
+	if errInfo := must.NotFail(writeErrorInfo(we)); errInfo != nil {
+		doc.Set("errInfo", errInfo)
+	}
+
+	return doc
 }
 
 // MsgInsert implements `insert` command.
@@ -77,6 +83,20 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, params.DB, params.Collection, document.Command()); err != nil {
+		return nil, err
+	}
+
+	validator, err := h.newDocumentValidator(ctx, db, params.Collection, params.BypassDocumentValidation)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	uniqueChecker, err := h.newPrepareUniqueChecker(ctx, c)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	docsIter := params.Docs.Iterator()
 	defer docsIter.Close()
 
@@ -113,6 +133,41 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 			// TODO https://github.com/FerretDB/FerretDB/issues/3454
 			if err = doc.ValidateData(); err == nil {
+				var errInfo *types.Document
+				if errInfo, err = validator.validate(doc, nil); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				if errInfo != nil {
+					var we *mongo.WriteError
+					if we, err = newDocumentValidationWriteError(i, errInfo); err != nil {
+						return nil, lazyerrors.Error(err)
+					}
+
+					writeErrors = append(writeErrors, we)
+
+					if params.Ordered {
+						break
+					}
+
+					continue
+				}
+
+				var duplicate bool
+				if duplicate, err = uniqueChecker.duplicate(ctx, doc, nil, docs); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				if duplicate {
+					writeErrors = append(writeErrors, newDuplicateKeyWriteError(i, params.DB, params.Collection))
+
+					if params.Ordered {
+						break
+					}
+
+					continue
+				}
+
 				docs = append(docs, doc)
 				docsIndexes = append(docsIndexes, i)
 
@@ -78,12 +78,22 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 	collections := types.MakeArray(len(res.Collections))
 
 	for _, collection := range res.Collections {
+		typ := "collection"
+		if collection.View != nil {
+			typ = "view"
+		}
+
 		d := must.NotFail(types.NewDocument(
 			"name", collection.Name,
-			"type", "collection",
+			"type", typ,
+			"options", collectionOptions(collection),
 		))
 
-		if collection.UUID != "" {
+		switch {
+		case collection.View != nil:
+			d.Set("info", must.NotFail(types.NewDocument("readOnly", true)))
+
+		case collection.UUID != "":
 			uuid, err := uuid.Parse(collection.UUID)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
@@ -130,3 +140,33 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 
 	return &reply, nil
 }
+
+// collectionOptions returns `options` document of the collection as returned by `listCollections`.
+func collectionOptions(collection backends.CollectionInfo) *types.Document {
+	options := must.NotFail(types.NewDocument())
+
+	if v := collection.View; v != nil {
+		options.Set("viewOn", v.ViewOn)
+		options.Set("pipeline", v.Pipeline)
+	}
+
+	if collection.Capped() {
+		options.Set("capped", true)
+		options.Set("size", collection.CappedSize)
+
+		if collection.CappedDocuments > 0 {
+			options.Set("max", collection.CappedDocuments)
+		}
+	}
+
+	if v := collection.Validation; v != nil {
+		if v.Validator != nil {
+			options.Set("validator", v.Validator)
+		}
+
+		options.Set("validationLevel", v.Level)
+		options.Set("validationAction", v.Action)
+	}
+
+	return options
+}
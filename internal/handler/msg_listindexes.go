@@ -66,6 +66,10 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, dbName, collection, command); err != nil {
+		return nil, err
+	}
+
 	res, err := c.ListIndexes(ctx, nil)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
@@ -78,30 +82,8 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 
 	firstBatch := types.MakeArray(len(res.Indexes))
 
-	for _, index := range res.Indexes {
-		indexKey := must.NotFail(types.NewDocument())
-
-		for _, key := range index.Key {
-			order := int32(1)
-			if key.Descending {
-				order = -1
-			}
-
-			indexKey.Set(key.Field, order)
-		}
-
-		indexDoc := must.NotFail(types.NewDocument(
-			"v", int32(2), // for compatibility, the meaning of this field is not documented
-			"key", indexKey,
-			"name", index.Name,
-		))
-
-		// only non-default unique indexes should have unique field in the response
-		if index.Unique && index.Name != backends.DefaultIndexName {
-			indexDoc.Set("unique", index.Unique)
-		}
-
-		firstBatch.Append(indexDoc)
+	for i := range res.Indexes {
+		firstBatch.Append(indexSpec(&res.Indexes[i]))
 	}
 
 	var reply wire.OpMsg
@@ -118,3 +100,98 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 
 	return &reply, nil
 }
+
+// indexSpec returns the index specification document as returned by listIndexes.
+func indexSpec(index *backends.IndexInfo) *types.Document {
+	indexKey := must.NotFail(types.NewDocument())
+
+	if index.Text != nil {
+		indexKey.Set("_fts", "text")
+		indexKey.Set("_ftsx", int32(1))
+	}
+
+	for _, key := range index.Key {
+		if key.Type == backends.IndexKeyTypeText {
+			continue
+		}
+
+		if key.Type != "" {
+			indexKey.Set(key.Field, string(key.Type))
+			continue
+		}
+
+		order := int32(1)
+		if key.Descending {
+			order = -1
+		}
+
+		indexKey.Set(key.Field, order)
+	}
+
+	indexDoc := must.NotFail(types.NewDocument(
+		"v", int32(2), // for compatibility, the meaning of this field is not documented
+		"key", indexKey,
+		"name", index.Name,
+	))
+
+	// only non-default unique indexes should have unique field in the response
+	if index.Unique && index.Name != backends.DefaultIndexName {
+		indexDoc.Set("unique", index.Unique)
+	}
+
+	if index.Text != nil {
+		weights := types.MakeDocument(len(index.Text.Weights))
+		for _, w := range index.Text.Weights {
+			weights.Set(w.Field, w.Weight)
+		}
+
+		indexDoc.Set("weights", weights)
+		indexDoc.Set("default_language", index.Text.DefaultLanguage)
+		indexDoc.Set("language_override", index.Text.LanguageOverride)
+		indexDoc.Set("textIndexVersion", int32(textIndexVersion))
+	}
+
+	if index.Geo != nil {
+		if index.Geo.Version != 0 {
+			indexDoc.Set("2dsphereIndexVersion", index.Geo.Version)
+		}
+
+		if index.Geo.Bits != 0 {
+			indexDoc.Set("bits", index.Geo.Bits)
+		}
+
+		if index.Geo.Min != nil {
+			indexDoc.Set("min", *index.Geo.Min)
+		}
+
+		if index.Geo.Max != nil {
+			indexDoc.Set("max", *index.Geo.Max)
+		}
+	}
+
+	if index.ExpireAfterSeconds != nil {
+		indexDoc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+	}
+
+	if index.Hidden {
+		indexDoc.Set("hidden", true)
+	}
+
+	if index.PrepareUnique {
+		indexDoc.Set("prepareUnique", true)
+	}
+
+	if index.Sparse {
+		indexDoc.Set("sparse", true)
+	}
+
+	if index.PartialFilterExpression != nil {
+		indexDoc.Set("partialFilterExpression", index.PartialFilterExpression)
+	}
+
+	if index.WildcardProjection != nil {
+		indexDoc.Set("wildcardProjection", index.WildcardProjection)
+	}
+
+	return indexDoc
+}
@@ -116,6 +116,10 @@ func (h *Handler) MsgRenameCollection(ctx context.Context, msg *wire.OpMsg) (*wi
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, oldDBName, oldCName, command); err != nil {
+		return nil, err
+	}
+
 	err = db.RenameCollection(ctx, &backends.RenameCollectionParams{
 		OldName: oldCName,
 		NewName: newCName,
@@ -123,7 +127,7 @@ func (h *Handler) MsgRenameCollection(ctx context.Context, msg *wire.OpMsg) (*wi
 
 	switch {
 	case err == nil:
-	// do nothing
+		h.indexUsage.forget(oldDBName, oldCName)
 	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrNamespaceExists,
@@ -18,6 +18,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
@@ -59,6 +64,9 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 				Message: fmt.Sprintf(`E11000 duplicate key error collection: %s.%s`, params.DB, params.Collection),
 			}
 
+		case errors.As(err, &we):
+			// document failed collection validation
+
 		default:
 			if we, err = handleValidationError(err); err != nil {
 				return nil, lazyerrors.Error(err)
@@ -104,6 +112,10 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		return 0, 0, nil, lazyerrors.Error(err)
 	}
 
+	if err = checkNotView(ctx, db, params.DB, params.Collection, "update"); err != nil {
+		return 0, 0, nil, err
+	}
+
 	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: params.Collection})
 
 	switch {
@@ -118,7 +130,12 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		return 0, 0, nil, lazyerrors.Error(err)
 	}
 
-	for _, u := range params.Updates {
+	validator, err := h.newDocumentValidator(ctx, db, params.Collection, params.BypassDocumentValidation)
+	if err != nil {
+		return 0, 0, nil, lazyerrors.Error(err)
+	}
+
+	for i, u := range params.Updates {
 		c, err := db.Collection(params.Collection)
 		if err != nil {
 			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
@@ -129,137 +146,336 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 			return 0, 0, nil, lazyerrors.Error(err)
 		}
 
+		uniqueChecker, err := h.newPrepareUniqueChecker(ctx, c)
+		if err != nil {
+			return 0, 0, nil, lazyerrors.Error(err)
+		}
+
 		var qp backends.QueryParams
 		if !h.DisableFilterPushdown {
 			qp.Filter = u.Filter
 		}
 
-		res, err := c.Query(ctx, &qp)
+		if qp.Hint, err = getHintIndex(ctx, c, "update", u.Hint); err != nil {
+			var ce *handlererrors.CommandError
+			if !errors.As(err, &ce) {
+				return 0, 0, nil, lazyerrors.Error(err)
+			}
+
+			return 0, 0, nil, &mongo.WriteError{Index: i, Code: int(ce.Code()), Message: ce.Err().Error()}
+		}
+
+		// documents are validated and checked for uniqueness by the handler
+		if !h.DisableFilterPushdown && qp.Hint == "" && validator == nil && uniqueChecker == nil {
+			if ops := getUpdatePushdown(u.Update); ops != nil {
+				var updateRes *backends.UpdateAllResult
+
+				updateRes, err = c.UpdateAll(ctx, &backends.UpdateAllParams{
+					Filter: u.Filter,
+					Update: ops,
+					Multi:  u.Multi,
+				})
+				if err != nil {
+					return 0, 0, nil, lazyerrors.Error(err)
+				}
+
+				if updateRes.UpdatePushdown && (updateRes.Matched > 0 || !u.Upsert) {
+					matched += updateRes.Matched
+					modified += updateRes.Updated
+
+					continue
+				}
+			}
+		}
+
+		var updateMatched int32
+		var upsert *types.Document
+
+		// documents are found, checked and changed atomically,
+		// so concurrent upserts and updates do not insert duplicates
+		modifyRes, err := c.ModifyAll(ctx, &backends.ModifyAllParams{
+			Filter: qp.Filter,
+			Hint:   qp.Hint,
+			Modify: func(iter types.DocumentsIterator, query backends.ModifyQueryFunc) (*backends.ModifyChanges, error) {
+				var changes *backends.ModifyChanges
+				var err error
+
+				uc := uniqueChecker.inTransaction(query)
+				changes, updateMatched, upsert, err = updateChanges(ctx, iter, i, &u, params, validator, uc)
+
+				return changes, err
+			},
+		})
 		if err != nil {
-			return 0, 0, nil, lazyerrors.Error(err)
+			return 0, 0, nil, err
 		}
 
-		var resDocs []*types.Document
+		h.indexUsage.record(params.DB, params.Collection, modifyRes.Index)
 
-		defer res.Iter.Close()
+		if upsert != nil {
+			upserted.Append(must.NotFail(types.NewDocument(
+				"index", int32(upserted.Len()),
+				"_id", must.NotFail(upsert.Get("_id")),
+			)))
 
-		for {
-			var doc *types.Document
+			matched++
 
-			_, doc, err = res.Iter.Next()
-			if err != nil {
-				if errors.Is(err, iterator.ErrIteratorDone) {
-					break
-				}
+			continue
+		}
 
-				return 0, 0, nil, lazyerrors.Error(err)
-			}
+		matched += updateMatched
+		modified += modifyRes.Updated
+	}
 
-			var matches bool
+	return matched, modified, &upserted, nil
+}
 
-			matches, err = common.FilterDocument(doc, u.Filter)
-			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
-			}
+// updateChanges returns changes made by a single update operation to documents returned by the iterator,
+// and the number of matched documents.
+// If no documents match and the operation is an upsert, the inserted document is also returned.
+//
+// It is called by ModifyAll and could be called more than once, so it does not modify the operation.
+func updateChanges(ctx context.Context, iter types.DocumentsIterator, i int, u *common.Update, params *common.UpdateParams, validator *documentValidator, uniqueChecker *prepareUniqueChecker) (*backends.ModifyChanges, int32, *types.Document, error) { //nolint:lll // for readability
+	defer iter.Close()
+
+	var resDocs []*types.Document
 
-			if !matches {
-				continue
+	for {
+		_, doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
 			}
 
-			resDocs = append(resDocs, doc)
+			return nil, 0, nil, lazyerrors.Error(err)
 		}
 
-		res.Iter.Close()
+		matches, err := common.FilterDocument(doc, u.Filter)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
 
-		if len(resDocs) == 0 {
-			if !u.Upsert {
-				// nothing to do, continue to the next update operation
-				continue
-			}
+		if !matches {
+			continue
+		}
 
-			// TODO https://github.com/FerretDB/FerretDB/issues/3040
-			hasQueryOperators, err := common.HasQueryOperator(u.Filter)
-			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
-			}
+		resDocs = append(resDocs, doc)
 
-			var doc *types.Document
-			if hasQueryOperators {
-				doc = must.NotFail(types.NewDocument())
-			} else {
-				doc = u.Filter
-			}
+		if !u.Multi {
+			break
+		}
+	}
 
-			hasUpdateOperators, err := common.HasSupportedUpdateModifiers("update", u.Update)
-			if err != nil {
-				return 0, 0, nil, err
-			}
+	if len(resDocs) == 0 {
+		if !u.Upsert {
+			// nothing to do, continue to the next update operation
+			return nil, 0, nil, nil
+		}
 
-			if hasUpdateOperators {
-				// TODO https://github.com/FerretDB/FerretDB/issues/3044
-				if _, err = common.UpdateDocument("update", doc, u.Update); err != nil {
-					return 0, 0, nil, err
-				}
-			} else {
-				doc = u.Update
-			}
+		// TODO https://github.com/FerretDB/FerretDB/issues/3040
+		hasQueryOperators, err := common.HasQueryOperator(u.Filter)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
 
-			if !doc.Has("_id") {
-				doc.Set("_id", types.NewObjectID())
-			}
-			upserted.Append(must.NotFail(types.NewDocument(
-				"index", int32(upserted.Len()),
-				"_id", must.NotFail(doc.Get("_id")),
-			)))
+		var doc *types.Document
+		if hasQueryOperators {
+			doc = must.NotFail(types.NewDocument())
+		} else {
+			doc = u.Filter.DeepCopy()
+		}
 
-			// TODO https://github.com/FerretDB/FerretDB/issues/3454
-			if err = doc.ValidateData(); err != nil {
-				return 0, 0, nil, err
+		hasUpdateOperators, err := common.HasSupportedUpdateModifiers("update", u.Update)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+
+		if hasUpdateOperators {
+			// TODO https://github.com/FerretDB/FerretDB/issues/3044
+			if _, err = common.UpdateDocument("update", doc, u.Update); err != nil {
+				return nil, 0, nil, err
 			}
+		} else {
+			doc = u.Update.DeepCopy()
+		}
 
-			// TODO https://github.com/FerretDB/FerretDB/issues/2612
+		if !doc.Has("_id") {
+			doc.Set("_id", types.NewObjectID())
+		}
 
-			_, err = c.InsertAll(ctx, &backends.InsertAllParams{
-				Docs: []*types.Document{doc},
-			})
+		errInfo, err := validator.validate(doc, nil)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
+
+		if errInfo != nil {
+			we, err := newDocumentValidationWriteError(i, errInfo)
 			if err != nil {
-				return 0, 0, nil, err
+				return nil, 0, nil, lazyerrors.Error(err)
 			}
 
-			matched++
+			return nil, 0, nil, we
+		}
+
+		duplicate, err := uniqueChecker.duplicate(ctx, doc, nil, nil)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
+
+		if duplicate {
+			return nil, 0, nil, newDuplicateKeyWriteError(i, params.DB, params.Collection)
+		}
 
+		// TODO https://github.com/FerretDB/FerretDB/issues/3454
+		if err = doc.ValidateData(); err != nil {
+			return nil, 0, nil, err
+		}
+
+		// TODO https://github.com/FerretDB/FerretDB/issues/2612
+
+		return &backends.ModifyChanges{Insert: []*types.Document{doc}}, 0, doc, nil
+	}
+
+	var changes backends.ModifyChanges
+
+	for _, doc := range resDocs {
+		var old *types.Document
+		if validator != nil || uniqueChecker != nil {
+			old = doc.DeepCopy()
+		}
+
+		changed, err := common.UpdateDocument("update", doc, u.Update)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
+
+		if !changed {
 			continue
 		}
 
-		if len(resDocs) > 1 && !u.Multi {
-			resDocs = resDocs[:1]
+		// TODO https://github.com/FerretDB/FerretDB/issues/3454
+		if err = doc.ValidateData(); err != nil {
+			return nil, 0, nil, err
 		}
 
-		matched += int32(len(resDocs))
+		errInfo, err := validator.validate(doc, old)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
 
-		for _, doc := range resDocs {
-			changed, err := common.UpdateDocument("update", doc, u.Update)
+		if errInfo != nil {
+			we, err := newDocumentValidationWriteError(i, errInfo)
 			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
+				return nil, 0, nil, lazyerrors.Error(err)
 			}
 
-			if !changed {
-				continue
+			return nil, 0, nil, we
+		}
+
+		duplicate, err := uniqueChecker.duplicate(ctx, doc, old, changes.Update)
+		if err != nil {
+			return nil, 0, nil, lazyerrors.Error(err)
+		}
+
+		if duplicate {
+			return nil, 0, nil, newDuplicateKeyWriteError(i, params.DB, params.Collection)
+		}
+
+		changes.Update = append(changes.Update, doc)
+	}
+
+	return &changes, int32(len(resDocs)), nil, nil
+}
+
+// getUpdatePushdown returns `$set`, `$unset` and `$inc` operators of the update document
+// that could be pushed down to the backend, in the order the handler applies them.
+//
+// It returns nil if the update can't be pushed down, for example,
+// if it contains other operators, invalid or conflicting paths, or non-scalar values.
+// In that case, the handler validates and applies the update itself.
+func getUpdatePushdown(update *types.Document) []backends.UpdateOperator {
+	if update.Len() == 0 {
+		return nil
+	}
+
+	var ops []backends.UpdateOperator
+
+	for _, operator := range update.Keys() {
+		doc, ok := must.NotFail(update.Get(operator)).(*types.Document)
+		if !ok || doc.Len() == 0 {
+			return nil
+		}
+
+		keys := doc.Keys()
+
+		// see processSetFieldExpression
+		if operator == "$set" {
+			keys = slices.Clone(keys)
+			sort.Strings(keys)
+		}
+
+		for _, key := range keys {
+			path, err := types.NewPathFromString(key)
+			if err != nil || path.Prefix() == "_id" {
+				return nil
 			}
 
-			// TODO https://github.com/FerretDB/FerretDB/issues/3454
-			if err = doc.ValidateData(); err != nil {
-				return 0, 0, nil, err
+			for _, e := range path.Slice() {
+				if strings.HasPrefix(e, "$") {
+					return nil
+				}
 			}
 
-			updateRes, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
-			if err != nil {
-				return 0, 0, nil, lazyerrors.Error(err)
+			v := must.NotFail(doc.Get(key))
+
+			switch operator {
+			case "$set":
+				switch v := v.(type) {
+				case string, int32, int64, types.ObjectID, bool, time.Time, types.NullType:
+					// nothing
+				case float64:
+					if math.IsNaN(v) || math.IsInf(v, 0) {
+						return nil
+					}
+				default:
+					return nil
+				}
+
+			case "$inc":
+				switch v.(type) {
+				case int32, int64:
+					// nothing
+				default:
+					return nil
+				}
+
+			case "$unset":
+				v = nil
+
+			default:
+				return nil
 			}
 
-			modified += int32(updateRes.Updated)
+			for _, op := range ops {
+				if updatePathsConflict(op.Path, path) {
+					return nil
+				}
+			}
+
+			ops = append(ops, backends.UpdateOperator{
+				Operator: operator,
+				Path:     path,
+				Value:    v,
+			})
 		}
 	}
 
-	return matched, modified, &upserted, nil
+	return ops
+}
+
+// updatePathsConflict returns true if one of the given paths is equal to or a prefix of another.
+func updatePathsConflict(a, b types.Path) bool {
+	n := min(a.Len(), b.Len())
+
+	return slices.Equal(a.Slice()[:n], b.Slice()[:n])
 }
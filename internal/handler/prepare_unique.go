@@ -0,0 +1,246 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// prepareUniqueChecker rejects new duplicate keys of indexes that are being converted to unique ones
+// with `collMod`'s `prepareUnique` option.
+//
+// Existing duplicates are allowed; they are reported when the index is converted.
+type prepareUniqueChecker struct {
+	c              backends.Collection
+	query          backends.ModifyQueryFunc // used instead of c if set
+	indexes        []backends.IndexInfo
+	filterPushdown bool
+}
+
+// newPrepareUniqueChecker returns a checker for the given collection,
+// or nil if it does not have indexes with `prepareUnique` option.
+func (h *Handler) newPrepareUniqueChecker(ctx context.Context, c backends.Collection) (*prepareUniqueChecker, error) {
+	list, err := c.ListIndexes(ctx, new(backends.ListIndexesParams))
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			return nil, nil
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	var indexes []backends.IndexInfo
+
+	for _, index := range list.Indexes {
+		if index.PrepareUnique && !index.Unique {
+			indexes = append(indexes, index)
+		}
+	}
+
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+
+	return &prepareUniqueChecker{
+		c:              c,
+		indexes:        indexes,
+		filterPushdown: !h.DisableFilterPushdown,
+	}, nil
+}
+
+// inTransaction returns a copy of the checker that queries documents with the given function
+// of the ModifyAll transaction, as backend methods can't be used there.
+//
+// It is safe to call it on nil checker.
+func (uc *prepareUniqueChecker) inTransaction(query backends.ModifyQueryFunc) *prepareUniqueChecker {
+	if uc == nil {
+		return nil
+	}
+
+	res := *uc
+	res.query = query
+
+	return &res
+}
+
+// duplicate returns true if the given document has the same key as another document in the collection
+// or in the given slice of documents that are about to be written.
+//
+// The old document is the document before update, or nil for inserts.
+// Keys that are not changed by the update are not checked, so existing duplicates could still be updated.
+//
+// It is safe to call it on nil checker.
+func (uc *prepareUniqueChecker) duplicate(ctx context.Context, doc, old *types.Document, pending []*types.Document) (bool, error) { //nolint:lll // for readability
+	if uc == nil {
+		return false, nil
+	}
+
+	id := must.NotFail(doc.Get("_id"))
+
+	for _, index := range uc.indexes {
+		filter := indexKeyFilter(&index, doc)
+		if filter == nil {
+			continue
+		}
+
+		if old != nil {
+			if oldKey := indexKey(&index, old); oldKey != nil && types.Identical(indexKey(&index, doc), oldKey) {
+				continue
+			}
+		}
+
+		for _, p := range pending {
+			matches, err := common.FilterDocument(p, filter)
+			if err != nil {
+				return false, lazyerrors.Error(err)
+			}
+
+			if matches {
+				return true, nil
+			}
+		}
+
+		filter.Set("_id", must.NotFail(types.NewDocument("$ne", id)))
+
+		found, err := uc.exists(ctx, filter)
+		if err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		if found {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// newDuplicateKeyWriteError returns a write error for the document with a duplicate key.
+func newDuplicateKeyWriteError(index int, db, collection string) *mongo.WriteError {
+	return &mongo.WriteError{
+		Index:   index,
+		Code:    int(handlererrors.ErrDuplicateKeyInsert),
+		Message: fmt.Sprintf(`E11000 duplicate key error collection: %s.%s`, db, collection),
+	}
+}
+
+// exists returns true if the collection contains a document matching the filter.
+func (uc *prepareUniqueChecker) exists(ctx context.Context, filter *types.Document) (bool, error) {
+	var qp backends.QueryParams
+	if uc.filterPushdown {
+		qp.Filter = filter
+	}
+
+	var resIter types.DocumentsIterator
+	var err error
+
+	if uc.query != nil {
+		if resIter, err = uc.query(qp.Filter); err != nil {
+			return false, lazyerrors.Error(err)
+		}
+	} else {
+		var res *backends.QueryResult
+		if res, err = uc.c.Query(ctx, &qp); err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		resIter = res.Iter
+	}
+
+	closer := iterator.NewMultiCloser(resIter)
+	defer closer.Close()
+
+	iter := common.FilterIterator(resIter, closer, filter)
+
+	_, _, err = iter.Next()
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, iterator.ErrIteratorDone):
+		return false, nil
+	default:
+		return false, lazyerrors.Error(err)
+	}
+}
+
+// indexKeyFilter returns a filter matching documents with the same index key as the given document,
+// or nil if the key does not conflict with other keys.
+func indexKeyFilter(index *backends.IndexInfo, doc *types.Document) *types.Document {
+	key := indexKey(index, doc)
+	if key == nil {
+		return nil
+	}
+
+	filter := types.MakeDocument(key.Len())
+
+	for _, field := range key.Keys() {
+		filter.Set(field, must.NotFail(types.NewDocument("$eq", must.NotFail(key.Get(field)))))
+	}
+
+	// only documents in the partial index conflict
+	if index.PartialFilterExpression != nil {
+		filter.Set("$and", must.NotFail(types.NewArray(index.PartialFilterExpression)))
+	}
+
+	return filter
+}
+
+// indexKey returns indexed field values of the given document.
+//
+// It returns nil if the document does not have some of the indexed fields,
+// or if some of them are arrays; like in backends' unique indexes, such keys do not conflict.
+// It also returns nil if the document does not match the filter of the partial index.
+func indexKey(index *backends.IndexInfo, doc *types.Document) *types.Document {
+	if index.PartialFilterExpression != nil {
+		if matches, err := common.FilterDocument(doc, index.PartialFilterExpression); err != nil || !matches {
+			return nil
+		}
+	}
+
+	key := types.MakeDocument(len(index.Key))
+
+	for _, pair := range index.Key {
+		path, err := types.NewPathFromString(pair.Field)
+		if err != nil {
+			return nil
+		}
+
+		v, err := doc.GetByPath(path)
+		if err != nil {
+			return nil
+		}
+
+		if _, ok := v.(*types.Array); ok {
+			return nil
+		}
+
+		key.Set(pair.Field, v)
+	}
+
+	return key
+}
@@ -42,6 +42,12 @@ func init() {
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
 
+			GroupMemoryLimit: opts.GroupMemoryLimit,
+			SortMemoryLimit:  opts.SortMemoryLimit,
+			SpillDir:         opts.SpillDir,
+
+			TTLMonitorInterval: opts.TTLMonitorInterval,
+
 			DisableFilterPushdown: opts.DisableFilterPushdown,
 			EnableOplog:           opts.EnableOplog,
 			EnableNewAuth:         opts.EnableNewAuth,
@@ -17,6 +17,7 @@ package registry
 
 import (
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -44,6 +45,12 @@ type NewHandlerOpts struct {
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
 
+	GroupMemoryLimit int64
+	SortMemoryLimit  int64
+	SpillDir         string
+
+	TTLMonitorInterval time.Duration
+
 	// for `postgresql` handler
 	PostgreSQLURL string
 
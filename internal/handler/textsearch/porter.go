@@ -0,0 +1,354 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textsearch
+
+// stem returns the stem of the given lowercase English word using the Porter stemming algorithm.
+//
+// See https://tartarus.org/martin/PorterStemmer/def.txt.
+// Words with characters other than ASCII lowercase letters are returned as-is.
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &stemmer{b: []byte(word)}
+	s.k = len(s.b) - 1
+
+	s.step1ab()
+
+	if s.k > 0 {
+		s.step1c()
+		s.step2()
+		s.step3()
+		s.step4()
+		s.step5()
+	}
+
+	return string(s.b[:s.k+1])
+}
+
+// stemmer contains the state of the Porter stemming algorithm.
+//
+// b[0:k+1] is the current word, j is a general offset into it.
+type stemmer struct {
+	b []byte
+	k int
+	j int
+}
+
+// cons returns true if b[i] is a consonant.
+func (s *stemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		if i == 0 {
+			return true
+		}
+
+		return !s.cons(i - 1)
+	default:
+		return true
+	}
+}
+
+// m returns the number of consonant sequences between 0 and j.
+//
+// If c is a consonant sequence and v a vowel sequence, then
+//
+//	<c><v>       gives 0
+//	<c>vc<v>     gives 1
+//	<c>vcvc<v>   gives 2
+//	...
+func (s *stemmer) m() int {
+	var n int
+	i := 0
+
+	for {
+		if i > s.j {
+			return n
+		}
+
+		if !s.cons(i) {
+			break
+		}
+
+		i++
+	}
+
+	i++
+
+	for {
+		for {
+			if i > s.j {
+				return n
+			}
+
+			if s.cons(i) {
+				break
+			}
+
+			i++
+		}
+
+		i++
+		n++
+
+		for {
+			if i > s.j {
+				return n
+			}
+
+			if !s.cons(i) {
+				break
+			}
+
+			i++
+		}
+
+		i++
+	}
+}
+
+// vowelInStem returns true if b[0:j+1] contains a vowel.
+func (s *stemmer) vowelInStem() bool {
+	for i := 0; i <= s.j; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// doubleC returns true if b[i-1:i+1] is a double consonant.
+func (s *stemmer) doubleC(i int) bool {
+	if i < 1 || s.b[i] != s.b[i-1] {
+		return false
+	}
+
+	return s.cons(i)
+}
+
+// cvc returns true if b[i-2:i+1] has the form consonant - vowel - consonant
+// and the second consonant is not w, x or y.
+//
+// It is used when trying to restore an e at the end of a short word
+// (cav(e), lov(e), hop(e), crim(e), but snow, box, tray).
+func (s *stemmer) cvc(i int) bool {
+	if i < 2 || !s.cons(i) || s.cons(i-1) || !s.cons(i-2) {
+		return false
+	}
+
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	default:
+		return true
+	}
+}
+
+// ends returns true if b[0:k+1] ends with the given suffix; it sets j to the end of the stem in that case.
+func (s *stemmer) ends(suffix string) bool {
+	l := len(suffix)
+
+	if l > s.k+1 || string(s.b[s.k-l+1:s.k+1]) != suffix {
+		return false
+	}
+
+	s.j = s.k - l
+
+	return true
+}
+
+// setTo sets b[j+1:] to the given string, adjusting k.
+func (s *stemmer) setTo(str string) {
+	s.b = append(s.b[:s.j+1], str...)
+	s.k = s.j + len(str)
+}
+
+// r sets b[j+1:] to the given string if m() > 0.
+func (s *stemmer) r(str string) {
+	if s.m() > 0 {
+		s.setTo(str)
+	}
+}
+
+// step1ab gets rid of plurals and -ed or -ing.
+func (s *stemmer) step1ab() {
+	if s.b[s.k] == 's' {
+		switch {
+		case s.ends("sses"):
+			s.k -= 2
+		case s.ends("ies"):
+			s.setTo("i")
+		case s.b[s.k-1] != 's':
+			s.k--
+		}
+	}
+
+	if s.ends("eed") {
+		if s.m() > 0 {
+			s.k--
+		}
+
+		return
+	}
+
+	if !(s.ends("ed") || s.ends("ing")) || !s.vowelInStem() {
+		return
+	}
+
+	s.k = s.j
+
+	switch {
+	case s.ends("at"):
+		s.setTo("ate")
+	case s.ends("bl"):
+		s.setTo("ble")
+	case s.ends("iz"):
+		s.setTo("ize")
+	case s.doubleC(s.k):
+		switch s.b[s.k-1] {
+		case 'l', 's', 'z':
+		default:
+			s.k--
+		}
+	default:
+		s.j = s.k
+		if s.m() == 1 && s.cvc(s.k) {
+			s.setTo("e")
+		}
+	}
+}
+
+// step1c turns terminal y to i when there is another vowel in the stem.
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[s.k] = 'i'
+	}
+}
+
+// replaceSuffix replaces the first matching suffix of the given pairs if m() > 0.
+func (s *stemmer) replaceSuffix(pairs ...string) {
+	for i := 0; i < len(pairs); i += 2 {
+		if s.ends(pairs[i]) {
+			s.r(pairs[i+1])
+			return
+		}
+	}
+}
+
+// step2 maps double suffixes to single ones.
+func (s *stemmer) step2() {
+	if s.k < 1 {
+		return
+	}
+
+	switch s.b[s.k-1] {
+	case 'a':
+		s.replaceSuffix("ational", "ate", "tional", "tion")
+	case 'c':
+		s.replaceSuffix("enci", "ence", "anci", "ance")
+	case 'e':
+		s.replaceSuffix("izer", "ize")
+	case 'l':
+		s.replaceSuffix("bli", "ble", "alli", "al", "entli", "ent", "eli", "e", "ousli", "ous")
+	case 'o':
+		s.replaceSuffix("ization", "ize", "ation", "ate", "ator", "ate")
+	case 's':
+		s.replaceSuffix("alism", "al", "iveness", "ive", "fulness", "ful", "ousness", "ous")
+	case 't':
+		s.replaceSuffix("aliti", "al", "iviti", "ive", "biliti", "ble")
+	case 'g':
+		s.replaceSuffix("logi", "log")
+	}
+}
+
+// step3 deals with -ic-, -full, -ness etc.
+func (s *stemmer) step3() {
+	switch s.b[s.k] {
+	case 'e':
+		s.replaceSuffix("icate", "ic", "ative", "", "alize", "al")
+	case 'i':
+		s.replaceSuffix("iciti", "ic")
+	case 'l':
+		s.replaceSuffix("ical", "ic", "ful", "")
+	case 's':
+		s.replaceSuffix("ness", "")
+	}
+}
+
+// step4 takes off -ant, -ence etc. in context <c>vcvc<v>.
+func (s *stemmer) step4() {
+	if s.k < 1 {
+		return
+	}
+
+	var found bool
+
+	switch s.b[s.k-1] {
+	case 'a':
+		found = s.ends("al")
+	case 'c':
+		found = s.ends("ance") || s.ends("ence")
+	case 'e':
+		found = s.ends("er")
+	case 'i':
+		found = s.ends("ic")
+	case 'l':
+		found = s.ends("able") || s.ends("ible")
+	case 'n':
+		found = s.ends("ant") || s.ends("ement") || s.ends("ment") || s.ends("ent")
+	case 'o':
+		found = (s.ends("ion") && s.j >= 0 && (s.b[s.j] == 's' || s.b[s.j] == 't')) || s.ends("ou")
+	case 's':
+		found = s.ends("ism")
+	case 't':
+		found = s.ends("ate") || s.ends("iti")
+	case 'u':
+		found = s.ends("ous")
+	case 'v':
+		found = s.ends("ive")
+	case 'z':
+		found = s.ends("ize")
+	}
+
+	if found && s.m() > 1 {
+		s.k = s.j
+	}
+}
+
+// step5 removes a final -e if m() > 1, and changes -ll to -l if m() > 1.
+func (s *stemmer) step5() {
+	s.j = s.k
+
+	if s.b[s.k] == 'e' {
+		a := s.m()
+		if a > 1 || (a == 1 && !s.cvc(s.k-1)) {
+			s.k--
+		}
+	}
+
+	if s.b[s.k] == 'l' && s.doubleC(s.k) && s.m() > 1 {
+		s.k--
+	}
+}
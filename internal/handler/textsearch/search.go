@@ -0,0 +1,372 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textsearch
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// WildcardField is the field name of text index that indexes all string fields.
+const WildcardField = "$**"
+
+// Index represents a text index.
+type Index struct {
+	// Weights maps indexed field paths (or WildcardField) to their weights.
+	Weights map[string]int32
+
+	DefaultLanguage  string
+	LanguageOverride string
+}
+
+// QueryParams represents parameters of `$text` query operator.
+type QueryParams struct {
+	Search string
+
+	// Language overrides the default language of the index if set.
+	Language string
+
+	CaseSensitive bool
+}
+
+// Search matches and scores documents for `$text` query.
+type Search struct {
+	index *Index
+	query *Query
+
+	// terms, negatedTerms, and phrases are normalized for the query language and case sensitivity
+	terms          []string
+	negatedTerms   []string
+	phrases        []string
+	negatedPhrases []string
+
+	caseSensitive bool
+}
+
+// NewSearch returns a new search for the given index and query.
+//
+// Languages should be validated by the caller.
+func NewSearch(index *Index, params *QueryParams) *Search {
+	language := params.Language
+	if language == "" {
+		language = index.DefaultLanguage
+	}
+
+	n := newNormalizer(language, params.CaseSensitive)
+	query := ParseQuery(params.Search)
+
+	s := &Search{
+		index:         index,
+		query:         query,
+		caseSensitive: params.CaseSensitive,
+	}
+
+	s.terms = normalizeTerms(n, query.Terms)
+	s.negatedTerms = normalizeTerms(n, query.NegatedTerms)
+
+	for _, p := range query.Phrases {
+		s.phrases = append(s.phrases, s.fold(p))
+	}
+
+	for _, p := range query.NegatedPhrases {
+		s.negatedPhrases = append(s.negatedPhrases, s.fold(p))
+	}
+
+	return s
+}
+
+// normalizeTerms returns unique terms for the given words, skipping stop words.
+func normalizeTerms(n *normalizer, words []string) []string {
+	var res []string
+
+	seen := make(map[string]struct{}, len(words))
+
+	for _, w := range words {
+		t, ok := n.term(w)
+		if !ok {
+			continue
+		}
+
+		if _, ok = seen[t]; ok {
+			continue
+		}
+
+		seen[t] = struct{}{}
+		res = append(res, t)
+	}
+
+	return res
+}
+
+// Words returns unique lowercase words of the query that documents should contain at least one of,
+// excluding stop words of the given language.
+//
+// It is used by backends to pre-filter documents with their text indexes.
+func (s *Search) Words(language string) []string {
+	n := newNormalizer(language, false)
+
+	var res []string
+
+	seen := make(map[string]struct{}, len(s.query.Terms))
+
+	for _, w := range s.query.Terms {
+		w = strings.ToLower(w)
+
+		if _, ok := n.term(w); !ok {
+			continue
+		}
+
+		if _, ok := seen[w]; ok {
+			continue
+		}
+
+		seen[w] = struct{}{}
+		res = append(res, w)
+	}
+
+	return res
+}
+
+// fold returns the given text lowercased unless search is case-sensitive.
+func (s *Search) fold(text string) string {
+	if s.caseSensitive {
+		return text
+	}
+
+	return strings.ToLower(text)
+}
+
+// Score returns the text score of the given document and true if it matches the search.
+func (s *Search) Score(doc *types.Document) (float64, bool) {
+	if len(s.terms) == 0 {
+		return 0, false
+	}
+
+	language := s.index.DefaultLanguage
+
+	if v, err := doc.Get(s.index.LanguageOverride); err == nil {
+		if l, ok := v.(string); ok && ValidateLanguage(l) == nil {
+			language = l
+		}
+	}
+
+	n := newNormalizer(language, s.caseSensitive)
+
+	scores := map[string]float64{}
+
+	var texts []string
+
+	for _, f := range s.fields(doc) {
+		texts = append(texts, f.text)
+		scoreText(n, f.text, float64(f.weight), scores)
+	}
+
+	for _, t := range s.negatedTerms {
+		if _, ok := scores[t]; ok {
+			return 0, false
+		}
+	}
+
+	for _, p := range s.phrases {
+		if !s.containsPhrase(texts, p) {
+			return 0, false
+		}
+	}
+
+	for _, p := range s.negatedPhrases {
+		if s.containsPhrase(texts, p) {
+			return 0, false
+		}
+	}
+
+	var res float64
+	var matched bool
+
+	for _, t := range s.terms {
+		if score, ok := scores[t]; ok {
+			res += score
+			matched = true
+		}
+	}
+
+	return res, matched
+}
+
+// containsPhrase returns true if any of the given texts contains the given folded phrase.
+func (s *Search) containsPhrase(texts []string, phrase string) bool {
+	for _, text := range texts {
+		if strings.Contains(s.fold(text), phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// field represents an indexed string value of the document.
+type field struct {
+	text   string
+	weight int32
+}
+
+// fields returns all indexed string values of the document.
+func (s *Search) fields(doc *types.Document) []field {
+	var res []field
+
+	paths := make([]string, 0, len(s.index.Weights))
+	for path := range s.index.Weights {
+		paths = append(paths, path)
+	}
+
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		weight := s.index.Weights[path]
+
+		if path == WildcardField {
+			res = s.appendWildcardFields(res, "", doc, weight)
+			continue
+		}
+
+		p, err := types.NewPathFromString(path)
+		if err != nil {
+			continue
+		}
+
+		values, err := commonpath.FindValues(doc, p, &commonpath.FindValuesOpts{FindArrayDocuments: true})
+		if err != nil {
+			continue
+		}
+
+		for _, v := range values {
+			res = appendStrings(res, v, weight)
+		}
+	}
+
+	return res
+}
+
+// appendWildcardFields appends all string values of the given value to res,
+// except values of fields with explicit weights and the language override field.
+func (s *Search) appendWildcardFields(res []field, path string, v any, weight int32) []field {
+	if path != "" {
+		if _, ok := s.index.Weights[path]; ok {
+			return res
+		}
+
+		if path == s.index.LanguageOverride {
+			return res
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		return append(res, field{text: v, weight: weight})
+
+	case *types.Document:
+		iter := v.Iterator()
+		defer iter.Close()
+
+		for {
+			k, fv, err := iter.Next()
+			if err != nil {
+				// only iterator.ErrIteratorDone is possible for documents
+				return res
+			}
+
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			res = s.appendWildcardFields(res, p, fv, weight)
+		}
+
+	case *types.Array:
+		for _, e := range must.NotFail(iterator.ConsumeValues(v.Iterator())) {
+			res = s.appendWildcardFields(res, path, e, weight)
+		}
+	}
+
+	return res
+}
+
+// appendStrings appends the given string value or string elements of the given array to res.
+func appendStrings(res []field, v any, weight int32) []field {
+	switch v := v.(type) {
+	case string:
+		return append(res, field{text: v, weight: weight})
+
+	case *types.Array:
+		for _, e := range must.NotFail(iterator.ConsumeValues(v.Iterator())) {
+			if s, ok := e.(string); ok {
+				res = append(res, field{text: s, weight: weight})
+			}
+		}
+	}
+
+	return res
+}
+
+// scoreText adds scores of terms of the given text to scores.
+//
+// Repeated terms contribute less: the n-th occurrence adds 1/2^(n-1).
+// Terms that make a larger part of the text, and terms equal to the whole text, have higher scores.
+func scoreText(n *normalizer, text string, weight float64, scores map[string]float64) {
+	type termData struct {
+		exp   float64
+		freq  float64
+		count int
+	}
+
+	terms := map[string]*termData{}
+
+	var numTokens int
+
+	for _, w := range Words(text) {
+		t, ok := n.term(w)
+		if !ok {
+			continue
+		}
+
+		d := terms[t]
+		if d == nil {
+			d = &termData{exp: 1}
+			terms[t] = d
+		} else {
+			d.exp *= 2
+		}
+
+		d.count++
+		d.freq += 1 / d.exp
+		numTokens++
+	}
+
+	for t, d := range terms {
+		coeff := 0.5*float64(d.count)/float64(numTokens) + 0.5
+
+		adjustment := 1.0
+		if strings.EqualFold(t, text) {
+			adjustment += 0.1
+		}
+
+		scores[t] += weight * d.freq * coeff * adjustment
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package textsearch implements full-text search used by text indexes and `$text` query operator.
+//
+// Text is split into words (sequences of letters and digits).
+// Words are lowercased (unless search is case-sensitive).
+// For English, stop words are ignored and remaining words are stemmed with the Porter stemmer;
+// for other supported languages, words are used as-is.
+//
+// Document scores are computed the same way as MongoDB does that.
+package textsearch
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultLanguage is the default language of text indexes.
+const DefaultLanguage = "english"
+
+// DefaultLanguageOverride is the default name of the document field that contains the document's language.
+const DefaultLanguageOverride = "language"
+
+// languages maps supported language names and codes to language names.
+var languages = map[string]string{
+	"none": "none",
+
+	"danish": "danish", "da": "danish",
+	"dutch": "dutch", "nl": "dutch",
+	"english": "english", "en": "english",
+	"finnish": "finnish", "fi": "finnish",
+	"french": "french", "fr": "french",
+	"german": "german", "de": "german",
+	"hungarian": "hungarian", "hu": "hungarian",
+	"italian": "italian", "it": "italian",
+	"norwegian": "norwegian", "nb": "norwegian",
+	"portuguese": "portuguese", "pt": "portuguese",
+	"romanian": "romanian", "ro": "romanian",
+	"russian": "russian", "ru": "russian",
+	"spanish": "spanish", "es": "spanish",
+	"swedish": "swedish", "sv": "swedish",
+	"turkish": "turkish", "tr": "turkish",
+}
+
+// ValidateLanguage returns an error if the given language is not supported.
+func ValidateLanguage(language string) error {
+	if _, ok := languages[strings.ToLower(language)]; !ok {
+		return fmt.Errorf("unsupported language: %q for text index version 3", language)
+	}
+
+	return nil
+}
+
+// SameLanguage returns true if given supported languages are the same.
+func SameLanguage(a, b string) bool {
+	return languages[strings.ToLower(a)] == languages[strings.ToLower(b)]
+}
+
+// isWordRune returns true if the given rune is a part of the word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Words splits the given text into words.
+func Words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) })
+}
+
+// Query represents a parsed `$search` string of `$text` query operator.
+//
+// Words and phrases are not lowercased.
+type Query struct {
+	// Terms contains all words that are not negated, including words of phrases.
+	Terms          []string
+	NegatedTerms   []string
+	Phrases        []string
+	NegatedPhrases []string
+}
+
+// ParseQuery parses the given `$search` string.
+//
+// Words are separated by non-letter and non-digit characters.
+// Word prefixed with hyphen-minus is negated.
+// Text in double quotes is a phrase; it is negated if prefixed with hyphen-minus.
+func ParseQuery(search string) *Query {
+	var res Query
+
+	runes := []rune(search)
+
+	// negationAt returns true if there is a negation right before the given position
+	negationAt := func(i int) bool {
+		return i > 0 && runes[i-1] == '-' && (i == 1 || unicode.IsSpace(runes[i-2]))
+	}
+
+	var inPhrase, negatedPhrase bool
+	var phraseStart int
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case r == '"':
+			if inPhrase {
+				phrase := string(runes[phraseStart:i])
+
+				if negatedPhrase {
+					res.NegatedPhrases = append(res.NegatedPhrases, phrase)
+				} else {
+					res.Phrases = append(res.Phrases, phrase)
+				}
+
+				inPhrase = false
+			} else {
+				inPhrase = true
+				negatedPhrase = negationAt(i)
+				phraseStart = i + 1
+			}
+
+			i++
+
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+
+			word := string(runes[start:i])
+
+			switch {
+			case inPhrase && negatedPhrase:
+				// words of negated phrases are not terms
+			case inPhrase:
+				res.Terms = append(res.Terms, word)
+			case negationAt(start):
+				res.NegatedTerms = append(res.NegatedTerms, word)
+			default:
+				res.Terms = append(res.Terms, word)
+			}
+
+		default:
+			i++
+		}
+	}
+
+	// unterminated phrase is a phrase till the end of the string
+	if inPhrase && phraseStart < len(runes) {
+		phrase := string(runes[phraseStart:])
+
+		if negatedPhrase {
+			res.NegatedPhrases = append(res.NegatedPhrases, phrase)
+		} else {
+			res.Phrases = append(res.Phrases, phrase)
+		}
+	}
+
+	return &res
+}
+
+// normalizer converts words to terms that are compared.
+type normalizer struct {
+	english       bool
+	caseSensitive bool
+}
+
+// newNormalizer returns a new normalizer for the given supported language.
+func newNormalizer(language string, caseSensitive bool) *normalizer {
+	return &normalizer{
+		english:       languages[strings.ToLower(language)] == "english",
+		caseSensitive: caseSensitive,
+	}
+}
+
+// term returns the term for the given word, or false if the word is a stop word.
+func (n *normalizer) term(word string) (string, bool) {
+	lower := strings.ToLower(word)
+
+	if !n.english {
+		if n.caseSensitive {
+			return word, true
+		}
+
+		return lower, true
+	}
+
+	if _, ok := englishStopWords[lower]; ok {
+		return "", false
+	}
+
+	if n.caseSensitive && word != lower {
+		return word, true
+	}
+
+	return stem(lower), true
+}
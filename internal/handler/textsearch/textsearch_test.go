@@ -0,0 +1,163 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestStem(t *testing.T) {
+	t.Parallel()
+
+	for word, expected := range map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"cats":           "cat",
+		"agreed":         "agre",
+		"plastered":      "plaster",
+		"motoring":       "motor",
+		"hopping":        "hop",
+		"filing":         "file",
+		"happy":          "happi",
+		"relational":     "relat",
+		"conditional":    "condit",
+		"generalization": "gener",
+		"electrical":     "electr",
+		"adjustment":     "adjust",
+		"controlling":    "control",
+		"running":        "run",
+		"runs":           "run",
+		"is":             "is",
+		"café":           "café",
+	} {
+		assert.Equal(t, expected, stem(word), word)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	for search, expected := range map[string]*Query{
+		"coffee shop": {
+			Terms: []string{"coffee", "shop"},
+		},
+		"coffee -shop": {
+			Terms:        []string{"coffee"},
+			NegatedTerms: []string{"shop"},
+		},
+		"coffee-shop": {
+			Terms: []string{"coffee", "shop"},
+		},
+		`"coffee shop" cake`: {
+			Terms:   []string{"coffee", "shop", "cake"},
+			Phrases: []string{"coffee shop"},
+		},
+		`cake -"coffee shop"`: {
+			Terms:          []string{"cake"},
+			NegatedPhrases: []string{"coffee shop"},
+		},
+		`"unterminated phrase`: {
+			Terms:   []string{"unterminated", "phrase"},
+			Phrases: []string{"unterminated phrase"},
+		},
+	} {
+		assert.Equal(t, expected, ParseQuery(search), search)
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	index := &Index{
+		Weights:          map[string]int32{"subject": 1},
+		DefaultLanguage:  DefaultLanguage,
+		LanguageOverride: DefaultLanguageOverride,
+	}
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(0), "subject", "coffee")),
+		must.NotFail(types.NewDocument("_id", int32(1), "subject", "Coffee Shopper")),
+		must.NotFail(types.NewDocument("_id", int32(2), "subject", "Baking a cake")),
+		must.NotFail(types.NewDocument("_id", int32(3), "subject", "baking")),
+		must.NotFail(types.NewDocument("_id", int32(4), "subject", "Cafe Con Leche")),
+		must.NotFail(types.NewDocument("_id", int32(5), "subject", "coffee and cream", "language", "none")),
+		must.NotFail(types.NewDocument("_id", int32(6), "subject", types.MakeArray(0))),
+		must.NotFail(types.NewDocument("_id", int32(7))),
+	}
+
+	for name, tc := range map[string]struct {
+		params   *QueryParams
+		expected map[int32]float64
+	}{
+		"Term": {
+			params:   &QueryParams{Search: "coffee"},
+			expected: map[int32]float64{0: 1, 1: 0.75},
+		},
+		"Stemming": {
+			params:   &QueryParams{Search: "bake"},
+			expected: map[int32]float64{2: 0.75, 3: 1},
+		},
+		"Negation": {
+			params:   &QueryParams{Search: "coffee -shopper"},
+			expected: map[int32]float64{0: 1},
+		},
+		"Phrase": {
+			params:   &QueryParams{Search: `"coffee shop"`},
+			expected: map[int32]float64{1: 0.75},
+		},
+		"Language": {
+			params:   &QueryParams{Search: "coffee", Language: "none"},
+			expected: map[int32]float64{5: 2.0 / 3},
+		},
+		"CaseSensitive": {
+			params:   &QueryParams{Search: "Coffee", CaseSensitive: true},
+			expected: map[int32]float64{1: 0.75},
+		},
+		"StopWords": {
+			params:   &QueryParams{Search: "a and the"},
+			expected: map[int32]float64{},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewSearch(index, tc.params)
+
+			actual := map[int32]float64{}
+
+			for _, doc := range docs {
+				score, ok := s.Score(doc)
+				if !ok {
+					continue
+				}
+
+				actual[must.NotFail(doc.Get("_id")).(int32)] = score
+			}
+
+			require.Len(t, actual, len(tc.expected))
+
+			for id, score := range tc.expected {
+				assert.InDelta(t, score, actual[id], 1e-9, "_id %d", id)
+			}
+		})
+	}
+}
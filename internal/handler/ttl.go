@@ -0,0 +1,306 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Parts of Prometheus metric names of TTL monitor.
+const (
+	ttlNamespace = "ferretdb"
+	ttlSubsystem = "ttl_monitor"
+)
+
+// ttlBatchSize is the maximal number of expired documents deleted by _id values at once
+// when the backend can't delete them by filter.
+const ttlBatchSize = 1000
+
+// ttlMonitor periodically deletes expired documents from collections with TTL indexes.
+//
+// A document expires when the field of the TTL index contains a date (or an array with a date)
+// that is older than the index's expireAfterSeconds.
+// Documents without such dates never expire.
+//
+//nolint:vet // for readability
+type ttlMonitor struct {
+	b        backends.Backend
+	l        *zap.Logger
+	interval time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	passes  prometheus.Counter
+	deleted *prometheus.CounterVec
+}
+
+// newTTLMonitor creates a new TTL monitor and starts it in the background.
+//
+// It runs a pass every interval until close is called.
+func newTTLMonitor(b backends.Backend, l *zap.Logger, interval time.Duration) *ttlMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := &ttlMonitor{
+		b:        b,
+		l:        l,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		passes: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: ttlNamespace,
+				Subsystem: ttlSubsystem,
+				Name:      "passes_total",
+				Help:      "Total number of TTL monitor passes.",
+			},
+		),
+		deleted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: ttlNamespace,
+				Subsystem: ttlSubsystem,
+				Name:      "deleted_total",
+				Help:      "Total number of expired documents deleted by TTL monitor.",
+			},
+			[]string{"db", "collection"},
+		),
+	}
+
+	go m.run(ctx)
+
+	return m
+}
+
+// run runs passes until context is canceled.
+func (m *ttlMonitor) run(ctx context.Context) {
+	defer close(m.done)
+
+	m.l.Debug("TTL monitor started.", zap.Duration("interval", m.interval))
+	defer m.l.Debug("TTL monitor stopped.")
+
+	for {
+		ctxutil.Sleep(ctx, m.interval)
+
+		if context.Cause(ctx) != nil {
+			return
+		}
+
+		if err := m.pass(ctx, time.Now()); err != nil && context.Cause(ctx) == nil {
+			m.l.Error("TTL monitor pass failed.", zap.Error(err))
+		}
+	}
+}
+
+// close stops the monitor and waits for the current pass to finish.
+func (m *ttlMonitor) close() {
+	m.cancel()
+	<-m.done
+}
+
+// pass deletes documents expired at the given time from all collections.
+//
+// Errors for individual collections are logged and do not stop the pass.
+func (m *ttlMonitor) pass(ctx context.Context, now time.Time) error {
+	defer m.passes.Inc()
+
+	dbList, err := m.b.ListDatabases(ctx, new(backends.ListDatabasesParams))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, dbInfo := range dbList.Databases {
+		db, err := m.b.Database(dbInfo.Name)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		cList, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+		if err != nil {
+			// database might be dropped concurrently
+			m.l.Debug("Failed to list collections.", zap.String("db", dbInfo.Name), zap.Error(err))
+			continue
+		}
+
+		for _, cInfo := range cList.Collections {
+			if cInfo.View != nil {
+				continue
+			}
+
+			if err = m.expire(ctx, db, dbInfo.Name, cInfo.Name, now); err != nil {
+				if context.Cause(ctx) != nil {
+					return lazyerrors.Error(err)
+				}
+
+				m.l.Warn(
+					"Failed to delete expired documents.",
+					zap.String("db", dbInfo.Name), zap.String("collection", cInfo.Name), zap.Error(err),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// expire deletes documents expired at the given time from the collection.
+func (m *ttlMonitor) expire(ctx context.Context, db backends.Database, dbName, cName string, now time.Time) error {
+	c, err := db.Collection(cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	indexes, err := c.ListIndexes(ctx, new(backends.ListIndexesParams))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, index := range indexes.Indexes {
+		if index.ExpireAfterSeconds == nil || len(index.Key) != 1 {
+			continue
+		}
+
+		expireAt := now.Add(-time.Duration(*index.ExpireAfterSeconds) * time.Second)
+
+		// $lte matches only dates and arrays containing dates; other values never expire
+		filter := must.NotFail(types.NewDocument(
+			index.Key[0].Field, must.NotFail(types.NewDocument("$lte", expireAt)),
+		))
+
+		// documents that are not in the partial index never expire
+		if index.PartialFilterExpression != nil {
+			filter.Set("$and", must.NotFail(types.NewArray(index.PartialFilterExpression)))
+		}
+
+		res, err := c.DeleteAll(ctx, &backends.DeleteAllParams{Filter: filter})
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		deleted := res.Deleted
+
+		if !res.FilterPushdown {
+			if deleted, err = deleteExpired(ctx, c, filter); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		if deleted == 0 {
+			continue
+		}
+
+		m.l.Debug(
+			"Expired documents deleted.",
+			zap.String("db", dbName), zap.String("collection", cName),
+			zap.String("index", index.Name), zap.Int32("deleted", deleted),
+		)
+
+		m.deleted.WithLabelValues(dbName, cName).Add(float64(deleted))
+	}
+
+	return nil
+}
+
+// deleteExpired deletes collection documents matching the given filter by their _id values,
+// at most ttlBatchSize documents at once, and returns the number of deleted documents.
+func deleteExpired(ctx context.Context, c backends.Collection, filter *types.Document) (int32, error) {
+	var deleted int32
+
+	for {
+		ids, err := expiredIDs(ctx, c, filter)
+		if err != nil {
+			return deleted, lazyerrors.Error(err)
+		}
+
+		if len(ids) == 0 {
+			return deleted, nil
+		}
+
+		res, err := c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: ids})
+		if err != nil {
+			return deleted, lazyerrors.Error(err)
+		}
+
+		deleted += res.Deleted
+
+		// stop if there are no more expired documents or they can't be deleted
+		if len(ids) < ttlBatchSize || res.Deleted == 0 {
+			return deleted, nil
+		}
+	}
+}
+
+// expiredIDs returns _id values of at most ttlBatchSize collection documents matching the given filter.
+func expiredIDs(ctx context.Context, c backends.Collection, filter *types.Document) ([]any, error) {
+	q, err := c.Query(ctx, &backends.QueryParams{Filter: filter})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer q.Iter.Close()
+
+	var ids []any
+
+	for len(ids) < ttlBatchSize {
+		_, doc, err := q.Iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		matches, err := common.FilterDocument(doc, filter)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if matches {
+			ids = append(ids, must.NotFail(doc.Get("_id")))
+		}
+	}
+
+	return ids, nil
+}
+
+// Describe implements prometheus.Collector interface.
+func (m *ttlMonitor) Describe(ch chan<- *prometheus.Desc) {
+	m.passes.Describe(ch)
+	m.deleted.Describe(ch)
+}
+
+// Collect implements prometheus.Collector interface.
+func (m *ttlMonitor) Collect(ch chan<- prometheus.Metric) {
+	m.passes.Collect(ch)
+	m.deleted.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*ttlMonitor)(nil)
+)
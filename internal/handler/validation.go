@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// documentValidationFailed is the message of document validation errors.
+const documentValidationFailed = "Document failed validation"
+
+// documentValidator validates documents written to the collection.
+type documentValidator struct {
+	l         *zap.Logger
+	validator *types.Document
+	level     string
+	action    string
+}
+
+// newDocumentValidator returns a validator of documents written to the given collection,
+// or nil if they should not be validated.
+func (h *Handler) newDocumentValidator(ctx context.Context, db backends.Database, collection string, bypass bool) (*documentValidator, error) {
+	if bypass {
+		return nil, nil
+	}
+
+	list, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// TODO https://github.com/FerretDB/FerretDB/issues/3601
+	i, found := slices.BinarySearchFunc(list.Collections, collection, func(e backends.CollectionInfo, t string) int {
+		return cmp.Compare(e.Name, t)
+	})
+	if !found {
+		return nil, nil
+	}
+
+	v := list.Collections[i].Validation
+	if v == nil || v.Validator == nil || v.Validator.Len() == 0 || v.Level == common.ValidationLevelOff {
+		return nil, nil
+	}
+
+	return &documentValidator{
+		l:         h.L,
+		validator: v.Validator,
+		level:     v.Level,
+		action:    v.Action,
+	}, nil
+}
+
+// validate returns document validation error information (`errInfo`)
+// if the document does not satisfy the validator and should not be written.
+//
+// The old document is the document before update, or nil for inserts.
+// With moderate validation level, updates of documents that already do not satisfy the validator are not validated.
+//
+// It is safe to call it on nil validator.
+func (v *documentValidator) validate(doc, old *types.Document) (*types.Document, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if old != nil && v.level == common.ValidationLevelModerate {
+		errInfo, err := common.ValidateDocument(old, v.validator)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if errInfo != nil {
+			return nil, nil
+		}
+	}
+
+	errInfo, err := common.ValidateDocument(doc, v.validator)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if errInfo == nil {
+		return nil, nil
+	}
+
+	if v.action == common.ValidationActionWarn {
+		v.l.Warn(
+			"Document would fail validation",
+			zap.Any("id", must.NotFail(doc.Get("_id"))), zap.String("errInfo", types.FormatAnyValue(errInfo)),
+		)
+
+		return nil, nil
+	}
+
+	return errInfo, nil
+}
+
+// newDocumentValidationWriteError returns a write error for the document that failed validation.
+func newDocumentValidationWriteError(index int, errInfo *types.Document) (*mongo.WriteError, error) {
+	d, err := bson.ConvertDocument(errInfo)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	raw, err := d.MarshalBinary()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &mongo.WriteError{
+		Index:   index,
+		Code:    int(handlererrors.ErrDocumentValidationFailure),
+		Message: documentValidationFailed,
+		Details: raw,
+	}, nil
+}
+
+// writeErrorInfo returns `errInfo` of the write error, or nil if it is not set.
+func writeErrorInfo(we *mongo.WriteError) (*types.Document, error) {
+	if len(we.Details) == 0 {
+		return nil, nil
+	}
+
+	var d bson.Document
+	if err := d.ReadFrom(bufio.NewReader(bytes.NewReader(we.Details))); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := types.ConvertDocument(&d)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return doc, nil
+}
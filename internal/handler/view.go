@@ -0,0 +1,246 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// maxViewDepth is the maximum number of nested views.
+const maxViewDepth = 20
+
+// view represents a read-only view resolved to the collection it is defined on.
+type view struct {
+	c        backends.Collection
+	dbName   string
+	source   string // name of the collection the view is (maybe indirectly) defined on
+	pipeline []any  // pipelines of the view and views it is defined on, starting from the innermost one
+}
+
+// getView returns the view with the given name resolved to the collection it is (maybe indirectly) defined on,
+// or nil if there is no such view.
+func (h *Handler) getView(ctx context.Context, db backends.Database, dbName, name string) (*view, error) {
+	list, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	source, pipeline, err := resolveView(list.Collections, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if source == name {
+		return nil, nil
+	}
+
+	c, err := db.Collection(source)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &view{
+		c:        c,
+		dbName:   dbName,
+		source:   source,
+		pipeline: pipeline,
+	}, nil
+}
+
+// resolveView returns the name of the collection the view with the given name is (maybe indirectly) defined on,
+// and the combined pipeline of all nested views.
+//
+// For regular and non-existing collections, it returns the given name and nil pipeline.
+func resolveView(collections []backends.CollectionInfo, name string) (string, []any, error) {
+	var pipeline []any
+
+	for depth := 0; ; depth++ {
+		// TODO https://github.com/FerretDB/FerretDB/issues/3601
+		i, found := slices.BinarySearchFunc(collections, name, func(e backends.CollectionInfo, t string) int {
+			return cmp.Compare(e.Name, t)
+		})
+		if !found || collections[i].View == nil {
+			return name, pipeline, nil
+		}
+
+		if depth == maxViewDepth {
+			return "", nil, handlererrors.NewCommandErrorMsg(
+				handlererrors.ErrViewDepthLimitExceeded,
+				fmt.Sprintf("View depth too deep or view cycle detected. Maximum depth is %d", maxViewDepth),
+			)
+		}
+
+		v := collections[i].View
+		pipeline = append(must.NotFail(iterator.ConsumeValues(v.Pipeline.Iterator())), pipeline...)
+		name = v.ViewOn
+	}
+}
+
+// checkViewCycle returns an error if the view with the given name defined on viewOn
+// would be (maybe indirectly) defined on itself.
+func checkViewCycle(collections []backends.CollectionInfo, dbName, name, viewOn, command string) error {
+	path := []string{dbName + "." + name}
+
+	for next := viewOn; ; {
+		path = append(path, dbName+"."+next)
+
+		if next == name {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrGraphContainsCycle,
+				"View cycle detected: "+strings.Join(path, " => "),
+				command,
+			)
+		}
+
+		// TODO https://github.com/FerretDB/FerretDB/issues/3601
+		i, found := slices.BinarySearchFunc(collections, next, func(e backends.CollectionInfo, t string) int {
+			return cmp.Compare(e.Name, t)
+		})
+		if !found || collections[i].View == nil {
+			break
+		}
+
+		if len(path) > maxViewDepth {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrViewDepthLimitExceeded,
+				fmt.Sprintf("View depth too deep or view cycle detected. Maximum depth is %d", maxViewDepth),
+				command,
+			)
+		}
+
+		next = collections[i].View.ViewOn
+	}
+
+	return nil
+}
+
+// queryView returns an iterator over documents of the view.
+//
+// The leading $match stage of the view pipeline is pushed down to the backend.
+func (h *Handler) queryView(ctx context.Context, v *view, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
+	var qp backends.QueryParams
+	if !h.DisableFilterPushdown {
+		qp.Filter, _ = aggregations.GetPushdownQuery(v.pipeline)
+	}
+
+	queryRes, err := v.c.Query(ctx, &qp)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.indexUsage.record(v.dbName, v.source, queryRes.Index)
+
+	closer.Add(queryRes.Iter)
+
+	iter := types.DocumentsIterator(queryRes.Iter)
+
+	stageParams := &stages.NewStageParams{
+		AllowDiskUse:     true,
+		GroupMemoryLimit: h.GroupMemoryLimit,
+		SortMemoryLimit:  h.SortMemoryLimit,
+		SpillDir:         h.SpillDir,
+	}
+
+	for _, d := range v.pipeline {
+		var s aggregations.Stage
+
+		if s, err = stages.NewStage(d.(*types.Document), stageParams); err != nil {
+			return nil, err
+		}
+
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
+
+// newQueryOnViewError returns an error for the query operator that can't be used with views
+// because they have no indexes.
+func newQueryOnViewError(operator, command string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrOptionNotSupportedOnView,
+		fmt.Sprintf("%s is not supported on views", operator),
+		command,
+	)
+}
+
+// validateViewPipeline returns an error if the given pipeline can't be used in the view definition.
+func validateViewPipeline(pipeline *types.Array, command string) error {
+	iter := pipeline.Iterator()
+	defer iter.Close()
+
+	for _, v := range must.NotFail(iterator.ConsumeValues(iter)) {
+		d, ok := v.(*types.Document)
+		if !ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"Each element of the 'pipeline' array must be an object",
+				command,
+			)
+		}
+
+		if d.Command() == "$collStats" {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrOptionNotSupportedOnView,
+				"$collStats is not allowed in a view definition",
+				command,
+			)
+		}
+
+		if _, err := stages.NewStage(d, new(stages.NewStageParams)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkNotView returns an error if the collection with the given name is a view;
+// views are read-only and have no indexes.
+func checkNotView(ctx context.Context, db backends.Database, dbName, collection, command string) error {
+	list, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	// TODO https://github.com/FerretDB/FerretDB/issues/3601
+	i, found := slices.BinarySearchFunc(list.Collections, collection, func(e backends.CollectionInfo, t string) int {
+		return cmp.Compare(e.Name, t)
+	})
+	if !found || list.Collections[i].View == nil {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrCommandNotSupportedOnView,
+		fmt.Sprintf("Namespace %s.%s is a view, not a collection", dbName, collection),
+		command,
+	)
+}
@@ -39,11 +39,16 @@ type document interface {
 // Data documents (that are stored in the backend) have a special RecordID property
 // that is not a field and can't be accessed by most methods.
 // It is used to locate the document in the backend.
+//
+// Documents matched by `$text` query operator have a special TextScore property
+// that is not a field too.
 type Document struct {
-	keys     map[string]int
-	fields   []field
-	frozen   bool
-	recordID int64
+	keys         map[string]int
+	fields       []field
+	recordID     int64
+	textScore    float64
+	frozen       bool
+	hasTextScore bool
 }
 
 // field represents a field in the document.
@@ -154,10 +159,21 @@ func (d *Document) SetRecordID(recordID int64) {
 	d.recordID = recordID
 }
 
+// TextScore returns the document's text score and true if it is set.
+func (d *Document) TextScore() (float64, bool) {
+	return d.textScore, d.hasTextScore
+}
+
+// SetTextScore sets the document's text score.
+func (d *Document) SetTextScore(score float64) {
+	d.textScore = score
+	d.hasTextScore = true
+}
+
 // Freeze prevents document from further field modifications.
 // Any methods that would modify document fields will panic.
 //
-// RecordID modification is not prevented.
+// RecordID and TextScore modifications are not prevented.
 //
 // It is safe to call Freeze multiple times.
 func (d *Document) Freeze() {
@@ -174,7 +190,7 @@ func (d *Document) checkFrozen() {
 }
 
 // DeepCopy returns an unfrozen deep copy of this Document.
-// RecordID is copied too.
+// RecordID and TextScore are copied too.
 func (d *Document) DeepCopy() *Document {
 	if d == nil {
 		panic("types.Document.DeepCopy: nil document")
@@ -137,9 +137,11 @@ func deepCopy(value any) any {
 		}
 
 		return &Document{
-			fields:   fields,
-			keys:     maps.Clone(value.keys),
-			recordID: value.recordID,
+			fields:       fields,
+			keys:         maps.Clone(value.keys),
+			recordID:     value.recordID,
+			textScore:    value.textScore,
+			hasTextScore: value.hasTextScore,
 		}
 
 	case *Array:
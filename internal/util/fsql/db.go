@@ -139,8 +139,44 @@ func (db *DB) InTransaction(ctx context.Context, f func(*Tx) error) (err error)
 		return
 	}
 
-	tx := wrapTx(sqlTx, db.l)
+	return runTransaction(wrapTx(sqlTx, db.l), f)
+}
+
+// InTransactionImmediate is a variant of InTransaction that starts SQLite transaction
+// with `BEGIN IMMEDIATE` statement.
+//
+// Unlike a default deferred transaction, it acquires the write lock at the start,
+// so it waits for other writers (up to the busy timeout) instead of failing to upgrade the read lock later.
+// That makes read-modify-write cycles inside f atomic.
+func (db *DB) InTransactionImmediate(ctx context.Context, f func(*Tx) error) (err error) {
+	defer observability.FuncCall(ctx)()
+
+	var conn *sql.Conn
+
+	if conn, err = db.sqlDB.Conn(ctx); err != nil {
+		err = lazyerrors.Error(err)
+		return
+	}
+
+	db.l.Sugar().Debugf(">>> %s", "BEGIN IMMEDIATE")
+
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+
+	db.l.Sugar().With(zap.Error(err)).Debugf("<<< %s", "BEGIN IMMEDIATE")
+
+	if err != nil {
+		_ = conn.Close()
+		err = lazyerrors.Error(err)
+
+		return
+	}
+
+	return runTransaction(wrapConnTx(conn, db.l), f)
+}
 
+// runTransaction calls the given function f with the started transaction,
+// and then commits or rolls back that transaction.
+func runTransaction(tx *Tx, f func(*Tx) error) (err error) {
 	var done bool
 
 	defer func() {
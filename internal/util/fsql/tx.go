@@ -17,6 +17,7 @@ package fsql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"time"
 
 	"go.uber.org/zap"
@@ -30,10 +31,18 @@ import (
 // It exposes the subset of *sql.Tx methods we use.
 type Tx struct {
 	sqlTx *sql.Tx
+	conn  *sql.Conn // used instead of sqlTx for transactions started by an explicit statement
 	l     *zap.Logger
 	token *resource.Token
 }
 
+// querier represents methods shared by [*database/sql.Tx] and [*database/sql.Conn].
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
 // wrapTx creates new Tx.
 func wrapTx(tx *sql.Tx, l *zap.Logger) *Tx {
 	if tx == nil {
@@ -51,18 +60,72 @@ func wrapTx(tx *sql.Tx, l *zap.Logger) *Tx {
 	return res
 }
 
+// wrapConnTx creates new Tx for the transaction started on the given connection.
+//
+// The connection is closed (returned to the pool) when the transaction ends.
+func wrapConnTx(conn *sql.Conn, l *zap.Logger) *Tx {
+	res := &Tx{
+		conn:  conn,
+		l:     l,
+		token: resource.NewToken(),
+	}
+
+	resource.Track(res, res.token)
+
+	return res
+}
+
+// querier returns the object that executes queries in the transaction.
+func (tx *Tx) querier() querier {
+	if tx.conn != nil {
+		return tx.conn
+	}
+
+	return tx.sqlTx
+}
+
 // Commit calls [*sql.Tx.Commit].
 func (tx *Tx) Commit() error {
 	resource.Untrack(tx, tx.token)
+
+	if tx.conn != nil {
+		return tx.end("COMMIT")
+	}
+
 	return tx.sqlTx.Commit()
 }
 
 // Rollback calls [*sql.Tx.Rollback].
 func (tx *Tx) Rollback() error {
 	resource.Untrack(tx, tx.token)
+
+	if tx.conn != nil {
+		return tx.end("ROLLBACK")
+	}
+
 	return tx.sqlTx.Rollback()
 }
 
+// end executes the given statement that ends the transaction started on the connection,
+// and closes that connection.
+//
+// If the transaction can't be ended, the connection is discarded instead of being returned to the pool.
+func (tx *Tx) end(query string) error {
+	tx.l.Sugar().Debugf(">>> %s", query)
+
+	_, err := tx.conn.ExecContext(context.Background(), query)
+
+	tx.l.Sugar().With(zap.Error(err)).Debugf("<<< %s", query)
+
+	if err != nil {
+		_ = tx.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+
+	_ = tx.conn.Close()
+
+	return err
+}
+
 // QueryContext calls [*sql.Tx.QueryContext].
 func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
 	defer observability.FuncCall(ctx)()
@@ -72,7 +135,7 @@ func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*Row
 	fields := []any{zap.Any("args", args)}
 	tx.l.Sugar().With(fields...).Debugf(">>> %s", query)
 
-	rows, err := tx.sqlTx.QueryContext(ctx, query, args...)
+	rows, err := tx.querier().QueryContext(ctx, query, args...)
 
 	fields = append(fields, zap.Duration("time", time.Since(start)), zap.Error(err))
 	tx.l.Sugar().With(fields...).Debugf("<<< %s", query)
@@ -89,7 +152,7 @@ func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *s
 	fields := []any{zap.Any("args", args)}
 	tx.l.Sugar().With(fields...).Debugf(">>> %s", query)
 
-	row := tx.sqlTx.QueryRowContext(ctx, query, args...)
+	row := tx.querier().QueryRowContext(ctx, query, args...)
 
 	fields = append(fields, zap.Duration("time", time.Since(start)), zap.Error(row.Err()))
 	tx.l.Sugar().With(fields...).Debugf("<<< %s", query)
@@ -106,7 +169,7 @@ func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.R
 	fields := []any{zap.Any("args", args)}
 	tx.l.Sugar().With(fields...).Debugf(">>> %s", query)
 
-	res, err := tx.sqlTx.ExecContext(ctx, query, args...)
+	res, err := tx.querier().ExecContext(ctx, query, args...)
 
 	// to differentiate between 0 and nil
 	var ra *int64